@@ -5,6 +5,7 @@ import "time"
 type AlertManager interface {
 	TopologyAlertManager
 	CoordinatorAlertManager
+	BreakerAlertManager
 }
 
 type Cancellable interface {
@@ -23,3 +24,11 @@ type TopologyAlertManager interface {
 type CoordinatorAlertManager interface {
 	CoordinatorPanic() Cancellable
 }
+
+// BreakerAlertManager defines the alerts for the state changes of the circuit
+// breaker of a cluster, the alerts are given the name of the breaker.
+type BreakerAlertManager interface {
+	BreakerOpen(string) Cancellable
+	BreakerHalfOpen(string) Cancellable
+	BreakerClosed(string) Cancellable
+}
@@ -26,6 +26,30 @@ func (m manager) CoordinatorPanic() alertmanager.Cancellable {
 	return &cancellable{nodes}
 }
 
+func (m manager) BreakerOpen(name string) alertmanager.Cancellable {
+	nodes := make([]alertmanager.Cancellable, 0, len(m.managers))
+	for _, v := range m.managers {
+		nodes = append(nodes, v.BreakerOpen(name))
+	}
+	return &cancellable{nodes}
+}
+
+func (m manager) BreakerHalfOpen(name string) alertmanager.Cancellable {
+	nodes := make([]alertmanager.Cancellable, 0, len(m.managers))
+	for _, v := range m.managers {
+		nodes = append(nodes, v.BreakerHalfOpen(name))
+	}
+	return &cancellable{nodes}
+}
+
+func (m manager) BreakerClosed(name string) alertmanager.Cancellable {
+	nodes := make([]alertmanager.Cancellable, 0, len(m.managers))
+	for _, v := range m.managers {
+		nodes = append(nodes, v.BreakerClosed(name))
+	}
+	return &cancellable{nodes}
+}
+
 type cancellable struct {
 	nodes []alertmanager.Cancellable
 }
@@ -12,6 +12,12 @@ func (m manager) TopologyPanic() alertmanager.Cancellable { return cancellable{}
 
 func (m manager) CoordinatorPanic() alertmanager.Cancellable { return cancellable{} }
 
+func (m manager) BreakerOpen(string) alertmanager.Cancellable { return cancellable{} }
+
+func (m manager) BreakerHalfOpen(string) alertmanager.Cancellable { return cancellable{} }
+
+func (m manager) BreakerClosed(string) alertmanager.Cancellable { return cancellable{} }
+
 type cancellable struct{}
 
 func (c cancellable) Cancel() {}
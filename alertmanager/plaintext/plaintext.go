@@ -31,3 +31,24 @@ func (m manager) CoordinatorPanic() alertmanager.Cancellable {
 		Delay:       time.Second * 30,
 	})
 }
+
+func (m manager) BreakerOpen(name string) alertmanager.Cancellable {
+	return m.Dispatch(fmt.Sprintf("breaker_open.count 1 %s", name), alertmanager.AlertOptions{
+		Repititions: 1,
+		Delay:       time.Second * 30,
+	})
+}
+
+func (m manager) BreakerHalfOpen(name string) alertmanager.Cancellable {
+	return m.Dispatch(fmt.Sprintf("breaker_half_open.count 1 %s", name), alertmanager.AlertOptions{
+		Repititions: 1,
+		Delay:       time.Second * 30,
+	})
+}
+
+func (m manager) BreakerClosed(name string) alertmanager.Cancellable {
+	return m.Dispatch(fmt.Sprintf("breaker_closed.count 1 %s", name), alertmanager.AlertOptions{
+		Repititions: 1,
+		Delay:       time.Second * 30,
+	})
+}
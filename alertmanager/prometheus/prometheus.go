@@ -1,6 +1,7 @@
 package prometheus
 
 import (
+	"strings"
 	"time"
 
 	"github.com/SimonRichardson/echelon/alertmanager"
@@ -12,6 +13,13 @@ type namespace string
 const (
 	topologyPanic    namespace = "topology_panic"
 	coordinatorPanic namespace = "coordinator_panic"
+
+	breakerOpen     namespace = "breaker_open"
+	breakerHalfOpen namespace = "breaker_half_open"
+	breakerClosed   namespace = "breaker_closed"
+
+	// labelSeparator separates the namespace of an alert from it's label.
+	labelSeparator = ":"
 )
 
 func (n namespace) String() string {
@@ -21,6 +29,7 @@ func (n namespace) String() string {
 type manager struct {
 	alertmanager.AlertBase
 	counters map[string]prometheus.Counter
+	vectors  map[string]*prometheus.CounterVec
 }
 
 func New(prefix string, maxSummaryAge time.Duration) alertmanager.AlertManager {
@@ -37,15 +46,45 @@ func New(prefix string, maxSummaryAge time.Duration) alertmanager.AlertManager {
 		}),
 	}
 
+	vectors := map[string]*prometheus.CounterVec{
+		breakerOpen.String(): prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      breakerOpen.String(),
+			Help:      "How many breaker_open have been made.",
+		}, []string{"breaker"}),
+		breakerHalfOpen.String(): prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      breakerHalfOpen.String(),
+			Help:      "How many breaker_half_open have been made.",
+		}, []string{"breaker"}),
+		breakerClosed.String(): prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      breakerClosed.String(),
+			Help:      "How many breaker_closed have been made.",
+		}, []string{"breaker"}),
+	}
+
 	for _, v := range counters {
 		prometheus.MustRegister(v)
 	}
+	for _, v := range vectors {
+		prometheus.MustRegister(v)
+	}
 
 	return manager{alertmanager.Make(func(s string) {
 		if counter, ok := counters[s]; ok {
 			counter.Inc()
+			return
+		}
+
+		parts := strings.SplitN(s, labelSeparator, 2)
+		if len(parts) != 2 {
+			return
+		}
+		if vector, ok := vectors[parts[0]]; ok {
+			vector.WithLabelValues(parts[1]).Inc()
 		}
-	}), counters}
+	}), counters, vectors}
 }
 
 func (m manager) TopologyPanic() alertmanager.Cancellable {
@@ -61,3 +100,24 @@ func (m manager) CoordinatorPanic() alertmanager.Cancellable {
 		Delay:       time.Second * 30,
 	})
 }
+
+func (m manager) BreakerOpen(name string) alertmanager.Cancellable {
+	return m.Dispatch(breakerOpen.String()+labelSeparator+name, alertmanager.AlertOptions{
+		Repititions: 1,
+		Delay:       time.Second * 30,
+	})
+}
+
+func (m manager) BreakerHalfOpen(name string) alertmanager.Cancellable {
+	return m.Dispatch(breakerHalfOpen.String()+labelSeparator+name, alertmanager.AlertOptions{
+		Repititions: 1,
+		Delay:       time.Second * 30,
+	})
+}
+
+func (m manager) BreakerClosed(name string) alertmanager.Cancellable {
+	return m.Dispatch(breakerClosed.String()+labelSeparator+name, alertmanager.AlertOptions{
+		Repititions: 1,
+		Delay:       time.Second * 30,
+	})
+}
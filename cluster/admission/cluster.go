@@ -0,0 +1,216 @@
+package admission
+
+import (
+	"time"
+
+	p "github.com/SimonRichardson/echelon/internal/redis"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+const (
+	// The counters of a queue are kept in a hash, whilst the uses of a token
+	// and the issues of a client are kept in counters that expire. All of them
+	// are routed by the same key, so that they're on the same instance.
+	queuePrefix  = "a:"
+	usesPrefix   = "a:u:"
+	clientPrefix = "a:c:"
+)
+
+var (
+	// issueScript issues the next sequence in the queue, unless the client has
+	// already been issued too many in the window.
+	issueScript = redis.NewScript(2, `
+		local limit = tonumber(ARGV[1])
+		if limit > 0 then
+			local issues = redis.call("INCR", KEYS[2])
+			if issues == 1 and tonumber(ARGV[2]) > 0 then
+				redis.call("PEXPIRE", KEYS[2], ARGV[2])
+			end
+			if issues > limit then
+				return -1
+			end
+		end
+		local sequence = redis.call("HINCRBY", KEYS[1], "issued", 1)
+		if tonumber(ARGV[3]) > 0 then
+			redis.call("PEXPIRE", KEYS[1], ARGV[3])
+		end
+		return sequence
+	`)
+
+	// advanceScript admits as many of the waiting sequences as the allowance
+	// lets through. The allowance is refilled by how long it's been since the
+	// last advance, up to the rate, much like a token bucket.
+	advanceScript = redis.NewScript(1, `
+		local rate = tonumber(ARGV[1])
+		local duration = tonumber(ARGV[2])
+		local now = tonumber(ARGV[3])
+		local values = redis.call("HMGET", KEYS[1], "issued", "admitted", "allowance", "last")
+		local issued = tonumber(values[1] or "0")
+		local admitted = tonumber(values[2] or "0")
+		local allowance = tonumber(values[3] or ARGV[1])
+		local last = tonumber(values[4] or ARGV[3])
+		if rate <= 0 then
+			admitted = issued
+		else
+			if now > last then
+				allowance = math.min(rate, allowance + ((now - last) * rate / duration))
+				last = now
+			end
+			local admit = math.min(issued - admitted, math.floor(allowance))
+			if admit > 0 then
+				admitted = admitted + admit
+				allowance = allowance - admit
+			end
+		end
+		if issued > 0 then
+			redis.call("HMSET", KEYS[1], "admitted", admitted, "allowance", tostring(allowance), "last", last)
+			if tonumber(ARGV[4]) > 0 then
+				redis.call("PEXPIRE", KEYS[1], ARGV[4])
+			end
+		end
+		return {issued, admitted}
+	`)
+
+	// useScript takes a use of a token, unless it's been used up.
+	useScript = redis.NewScript(1, `
+		local used = redis.call("INCR", KEYS[1])
+		if used == 1 and tonumber(ARGV[2]) > 0 then
+			redis.call("PEXPIRE", KEYS[1], ARGV[2])
+		end
+		if used > tonumber(ARGV[1]) then
+			redis.call("DECR", KEYS[1])
+			return 0
+		end
+		return 1
+	`)
+
+	// releaseScript gives a use back, without ever going below none.
+	releaseScript = redis.NewScript(1, `
+		local used = tonumber(redis.call("GET", KEYS[1]) or "0")
+		if used > 0 then
+			redis.call("DECR", KEYS[1])
+		end
+		return used
+	`)
+)
+
+// Cluster defines a way to hold the queues of the waiting room, so that every
+// instance shares the same queue for a key.
+type Cluster interface {
+	// Issue issues the next sequence in the queue of the key. If the client
+	// has already been issued limit sequences within the window, then none is
+	// issued. A limit of 0 lets a client be issued any amount. The queue is
+	// forgotten once nothing has happened to it for the expiry (0 never).
+	Issue(key bs.Key, client string, limit int, window, expiry time.Duration) (int64, bool, error)
+
+	// Advance admits the sequences that are waiting at rate every duration,
+	// returning how many have been issued and how many have been admitted. A
+	// rate of 0 admits every sequence.
+	Advance(key bs.Key, rate int, duration, expiry time.Duration) (int64, int64, error)
+
+	// Use takes one of the uses of the token, returning false once all of them
+	// have been taken.
+	Use(key bs.Key, token string, uses int, expiry time.Duration) (bool, error)
+
+	// Release gives back a use of the token that was taken.
+	Release(key bs.Key, token string) error
+
+	Close() error
+}
+
+type cluster struct {
+	pool *p.Pool
+}
+
+// New creates a cluster using a pool to hold the queues in redis.
+func New(pool *p.Pool) Cluster {
+	return &cluster{
+		pool: pool,
+	}
+}
+
+func (c *cluster) Issue(key bs.Key, client string,
+	limit int,
+	window, expiry time.Duration,
+) (sequence int64, ok bool, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		value, err := redis.Int64(issueScript.Do(conn,
+			queuePrefix+key.String(),
+			clientPrefix+key.String()+":"+client,
+			limit,
+			milliseconds(window),
+			milliseconds(expiry),
+		))
+		if err != nil {
+			return err
+		}
+
+		sequence, ok = value, value > 0
+		return nil
+	})
+	return
+}
+
+func (c *cluster) Advance(key bs.Key,
+	rate int,
+	duration, expiry time.Duration,
+) (issued, admitted int64, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		values, err := redis.Int64s(advanceScript.Do(conn,
+			queuePrefix+key.String(),
+			rate,
+			milliseconds(duration),
+			time.Now().UnixNano()/int64(time.Millisecond),
+			milliseconds(expiry),
+		))
+		if err != nil {
+			return err
+		}
+
+		issued, admitted = values[0], values[1]
+		return nil
+	})
+	return
+}
+
+func (c *cluster) Use(key bs.Key, token string,
+	uses int,
+	expiry time.Duration,
+) (ok bool, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		ok, err = redis.Bool(useScript.Do(conn,
+			usesPrefix+token,
+			uses,
+			milliseconds(expiry),
+		))
+		return err
+	})
+	return
+}
+
+func (c *cluster) Release(key bs.Key, token string) error {
+	return c.pool.With(key.String(), func(conn redis.Conn) error {
+		_, err := releaseScript.Do(conn, usesPrefix+token)
+		return err
+	})
+}
+
+func (c *cluster) Close() error {
+	c.pool.Close()
+	return nil
+}
+
+// milliseconds rounds the duration up, so that a duration of less than a
+// millisecond doesn't become no expiry at all. A duration of 0 stays 0, which
+// the scripts take to mean that there is no expiry.
+func milliseconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	ms := int64(d / time.Millisecond)
+	if d%time.Millisecond != 0 {
+		ms++
+	}
+	return ms
+}
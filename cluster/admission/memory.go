@@ -0,0 +1,190 @@
+package admission
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+)
+
+var (
+	memoryMutex = &sync.Mutex{}
+	memories    = map[string]*memoryQueues{}
+)
+
+type memoryQueues struct {
+	mutex   *sync.Mutex
+	queues  map[bs.Key]*memoryQueue
+	clients map[string]*memoryCounter
+	uses    map[string]*memoryCounter
+}
+
+type memoryQueue struct {
+	issued, admitted int64
+	allowance        float64
+	last             time.Time
+	expires          time.Time
+}
+
+type memoryCounter struct {
+	value   int
+	expires time.Time
+}
+
+type memory struct {
+	*memoryQueues
+}
+
+// NewMemory creates a cluster that is held in memory, but has identical
+// semantics to the redis cluster. Clusters that share the same name also share
+// the same queues, much like pointing at the same redis instance.
+func NewMemory(name string) Cluster {
+	memoryMutex.Lock()
+	defer memoryMutex.Unlock()
+
+	queues, ok := memories[name]
+	if !ok {
+		queues = &memoryQueues{
+			mutex:   &sync.Mutex{},
+			queues:  map[bs.Key]*memoryQueue{},
+			clients: map[string]*memoryCounter{},
+			uses:    map[string]*memoryCounter{},
+		}
+		memories[name] = queues
+	}
+	return &memory{queues}
+}
+
+func (c *memory) Issue(key bs.Key, client string,
+	limit int,
+	window, expiry time.Duration,
+) (int64, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	if limit > 0 {
+		counter := c.counter(c.clients, key.String()+":"+client, window, now)
+		if counter.value++; counter.value > limit {
+			return 0, false, nil
+		}
+	}
+
+	q := c.queue(key, now)
+	q.issued++
+	q.expires = expires(now, expiry)
+	return q.issued, true, nil
+}
+
+func (c *memory) Advance(key bs.Key,
+	rate int,
+	duration, expiry time.Duration,
+) (int64, int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	q, ok := c.queues[key]
+	if !ok || expired(q.expires, now) {
+		return 0, 0, nil
+	}
+
+	if rate <= 0 {
+		q.admitted = q.issued
+	} else {
+		if now.After(q.last) {
+			refill := float64(rate)
+			if duration > 0 {
+				refill = float64(now.Sub(q.last)) * float64(rate) / float64(duration)
+			}
+			q.allowance = math.Min(float64(rate), q.allowance+refill)
+			q.last = now
+		}
+		if admit := math.Min(float64(q.issued-q.admitted), math.Floor(q.allowance)); admit > 0 {
+			q.admitted += int64(admit)
+			q.allowance -= admit
+		}
+	}
+
+	q.expires = expires(now, expiry)
+	return q.issued, q.admitted, nil
+}
+
+func (c *memory) Use(key bs.Key, token string,
+	uses int,
+	expiry time.Duration,
+) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	counter := c.counter(c.uses, token, expiry, time.Now())
+	if counter.value >= uses {
+		return false, nil
+	}
+	counter.value++
+	return true, nil
+}
+
+func (c *memory) Release(key bs.Key, token string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if counter, ok := c.uses[token]; ok && counter.value > 0 {
+		counter.value--
+	}
+	return nil
+}
+
+func (c *memory) Close() error {
+	return nil
+}
+
+// queue returns the queue of the key, starting a new one if the previous one
+// has expired. A new queue starts with a full allowance.
+func (c *memory) queue(key bs.Key, now time.Time) *memoryQueue {
+	q, ok := c.queues[key]
+	if !ok || expired(q.expires, now) {
+		q = &memoryQueue{
+			allowance: math.Inf(1),
+			last:      now,
+		}
+		c.queues[key] = q
+	}
+	return q
+}
+
+// counter returns the counter of the name, starting a new one if the previous
+// one has expired. The counters that have expired are removed as well, much
+// like redis would.
+func (c *memory) counter(counters map[string]*memoryCounter,
+	name string,
+	expiry time.Duration,
+	now time.Time,
+) *memoryCounter {
+	for k, v := range counters {
+		if expired(v.expires, now) {
+			delete(counters, k)
+		}
+	}
+
+	counter, ok := counters[name]
+	if !ok {
+		counter = &memoryCounter{
+			expires: expires(now, expiry),
+		}
+		counters[name] = counter
+	}
+	return counter
+}
+
+func expires(now time.Time, expiry time.Duration) time.Time {
+	if expiry <= 0 {
+		return time.Time{}
+	}
+	return now.Add(expiry)
+}
+
+func expired(expires, now time.Time) bool {
+	return !expires.IsZero() && !now.Before(expires)
+}
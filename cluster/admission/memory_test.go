@@ -0,0 +1,184 @@
+package admission
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+)
+
+func newMemoryCluster() (string, Cluster) {
+	name := fmt.Sprintf("memory_%d", rand.Int63())
+	return name, NewMemory(name)
+}
+
+func TestMemoryIssue(t *testing.T) {
+	var (
+		_, cluster = newMemoryCluster()
+		key        = bs.Key("key")
+	)
+
+	for i := 1; i <= 3; i++ {
+		sequence, ok, err := cluster.Issue(key, fmt.Sprintf("client%d", i), 1, time.Hour, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := true, ok; expected != actual {
+			t.Errorf("Expected: %v, Actual: %v", expected, actual)
+		}
+		if expected, actual := int64(i), sequence; expected != actual {
+			t.Errorf("Expected: %v, Actual: %v", expected, actual)
+		}
+	}
+}
+
+func TestMemoryIssueLimit(t *testing.T) {
+	var (
+		_, cluster = newMemoryCluster()
+		key        = bs.Key("key")
+	)
+
+	for k, v := range []bool{true, true, false} {
+		_, ok, err := cluster.Issue(key, "client", 2, time.Hour, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := v, ok; expected != actual {
+			t.Errorf("%d: Expected: %v, Actual: %v", k, expected, actual)
+		}
+	}
+
+	// Other clients aren't limited by the client.
+	if _, ok, err := cluster.Issue(key, "other", 2, time.Hour, time.Hour); err != nil {
+		t.Fatal(err)
+	} else if expected, actual := true, ok; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemoryIssueLimitWindow(t *testing.T) {
+	var (
+		_, cluster = newMemoryCluster()
+		key        = bs.Key("key")
+	)
+
+	if _, ok, err := cluster.Issue(key, "client", 1, time.Millisecond, time.Hour); err != nil || !ok {
+		t.Fatalf("Expected: %v, Actual: %v (%v)", true, ok, err)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+
+	_, ok, err := cluster.Issue(key, "client", 1, time.Millisecond, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := true, ok; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemoryAdvance(t *testing.T) {
+	var (
+		_, cluster = newMemoryCluster()
+		key        = bs.Key("key")
+	)
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := cluster.Issue(key, "client", 0, 0, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issued, admitted, err := cluster.Advance(key, 2, time.Hour, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := int64(3), issued; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := int64(2), admitted; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	// The allowance has been used up, so advancing again admits no more.
+	if _, admitted, err = cluster.Advance(key, 2, time.Hour, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := int64(2), admitted; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemoryAdvanceAll(t *testing.T) {
+	var (
+		_, cluster = newMemoryCluster()
+		key        = bs.Key("key")
+	)
+
+	for i := 0; i < 10; i++ {
+		if _, _, err := cluster.Issue(key, "client", 0, 0, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, admitted, err := cluster.Advance(key, 0, time.Second, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := int64(10), admitted; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemoryUse(t *testing.T) {
+	var (
+		_, cluster = newMemoryCluster()
+		key        = bs.Key("key")
+		token      = "key.1.1"
+	)
+
+	for k, v := range []bool{true, true, false} {
+		ok, err := cluster.Use(key, token, 2, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := v, ok; expected != actual {
+			t.Errorf("%d: Expected: %v, Actual: %v", k, expected, actual)
+		}
+	}
+
+	// Releasing a use lets the token be used again.
+	if err := cluster.Release(key, token); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := cluster.Use(key, token, 2, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := true, ok; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemoryShared(t *testing.T) {
+	var (
+		name, cluster = newMemoryCluster()
+		other         = NewMemory(name)
+		key           = bs.Key("key")
+	)
+
+	if _, _, err := cluster.Issue(key, "client", 0, 0, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	sequence, _, err := other.Issue(key, "client", 0, 0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := int64(2), sequence; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
@@ -0,0 +1,60 @@
+package admission
+
+import (
+	"strings"
+
+	"github.com/SimonRichardson/echelon/common"
+	"github.com/SimonRichardson/echelon/errors"
+	r "github.com/SimonRichardson/echelon/internal/redis"
+	"github.com/SimonRichardson/echelon/internal/typex"
+)
+
+// ParseString parses various inputs and returns the cluster that holds the
+// queues of the waiting room. Unlike the other clusters, there is only ever
+// one, so that every instance shares the same queue for a key.
+// - addresses is a comma separated string of redis addresses, or a mem://name
+//   address for a cluster that is held in memory
+// - connectTimeout, readTimeout and writeTimeout is a set of durations in
+//   string format
+// - poolRoutingStrategy defines a strategy for how the pool routing works
+func ParseString(addresses string,
+	connectTimeout, readTimeout, writeTimeout string,
+	poolRoutingStrategy string,
+	maxSize int,
+	creator r.RedisCreator,
+) (Cluster, error) {
+	address := common.StripWhitespace(addresses)
+	if name, ok, err := r.MemoryHost(address); err != nil {
+		return nil, err
+	} else if ok {
+		return NewMemory(name), nil
+	}
+
+	timeouts, strategy, err := r.Parse(connectTimeout,
+		readTimeout,
+		writeTimeout,
+		poolRoutingStrategy,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := []string{}
+	for _, host := range strings.Split(address, ",") {
+		if len(host) < 1 {
+			continue
+		}
+		if err := r.ValidRedisHost(host); err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host)
+	}
+
+	if len(hosts) < 1 {
+		return nil, typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
+			"No hosts specified %q", addresses)
+	}
+
+	return New(r.New(hosts, strategy, timeouts, maxSize, creator)), nil
+}
@@ -1,6 +1,7 @@
 package counter
 
 import (
+	"context"
 	"sync"
 
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
@@ -42,38 +43,38 @@ func New(pool *p.Pool) Cluster {
 	}
 }
 
-func (c *cluster) Insert(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+func (c *cluster) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
 	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
-	return c.countCommon(keys, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
+	return c.countCommon(ctx, keys, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
 		return insertion(conn, values[key], sizeExpiry[key])
 	})
 }
 
-func (c *cluster) Delete(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+func (c *cluster) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
 	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
-	return c.countCommon(keys, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
+	return c.countCommon(ctx, keys, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
 		return deletion(conn, values[key], sizeExpiry[key])
 	})
 }
 
-func (c *cluster) Rollback(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
-	return c.Delete(members, sizeExpiry)
+func (c *cluster) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	return c.Delete(ctx, members, sizeExpiry)
 }
 
-func (c *cluster) Size(key bs.Key) <-chan t.Element {
-	return c.countCommon([]bs.Key{key}, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
+func (c *cluster) Size(ctx context.Context, key bs.Key) <-chan t.Element {
+	return c.countCommon(ctx, []bs.Key{key}, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
 		return cardinality(conn, key)
 	})
 }
 
-func (c *cluster) Keys() <-chan t.Element {
-	return c.keyCommon(bs.Key(defaultKeysKey), func(conn redis.Conn) ([]bs.Key, error) {
+func (c *cluster) Keys(ctx context.Context) <-chan t.Element {
+	return c.keyCommon(ctx, bs.Key(defaultKeysKey), func(conn redis.Conn) ([]bs.Key, error) {
 		return keys(conn, defaultBatchSize)
 	})
 }
 
-func (c *cluster) Members(key bs.Key) <-chan t.Element {
-	return c.keyCommon(key, func(conn redis.Conn) ([]bs.Key, error) {
+func (c *cluster) Members(ctx context.Context, key bs.Key) <-chan t.Element {
+	return c.keyCommon(ctx, key, func(conn redis.Conn) ([]bs.Key, error) {
 		return members(conn, key)
 	})
 }
@@ -89,7 +90,7 @@ func (c *cluster) Close() error {
 	return nil
 }
 
-func (c *cluster) countCommon(keys []bs.Key, f func(redis.Conn, bs.Key) ([]s.KeyCount, error)) <-chan t.Element {
+func (c *cluster) countCommon(ctx context.Context, keys []bs.Key, f func(redis.Conn, bs.Key) ([]s.KeyCount, error)) <-chan t.Element {
 	out := make(chan t.Element)
 	go func() {
 
@@ -104,7 +105,7 @@ func (c *cluster) countCommon(keys []bs.Key, f func(redis.Conn, bs.Key) ([]s.Key
 					elements []t.Element
 					result   = []s.KeyCount{s.KeyCount{Key: key, Count: 0}}
 				)
-				if err := c.pool.With(key.String(), func(conn redis.Conn) (err error) {
+				if err := with(ctx, c.pool, key, func(conn redis.Conn) (err error) {
 					result, err = f(conn, key)
 					return
 				}); err != nil {
@@ -114,7 +115,9 @@ func (c *cluster) countCommon(keys []bs.Key, f func(redis.Conn, bs.Key) ([]s.Key
 				}
 
 				for _, element := range elements {
-					out <- element
+					if !t.Send(ctx, out, element) {
+						return
+					}
 				}
 			}(k, v)
 		}
@@ -151,7 +154,7 @@ func successElementsFromKeyCount(keys []s.KeyCount) []t.Element {
 	return elements
 }
 
-func (c *cluster) keyCommon(key bs.Key,
+func (c *cluster) keyCommon(ctx context.Context, key bs.Key,
 	f func(redis.Conn) ([]bs.Key, error),
 ) <-chan t.Element {
 	out := make(chan t.Element)
@@ -164,13 +167,13 @@ func (c *cluster) keyCommon(key bs.Key,
 			defer wg.Done()
 
 			var result []bs.Key
-			if err := c.pool.With(key.String(), func(conn redis.Conn) (err error) {
+			if err := with(ctx, c.pool, key, func(conn redis.Conn) (err error) {
 				result, err = f(conn)
 				return
 			}); err != nil {
-				out <- t.NewErrorElement(key, err)
+				t.Send(ctx, out, t.NewErrorElement(key, err))
 			} else {
-				out <- t.NewKeyElement(key, result)
+				t.Send(ctx, out, t.NewKeyElement(key, result))
 			}
 		}()
 
@@ -221,3 +224,12 @@ func (c *cluster) scoreCommon(members []s.KeyFieldTxnValue,
 	}
 	return presenceMap, nil
 }
+
+// with runs the function with a connection to the host of the key, unless the
+// context is already done.
+func with(ctx context.Context, pool *p.Pool, key bs.Key, fn func(redis.Conn) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return pool.With(key.String(), fn)
+}
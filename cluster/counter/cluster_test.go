@@ -1,6 +1,7 @@
 package counter
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"math/rand"
@@ -51,7 +52,7 @@ func newCluster(creator p.RedisCreator) Cluster {
 
 type fnAlias func(string, string, string, string, time.Duration) <-chan c.Element
 
-func execute(fn func([]selectors.KeyFieldScoreTxnValue, selectors.KeySizeExpiry) <-chan c.Element, amount int) fnAlias {
+func execute(fn func(context.Context, []selectors.KeyFieldScoreTxnValue, selectors.KeySizeExpiry) <-chan c.Element, amount int) fnAlias {
 	return func(key, field, txn, value string, duration time.Duration) <-chan c.Element {
 
 		members := []selectors.KeyFieldScoreTxnValue{}
@@ -64,7 +65,7 @@ func execute(fn func([]selectors.KeyFieldScoreTxnValue, selectors.KeySizeExpiry)
 				Value: value,
 			})
 		}
-		return fn(members, selectors.KeySizeExpiry{
+		return fn(context.Background(), members, selectors.KeySizeExpiry{
 			bs.Key(key): selectors.SizeExpiry{
 				Size:   int64(amount) + 1,
 				Expiry: duration,
@@ -77,7 +78,7 @@ func insert(cluster Cluster, amount int) fnAlias {
 	return execute(cluster.Insert, amount)
 }
 
-func delete(cluster Cluster, amount int) fnAlias {
+func remove(cluster Cluster, amount int) fnAlias {
 	return execute(cluster.Delete, amount)
 }
 
@@ -158,7 +159,7 @@ func TestDelete(t *testing.T) {
 		amount  = rand.Intn(5) + 1
 		cluster = newCluster(creator)
 		in      = insert(cluster, amount)
-		del     = delete(cluster, amount)
+		del     = remove(cluster, amount)
 		pool    = getIdentPool()
 
 		f = func(field, txn, value string, duration time.Duration) bool {
@@ -215,7 +216,7 @@ func TestSize(t *testing.T) {
 				typex.Fatal(err)
 			}
 			checkErrors(in(key.Hex(), field, txn, value, duration))
-			dst := cluster.Size(bs.Key(key.Hex()))
+			dst := cluster.Size(context.Background(), bs.Key(key.Hex()))
 
 			result := 0
 			for e := range dst {
@@ -271,7 +272,7 @@ func TestKeys(t *testing.T) {
 			keys = append(keys, []byte(fmt.Sprintf("%s%s%s", prefix, val, insertSuffix)))
 
 			checkErrors(in(val, field, txn, value, duration))
-			dst := cluster.Keys()
+			dst := cluster.Keys(context.Background())
 
 			for e := range dst {
 				if err := c.ErrorFromElement(e); err != nil {
@@ -329,7 +330,7 @@ func TestMembers(t *testing.T) {
 			fields = append(fields, []byte(field))
 
 			checkErrors(in(key.Hex(), field, txn, value, duration))
-			dst := cluster.Members(bs.Key(key.Hex()))
+			dst := cluster.Members(context.Background(), bs.Key(key.Hex()))
 
 			for e := range dst {
 				if err := c.ErrorFromElement(e); err != nil {
@@ -0,0 +1,259 @@
+package counter
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	t "github.com/SimonRichardson/echelon/cluster"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+var (
+	memoryMutex = &sync.Mutex{}
+	memories    = map[string]*memory{}
+)
+
+type memory struct {
+	mutex   *sync.RWMutex
+	inserts map[bs.Key]map[bs.Key]float64
+	deletes map[bs.Key]map[bs.Key]float64
+}
+
+// NewMemory creates a cluster that is held in memory, but has identical
+// semantics to the redis cluster. Clusters that share the same name also share
+// the same storage, much like pointing at the same redis instance.
+func NewMemory(name string) Cluster {
+	memoryMutex.Lock()
+	defer memoryMutex.Unlock()
+
+	if m, ok := memories[name]; ok {
+		return m
+	}
+
+	m := &memory{
+		mutex:   &sync.RWMutex{},
+		inserts: map[bs.Key]map[bs.Key]float64{},
+		deletes: map[bs.Key]map[bs.Key]float64{},
+	}
+	memories[name] = m
+	return m
+}
+
+func (c *memory) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
+	return memoryCountCommon(ctx, keys, func(key bs.Key) ([]s.KeyCount, error) {
+		return c.insertion(values[key], sizeExpiry[key])
+	})
+}
+
+func (c *memory) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
+	return memoryCountCommon(ctx, keys, func(key bs.Key) ([]s.KeyCount, error) {
+		return c.deletion(values[key], sizeExpiry[key])
+	})
+}
+
+func (c *memory) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	return c.Delete(ctx, members, sizeExpiry)
+}
+
+func (c *memory) Size(ctx context.Context, key bs.Key) <-chan t.Element {
+	return memoryCountCommon(ctx, []bs.Key{key}, func(key bs.Key) ([]s.KeyCount, error) {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+
+		return []s.KeyCount{
+			s.KeyCount{Key: key, Count: len(c.inserts[key])},
+		}, nil
+	})
+}
+
+func (c *memory) Keys(ctx context.Context) <-chan t.Element {
+	return memoryKeyCommon(ctx, bs.Key(defaultKeysKey), func() ([]bs.Key, error) {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+
+		result := make([]bs.Key, 0, len(c.inserts))
+		for key := range c.inserts {
+			result = append(result, key)
+		}
+		return result, nil
+	})
+}
+
+func (c *memory) Members(ctx context.Context, key bs.Key) <-chan t.Element {
+	return memoryKeyCommon(ctx, key, func() ([]bs.Key, error) {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+
+		// Members are ordered by score, then by field, the same as a sorted set.
+		var (
+			values = c.inserts[key]
+			result = make([]bs.Key, 0, len(values))
+		)
+		for field := range values {
+			result = append(result, field)
+		}
+		sort.Slice(result, func(i, j int) bool {
+			a, b := values[result[i]], values[result[j]]
+			return a < b || (a == b && result[i] < result[j])
+		})
+		return result, nil
+	})
+}
+
+func (c *memory) Score(members []s.KeyFieldTxnValue) (map[s.KeyFieldTxnValue]s.Presence, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	m := map[s.KeyFieldTxnValue]s.Presence{}
+	for _, member := range members {
+		var (
+			insertScore, inserted = c.inserts[member.Key][member.Field]
+			deleteScore, deleted  = c.deletes[member.Key][member.Field]
+		)
+
+		switch {
+		case inserted && !deleted:
+			m[member] = s.Presence{
+				Present:  true,
+				Inserted: true,
+				Score:    insertScore,
+			}
+		case !inserted && deleted:
+			m[member] = s.Presence{
+				Present:  true,
+				Inserted: false,
+				Score:    deleteScore,
+			}
+		default:
+			m[member] = s.Presence{
+				Present: false,
+			}
+		}
+	}
+	return m, nil
+}
+
+func (c *memory) Close() error {
+	return nil
+}
+
+func (c *memory) insertion(members []s.KeyFieldScoreTxnValue, sizeExpiry s.SizeExpiry) ([]s.KeyCount, error) {
+	result := make([]s.KeyCount, 0, len(members))
+
+	for _, m := range members {
+		res := c.write(c.inserts, c.deletes, m, sizeExpiry.Size)
+		if res == defaultFieldExists || res == defaultFieldInsertion {
+			result = append(result, s.KeyCount{Key: m.Key, Count: 1})
+		}
+	}
+
+	if len(result) < len(members) {
+		return result, t.ErrPartialInsertions
+	}
+
+	return result, nil
+}
+
+func (c *memory) deletion(members []s.KeyFieldScoreTxnValue, sizeExpiry s.SizeExpiry) ([]s.KeyCount, error) {
+	result := make([]s.KeyCount, 0, len(members))
+
+	for _, m := range members {
+		res := c.write(c.deletes, c.inserts, m, sizeExpiry.Size)
+		result = append(result, s.KeyCount{Key: m.Key, Count: abs(res)})
+	}
+
+	return result, nil
+}
+
+// write mirrors the counter script, the member is added to the add set after
+// removing it from the rem set, as long as the add set is under the max size
+// and the score is greater than the one already existing in either set.
+func (c *memory) write(add, rem map[bs.Key]map[bs.Key]float64,
+	member s.KeyFieldScoreTxnValue,
+	maxSize int64,
+) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var (
+		key   = member.Key
+		field = member.Field
+	)
+
+	if int64(len(add[key])) >= maxSize {
+		return -1
+	}
+
+	if score, ok := c.inserts[key][field]; ok && member.Score <= score {
+		return -1
+	}
+	if score, ok := c.deletes[key][field]; ok && member.Score <= score {
+		return -1
+	}
+
+	if values, ok := rem[key]; ok {
+		delete(values, field)
+		if len(values) < 1 {
+			delete(rem, key)
+		}
+	}
+
+	values, ok := add[key]
+	if !ok {
+		values = map[bs.Key]float64{}
+		add[key] = values
+	}
+
+	_, exists := values[field]
+	values[field] = member.Score
+
+	if exists {
+		return defaultFieldExists
+	}
+	return defaultFieldInsertion
+}
+
+func memoryCountCommon(ctx context.Context, keys []bs.Key, f func(bs.Key) ([]s.KeyCount, error)) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		for _, key := range keys {
+			var elements []t.Element
+			if err := ctx.Err(); err != nil {
+				elements = []t.Element{t.NewErrorElement(key, err)}
+			} else if result, err := f(key); err != nil {
+				elements = errorElementsFromKeyCount(result, err)
+			} else {
+				elements = successElementsFromKeyCount(result)
+			}
+
+			for _, element := range elements {
+				if !t.Send(ctx, out, element) {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+func memoryKeyCommon(ctx context.Context, key bs.Key, f func() ([]bs.Key, error)) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		if err := ctx.Err(); err != nil {
+			t.Send(ctx, out, t.NewErrorElement(key, err))
+		} else if result, err := f(); err != nil {
+			t.Send(ctx, out, t.NewErrorElement(key, err))
+		} else {
+			t.Send(ctx, out, t.NewKeyElement(key, result))
+		}
+	}()
+	return out
+}
@@ -0,0 +1,116 @@
+package counter
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"testing/quick"
+	"time"
+
+	c "github.com/SimonRichardson/echelon/cluster"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/selectors"
+	"github.com/SimonRichardson/echelon/tests"
+)
+
+func newMemoryCluster() Cluster {
+	return NewMemory(fmt.Sprintf("memory_%d", rand.Int63()))
+}
+
+func TestMemoryInsert(t *testing.T) {
+	var (
+		amount = rand.Intn(5) + 1
+
+		f = func(key, field, txn, value string) bool {
+			var (
+				cluster = newMemoryCluster()
+				in      = insert(cluster, amount)
+			)
+
+			dst := in(key, field, txn, value, time.Minute)
+
+			result := 0
+			for e := range dst {
+				if err := c.ErrorFromElement(e); err != nil {
+					typex.Fatal(err)
+				}
+				result += c.AmountFromElement(e)
+			}
+			return result == amount
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMemorySize(t *testing.T) {
+	var (
+		amount = rand.Intn(5) + 1
+
+		f = func(key, field, txn, value string) bool {
+			var (
+				cluster = newMemoryCluster()
+				in      = insert(cluster, amount)
+			)
+
+			checkErrors(in(key, field, txn, value, time.Minute))
+
+			result := 0
+			for e := range cluster.Size(context.Background(), bs.Key(key)) {
+				if err := c.ErrorFromElement(e); err != nil {
+					typex.Fatal(err)
+				}
+				result += c.AmountFromElement(e)
+			}
+			return result == amount
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMemoryMaxSize(t *testing.T) {
+	var (
+		f = func(key, field, txn string) bool {
+			cluster := newMemoryCluster()
+
+			members := []selectors.KeyFieldScoreTxnValue{
+				selectors.KeyFieldScoreTxnValue{
+					Key:   bs.Key(key),
+					Field: bs.Key(field + "_a"),
+					Score: 1,
+					Txn:   bs.Key(txn),
+				},
+				selectors.KeyFieldScoreTxnValue{
+					Key:   bs.Key(key),
+					Field: bs.Key(field + "_b"),
+					Score: 1,
+					Txn:   bs.Key(txn),
+				},
+			}
+
+			var err error
+			for e := range cluster.Insert(context.Background(), members, selectors.KeySizeExpiry{
+				bs.Key(key): selectors.SizeExpiry{
+					Size:   1,
+					Expiry: time.Minute,
+				},
+			}) {
+				if e := c.ErrorFromElement(e); e != nil {
+					err = e
+				}
+			}
+			return err == c.ErrPartialInsertions
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,141 @@
+package counter
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/garyburd/redigo/redis"
+)
+
+// Moved returns the keys held by the hosts of the previous cluster, that the
+// next cluster routes to a different host. Clusters that are held in memory
+// never move any keys.
+func Moved(prev, next Cluster) ([]bs.Key, error) {
+	from, ok0 := prev.(*cluster)
+	to, ok1 := next.(*cluster)
+	if !ok0 || !ok1 {
+		return []bs.Key{}, nil
+	}
+
+	moved, err := from.pool.Moved(to.pool, func(conn redis.Conn) ([]string, error) {
+		res, err := keys(conn, defaultBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]string, 0, len(res))
+		for _, v := range res {
+			result = append(result, v.String())
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]bs.Key, 0, len(moved))
+	for _, v := range moved {
+		result = append(result, bs.Key(v))
+	}
+	return result, nil
+}
+
+// ErrMigrationConflict defines an error where the key was written to the
+// previous host whilst it was being moved, so the move has to be tried again.
+var ErrMigrationConflict = typex.Errorf(errors.Source, errors.ConcurrentModification, "Migration Conflict")
+
+// Migrate moves the sorted sets of a key from the host of the previous cluster
+// to the host of the next cluster, it returns the number of members that were
+// moved. The members are replayed with the same scripts as a write, so any
+// member that's been written to the next cluster since isn't overwritten. Once
+// the members have moved, the key is removed from the previous host. The key is
+// watched whilst it's moved, so if it's written to the previous host in the
+// mean time, nothing is removed and ErrMigrationConflict is returned.
+func Migrate(prev, next Cluster, key bs.Key) (int, error) {
+	from, ok0 := prev.(*cluster)
+	to, ok1 := next.(*cluster)
+	if !ok0 || !ok1 {
+		return 0, nil
+	}
+
+	var (
+		insertKey = prefix + key.String() + insertSuffix
+		deleteKey = prefix + key.String() + deleteSuffix
+		amount    = 0
+	)
+	err := from.pool.With(key.String(), func(conn redis.Conn) error {
+		if _, err := conn.Do("WATCH", insertKey, deleteKey); err != nil {
+			return err
+		}
+
+		insertions, err := redis.Strings(conn.Do("ZRANGE", insertKey, 0, -1, "WITHSCORES"))
+		if err != nil {
+			conn.Do("UNWATCH")
+			return err
+		}
+		deletions, err := redis.Strings(conn.Do("ZRANGE", deleteKey, 0, -1, "WITHSCORES"))
+		if err != nil {
+			conn.Do("UNWATCH")
+			return err
+		}
+
+		if amount, err = replay(to, key, insertions, deletions); err != nil {
+			conn.Do("UNWATCH")
+			return err
+		}
+
+		conn.Send("MULTI")
+		conn.Send("DEL", insertKey, deleteKey)
+		reply, err := conn.Do("EXEC")
+		if err != nil {
+			return err
+		} else if reply == nil {
+			return ErrMigrationConflict
+		}
+		return nil
+	})
+	return amount, err
+}
+
+// replay writes the members to the host of the next cluster, returning the
+// number of members that were written.
+func replay(to *cluster, key bs.Key, insertions, deletions []string) (int, error) {
+	amount := 0
+	err := to.pool.With(key.String(), func(conn redis.Conn) error {
+		for _, v := range []struct {
+			values []string
+			send   func(redis.Conn, bs.Key, bs.Key, float64, int64) error
+		}{
+			{insertions, sendInsertScript},
+			{deletions, sendDeleteScript},
+		} {
+			for i := 0; i+1 < len(v.values); i += 2 {
+				score, err := strconv.ParseFloat(v.values[i+1], 64)
+				if err != nil {
+					return err
+				}
+				// The members have already been capped, so they're moved
+				// regardless of the size.
+				if err := v.send(conn, key, bs.Key(v.values[i]), score, math.MaxInt64); err != nil {
+					return err
+				}
+				amount++
+			}
+		}
+
+		if err := conn.Flush(); err != nil {
+			return err
+		}
+
+		for i := 0; i < amount; i++ {
+			if _, err := conn.Receive(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return amount, err
+}
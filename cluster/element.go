@@ -1,6 +1,8 @@
 package cluster
 
 import (
+	"context"
+
 	"github.com/SimonRichardson/echelon/errors"
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	"github.com/SimonRichardson/echelon/internal/typex"
@@ -28,6 +30,7 @@ const (
 	KeyFieldScoreTxnValueType
 	KeyFieldScoreSizeExpiryType
 	CountElementType
+	ChangeElementType
 )
 
 // Element combines a submitted key with the resulting values. If there was an
@@ -70,6 +73,17 @@ func ErrorFromElement(e Element) error {
 	return nil
 }
 
+// Send sends the element, unless the context is done before it's received. It
+// returns false if the element wasn't sent.
+func Send(ctx context.Context, out chan<- Element, e Element) bool {
+	select {
+	case out <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // KeyFieldScoreTxnValue defines a struct that is a container for members with in the
 // store.
 type KeyFieldScoreTxnValue struct {
@@ -182,14 +196,23 @@ type KeyFieldScoreSizeExpiryElement struct {
 	key    bs.Key
 	typ    ElementType
 	member s.KeyFieldScoreSizeExpiry
+	ack    func() error
 }
 
 // NewKeyFieldScoreSizeExpiryElement creates a new KeyFieldScoreSizeExpiryElement
 func NewKeyFieldScoreSizeExpiryElement(keyFieldScoreSizeExpiry s.KeyFieldScoreSizeExpiry) *KeyFieldScoreSizeExpiryElement {
+	return NewAckKeyFieldScoreSizeExpiryElement(keyFieldScoreSizeExpiry, nil)
+}
+
+// NewAckKeyFieldScoreSizeExpiryElement creates a new
+// KeyFieldScoreSizeExpiryElement for a member of a stream, which is
+// acknowledged by calling ack once it has been handled.
+func NewAckKeyFieldScoreSizeExpiryElement(keyFieldScoreSizeExpiry s.KeyFieldScoreSizeExpiry, ack func() error) *KeyFieldScoreSizeExpiryElement {
 	return &KeyFieldScoreSizeExpiryElement{
 		keyFieldScoreSizeExpiry.Key,
 		KeyFieldScoreSizeExpiryType,
 		keyFieldScoreSizeExpiry,
+		ack,
 	}
 }
 
@@ -205,10 +228,23 @@ func (e *KeyFieldScoreSizeExpiryElement) KeyFieldScoreSizeExpiry() s.KeyFieldSco
 	return e.member
 }
 
+// Ack acknowledges the member of the KeyFieldScoreSizeExpiryElement, members
+// that aren't from a stream don't need acknowledging.
+func (e *KeyFieldScoreSizeExpiryElement) Ack() error {
+	if e.ack == nil {
+		return nil
+	}
+	return e.ack()
+}
+
 type keyFieldScoreSizeExpiryElement interface {
 	KeyFieldScoreSizeExpiry() s.KeyFieldScoreSizeExpiry
 }
 
+type ackElement interface {
+	Ack() error
+}
+
 // KeyFieldScoreSizeExpiryFromElement attempts to get an key score members from
 // the element if it exists.
 func KeyFieldScoreSizeExpiryFromElement(e Element) s.KeyFieldScoreSizeExpiry {
@@ -217,3 +253,48 @@ func KeyFieldScoreSizeExpiryFromElement(e Element) s.KeyFieldScoreSizeExpiry {
 	}
 	return s.KeyFieldScoreSizeExpiry{}
 }
+
+// DeliveryFromElement attempts to get a delivery of a member from the element
+// if it exists, so that the member can be acknowledged once it's been handled.
+func DeliveryFromElement(e Element) s.Delivery {
+	member := KeyFieldScoreSizeExpiryFromElement(e)
+	if ae, ok := e.(ackElement); ok {
+		return s.NewDelivery(member, ae.Ack)
+	}
+	return s.NewDelivery(member, nil)
+}
+
+// ChangeElement defines a struct that is a container for changes with in the
+// notifier.
+type ChangeElement struct {
+	key     bs.Key
+	typ     ElementType
+	changes []s.Change
+}
+
+// NewChangeElement creates a new ChangeElement
+func NewChangeElement(key bs.Key, changes []s.Change) *ChangeElement {
+	return &ChangeElement{key, ChangeElementType, changes}
+}
+
+// Key defines the key associated with the ChangeElement
+func (e *ChangeElement) Key() bs.Key { return e.key }
+
+// Type defines the type associated with the ChangeElement
+func (e *ChangeElement) Type() ElementType { return e.typ }
+
+// Changes defines the changes associated with the ChangeElement
+func (e *ChangeElement) Changes() []s.Change { return e.changes }
+
+type changeElement interface {
+	Changes() []s.Change
+}
+
+// ChangesFromElement attempts to get the changes from the element if it
+// exists.
+func ChangesFromElement(e Element) []s.Change {
+	if ce, ok := e.(changeElement); ok {
+		return ce.Changes()
+	}
+	return []s.Change{}
+}
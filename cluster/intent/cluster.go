@@ -0,0 +1,138 @@
+package intent
+
+import (
+	"encoding/json"
+	"time"
+
+	p "github.com/SimonRichardson/echelon/internal/redis"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+const (
+	// The deadlines of the intents are kept in a sorted set, whilst the
+	// intents are kept in a hash. Both are routed by the same key, so that
+	// they're on the same instance.
+	deadlinesKey = "i:"
+	intentsKey   = "i:e:"
+)
+
+var (
+	// dueScript claims the intents that are past their deadline, by moving the
+	// deadline on by the lease. An intent that can't be found is removed.
+	dueScript = redis.NewScript(2, `
+		local ids = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, ARGV[3])
+		local result = {}
+		for _, id in ipairs(ids) do
+			local intent = redis.call("HGET", KEYS[2], id)
+			if intent == false then
+				redis.call("ZREM", KEYS[1], id)
+			else
+				redis.call("ZADD", KEYS[1], ARGV[2], id)
+				table.insert(result, intent)
+			end
+		end
+		return result
+	`)
+)
+
+// Intent defines the members of a batch that are being written, so that they
+// can be rolled back if the batch never completes.
+type Intent struct {
+	Id      bs.Key
+	Members []s.KeyFieldScoreTxnValue
+	MaxSize s.KeySizeExpiry
+}
+
+// Cluster defines a way to hold the intents of the batches outside of the
+// instance that's writing them, so that a batch that's left half written by an
+// instance that's gone away can be rolled back by another.
+type Cluster interface {
+	// Write holds the intent until it's removed, it's recovered once the
+	// deadline has passed.
+	Write(Intent, time.Time) error
+
+	// Remove removes the intent, returning false if it's already been removed
+	// (e.g. it's been recovered).
+	Remove(bs.Key) (bool, error)
+
+	// Due claims the intents that are past their deadline by now, moving the
+	// deadline on by the lease, so that they're not claimed by another
+	// instance whilst they're recovered.
+	Due(now time.Time, lease time.Duration, limit int) ([]Intent, error)
+
+	Close() error
+}
+
+type cluster struct {
+	pool *p.Pool
+}
+
+// New creates a cluster using a pool to hold the intents in redis.
+func New(pool *p.Pool) Cluster {
+	return &cluster{
+		pool: pool,
+	}
+}
+
+func (c *cluster) Write(intent Intent, deadline time.Time) error {
+	bytes, err := json.Marshal(intent)
+	if err != nil {
+		return err
+	}
+
+	return c.pool.With(deadlinesKey, func(conn redis.Conn) error {
+		conn.Send("MULTI")
+		conn.Send("ZADD", deadlinesKey, deadline.UnixNano(), intent.Id.String())
+		conn.Send("HSET", intentsKey, intent.Id.String(), bytes)
+		_, err := conn.Do("EXEC")
+		return err
+	})
+}
+
+func (c *cluster) Remove(id bs.Key) (ok bool, err error) {
+	err = c.pool.With(deadlinesKey, func(conn redis.Conn) error {
+		conn.Send("MULTI")
+		conn.Send("ZREM", deadlinesKey, id.String())
+		conn.Send("HDEL", intentsKey, id.String())
+		values, err := redis.Ints(conn.Do("EXEC"))
+		if err != nil {
+			return err
+		}
+		ok = len(values) > 1 && values[1] > 0
+		return nil
+	})
+	return
+}
+
+func (c *cluster) Due(now time.Time, lease time.Duration, limit int) (intents []Intent, err error) {
+	err = c.pool.With(deadlinesKey, func(conn redis.Conn) error {
+		values, err := redis.ByteSlices(dueScript.Do(conn,
+			deadlinesKey,
+			intentsKey,
+			now.UnixNano(),
+			now.Add(lease).UnixNano(),
+			limit,
+		))
+		if err != nil {
+			return err
+		}
+
+		intents = make([]Intent, 0, len(values))
+		for _, v := range values {
+			var intent Intent
+			if err := json.Unmarshal(v, &intent); err != nil {
+				return err
+			}
+			intents = append(intents, intent)
+		}
+		return nil
+	})
+	return
+}
+
+func (c *cluster) Close() error {
+	c.pool.Close()
+	return nil
+}
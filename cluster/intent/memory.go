@@ -0,0 +1,103 @@
+package intent
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+)
+
+var (
+	memoryMutex = &sync.Mutex{}
+	memories    = map[string]*memoryIntents{}
+)
+
+type memoryIntents struct {
+	mutex   *sync.Mutex
+	intents map[bs.Key]*memoryIntent
+}
+
+type memoryIntent struct {
+	intent   Intent
+	deadline time.Time
+}
+
+type memory struct {
+	*memoryIntents
+}
+
+// NewMemory creates a cluster that is held in memory, but has identical
+// semantics to the redis cluster. Clusters that share the same name also share
+// the same intents, much like pointing at the same redis instance.
+func NewMemory(name string) Cluster {
+	memoryMutex.Lock()
+	defer memoryMutex.Unlock()
+
+	intents, ok := memories[name]
+	if !ok {
+		intents = &memoryIntents{
+			mutex:   &sync.Mutex{},
+			intents: map[bs.Key]*memoryIntent{},
+		}
+		memories[name] = intents
+	}
+	return &memory{intents}
+}
+
+func (c *memory) Write(intent Intent, deadline time.Time) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.intents[intent.Id] = &memoryIntent{
+		intent:   intent,
+		deadline: deadline,
+	}
+	return nil
+}
+
+func (c *memory) Remove(id bs.Key) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.intents[id]; !ok {
+		return false, nil
+	}
+	delete(c.intents, id)
+	return true, nil
+}
+
+func (c *memory) Due(now time.Time, lease time.Duration, limit int) ([]Intent, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	due := []*memoryIntent{}
+	for _, v := range c.intents {
+		if !v.deadline.After(now) {
+			due = append(due, v)
+		}
+	}
+
+	// Order the intents by the deadline and then the id, much like a sorted
+	// set.
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].deadline.Equal(due[j].deadline) {
+			return due[i].intent.Id.String() < due[j].intent.Id.String()
+		}
+		return due[i].deadline.Before(due[j].deadline)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	result := make([]Intent, 0, len(due))
+	for _, v := range due {
+		v.deadline = now.Add(lease)
+		result = append(result, v.intent)
+	}
+	return result, nil
+}
+
+func (c *memory) Close() error {
+	return nil
+}
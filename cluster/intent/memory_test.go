@@ -0,0 +1,105 @@
+package intent
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+)
+
+func newMemoryCluster() Cluster {
+	return NewMemory(fmt.Sprintf("memory_%d", rand.Int63()))
+}
+
+func TestMemoryDue(t *testing.T) {
+	var (
+		cluster = newMemoryCluster()
+		now     = time.Now()
+	)
+
+	for k, v := range []time.Duration{time.Minute, -time.Minute, -time.Hour} {
+		if err := cluster.Write(Intent{Id: bs.Key(fmt.Sprintf("%d", k))}, now.Add(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	intents, err := cluster.Due(now, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 2, len(intents); expected != actual {
+		t.Fatalf("Expected: %v, Actual: %v", expected, actual)
+	}
+	for k, v := range []bs.Key{"2", "1"} {
+		if expected, actual := v, intents[k].Id; expected != actual {
+			t.Errorf("Expected: %v, Actual: %v", expected, actual)
+		}
+	}
+
+	// The intents that are claimed aren't due again until the lease is up.
+	if intents, err = cluster.Due(now, time.Minute, 10); err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 0, len(intents); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	if intents, err = cluster.Due(now.Add(time.Minute), time.Minute, 10); err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 3, len(intents); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemoryDueLimit(t *testing.T) {
+	var (
+		cluster = newMemoryCluster()
+		now     = time.Now()
+	)
+
+	for i := 0; i < 3; i++ {
+		if err := cluster.Write(Intent{Id: bs.Key(fmt.Sprintf("%d", i))}, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	intents, err := cluster.Due(now, time.Minute, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 2, len(intents); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemoryRemove(t *testing.T) {
+	var (
+		cluster = newMemoryCluster()
+		now     = time.Now()
+	)
+
+	if err := cluster.Write(Intent{Id: bs.Key("a")}, now); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []bool{true, false} {
+		ok, err := cluster.Remove(bs.Key("a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := ok; expected != actual {
+			t.Errorf("Expected: %v, Actual: %v", expected, actual)
+		}
+	}
+
+	intents, err := cluster.Due(now, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 0, len(intents); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
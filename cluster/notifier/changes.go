@@ -0,0 +1,183 @@
+package notifier
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	s "github.com/SimonRichardson/echelon/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+const (
+	changesPrefix    = "c:"
+	changesSeparator = ":"
+)
+
+func record(conn redis.Conn, key bs.Key, values []s.Change, maxSize int) error {
+	name := changesKey(key)
+	for _, v := range values {
+		if err := conn.Send("ZADD", name, formatScore(v.Score), changeMember(v)); err != nil {
+			return err
+		}
+	}
+
+	// Only the latest changes are kept, so trim the oldest ones.
+	if err := conn.Send("ZREMRANGEBYRANK", name, 0, -(maxSize + 1)); err != nil {
+		return err
+	}
+
+	if err := conn.Flush(); err != nil {
+		return err
+	}
+
+	if !defaultVerifyResults {
+		return nil
+	}
+
+	for i := 0; i < len(values)+1; i++ {
+		if _, err := conn.Receive(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func changes(conn redis.Conn, key bs.Key, since float64, limit int) ([]s.Change, error) {
+	values, err := redis.Strings(conn.Do("ZRANGEBYSCORE", changesKey(key),
+		"("+formatScore(since), "+inf",
+		"LIMIT", 0, limit,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	// The changes that share the score of the last change aren't split, so
+	// that a read that resumes from that score doesn't lose any of them.
+	if num := len(values); num > 0 && num >= limit {
+		last, err := readChangeMember(key, values[num-1])
+		if err != nil {
+			return nil, err
+		}
+
+		ties, err := redis.Strings(conn.Do("ZRANGEBYSCORE", changesKey(key),
+			formatScore(last.Score), formatScore(last.Score),
+		))
+		if err != nil {
+			return nil, err
+		}
+
+		read := make(map[string]struct{}, num)
+		for _, v := range values {
+			read[v] = struct{}{}
+		}
+		for _, v := range ties {
+			if _, ok := read[v]; !ok {
+				values = append(values, v)
+			}
+		}
+	}
+
+	result := make([]s.Change, 0, len(values))
+	for _, v := range values {
+		change, err := readChangeMember(key, v)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+func changesKey(key bs.Key) string {
+	return changesPrefix + key.String()
+}
+
+// changeMember encodes a change as its parts, each prefixed by its length, as
+// the field and the transaction can hold any character, including the
+// separator.
+func changeMember(change s.Change) string {
+	parts := []string{
+		change.Type.String(),
+		change.Field.String(),
+		change.Txn.String(),
+		formatScore(change.Score),
+	}
+	// The state is appended, so that the changes recorded before it existed
+	// can still be read.
+	if change.State != "" {
+		parts = append(parts, change.State)
+	}
+
+	var member []byte
+	for _, v := range parts {
+		member = strconv.AppendInt(member, int64(len(v)), 10)
+		member = append(member, changesSeparator...)
+		member = append(member, v...)
+	}
+	return string(member)
+}
+
+func readChangeMember(key bs.Key, member string) (s.Change, error) {
+	parts, ok := splitChangeMember(member)
+	if !ok || (len(parts) != 4 && len(parts) != 5) {
+		return s.Change{}, typex.Errorf(errors.Source, errors.UnexpectedResults,
+			"Invalid change %q", member)
+	}
+
+	score, err := strconv.ParseFloat(parts[3], 64)
+	if err != nil {
+		return s.Change{}, typex.Errorf(errors.Source, errors.UnexpectedResults,
+			"Invalid change score %q", member)
+	}
+
+	var state string
+	if len(parts) == 5 {
+		state = parts[4]
+	}
+
+	return s.Change{
+		Type:  s.ChangeType(parts[0]),
+		Key:   key,
+		Field: bs.Key(parts[1]),
+		Score: score,
+		Txn:   bs.Key(parts[2]),
+		State: state,
+	}, nil
+}
+
+// splitChangeMember splits the length prefixed parts of a change. The changes
+// recorded before the parts were prefixed start with their type rather than a
+// length, so they're split on the separator instead.
+func splitChangeMember(member string) ([]string, bool) {
+	if member == "" || member[0] < '0' || member[0] > '9' {
+		return strings.Split(member, changesSeparator), true
+	}
+
+	parts := []string{}
+	for len(member) > 0 {
+		index := strings.Index(member, changesSeparator)
+		if index < 1 {
+			return nil, false
+		}
+		size, err := strconv.Atoi(member[:index])
+		if err != nil || size < 0 {
+			return nil, false
+		}
+
+		member = member[index+len(changesSeparator):]
+		if size > len(member) {
+			return nil, false
+		}
+		parts = append(parts, member[:size])
+		member = member[size:]
+	}
+	return parts, true
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
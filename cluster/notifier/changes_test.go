@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"testing"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+func TestChangeMemberHoldsTheSeparator(t *testing.T) {
+	for _, expected := range []s.Change{
+		s.Change{Type: s.ChangeInsert, Key: "key", Field: "a:b", Txn: "c:d", Score: 1.5},
+		s.Change{Type: s.ChangeDelete, Key: "key", Field: ":", Txn: "", Score: 2, State: "held:x"},
+		s.Change{Type: s.ChangeInsert, Key: "key", Field: "12:34", Txn: "5", Score: 3},
+	} {
+		actual, err := readChangeMember(bs.Key("key"), changeMember(expected))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected != actual {
+			t.Errorf("Expected: %v, Actual: %v", expected, actual)
+		}
+	}
+}
+
+func TestChangeMemberReadsTheSeparatedFormat(t *testing.T) {
+	actual, err := readChangeMember(bs.Key("key"), "insert:field:txn:1.5:held")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := s.Change{Type: s.ChangeInsert, Key: "key", Field: "field", Txn: "txn", Score: 1.5, State: "held"}
+	if expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestChangeMemberRejectsTruncatedMembers(t *testing.T) {
+	member := changeMember(s.Change{Type: s.ChangeInsert, Field: "field", Txn: "txn", Score: 1})
+	if _, err := readChangeMember(bs.Key("key"), member[:len(member)-1]); err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -1,6 +1,7 @@
 package notifier
 
 import (
+	"context"
 	"sync"
 
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
@@ -20,6 +21,8 @@ const (
 // possible.
 type Cluster interface {
 	t.Notifier
+	t.Streamer
+	t.Changer
 	t.Closer
 }
 
@@ -34,32 +37,99 @@ func New(pool *p.Pool) Cluster {
 	}
 }
 
-func (c *cluster) Publish(channel s.Channel, members []s.KeyFieldScoreSizeExpiry) <-chan t.Element {
+func (c *cluster) Publish(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry) <-chan t.Element {
 	keys, values := s.KeyFieldScoreSizeExpiries(members).Bucketize()
-	return c.common(keys, func(conn redis.Conn, key bs.Key) error {
+	return c.common(ctx, keys, func(conn redis.Conn, key bs.Key) error {
 		return publish(conn, channel, values[key])
 	})
 }
 
-func (c *cluster) Unpublish(channel s.Channel, members []s.KeyFieldScoreSizeExpiry) <-chan t.Element {
+func (c *cluster) Unpublish(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry) <-chan t.Element {
 	keys, values := s.KeyFieldScoreSizeExpiries(members).Bucketize()
-	return c.common(keys, func(conn redis.Conn, key bs.Key) error {
+	return c.common(ctx, keys, func(conn redis.Conn, key bs.Key) error {
 		return unpublish(conn, channel, values[key])
 	})
 }
 
-func (c *cluster) Subscribe(channel s.Channel) <-chan t.Element {
-	return c.subscribe(func(conn redis.Conn) <-chan t.Element {
+func (c *cluster) Subscribe(ctx context.Context, channel s.Channel) <-chan t.Element {
+	return c.subscribe(ctx, func(conn redis.Conn) <-chan t.Element {
 		return subscribe(conn, channel)
 	})
 }
 
+func (c *cluster) Append(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry, maxSize int) <-chan t.Element {
+	keys, values := s.KeyFieldScoreSizeExpiries(members).Bucketize()
+	return c.common(ctx, keys, func(conn redis.Conn, key bs.Key) error {
+		return appendStream(conn, channel, values[key], maxSize)
+	})
+}
+
+func (c *cluster) Consume(ctx context.Context, channel s.Channel, group s.ConsumerGroup) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		// The connection is held on to for as long as the stream is consumed.
+		key := bs.Key(channel.String())
+		if err := with(ctx, c.pool, key, func(conn redis.Conn) error {
+			return consume(ctx, conn, channel, group, out, func(id string) error {
+				return c.ack(channel, group, id)
+			})
+		}); err != nil {
+			t.Send(ctx, out, t.NewErrorElement(key, err))
+		}
+	}()
+	return out
+}
+
+// ack acknowledges a message of the stream on a connection of its own, as the
+// connection that consumes the stream is held on to.
+func (c *cluster) ack(channel s.Channel, group s.ConsumerGroup, id string) error {
+	return c.pool.With(channel.String(), func(conn redis.Conn) error {
+		_, err := conn.Do("XACK", channel.String(), group.Group, id)
+		return err
+	})
+}
+
+func (c *cluster) Rewind(ctx context.Context, channel s.Channel, group s.ConsumerGroup) <-chan t.Element {
+	key := bs.Key(channel.String())
+	return c.common(ctx, []bs.Key{key}, func(conn redis.Conn, key bs.Key) error {
+		return rewind(conn, channel, group)
+	})
+}
+
+func (c *cluster) Record(ctx context.Context, changes []s.Change, maxSize int) <-chan t.Element {
+	keys, values := s.Changes(changes).Bucketize()
+	return c.common(ctx, keys, func(conn redis.Conn, key bs.Key) error {
+		return record(conn, key, values[key], maxSize)
+	})
+}
+
+func (c *cluster) Changes(ctx context.Context, key bs.Key, since float64, limit int) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		var result []s.Change
+		if err := with(ctx, c.pool, key, func(conn redis.Conn) (err error) {
+			result, err = changes(conn, key, since, limit)
+			return
+		}); err != nil {
+			t.Send(ctx, out, t.NewErrorElement(key, err))
+			return
+		}
+
+		t.Send(ctx, out, t.NewChangeElement(key, result))
+	}()
+	return out
+}
+
 func (c *cluster) Close() error {
 	c.pool.Close()
 	return nil
 }
 
-func (c *cluster) common(keys []bs.Key, f func(redis.Conn, bs.Key) error) <-chan t.Element {
+func (c *cluster) common(ctx context.Context, keys []bs.Key, f func(redis.Conn, bs.Key) error) <-chan t.Element {
 	out := make(chan t.Element)
 	go func() {
 
@@ -71,7 +141,7 @@ func (c *cluster) common(keys []bs.Key, f func(redis.Conn, bs.Key) error) <-chan
 				defer wg.Done()
 
 				var elements []t.Element
-				if err := c.pool.With(key.String(), func(conn redis.Conn) (err error) {
+				if err := with(ctx, c.pool, key, func(conn redis.Conn) (err error) {
 					err = f(conn, key)
 					return
 				}); err != nil {
@@ -79,7 +149,9 @@ func (c *cluster) common(keys []bs.Key, f func(redis.Conn, bs.Key) error) <-chan
 				}
 
 				for _, element := range elements {
-					out <- element
+					if !t.Send(ctx, out, element) {
+						return
+					}
 				}
 			}(k, v)
 		}
@@ -94,19 +166,31 @@ func errorElementsFromKeyCount(key bs.Key, err error) []t.Element {
 	return []t.Element{t.NewErrorElement(key, err)}
 }
 
-func (c *cluster) subscribe(f func(redis.Conn) <-chan t.Element) <-chan t.Element {
+func (c *cluster) subscribe(ctx context.Context, f func(redis.Conn) <-chan t.Element) <-chan t.Element {
 	out := make(chan t.Element)
 	go func() {
-		if err := c.pool.With(defaultSubscribeKey, func(conn redis.Conn) error {
+		key := bs.Key(defaultSubscribeKey)
+		if err := with(ctx, c.pool, key, func(conn redis.Conn) error {
 			go func() {
 				for element := range f(conn) {
-					out <- element
+					if !t.Send(ctx, out, element) {
+						return
+					}
 				}
 			}()
 			return nil
 		}); err != nil {
-			out <- t.NewErrorElement(bs.Key(defaultSubscribeKey), err)
+			t.Send(ctx, out, t.NewErrorElement(key, err))
 		}
 	}()
 	return out
 }
+
+// with runs the function with a connection to the host of the key, unless the
+// context is already done.
+func with(ctx context.Context, pool *p.Pool, key bs.Key, fn func(redis.Conn) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return pool.With(key.String(), fn)
+}
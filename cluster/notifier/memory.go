@@ -0,0 +1,407 @@
+package notifier
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/SimonRichardson/echelon/cluster"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+var (
+	memoryMutex = &sync.Mutex{}
+	memories    = map[string]*memoryQueues{}
+)
+
+type memoryQueues struct {
+	mutex       *sync.Mutex
+	cond        *sync.Cond
+	queues      map[s.Channel][]s.KeyFieldScoreSizeExpiry
+	unpublished map[string]time.Time
+	changes     map[bs.Key]map[string]s.Change
+	streams     map[s.Channel]*memoryStream
+}
+
+type memoryEntry struct {
+	id     int64
+	member s.KeyFieldScoreSizeExpiry
+}
+
+type memoryStream struct {
+	next    int64
+	entries []memoryEntry
+	groups  map[string]*memoryGroup
+}
+
+type memoryGroup struct {
+	delivered int64
+	pending   map[string]map[int64]s.KeyFieldScoreSizeExpiry
+}
+
+type memory struct {
+	*memoryQueues
+	closed bool
+}
+
+// NewMemory creates a cluster that is held in memory, but has identical
+// semantics to the redis cluster. Clusters that share the same name also share
+// the same queues, much like pointing at the same redis instance.
+func NewMemory(name string) Cluster {
+	memoryMutex.Lock()
+	defer memoryMutex.Unlock()
+
+	queues, ok := memories[name]
+	if !ok {
+		mutex := &sync.Mutex{}
+		queues = &memoryQueues{
+			mutex:       mutex,
+			cond:        sync.NewCond(mutex),
+			queues:      map[s.Channel][]s.KeyFieldScoreSizeExpiry{},
+			unpublished: map[string]time.Time{},
+			changes:     map[bs.Key]map[string]s.Change{},
+			streams:     map[s.Channel]*memoryStream{},
+		}
+		memories[name] = queues
+	}
+
+	return &memory{
+		memoryQueues: queues,
+	}
+}
+
+func (c *memory) Publish(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry) <-chan t.Element {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.queues[channel] = append(c.queues[channel], members...)
+	c.cond.Broadcast()
+
+	return memoryCommon(ctx)
+}
+
+func (c *memory) Unpublish(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry) <-chan t.Element {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for _, v := range members {
+		c.unpublished[unpublishKey(channel, v.Key, v.Field)] = now.Add(v.Expiry)
+	}
+
+	return memoryCommon(ctx)
+}
+
+func (c *memory) Subscribe(ctx context.Context, channel s.Channel) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+		defer c.wake(ctx)()
+
+		for {
+			member, ok := c.pop(ctx, channel)
+			if !ok {
+				return
+			}
+
+			if !t.Send(ctx, out, t.NewKeyFieldScoreSizeExpiryElement(member)) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func (c *memory) Append(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry, maxSize int) <-chan t.Element {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stream := c.stream(channel)
+	for _, v := range members {
+		stream.next++
+		stream.entries = append(stream.entries, memoryEntry{stream.next, v})
+	}
+	if num := len(stream.entries) - maxSize; num > 0 {
+		stream.entries = stream.entries[num:]
+	}
+	c.cond.Broadcast()
+
+	return memoryCommon(ctx)
+}
+
+func (c *memory) Consume(ctx context.Context, channel s.Channel, group s.ConsumerGroup) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+		defer c.wake(ctx)()
+
+		// Start by replaying the messages that were delivered, but never
+		// acknowledged, before moving on to the new messages.
+		for _, entry := range c.pending(channel, group) {
+			if !c.send(ctx, channel, group, entry, out) {
+				return
+			}
+		}
+
+		for {
+			entry, ok := c.next(ctx, channel, group)
+			if !ok {
+				return
+			}
+
+			if !c.send(ctx, channel, group, entry, out) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func (c *memory) Rewind(ctx context.Context, channel s.Channel, group s.ConsumerGroup) <-chan t.Element {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stream := c.stream(channel)
+	c.group(stream, group).delivered = memoryOffset(stream, group.Offset)
+
+	return memoryCommon(ctx)
+}
+
+func (c *memory) Record(ctx context.Context, changes []s.Change, maxSize int) <-chan t.Element {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, v := range changes {
+		values, ok := c.changes[v.Key]
+		if !ok {
+			values = map[string]s.Change{}
+			c.changes[v.Key] = values
+		}
+		values[changeMember(v)] = v
+
+		// Only the latest changes are kept, so trim the oldest ones.
+		if num := len(values) - maxSize; num > 0 {
+			for _, change := range sortedChanges(values)[:num] {
+				delete(values, changeMember(change))
+			}
+		}
+	}
+
+	return memoryCommon(ctx)
+}
+
+func (c *memory) Changes(ctx context.Context, key bs.Key, since float64, limit int) <-chan t.Element {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make(chan t.Element, 1)
+	defer close(out)
+
+	if err := ctx.Err(); err != nil {
+		out <- t.NewErrorElement(key, err)
+		return out
+	}
+
+	result := []s.Change{}
+	for _, v := range sortedChanges(c.changes[key]) {
+		// The changes that share the score of the last change aren't split.
+		if num := len(result); num >= limit && v.Score != result[num-1].Score {
+			break
+		}
+		if v.Score > since {
+			result = append(result, v)
+		}
+	}
+
+	out <- t.NewChangeElement(key, result)
+	return out
+}
+
+func (c *memory) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.closed = true
+	c.cond.Broadcast()
+
+	return nil
+}
+
+// pop blocks until a member is published on the channel, skipping any members
+// that have been unpublished in the mean time.
+func (c *memory) pop(ctx context.Context, channel s.Channel) (s.KeyFieldScoreSizeExpiry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for {
+		for len(c.queues[channel]) < 1 && !c.closed && ctx.Err() == nil {
+			c.cond.Wait()
+		}
+		if c.closed || ctx.Err() != nil {
+			return s.KeyFieldScoreSizeExpiry{}, false
+		}
+
+		member := c.queues[channel][0]
+		c.queues[channel] = c.queues[channel][1:]
+
+		key := unpublishKey(channel, member.Key, member.Field)
+		if deadline, ok := c.unpublished[key]; ok {
+			delete(c.unpublished, key)
+			if deadline.After(time.Now()) {
+				continue
+			}
+		}
+
+		return member, true
+	}
+}
+
+func memoryCommon(ctx context.Context) <-chan t.Element {
+	out := make(chan t.Element, 1)
+	if err := ctx.Err(); err != nil {
+		out <- t.NewErrorElement(bs.Key(""), err)
+	}
+	close(out)
+	return out
+}
+
+func sortedChanges(values map[string]s.Change) []s.Change {
+	result := make([]s.Change, 0, len(values))
+	for _, v := range values {
+		result = append(result, v)
+	}
+	sort.Sort(s.Changes(result))
+	return result
+}
+
+func (c *memory) stream(channel s.Channel) *memoryStream {
+	stream, ok := c.streams[channel]
+	if !ok {
+		stream = &memoryStream{
+			groups: map[string]*memoryGroup{},
+		}
+		c.streams[channel] = stream
+	}
+	return stream
+}
+
+func (c *memory) group(stream *memoryStream, group s.ConsumerGroup) *memoryGroup {
+	g, ok := stream.groups[group.Group]
+	if !ok {
+		g = &memoryGroup{
+			delivered: memoryOffset(stream, group.Offset),
+			pending:   map[string]map[int64]s.KeyFieldScoreSizeExpiry{},
+		}
+		stream.groups[group.Group] = g
+	}
+	if _, ok := g.pending[group.Consumer]; !ok {
+		g.pending[group.Consumer] = map[int64]s.KeyFieldScoreSizeExpiry{}
+	}
+	return g
+}
+
+func (c *memory) pending(channel s.Channel, group s.ConsumerGroup) []memoryEntry {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	pending := c.group(c.stream(channel), group).pending[group.Consumer]
+
+	result := make([]memoryEntry, 0, len(pending))
+	for id, member := range pending {
+		result = append(result, memoryEntry{id, member})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].id < result[j].id })
+	return result
+}
+
+// next blocks until there is a message that hasn't been delivered to the group,
+// which is then marked as pending for the consumer.
+func (c *memory) next(ctx context.Context, channel s.Channel, group s.ConsumerGroup) (memoryEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for {
+		if c.closed || ctx.Err() != nil {
+			return memoryEntry{}, false
+		}
+
+		var (
+			stream = c.stream(channel)
+			g      = c.group(stream, group)
+		)
+		for _, entry := range stream.entries {
+			if entry.id > g.delivered {
+				g.delivered = entry.id
+				g.pending[group.Consumer][entry.id] = entry.member
+				return entry, true
+			}
+		}
+
+		c.cond.Wait()
+	}
+}
+
+func (c *memory) ack(channel s.Channel, group s.ConsumerGroup, id int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.group(c.stream(channel), group).pending[group.Consumer], id)
+}
+
+// send hands over the entry, unless it has been unpublished in the mean time.
+// The entry is only acknowledged once the subscriber has handled it. It returns
+// false if the context is done before the entry is handed over.
+func (c *memory) send(ctx context.Context, channel s.Channel, group s.ConsumerGroup, entry memoryEntry, out chan<- t.Element) bool {
+	c.mutex.Lock()
+	deadline, ok := c.unpublished[unpublishKey(channel, entry.member.Key, entry.member.Field)]
+	c.mutex.Unlock()
+
+	if ok && deadline.After(time.Now()) {
+		c.ack(channel, group, entry.id)
+		return true
+	}
+
+	return t.Send(ctx, out, t.NewAckKeyFieldScoreSizeExpiryElement(entry.member, func() error {
+		c.ack(channel, group, entry.id)
+		return nil
+	}))
+}
+
+// wake wakes up anything waiting on the queues once the context is done, so
+// that blocked subscribers and consumers can return. The returned function
+// stops watching the context.
+func (c *memory) wake(ctx context.Context) func() {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.mutex.Lock()
+			c.cond.Broadcast()
+			c.mutex.Unlock()
+		case <-stop:
+		}
+	}()
+	return func() { close(stop) }
+}
+
+// memoryOffset mirrors the offsets of a redis stream, "$" is the last message
+// of the stream and "0" is the start of the stream.
+func memoryOffset(stream *memoryStream, offset string) int64 {
+	if offset == "$" {
+		return stream.next
+	}
+
+	id, err := strconv.ParseInt(strings.SplitN(offset, "-", 2)[0], 10, 64)
+	if err != nil {
+		return stream.next
+	}
+	return id
+}
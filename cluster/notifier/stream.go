@@ -0,0 +1,420 @@
+package notifier
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	t "github.com/SimonRichardson/echelon/cluster"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/pool"
+	"github.com/SimonRichardson/echelon/schemas/records"
+	s "github.com/SimonRichardson/echelon/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+const (
+	streamField   = "m"
+	streamIdField = "id"
+
+	// streamDeadSuffix is appended to the channel to name the stream that the
+	// entries that can't be delivered are moved on to.
+	streamDeadSuffix = ":dead"
+
+	// streamPending is the id used to read the messages that have been
+	// delivered to the consumer, but never acknowledged.
+	streamPending = "0"
+	// streamNew is the id used to read messages that have never been delivered
+	// to any consumer with in the group.
+	streamNew = ">"
+
+	defaultStreamCount = 100
+	defaultStreamBlock = 1000
+
+	// defaultStreamClaimIdle is how long an entry is pending, without being
+	// acknowledged, before it's claimed and delivered again.
+	defaultStreamClaimIdle = time.Second * 30
+	// defaultStreamMaxDeliveries is how many times an entry is delivered before
+	// it's dead lettered.
+	defaultStreamMaxDeliveries = 5
+	defaultStreamDeadSize      = 10000
+)
+
+type streamEntry struct {
+	id    string
+	value []byte
+}
+
+func appendStream(conn redis.Conn, channel s.Channel, values []s.KeyFieldScoreSizeExpiry, maxSize int) error {
+	fb := pool.Get()
+	defer pool.Put(fb)
+
+	kfs := records.KeyFieldScoreSizeExpiry{}
+
+	for _, v := range values {
+		fb.Reset()
+
+		kfs.Key = v.Key
+		kfs.Field = v.Field
+		kfs.Score = v.Score
+		kfs.Size = v.Size
+		kfs.Expiry = v.Expiry
+
+		bytes, err := kfs.Write(fb)
+		if err != nil {
+			return err
+		}
+
+		if err := conn.Send("XADD", channel.String(),
+			"MAXLEN", "~", maxSize,
+			"*", streamField, bytes,
+		); err != nil {
+			return err
+		}
+	}
+
+	if err := conn.Flush(); err != nil {
+		return err
+	}
+
+	if !defaultVerifyResults {
+		return nil
+	}
+
+	for range values {
+		if _, err := conn.Receive(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func consume(ctx context.Context, conn redis.Conn, channel s.Channel, group s.ConsumerGroup, out chan<- t.Element, ack func(string) error) error {
+	if err := createGroup(conn, channel, group); err != nil {
+		return err
+	}
+
+	// Start by replaying the messages that were delivered, but never
+	// acknowledged, before moving on to the new messages.
+	var (
+		id      = streamPending
+		claimed = time.Now()
+	)
+	for {
+		// The read blocks for a while at most, so the context is checked
+		// between reads.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Deliveries that were never acknowledged (the subscriber failed to
+		// handle them or went away) are claimed every so often, so that
+		// they're retried.
+		if id == streamNew && time.Since(claimed) > defaultStreamClaimIdle {
+			if err := reclaim(ctx, conn, channel, group, out, ack); err != nil {
+				return err
+			}
+			claimed = time.Now()
+		}
+
+		reply, err := conn.Do("XREADGROUP",
+			"GROUP", group.Group, group.Consumer,
+			"COUNT", defaultStreamCount,
+			"BLOCK", defaultStreamBlock,
+			"STREAMS", channel.String(), id,
+		)
+		if err != nil {
+			return err
+		}
+
+		entries, err := readStreamEntries(reply)
+		if err != nil {
+			return err
+		}
+
+		if id != streamNew {
+			if len(entries) < 1 {
+				id = streamNew
+				continue
+			}
+			// Carry on replaying after the last entry that was read, the
+			// entries stay pending until they're acknowledged, so reading
+			// from the start again would send them twice.
+			id = entries[len(entries)-1].id
+		}
+
+		if err := deliver(ctx, conn, channel, group, entries, out, ack); err != nil {
+			return err
+		}
+	}
+}
+
+// deliver sends the entries to the subscriber. An entry is only acknowledged
+// once the subscriber has handled it, otherwise it stays pending until it's
+// reclaimed.
+func deliver(ctx context.Context,
+	conn redis.Conn,
+	channel s.Channel,
+	group s.ConsumerGroup,
+	entries []streamEntry,
+	out chan<- t.Element,
+	ack func(string) error,
+) error {
+	for _, entry := range entries {
+		// Entries that have been trimmed, but are still pending have no
+		// value, so there is nothing to send.
+		if len(entry.value) < 1 {
+			if _, err := conn.Do("XACK", channel.String(), group.Group, entry.id); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// An entry that can't be read will never be handled, so it's moved
+		// out of the way rather than stopping the consumer.
+		kfs := &records.KeyFieldScoreSizeExpiry{}
+		if err := kfs.Read(entry.value); err != nil {
+			if err := deadLetter(conn, channel, group, entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// If a value has been unpublished, then don't send it, but still
+		// acknowledge it so it's not replayed.
+		key := unpublishKey(channel, kfs.Key, kfs.Field)
+		if val, err := redis.Bool(conn.Do("GET", key)); err == nil && val {
+			if _, err := conn.Do("XACK", channel.String(), group.Group, entry.id); err != nil {
+				return err
+			}
+			continue
+		}
+
+		id := entry.id
+		if !t.Send(ctx, out, t.NewAckKeyFieldScoreSizeExpiryElement(s.KeyFieldScoreSizeExpiry{
+			Key:    kfs.Key,
+			Field:  kfs.Field,
+			Score:  kfs.Score,
+			Size:   kfs.Size,
+			Expiry: kfs.Expiry,
+		}, func() error {
+			return ack(id)
+		})) {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// reclaim claims the entries of the group that have been pending for longer
+// than the claim idle time and delivers them again. An entry that has already
+// been delivered the max number of times is dead lettered instead.
+func reclaim(ctx context.Context,
+	conn redis.Conn,
+	channel s.Channel,
+	group s.ConsumerGroup,
+	out chan<- t.Element,
+	ack func(string) error,
+) error {
+	pending, err := readStreamPending(conn.Do("XPENDING", channel.String(), group.Group,
+		"-", "+", defaultStreamCount,
+	))
+	if err != nil {
+		return err
+	}
+
+	idle := int64(defaultStreamClaimIdle / time.Millisecond)
+
+	args := []interface{}{channel.String(), group.Group, group.Consumer, idle}
+	for _, v := range pending {
+		if v.idle < idle {
+			continue
+		}
+
+		if v.deliveries >= defaultStreamMaxDeliveries {
+			entries, err := readEntries(conn.Do("XRANGE", channel.String(), v.id, v.id))
+			if err != nil {
+				return err
+			}
+			entry := streamEntry{id: v.id}
+			if len(entries) > 0 {
+				entry = entries[0]
+			}
+			if err := deadLetter(conn, channel, group, entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		args = append(args, v.id)
+	}
+
+	if len(args) < 5 {
+		return nil
+	}
+
+	entries, err := readEntries(conn.Do("XCLAIM", args...))
+	if err != nil {
+		return err
+	}
+	return deliver(ctx, conn, channel, group, entries, out, ack)
+}
+
+// deadLetter moves an entry that can't be delivered on to the dead letter
+// stream of the channel and acknowledges it, so that it's no longer replayed.
+func deadLetter(conn redis.Conn, channel s.Channel, group s.ConsumerGroup, entry streamEntry) error {
+	if len(entry.value) > 0 {
+		if _, err := conn.Do("XADD", channel.String()+streamDeadSuffix,
+			"MAXLEN", "~", defaultStreamDeadSize,
+			"*", streamField, entry.value, streamIdField, entry.id,
+		); err != nil {
+			return err
+		}
+	}
+
+	_, err := conn.Do("XACK", channel.String(), group.Group, entry.id)
+	return err
+}
+
+func rewind(conn redis.Conn, channel s.Channel, group s.ConsumerGroup) error {
+	if err := createGroup(conn, channel, group); err != nil {
+		return err
+	}
+
+	_, err := conn.Do("XGROUP", "SETID", channel.String(), group.Group, group.Offset)
+	return err
+}
+
+func createGroup(conn redis.Conn, channel s.Channel, group s.ConsumerGroup) error {
+	_, err := conn.Do("XGROUP", "CREATE", channel.String(), group.Group, group.Offset, "MKSTREAM")
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// readStreamEntries reads the reply of a XREADGROUP for a single stream, which
+// is in the form of [[stream, [[id, [field, value]], ...]]].
+func readStreamEntries(reply interface{}) ([]streamEntry, error) {
+	if reply == nil {
+		return nil, nil
+	}
+
+	streams, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []streamEntry
+	for _, v := range streams {
+		stream, err := redis.Values(v, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(stream) != 2 {
+			return nil, typex.Errorf(errors.Source, errors.UnexpectedResults,
+				"Invalid stream reply")
+		}
+
+		entries, err := readEntries(stream[1], nil)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, entries...)
+	}
+	return result, nil
+}
+
+// readEntries reads the entries of a stream, which are in the form of
+// [[id, [field, value]], ...]. Entries that have been deleted are nil in the
+// reply of a XCLAIM, so they're skipped.
+func readEntries(reply interface{}, err error) ([]streamEntry, error) {
+	entries, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []streamEntry
+	for _, e := range entries {
+		if e == nil {
+			continue
+		}
+
+		entry, err := redis.Values(e, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(entry) != 2 {
+			return nil, typex.Errorf(errors.Source, errors.UnexpectedResults,
+				"Invalid stream entry")
+		}
+
+		id, err := redis.String(entry[0], nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// Entries that have been trimmed, but are still pending have no
+		// fields.
+		var value []byte
+		if entry[1] != nil {
+			fields, err := redis.ByteSlices(entry[1], nil)
+			if err != nil {
+				return nil, err
+			}
+			for i := 0; i+1 < len(fields); i += 2 {
+				if string(fields[i]) == streamField {
+					value = fields[i+1]
+				}
+			}
+		}
+
+		result = append(result, streamEntry{id, value})
+	}
+	return result, nil
+}
+
+type streamPendingEntry struct {
+	id         string
+	idle       int64
+	deliveries int64
+}
+
+// readStreamPending reads the reply of a XPENDING with a range, which is in the
+// form of [[id, consumer, idle, deliveries], ...].
+func readStreamPending(reply interface{}, err error) ([]streamPendingEntry, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]streamPendingEntry, 0, len(values))
+	for _, v := range values {
+		pending, err := redis.Values(v, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(pending) != 4 {
+			return nil, typex.Errorf(errors.Source, errors.UnexpectedResults,
+				"Invalid pending entry")
+		}
+
+		id, err := redis.String(pending[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		idle, err := redis.Int64(pending[2], nil)
+		if err != nil {
+			return nil, err
+		}
+		deliveries, err := redis.Int64(pending[3], nil)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, streamPendingEntry{id, idle, deliveries})
+	}
+	return result, nil
+}
@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -40,27 +41,27 @@ func New(pool *p.Pool,
 	}
 }
 
-func (c *cluster) Insert(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+func (c *cluster) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
 	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
-	return c.countCommon(keys, func(db p.Database, key bs.Key) ([]s.KeyCount, error) {
+	return c.countCommon(ctx, keys, func(db p.Database, key bs.Key) ([]s.KeyCount, error) {
 		return insertion(db, c.transformer, values[key])
 	})
 }
 
-func (c *cluster) Delete(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+func (c *cluster) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
 	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
-	return c.countCommon(keys, func(db p.Database, key bs.Key) ([]s.KeyCount, error) {
+	return c.countCommon(ctx, keys, func(db p.Database, key bs.Key) ([]s.KeyCount, error) {
 		return deletion(db, c.transformer, values[key])
 	})
 }
 
-func (c *cluster) Rollback(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
-	return c.Delete(members, sizeExpiry)
+func (c *cluster) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	return c.Delete(ctx, members, sizeExpiry)
 }
 
-func (c *cluster) Repair(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+func (c *cluster) Repair(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
 	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
-	return c.countCommon(keys, func(db p.Database, key bs.Key) ([]s.KeyCount, error) {
+	return c.countCommon(ctx, keys, func(db p.Database, key bs.Key) ([]s.KeyCount, error) {
 		return repair(db, c.transformer, values[key])
 	})
 }
@@ -70,7 +71,7 @@ func (c *cluster) Close() error {
 	return nil
 }
 
-func (c *cluster) countCommon(keys []bs.Key, f func(p.Database, bs.Key) ([]s.KeyCount, error)) <-chan t.Element {
+func (c *cluster) countCommon(ctx context.Context, keys []bs.Key, f func(p.Database, bs.Key) ([]s.KeyCount, error)) <-chan t.Element {
 	out := make(chan t.Element)
 	go func() {
 
@@ -85,7 +86,7 @@ func (c *cluster) countCommon(keys []bs.Key, f func(p.Database, bs.Key) ([]s.Key
 					elements []t.Element
 					result   = []s.KeyCount{s.KeyCount{Key: key, Count: 0}}
 				)
-				if err := c.pool.With(key.String(), func(sess p.Session) (err error) {
+				if err := c.with(ctx, key, func(sess p.Session) (err error) {
 					result, err = f(c.database(sess), key)
 					return
 				}); err != nil {
@@ -95,7 +96,9 @@ func (c *cluster) countCommon(keys []bs.Key, f func(p.Database, bs.Key) ([]s.Key
 				}
 
 				for _, element := range elements {
-					out <- element
+					if !t.Send(ctx, out, element) {
+						return
+					}
 				}
 			}(k, v)
 		}
@@ -106,6 +109,15 @@ func (c *cluster) countCommon(keys []bs.Key, f func(p.Database, bs.Key) ([]s.Key
 	return out
 }
 
+// with runs the function with a session for the key, unless the context is
+// already done.
+func (c *cluster) with(ctx context.Context, key bs.Key, fn func(p.Session) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.pool.With(key.String(), fn)
+}
+
 func (c *cluster) database(sess p.Session) p.Database {
 	return sess.DB(c.dbName)
 }
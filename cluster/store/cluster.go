@@ -1,10 +1,14 @@
 package store
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	t "github.com/SimonRichardson/echelon/cluster"
+	"github.com/SimonRichardson/echelon/internal/fusion"
+	"github.com/SimonRichardson/echelon/internal/merkle"
 	p "github.com/SimonRichardson/echelon/internal/redis"
 	s "github.com/SimonRichardson/echelon/selectors"
 	"github.com/garyburd/redigo/redis"
@@ -25,10 +29,14 @@ const (
 // possible.
 type Cluster interface {
 	t.Inserter
+	t.Swapper
 	t.Deleter
 	t.Scanner
 	t.Selector
 	t.Scorer
+	t.Summarizer
+	t.Indexer
+	t.Scheduler
 	t.Closer
 }
 
@@ -43,50 +51,61 @@ func New(pool *p.Pool) Cluster {
 	}
 }
 
-func (c *cluster) Insert(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+// Routing returns the strategy that routes a key to a host of the cluster.
+func (c *cluster) Routing() fusion.SelectionStrategy {
+	return c.pool.Routing()
+}
+
+func (c *cluster) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
 	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
-	return c.countCommon(keys, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
-		return insertion(conn, values[key], sizeExpiry[key])
+	return c.countCommon(ctx, keys, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
+		return insertion(conn, sendInsertScript, values[key], sizeExpiry[key])
+	})
+}
+
+func (c *cluster) Swap(ctx context.Context, member s.KeyFieldScoreTxnValue, version float64, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	return c.countCommon(ctx, []bs.Key{member.Key}, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
+		return swapping(conn, member, version, sizeExpiry[key])
 	})
 }
 
-func (c *cluster) Delete(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+func (c *cluster) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
 	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
-	return c.countCommon(keys, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
-		return deletion(conn, values[key], sizeExpiry[key])
+	return c.countCommon(ctx, keys, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
+		return deletion(conn, sendDeleteScript, values[key], sizeExpiry[key])
 	})
 }
 
-func (c *cluster) Rollback(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
-	return c.Delete(members, sizeExpiry)
+func (c *cluster) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	return c.Delete(ctx, members, sizeExpiry)
 }
 
-func (c *cluster) Select(key bs.Key, field bs.Key) <-chan t.Element {
-	return c.selectCommon(key, func(conn redis.Conn, key bs.Key) ([]s.KeyFieldScoreTxnValue, error) {
+func (c *cluster) Select(ctx context.Context, key bs.Key, field bs.Key) <-chan t.Element {
+	return c.selectCommon(ctx, key, func(conn redis.Conn, key bs.Key) ([]s.KeyFieldScoreTxnValue, error) {
 		return wrapSelection(selection(conn, key, field))
 	})
 }
 
-func (c *cluster) SelectRange(key bs.Key, limit int, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
-	return c.selectCommon(key, func(conn redis.Conn, key bs.Key) ([]s.KeyFieldScoreTxnValue, error) {
+func (c *cluster) SelectRange(ctx context.Context, key bs.Key, limit int, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	return c.selectCommon(ctx, key, func(conn redis.Conn, key bs.Key) ([]s.KeyFieldScoreTxnValue, error) {
 		return selectionWithRange(conn, key, limit, sizeExpiry[key])
 	})
 }
 
-func (c *cluster) Size(key bs.Key) <-chan t.Element {
-	return c.countCommon([]bs.Key{key}, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
+func (c *cluster) Size(ctx context.Context, key bs.Key) <-chan t.Element {
+	return c.countCommon(ctx, []bs.Key{key}, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
 		return cardinality(conn, key)
 	})
 }
 
-func (c *cluster) Keys() <-chan t.Element {
-	return c.keyCommon(bs.Key(defaultKeysKey), func(conn redis.Conn) ([]bs.Key, error) {
+func (c *cluster) Keys(ctx context.Context) <-chan t.Element {
+	return c.keyCommon(ctx, bs.Key(defaultKeysKey), func(conn redis.Conn) ([]bs.Key, error) {
 		return keys(conn, defaultBatchSize)
 	})
 }
 
-func (c *cluster) Members(key bs.Key) <-chan t.Element {
-	return c.keyCommon(key, func(conn redis.Conn) ([]bs.Key, error) {
+func (c *cluster) Members(ctx context.Context, key bs.Key) <-chan t.Element {
+	return c.keyCommon(ctx, key, func(conn redis.Conn) ([]bs.Key, error) {
 		return members(conn, key)
 	})
 }
@@ -97,12 +116,76 @@ func (c *cluster) Score(members []s.KeyFieldTxnValue) (map[s.KeyFieldTxnValue]s.
 	})
 }
 
+func (c *cluster) Summary(key bs.Key, buckets int) (res merkle.Tree, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		res, err = summary(conn, key, buckets)
+		return err
+	})
+	return
+}
+
+func (c *cluster) Divergent(key bs.Key, buckets int, indices []int) (res []s.KeyFieldScoreTxnValue, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		res, err = divergent(conn, key, buckets, indices)
+		return err
+	})
+	return
+}
+
+func (c *cluster) Indexed(key bs.Key, index s.Index, value bs.Key) (res []bs.Key, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		res, err = indexed(conn, key, index, value)
+		return err
+	})
+	return
+}
+
+func (c *cluster) Reindex(key bs.Key) (res int, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		res, err = reindex(conn, key)
+		return err
+	})
+	return
+}
+
+// Due reads the members that are due to expire from the schedule of every
+// host of the cluster.
+func (c *cluster) Due(now time.Time, limit int) ([]s.KeyFieldScoreTxnValueExpiry, error) {
+	result := []s.KeyFieldScoreTxnValueExpiry{}
+	for i := 0; i < c.pool.Size(); i++ {
+		if err := c.pool.WithIndex(i, func(conn redis.Conn) error {
+			res, err := due(conn, now, limit)
+			if err != nil {
+				return err
+			}
+			result = append(result, res...)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Postpone moves the members that are still scheduled, on the hosts of their
+// keys, so that they're not due again until then.
+func (c *cluster) Postpone(members []s.KeyField, until time.Time) error {
+	for _, member := range members {
+		if err := c.pool.With(member.Key.String(), func(conn redis.Conn) error {
+			return postpone(conn, member, until)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *cluster) Close() error {
 	c.pool.Close()
 	return nil
 }
 
-func (c *cluster) countCommon(keys []bs.Key, f func(redis.Conn, bs.Key) ([]s.KeyCount, error)) <-chan t.Element {
+func (c *cluster) countCommon(ctx context.Context, keys []bs.Key, f func(redis.Conn, bs.Key) ([]s.KeyCount, error)) <-chan t.Element {
 	out := make(chan t.Element)
 	go func() {
 
@@ -117,7 +200,7 @@ func (c *cluster) countCommon(keys []bs.Key, f func(redis.Conn, bs.Key) ([]s.Key
 					elements []t.Element
 					result   = []s.KeyCount{s.KeyCount{Key: key, Count: 0}}
 				)
-				if err := c.pool.With(key.String(), func(conn redis.Conn) (err error) {
+				if err := with(ctx, c.pool, key, func(conn redis.Conn) (err error) {
 					result, err = f(conn, key)
 					return
 				}); err != nil {
@@ -127,7 +210,9 @@ func (c *cluster) countCommon(keys []bs.Key, f func(redis.Conn, bs.Key) ([]s.Key
 				}
 
 				for _, element := range elements {
-					out <- element
+					if !t.Send(ctx, out, element) {
+						return
+					}
 				}
 			}(k, v)
 		}
@@ -164,7 +249,7 @@ func successElementsFromKeyCount(keys []s.KeyCount) []t.Element {
 	return elements
 }
 
-func (c *cluster) keyCommon(key bs.Key,
+func (c *cluster) keyCommon(ctx context.Context, key bs.Key,
 	f func(redis.Conn) ([]bs.Key, error),
 ) <-chan t.Element {
 	out := make(chan t.Element)
@@ -177,13 +262,13 @@ func (c *cluster) keyCommon(key bs.Key,
 			defer wg.Done()
 
 			var result []bs.Key
-			if err := c.pool.With(key.String(), func(conn redis.Conn) (err error) {
+			if err := with(ctx, c.pool, key, func(conn redis.Conn) (err error) {
 				result, err = f(conn)
 				return
 			}); err != nil {
-				out <- t.NewErrorElement(key, err)
+				t.Send(ctx, out, t.NewErrorElement(key, err))
 			} else {
-				out <- t.NewKeyElement(key, result)
+				t.Send(ctx, out, t.NewKeyElement(key, result))
 			}
 		}()
 
@@ -194,7 +279,7 @@ func (c *cluster) keyCommon(key bs.Key,
 	return out
 }
 
-func (c *cluster) selectCommon(key bs.Key,
+func (c *cluster) selectCommon(ctx context.Context, key bs.Key,
 	f func(redis.Conn, bs.Key) ([]s.KeyFieldScoreTxnValue, error),
 ) <-chan t.Element {
 	out := make(chan t.Element)
@@ -210,7 +295,7 @@ func (c *cluster) selectCommon(key bs.Key,
 				elements []t.Element
 				result   = []s.KeyFieldScoreTxnValue{s.KeyFieldScoreTxnValue{Key: key}}
 			)
-			if err := c.pool.With(key.String(), func(conn redis.Conn) (err error) {
+			if err := with(ctx, c.pool, key, func(conn redis.Conn) (err error) {
 				result, err = f(conn, key)
 				return
 			}); err != nil {
@@ -220,7 +305,9 @@ func (c *cluster) selectCommon(key bs.Key,
 			}
 
 			for _, element := range elements {
-				out <- element
+				if !t.Send(ctx, out, element) {
+					return
+				}
 			}
 		}()
 
@@ -294,6 +381,16 @@ func (c *cluster) scoreCommon(members []s.KeyFieldTxnValue,
 	return presenceMap, nil
 }
 
+// with runs the function with a connection to the host of the key, unless the
+// context is already done. A request that's already been sent to redis can't
+// be cancelled, but nothing else is sent once the context is done.
+func with(ctx context.Context, pool *p.Pool, key bs.Key, fn func(redis.Conn) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return pool.With(key.String(), fn)
+}
+
 func wrapSelection(sel s.KeyFieldScoreTxnValue, err error) ([]s.KeyFieldScoreTxnValue, error) {
 	if err != nil {
 		return nil, err
@@ -8,14 +8,14 @@ import (
 	"github.com/garyburd/redigo/redis"
 )
 
-func deletion(conn redis.Conn, members []s.KeyFieldScoreTxnValue, sizeExpiry s.SizeExpiry) ([]s.KeyCount, error) {
+func deletion(conn redis.Conn, send sendScript, members []s.KeyFieldScoreTxnValue, sizeExpiry s.SizeExpiry) ([]s.KeyCount, error) {
 	var (
 		now    = time.Now()
 		expiry = now.Add(sizeExpiry.Expiry).UnixNano()
 	)
 
 	for _, member := range members {
-		if err := sendDeleteScript(conn,
+		if err := send(conn,
 			member.Key,
 			member.Field,
 			member.Score,
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/records"
+	"github.com/SimonRichardson/echelon/scripts"
+	s "github.com/SimonRichardson/echelon/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+// ErrNotIndexed defines an error where the cluster doesn't maintain the
+// secondary indexes, so the members have to be read instead.
+var (
+	ErrNotIndexed = typex.Errorf(errors.Source, errors.NoCaseFound, "Not Indexed")
+)
+
+var (
+	reindexScript *redis.Script
+)
+
+func init() {
+	raw, err := scripts.Asset("../scripts/store/reindex.lua")
+	if err != nil {
+		typex.Fatal(err)
+	}
+
+	reindexScript = redis.NewScript(1, strings.NewReplacer(
+		"SEPARATOR", separator,
+		"INSERTSUFFIX", insertSuffix,
+		"INDEXSUFFIX", indexSuffix,
+		"OWNERSUFFIX", ownerSuffix,
+		"TXNSUFFIX", txnSuffix,
+	).Replace(string(raw)))
+}
+
+func indexed(conn redis.Conn, key bs.Key, index s.Index, value bs.Key) ([]bs.Key, error) {
+	name, err := indexKey(key, index, value)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := redis.Strings(conn.Do("SMEMBERS", name))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]bs.Key, 0, len(fields))
+	for _, v := range fields {
+		res = append(res, bs.Key(v))
+	}
+	return res, nil
+}
+
+// reindex compares the insertions of a key with what they've been indexed
+// under and rebuilds the index of any field that is missing or stale. It
+// returns the number of fields that were rebuilt.
+func reindex(conn redis.Conn, key bs.Key) (int, error) {
+	inserts, err := redis.StringMap(conn.Do("HGETALL", prefix+key+insertSuffix))
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := redis.StringMap(conn.Do("HGETALL", prefix+key+indexSuffix))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for field, value := range inserts {
+		_, txn, _, v, err := ExtractScoreTxnExpiryValue(value)
+		if err != nil {
+			continue
+		}
+
+		owner := ownerOf(v)
+		if entries[field] == owner+separator+txn {
+			continue
+		}
+
+		if err := reindexScript.Send(conn, prefix+key.String(), field, owner); err != nil {
+			return 0, err
+		}
+		sent++
+	}
+
+	// Remove the fields that are still indexed, but no longer inserted.
+	for field := range entries {
+		if _, ok := inserts[field]; ok {
+			continue
+		}
+
+		if err := reindexScript.Send(conn, prefix+key.String(), field, ""); err != nil {
+			return 0, err
+		}
+		sent++
+	}
+
+	if sent < 1 {
+		return 0, nil
+	}
+
+	if err := conn.Flush(); err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < sent; i++ {
+		if _, err := redis.Int(conn.Receive()); err != nil {
+			return 0, err
+		}
+	}
+
+	return sent, nil
+}
+
+func indexKey(key bs.Key, index s.Index, value bs.Key) (string, error) {
+	switch index {
+	case s.OwnerIndex:
+		return prefix + key.String() + ownerSuffix + value.String(), nil
+	case s.TransactionIndex:
+		return prefix + key.String() + txnSuffix + value.String(), nil
+	}
+	return "", typex.Errorf(errors.Source, errors.InvalidArgument, "Invalid index %q", index)
+}
+
+// ownerOf reads the owner from the header of a record, a value that isn't a
+// record isn't indexed by its owner.
+func ownerOf(value string) string {
+	ownerId, err := records.ReadOwnerId(value)
+	if err != nil {
+		return ""
+	}
+	return ownerId.String()
+}
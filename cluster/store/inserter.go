@@ -13,14 +13,14 @@ const (
 	defaultFieldInsertion = 1
 )
 
-func insertion(conn redis.Conn, members []s.KeyFieldScoreTxnValue, sizeExpiry s.SizeExpiry) ([]s.KeyCount, error) {
+func insertion(conn redis.Conn, send sendScript, members []s.KeyFieldScoreTxnValue, sizeExpiry s.SizeExpiry) ([]s.KeyCount, error) {
 	var (
 		now    = time.Now()
 		expiry = now.Add(sizeExpiry.Expiry).UnixNano()
 	)
 
 	for _, member := range members {
-		if err := sendInsertScript(conn,
+		if err := send(conn,
 			member.Key,
 			member.Field,
 			member.Score,
@@ -59,3 +59,30 @@ func insertion(conn redis.Conn, members []s.KeyFieldScoreTxnValue, sizeExpiry s.
 
 	return result, nil
 }
+
+// swapping over writes the member, only if the member that's stored is still
+// the version that was read. A member that has since changed isn't an error,
+// it's just not swapped, so that it's not mistaken for a partial insertion
+// that needs repairing.
+func swapping(conn redis.Conn, member s.KeyFieldScoreTxnValue, version float64, sizeExpiry s.SizeExpiry) ([]s.KeyCount, error) {
+	expiry := time.Now().Add(sizeExpiry.Expiry).UnixNano()
+
+	res, err := redis.Int(doSwapScript(conn,
+		member.Key,
+		member.Field,
+		member.Score,
+		expiry,
+		member.Txn,
+		member.Value,
+		version,
+	))
+	if err != nil {
+		return generateResult([]s.KeyFieldScoreTxnValue{member}, 0), err
+	}
+
+	count := 0
+	if res == defaultFieldExists || res == defaultFieldInsertion {
+		count = 1
+	}
+	return []s.KeyCount{s.KeyCount{Key: member.Key, Count: count}}, nil
+}
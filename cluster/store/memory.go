@@ -0,0 +1,524 @@
+package store
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/SimonRichardson/echelon/cluster"
+	"github.com/SimonRichardson/echelon/internal/merkle"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+var (
+	memoryMutex = &sync.Mutex{}
+	memories    = map[string]*memory{}
+)
+
+type memoryValue struct {
+	score     float64
+	txn       string
+	expiry    int64
+	value     string
+	postponed int64
+}
+
+// invalid mirrors the checks of the store script, so that the last write wins.
+func (v memoryValue) invalid(score float64, txn string) bool {
+	return v.txn == "" || score <= v.score || txn != v.txn
+}
+
+func (v memoryValue) version() string {
+	return fmt.Sprintf("%f%s%s", v.score, separator, v.txn)
+}
+
+type memory struct {
+	mutex   *sync.RWMutex
+	inserts map[bs.Key]map[bs.Key]memoryValue
+	deletes map[bs.Key]map[bs.Key]memoryValue
+}
+
+// NewMemory creates a cluster that is held in memory, but has identical
+// semantics to the redis cluster. Clusters that share the same name also share
+// the same storage, much like pointing at the same redis instance.
+func NewMemory(name string) Cluster {
+	memoryMutex.Lock()
+	defer memoryMutex.Unlock()
+
+	if m, ok := memories[name]; ok {
+		return m
+	}
+
+	m := &memory{
+		mutex:   &sync.RWMutex{},
+		inserts: map[bs.Key]map[bs.Key]memoryValue{},
+		deletes: map[bs.Key]map[bs.Key]memoryValue{},
+	}
+	memories[name] = m
+	return m
+}
+
+func (c *memory) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
+	return memoryCountCommon(ctx, keys, func(key bs.Key) ([]s.KeyCount, error) {
+		return c.insertion(values[key], sizeExpiry[key])
+	})
+}
+
+func (c *memory) Swap(ctx context.Context, member s.KeyFieldScoreTxnValue, version float64, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	return memoryCountCommon(ctx, []bs.Key{member.Key}, func(key bs.Key) ([]s.KeyCount, error) {
+		return c.swapping(member, version, sizeExpiry[key])
+	})
+}
+
+func (c *memory) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
+	return memoryCountCommon(ctx, keys, func(key bs.Key) ([]s.KeyCount, error) {
+		return c.deletion(values[key], sizeExpiry[key])
+	})
+}
+
+func (c *memory) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	return c.Delete(ctx, members, sizeExpiry)
+}
+
+func (c *memory) Select(ctx context.Context, key bs.Key, field bs.Key) <-chan t.Element {
+	return memorySelectCommon(ctx, func() ([]s.KeyFieldScoreTxnValue, error) {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+
+		value, ok := c.inserts[key][field]
+		if !ok {
+			return nil, redis.ErrNil
+		}
+		if value.expiry < time.Now().UnixNano() {
+			return nil, ErrExpiredNode
+		}
+		return []s.KeyFieldScoreTxnValue{value.member(key, field)}, nil
+	})
+}
+
+func (c *memory) SelectRange(ctx context.Context, key bs.Key, limit int, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	return memorySelectCommon(ctx, func() ([]s.KeyFieldScoreTxnValue, error) {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+
+		var (
+			now    = time.Now().UnixNano()
+			values = c.inserts[key]
+			result = []s.KeyFieldScoreTxnValue{}
+		)
+		for _, field := range sortedFields(values) {
+			if len(result) >= limit {
+				break
+			}
+			if value := values[field]; value.expiry >= now {
+				result = append(result, value.member(key, field))
+			}
+		}
+		return result, nil
+	})
+}
+
+func (c *memory) Size(ctx context.Context, key bs.Key) <-chan t.Element {
+	return memoryCountCommon(ctx, []bs.Key{key}, func(key bs.Key) ([]s.KeyCount, error) {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+
+		return []s.KeyCount{
+			s.KeyCount{Key: key, Count: len(c.inserts[key])},
+		}, nil
+	})
+}
+
+func (c *memory) Keys(ctx context.Context) <-chan t.Element {
+	return memoryKeyCommon(ctx, bs.Key(defaultKeysKey), func() ([]bs.Key, error) {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+
+		result := make([]bs.Key, 0, len(c.inserts))
+		for key := range c.inserts {
+			result = append(result, key)
+		}
+		return result, nil
+	})
+}
+
+func (c *memory) Members(ctx context.Context, key bs.Key) <-chan t.Element {
+	return memoryKeyCommon(ctx, key, func() ([]bs.Key, error) {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+
+		return sortedFields(c.inserts[key]), nil
+	})
+}
+
+func (c *memory) Score(members []s.KeyFieldTxnValue) (map[s.KeyFieldTxnValue]s.Presence, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var (
+		m   = map[s.KeyFieldTxnValue]s.Presence{}
+		now = time.Now().UnixNano()
+	)
+	for _, member := range members {
+		var (
+			insert, inserted = c.inserts[member.Key][member.Field]
+			delete, deleted  = c.deletes[member.Key][member.Field]
+		)
+
+		switch {
+		case inserted && !deleted:
+			m[member] = s.Presence{
+				Present:  insert.expiry >= now,
+				Inserted: true,
+				Score:    insert.score,
+			}
+		case !inserted && deleted:
+			m[member] = s.Presence{
+				Present:  delete.expiry >= now,
+				Inserted: false,
+				Score:    delete.score,
+			}
+		default:
+			m[member] = s.Presence{
+				Present: false,
+			}
+		}
+	}
+	return m, nil
+}
+
+func (c *memory) Summary(key bs.Key, buckets int) (merkle.Tree, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if buckets < 1 {
+		return merkle.New(nil)
+	}
+
+	entries := make([][]string, buckets)
+	for suffix, values := range map[string]map[bs.Key]memoryValue{
+		insertSuffix: c.inserts[key],
+		deleteSuffix: c.deletes[key],
+	} {
+		for field, value := range values {
+			index := merkle.Bucket(field.String(), buckets)
+			entries[index] = append(entries[index], sha1hex(suffix+field.String()+value.version()))
+		}
+	}
+
+	leaves := make([]string, buckets)
+	for k, v := range entries {
+		if len(v) > 0 {
+			sort.Strings(v)
+			leaves[k] = sha1hex(strings.Join(v, ""))
+		}
+	}
+
+	return merkle.New(leaves)
+}
+
+func (c *memory) Divergent(key bs.Key, buckets int, indices []int) ([]s.KeyFieldScoreTxnValue, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	wanted := map[int]struct{}{}
+	for _, v := range indices {
+		wanted[v] = struct{}{}
+	}
+
+	result := []s.KeyFieldScoreTxnValue{}
+	for _, values := range []map[bs.Key]memoryValue{c.inserts[key], c.deletes[key]} {
+		for _, field := range sortedFields(values) {
+			if _, ok := wanted[merkle.Bucket(field.String(), buckets)]; ok {
+				result = append(result, values[field].member(key, field))
+			}
+		}
+	}
+	return result, nil
+}
+
+// Indexed mirrors the secondary indexes of the store script, which only index
+// the insertions of a key.
+func (c *memory) Indexed(key bs.Key, index s.Index, value bs.Key) ([]bs.Key, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if _, err := indexKey(key, index, value); err != nil {
+		return nil, err
+	}
+
+	values := c.inserts[key]
+
+	result := []bs.Key{}
+	for _, field := range sortedFields(values) {
+		v := values[field]
+		if (index == s.OwnerIndex && ownerOf(v.value) == value.String()) ||
+			(index == s.TransactionIndex && v.txn == value.String()) {
+			result = append(result, field)
+		}
+	}
+	return result, nil
+}
+
+// Reindex is a no-op, as the memory cluster never has missing indexes.
+func (c *memory) Reindex(key bs.Key) (int, error) {
+	return 0, nil
+}
+
+// Due mirrors the schedule of the store script, which holds every insertion
+// until it's deleted.
+func (c *memory) Due(now time.Time, limit int) ([]s.KeyFieldScoreTxnValueExpiry, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	result := []s.KeyFieldScoreTxnValueExpiry{}
+	for key, values := range c.inserts {
+		for field, value := range values {
+			if value.expiry > now.UnixNano() || value.postponed > now.UnixNano() {
+				continue
+			}
+			result = append(result, s.KeyFieldScoreTxnValueExpiry{
+				Key:    key,
+				Field:  field,
+				Score:  value.score,
+				Txn:    bs.Key(value.txn),
+				Value:  value.value,
+				Expiry: time.Unix(0, value.expiry),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Expiry.Before(result[j].Expiry)
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// Postpone mirrors the schedule of the store script, the postponement is
+// forgotten once the member is written again.
+func (c *memory) Postpone(members []s.KeyField, until time.Time) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, member := range members {
+		if value, ok := c.inserts[member.Key][member.Field]; ok {
+			value.postponed = until.UnixNano()
+			c.inserts[member.Key][member.Field] = value
+		}
+	}
+	return nil
+}
+
+func (c *memory) Close() error {
+	return nil
+}
+
+func (c *memory) insertion(members []s.KeyFieldScoreTxnValue, sizeExpiry s.SizeExpiry) ([]s.KeyCount, error) {
+	var (
+		expiry = time.Now().Add(sizeExpiry.Expiry).UnixNano()
+		result = make([]s.KeyCount, 0, len(members))
+	)
+
+	for _, m := range members {
+		res := c.write(c.inserts, c.deletes, m, expiry, "")
+		if res == defaultFieldExists || res == defaultFieldInsertion {
+			result = append(result, s.KeyCount{Key: m.Key, Count: 1})
+		}
+	}
+
+	if len(result) < len(members) {
+		return result, t.ErrPartialInsertions
+	}
+
+	return result, nil
+}
+
+// swapping mirrors the store script, the member is only written if the
+// insertion is still the version that was read.
+func (c *memory) swapping(member s.KeyFieldScoreTxnValue, version float64, sizeExpiry s.SizeExpiry) ([]s.KeyCount, error) {
+	expiry := time.Now().Add(sizeExpiry.Expiry).UnixNano()
+
+	count := 0
+	res := c.write(c.inserts, c.deletes, member, expiry, packageVersion(version))
+	if res == defaultFieldExists || res == defaultFieldInsertion {
+		count = 1
+	}
+	return []s.KeyCount{s.KeyCount{Key: member.Key, Count: count}}, nil
+}
+
+func (c *memory) deletion(members []s.KeyFieldScoreTxnValue, sizeExpiry s.SizeExpiry) ([]s.KeyCount, error) {
+	var (
+		expiry = time.Now().Add(sizeExpiry.Expiry).UnixNano()
+		result = make([]s.KeyCount, 0, len(members))
+	)
+
+	for _, m := range members {
+		res := c.write(c.deletes, c.inserts, m, expiry, "")
+		result = append(result, s.KeyCount{Key: m.Key, Count: abs(res)})
+	}
+
+	return result, nil
+}
+
+// write mirrors the store script, the member is added to the add set after
+// removing it from the rem set, as long as the score is greater than the one
+// already existing in either set. If there is a version, then the insertion
+// also has to still be that version.
+func (c *memory) write(add, rem map[bs.Key]map[bs.Key]memoryValue,
+	member s.KeyFieldScoreTxnValue,
+	expiry int64,
+	version string,
+) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var (
+		key   = member.Key
+		field = member.Field
+		txn   = member.Txn.String()
+	)
+
+	value, ok := c.inserts[key][field]
+	if ok && value.invalid(member.Score, txn) {
+		return -1
+	}
+	if version != "" && (!ok || packageVersion(value.score) != version) {
+		return -1
+	}
+	if value, ok := c.deletes[key][field]; ok && value.invalid(member.Score, txn) {
+		return -1
+	}
+
+	if values, ok := rem[key]; ok {
+		delete(values, field)
+		if len(values) < 1 {
+			delete(rem, key)
+		}
+	}
+
+	values, ok := add[key]
+	if !ok {
+		values = map[bs.Key]memoryValue{}
+		add[key] = values
+	}
+
+	_, exists := values[field]
+	values[field] = memoryValue{
+		score:  member.Score,
+		txn:    txn,
+		expiry: expiry,
+		value:  member.Value,
+	}
+
+	if exists {
+		return defaultFieldExists
+	}
+	return defaultFieldInsertion
+}
+
+func (v memoryValue) member(key, field bs.Key) s.KeyFieldScoreTxnValue {
+	return s.KeyFieldScoreTxnValue{
+		Key:   key,
+		Field: field,
+		Score: v.score,
+		Txn:   bs.Key(v.txn),
+		Value: v.value,
+	}
+}
+
+func memoryCountCommon(ctx context.Context, keys []bs.Key, f func(bs.Key) ([]s.KeyCount, error)) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		for _, key := range keys {
+			var elements []t.Element
+			if err := ctx.Err(); err != nil {
+				elements = []t.Element{t.NewErrorElement(key, err)}
+			} else if result, err := f(key); err != nil {
+				elements = errorElementsFromKeyCount(result, err)
+			} else {
+				elements = successElementsFromKeyCount(result)
+			}
+
+			for _, element := range elements {
+				if !t.Send(ctx, out, element) {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+func memoryKeyCommon(ctx context.Context, key bs.Key, f func() ([]bs.Key, error)) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		if err := ctx.Err(); err != nil {
+			t.Send(ctx, out, t.NewErrorElement(key, err))
+		} else if result, err := f(); err != nil {
+			t.Send(ctx, out, t.NewErrorElement(key, err))
+		} else {
+			t.Send(ctx, out, t.NewKeyElement(key, result))
+		}
+	}()
+	return out
+}
+
+func memorySelectCommon(ctx context.Context, f func() ([]s.KeyFieldScoreTxnValue, error)) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		// Much like the redis cluster, only the members that have been returned
+		// are reported as errors.
+		var elements []t.Element
+		if err := ctx.Err(); err != nil {
+			elements = []t.Element{t.NewErrorElement(bs.Key(""), err)}
+		} else if result, err := f(); err != nil {
+			elements = errorElementsFromKeyFieldScoreTxnValue(result, err)
+		} else {
+			elements = successElementsFromKeyFieldScoreTxnValue(result)
+		}
+
+		for _, element := range elements {
+			if !t.Send(ctx, out, element) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func sortedFields(values map[bs.Key]memoryValue) []bs.Key {
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field.String())
+	}
+	sort.Strings(fields)
+
+	result := make([]bs.Key, 0, len(fields))
+	for _, field := range fields {
+		result = append(result, bs.Key(field))
+	}
+	return result
+}
+
+func sha1hex(value string) string {
+	sum := sha1.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,366 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"testing/quick"
+	"time"
+
+	c "github.com/SimonRichardson/echelon/cluster"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/selectors"
+	"github.com/SimonRichardson/echelon/tests"
+)
+
+func newMemoryCluster() Cluster {
+	return NewMemory(fmt.Sprintf("memory_%d", rand.Int63()))
+}
+
+func TestMemoryInsert(t *testing.T) {
+	var (
+		amount = rand.Intn(5) + 1
+
+		f = func(key, field, txn, value string) bool {
+			var (
+				cluster = newMemoryCluster()
+				in      = insert(cluster, amount)
+			)
+
+			dst := in(key, field, txn, value, time.Minute)
+
+			result := 0
+			for e := range dst {
+				if err := c.ErrorFromElement(e); err != nil {
+					typex.Fatal(err)
+				}
+				result += c.AmountFromElement(e)
+			}
+			return result == amount
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMemoryDelete(t *testing.T) {
+	var (
+		amount = rand.Intn(5) + 1
+
+		f = func(key, field, txn, value string) bool {
+			var (
+				cluster = newMemoryCluster()
+				in      = insert(cluster, amount)
+				del     = remove(cluster, amount)
+			)
+
+			in(key, field, txn, value, time.Minute)
+			dst := del(key, field, txn, value, time.Minute)
+
+			result := 0
+			for e := range dst {
+				if err := c.ErrorFromElement(e); err != nil {
+					typex.Fatal(err)
+				}
+				result += c.AmountFromElement(e)
+			}
+			return result == amount
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMemoryInsertCancelled(t *testing.T) {
+	var (
+		cluster     = newMemoryCluster()
+		ctx, cancel = context.WithCancel(context.Background())
+		members     = []selectors.KeyFieldScoreTxnValue{
+			{Key: bs.Key("key"), Field: bs.Key("field"), Score: 1, Txn: bs.Key("txn")},
+		}
+	)
+	cancel()
+
+	// The error might not be handed over, as nobody is expected to be reading
+	// once the context is done, but nothing must be inserted.
+	for e := range cluster.Insert(ctx, members, selectors.KeySizeExpiry{
+		bs.Key("key"): selectors.SizeExpiry{Size: 10, Expiry: time.Minute},
+	}) {
+		if err := c.ErrorFromElement(e); err != context.Canceled {
+			t.Errorf("Expected: %v, Actual: %v", context.Canceled, err)
+		}
+	}
+
+	for e := range cluster.Size(context.Background(), bs.Key("key")) {
+		if amount := c.AmountFromElement(e); amount != 0 {
+			t.Errorf("Expected: %v, Actual: %v", 0, amount)
+		}
+	}
+}
+
+func TestMemoryIndexedTransaction(t *testing.T) {
+	var (
+		amount = rand.Intn(5) + 1
+
+		f = func(key, field, txn, value string) bool {
+			var (
+				cluster = newMemoryCluster()
+				in      = insert(cluster, amount)
+			)
+
+			checkErrors(in(key, field, txn, value, time.Minute))
+
+			fields, err := cluster.Indexed(bs.Key(key), selectors.TransactionIndex, bs.Key(txn))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			others, err := cluster.Indexed(bs.Key(key), selectors.TransactionIndex, bs.Key(txn+"_"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return len(fields) == amount && len(others) == 0
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMemoryIndexedInvalidIndex(t *testing.T) {
+	cluster := newMemoryCluster()
+	if _, err := cluster.Indexed(bs.Key("key"), selectors.Index("bad"), bs.Key("value")); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestMemoryLastWriteWins(t *testing.T) {
+	var (
+		f = func(key, field, value string, a, b uint8) bool {
+			cluster := newMemoryCluster()
+
+			write := func(score float64, value string) {
+				members := []selectors.KeyFieldScoreTxnValue{
+					selectors.KeyFieldScoreTxnValue{
+						Key:   bs.Key(key),
+						Field: bs.Key(field),
+						Score: score,
+						Txn:   bs.Key("txn"),
+						Value: value,
+					},
+				}
+				for range cluster.Insert(context.Background(), members, selectors.KeySizeExpiry{
+					bs.Key(key): selectors.SizeExpiry{
+						Size:   1,
+						Expiry: time.Minute,
+					},
+				}) {
+				}
+			}
+
+			write(float64(a)+1, "a")
+			write(float64(b)+1, "b")
+
+			var values []selectors.KeyFieldScoreTxnValue
+			for e := range cluster.Select(context.Background(), bs.Key(key), bs.Key(field)) {
+				if err := c.ErrorFromElement(e); err != nil {
+					typex.Fatal(err)
+				}
+				values = append(values, c.ValuesFromElement(e)...)
+			}
+
+			expected := "a"
+			if b > a {
+				expected = "b"
+			}
+			return len(values) == 1 && values[0].Value == expected
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMemorySwap(t *testing.T) {
+	var (
+		cluster = newMemoryCluster()
+		key     = bs.Key("key")
+		expiry  = selectors.KeySizeExpiry{
+			key: selectors.SizeExpiry{
+				Size:   1,
+				Expiry: time.Minute,
+			},
+		}
+	)
+
+	member := func(score float64, value string) selectors.KeyFieldScoreTxnValue {
+		return selectors.KeyFieldScoreTxnValue{
+			Key:   key,
+			Field: bs.Key("field"),
+			Score: score,
+			Txn:   bs.Key("txn"),
+			Value: value,
+		}
+	}
+	swap := func(score, version float64, value string) int {
+		result := 0
+		for e := range cluster.Swap(context.Background(), member(score, value), version, expiry) {
+			if err := c.ErrorFromElement(e); err != nil {
+				t.Fatal(err)
+			}
+			result += c.AmountFromElement(e)
+		}
+		return result
+	}
+
+	for range cluster.Insert(context.Background(), []selectors.KeyFieldScoreTxnValue{member(1, "a")}, expiry) {
+	}
+
+	// Both swaps read the first version, so only the first of them is written.
+	if expected, actual := 1, swap(2, 1, "b"); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 0, swap(3, 1, "c"); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	var values []selectors.KeyFieldScoreTxnValue
+	for e := range cluster.Select(context.Background(), key, bs.Key("field")) {
+		values = append(values, c.ValuesFromElement(e)...)
+	}
+	if expected, actual := "b", values[0].Value; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemorySummary(t *testing.T) {
+	var (
+		amount = rand.Intn(5) + 1
+
+		f = func(key, field, txn, value string) bool {
+			var (
+				a = newMemoryCluster()
+				b = newMemoryCluster()
+			)
+
+			checkErrors(insert(a, amount)(key, field, txn, value, time.Minute))
+			checkErrors(insert(b, amount)(key, field, txn, value, time.Hour))
+
+			x, err := a.Summary(bs.Key(key), 8)
+			if err != nil {
+				typex.Fatal(err)
+			}
+			y, err := b.Summary(bs.Key(key), 8)
+			if err != nil {
+				typex.Fatal(err)
+			}
+			return x.Root() == y.Root()
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMemoryDue(t *testing.T) {
+	var (
+		amount = rand.Intn(5) + 1
+
+		f = func(key, field, txn, value string) bool {
+			var (
+				cluster = newMemoryCluster()
+				in      = insert(cluster, amount)
+			)
+
+			checkErrors(in(key, field, txn, value, -time.Minute))
+			checkErrors(in(key+"_live", field, txn, value, time.Minute))
+
+			due, err := cluster.Due(time.Now(), amount+1)
+			if err != nil {
+				typex.Fatal(err)
+			}
+			if len(due) != amount {
+				return false
+			}
+			for _, v := range due {
+				if v.Key.String() != key {
+					return false
+				}
+			}
+			return true
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMemoryPostpone(t *testing.T) {
+	var (
+		amount = rand.Intn(5) + 1
+
+		f = func(key, field, txn, value string) bool {
+			var (
+				cluster = newMemoryCluster()
+				in      = insert(cluster, amount)
+				now     = time.Now()
+			)
+
+			checkErrors(in(key, field, txn, value, -time.Minute))
+
+			due, err := cluster.Due(now, amount)
+			if err != nil {
+				typex.Fatal(err)
+			}
+
+			members := make([]selectors.KeyField, 0, len(due))
+			for _, v := range due {
+				members = append(members, selectors.KeyField{Key: v.Key, Field: v.Field})
+			}
+			if err := cluster.Postpone(members, now.Add(time.Minute)); err != nil {
+				typex.Fatal(err)
+			}
+
+			before, err := cluster.Due(now, amount)
+			if err != nil {
+				typex.Fatal(err)
+			}
+			after, err := cluster.Due(now.Add(time.Minute*2), amount)
+			if err != nil {
+				typex.Fatal(err)
+			}
+			return len(due) == amount && len(before) == 0 && len(after) == amount
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScheduleMember(t *testing.T) {
+	var (
+		f = func(key, field string) bool {
+			k, v, err := extractScheduleMember(scheduleMember(bs.Key(key), bs.Key(field)))
+			if err != nil {
+				typex.Fatal(err)
+			}
+			return k.String() == key && v.String() == field
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,213 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/SimonRichardson/echelon/errors"
+	p "github.com/SimonRichardson/echelon/internal/redis"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/garyburd/redigo/redis"
+)
+
+// ErrNotMigratable defines an error where the keys of a cluster have moved
+// between the hosts of a cluster, but they can't be moved with them.
+var ErrNotMigratable = typex.Errorf(errors.Source, errors.NoCaseFound, "Not Migratable")
+
+// Moved returns the keys held by the hosts of the previous cluster, that the
+// next cluster routes to a different host. Clusters that are held in memory
+// never move any keys. If any keys of an OR-Set have moved ErrNotMigratable is
+// returned, as they can't be migrated.
+func Moved(prev, next Cluster) ([]bs.Key, error) {
+	from, to, ok := pools(prev, next)
+	if !ok {
+		return []bs.Key{}, nil
+	}
+
+	moved, err := from.Moved(to, func(conn redis.Conn) ([]string, error) {
+		res, err := keys(conn, defaultBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]string, 0, len(res))
+		for _, v := range res {
+			result = append(result, v.String())
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(moved) > 0 && !migratable(prev, next) {
+		return nil, ErrNotMigratable
+	}
+
+	result := make([]bs.Key, 0, len(moved))
+	for _, v := range moved {
+		result = append(result, bs.Key(v))
+	}
+	return result, nil
+}
+
+// ErrMigrationConflict defines an error where the key was written to the
+// previous host whilst it was being moved, so the move has to be tried again.
+var ErrMigrationConflict = typex.Errorf(errors.Source, errors.ConcurrentModification, "Migration Conflict")
+
+// Migrate moves the members of a key from the host of the previous cluster to
+// the host of the next cluster, it returns the number of members that were
+// moved. The members are replayed with the same scripts as a write, so any
+// member that's been written to the next cluster since isn't overwritten. Once
+// the members have moved, the key is removed from the previous host. The key is
+// watched whilst it's moved, so if it's written to the previous host in the
+// mean time, nothing is removed and ErrMigrationConflict is returned.
+func Migrate(prev, next Cluster, key bs.Key) (int, error) {
+	if !migratable(prev, next) {
+		return 0, ErrNotMigratable
+	}
+
+	from, to, ok := pools(prev, next)
+	if !ok {
+		return 0, nil
+	}
+
+	amount := 0
+	err := from.With(key.String(), func(conn redis.Conn) error {
+		k := prefix + key.String()
+		if _, err := conn.Do("WATCH", k+insertSuffix, k+deleteSuffix); err != nil {
+			return err
+		}
+
+		insertions, err := redis.StringMap(conn.Do("HGETALL", k+insertSuffix))
+		if err != nil {
+			conn.Do("UNWATCH")
+			return err
+		}
+		deletions, err := redis.StringMap(conn.Do("HGETALL", k+deleteSuffix))
+		if err != nil {
+			conn.Do("UNWATCH")
+			return err
+		}
+
+		if amount, err = replay(to, key, insertions, deletions); err != nil {
+			conn.Do("UNWATCH")
+			return err
+		}
+
+		return forget(conn, key)
+	})
+	return amount, err
+}
+
+// replay writes the members to the host of the next cluster, returning the
+// number of members that were written.
+func replay(to *p.Pool, key bs.Key, insertions, deletions map[string]string) (int, error) {
+	amount := 0
+	err := to.With(key.String(), func(conn redis.Conn) error {
+		for _, v := range []struct {
+			values map[string]string
+			send   sendScript
+		}{
+			{insertions, sendInsertScript},
+			{deletions, sendDeleteScript},
+		} {
+			for field, value := range v.values {
+				score, txn, expiry, data, err := ExtractScoreTxnExpiryValue(value)
+				if err != nil {
+					return err
+				}
+				if err := v.send(conn, key, bs.Key(field), score, expiry, bs.Key(txn), data); err != nil {
+					return err
+				}
+				amount++
+			}
+		}
+
+		if err := conn.Flush(); err != nil {
+			return err
+		}
+
+		// A member that's rejected because it's older than the one the next
+		// host has, isn't an error.
+		for i := 0; i < amount; i++ {
+			if _, err := conn.Receive(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return amount, err
+}
+
+// forget removes a key, it's secondary indexes and it's schedule from a host.
+// The key is expected to be watched, the removal only happens if it's not been
+// written to since.
+func forget(conn redis.Conn, key bs.Key) error {
+	k := prefix + key.String()
+
+	fields, err := redis.Strings(conn.Do("HKEYS", k+insertSuffix))
+	if err != nil {
+		conn.Do("UNWATCH")
+		return err
+	}
+
+	entries, err := redis.StringMap(conn.Do("HGETALL", k+indexSuffix))
+	if err != nil {
+		conn.Do("UNWATCH")
+		return err
+	}
+
+	args := []interface{}{k + insertSuffix, k + deleteSuffix, k + indexSuffix}
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, separator, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if parts[0] != "" {
+			args = append(args, k+ownerSuffix+parts[0])
+		}
+		args = append(args, k+txnSuffix+parts[1])
+	}
+
+	conn.Send("MULTI")
+	sendUnschedule(conn, key, fields)
+	conn.Send("DEL", args...)
+
+	reply, err := conn.Do("EXEC")
+	if err != nil {
+		return err
+	} else if reply == nil {
+		return ErrMigrationConflict
+	}
+	return nil
+}
+
+// pools returns the pools of the clusters, if they're both held in redis.
+func pools(prev, next Cluster) (*p.Pool, *p.Pool, bool) {
+	from, ok0 := pool(prev)
+	to, ok1 := pool(next)
+	return from, to, ok0 && ok1
+}
+
+func pool(c Cluster) (*p.Pool, bool) {
+	switch v := c.(type) {
+	case *cluster:
+		return v.pool, true
+	case *orSet:
+		return v.pool, true
+	}
+	return nil, false
+}
+
+// migratable returns if the members of the clusters can be moved, the tags of
+// an OR-Set can't be replayed as a write.
+func migratable(prev, next Cluster) bool {
+	if _, ok := prev.(*orSet); ok {
+		return false
+	}
+	if _, ok := next.(*orSet); ok {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	t "github.com/SimonRichardson/echelon/cluster"
+	"github.com/SimonRichardson/echelon/errors"
+	p "github.com/SimonRichardson/echelon/internal/redis"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/scripts"
+	s "github.com/SimonRichardson/echelon/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+const (
+	tagsSuffix      = "#"
+	taggedSuffix    = "@"
+	tombstoneSuffix = "!"
+	observedSuffix  = "%"
+
+	// tombstoneRetention is how long a deletion is kept, so that an insertion
+	// of the same transaction arriving later than that is no longer rejected.
+	tombstoneRetention = 24 * time.Hour
+)
+
+// ErrNotSwappable defines an error where the cluster can't over write a member
+// only if it's still the version that was read.
+var (
+	ErrNotSwappable = typex.Errorf(errors.Source, errors.NoCaseFound, "Not Swappable")
+)
+
+var (
+	orSetInsertScript *redis.Script
+	orSetDeleteScript *redis.Script
+)
+
+func init() {
+	replacer := strings.NewReplacer(
+		"SEPARATOR", separator,
+		"INSERTSUFFIX", insertSuffix,
+		"DELETESUFFIX", deleteSuffix,
+		"TAGSSUFFIX", tagsSuffix,
+		"TAGGEDSUFFIX", taggedSuffix,
+		"TOMBSTONESUFFIX", tombstoneSuffix,
+		"OBSERVEDSUFFIX", observedSuffix,
+	)
+
+	raw, err := scripts.Asset("../scripts/orset/insert.lua")
+	if err != nil {
+		typex.Fatal(err)
+	}
+	orSetInsertScript = redis.NewScript(1, replacer.Replace(string(raw)))
+
+	if raw, err = scripts.Asset("../scripts/orset/delete.lua"); err != nil {
+		typex.Fatal(err)
+	}
+	orSetDeleteScript = redis.NewScript(1, replacer.Replace(string(raw)))
+}
+
+type orSet struct {
+	*cluster
+}
+
+// NewORSet creates a cluster that resolves concurrent insertions and deletions
+// as an add-wins observed-remove set (OR-Set), instead of a LWW-element-set.
+// Every insertion is tagged with its score and transaction, and a deletion only
+// removes the tags of its transaction that are scored no later than itself, so
+// a concurrent deletion can't remove a later insertion of the same field.
+// Selecting, scanning and scoring are shared with the LWW-element-set, as the
+// winning value for each field is kept in the same place. The secondary indexes
+// and the expiry schedule aren't maintained, as the winning value can change on
+// a deletion, so both are rejected with ErrNotIndexed and ErrNotScheduled.
+func NewORSet(pool *p.Pool) Cluster {
+	return &orSet{
+		cluster: &cluster{
+			pool: pool,
+		},
+	}
+}
+
+func (c *orSet) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
+	return c.countCommon(ctx, keys, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
+		return insertion(conn, sendORSetInsertScript, values[key], sizeExpiry[key])
+	})
+}
+
+// Swap is refused, as a concurrent deletion of an OR-Set only removes the tag
+// it observed, so the version that was read isn't enough to know that nothing
+// else has changed.
+func (c *orSet) Swap(ctx context.Context, member s.KeyFieldScoreTxnValue, version float64, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	return c.countCommon(ctx, []bs.Key{member.Key}, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
+		return []s.KeyCount{s.KeyCount{Key: key, Count: 0}}, ErrNotSwappable
+	})
+}
+
+func (c *orSet) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	keys, values := s.KeyFieldScoreTxnValues(members).KeysBucketize()
+	return c.countCommon(ctx, keys, func(conn redis.Conn, key bs.Key) ([]s.KeyCount, error) {
+		return deletion(conn, sendORSetDeleteScript, values[key], sizeExpiry[key])
+	})
+}
+
+func (c *orSet) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	return c.Delete(ctx, members, sizeExpiry)
+}
+
+func (c *orSet) Indexed(key bs.Key, index s.Index, value bs.Key) ([]bs.Key, error) {
+	return nil, ErrNotIndexed
+}
+
+func (c *orSet) Reindex(key bs.Key) (int, error) {
+	return 0, ErrNotIndexed
+}
+
+// Due is rejected, as the tags of an OR-Set aren't scheduled, they expire via a
+// full sweep instead.
+func (c *orSet) Due(now time.Time, limit int) ([]s.KeyFieldScoreTxnValueExpiry, error) {
+	return nil, ErrNotScheduled
+}
+
+func (c *orSet) Postpone(members []s.KeyField, until time.Time) error {
+	return ErrNotScheduled
+}
+
+func sendORSetInsertScript(conn redis.Conn,
+	key, field bs.Key,
+	score float64,
+	expiry int64,
+	txn bs.Key,
+	value string,
+) error {
+	return orSetInsertScript.Send(conn,
+		prefix+key.String(),
+		field.String(),
+		score,
+		txn.String(),
+		PackageScoreTxnExpiryValue(score, txn, expiry, value),
+	)
+}
+
+func sendORSetDeleteScript(conn redis.Conn,
+	key, field bs.Key,
+	score float64,
+	expiry int64,
+	txn bs.Key,
+	value string,
+) error {
+	return orSetDeleteScript.Send(conn,
+		prefix+key.String(),
+		field.String(),
+		score,
+		txn.String(),
+		PackageScoreTxnExpiryValue(score, txn, expiry, value),
+		int64(tombstoneRetention/time.Millisecond),
+	)
+}
@@ -0,0 +1,337 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+
+	c "github.com/SimonRichardson/echelon/cluster"
+	"github.com/SimonRichardson/echelon/env"
+	p "github.com/SimonRichardson/echelon/internal/redis"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/selectors"
+	"github.com/SimonRichardson/echelon/tests"
+	"github.com/SimonRichardson/fusion/strategies"
+	stubs "github.com/SimonRichardson/fusion/tests/stubs/redis"
+	b "github.com/SimonRichardson/quatsch/pool/bson"
+)
+
+func newORSetCluster(creator p.RedisCreator) Cluster {
+	var (
+		e         = env.New(nil)
+		clusters  = strings.Split(e.StoreInstances, ";")
+		instances = strings.Split(clusters[0], ",")[0:1]
+
+		pool = p.New(instances, strategies.NewHash(), &p.ConnectionTimeout{}, 100, creator)
+	)
+
+	return NewORSet(pool)
+}
+
+type orSetOp struct {
+	Insert bool
+	Field  string
+	Txn    string
+	Score  float64
+}
+
+func randORSetOps(r *rand.Rand, amount int) []orSetOp {
+	ops := make([]orSetOp, 0, amount)
+	for i := 0; i < amount; i++ {
+		ops = append(ops, orSetOp{
+			Insert: r.Intn(3) > 0,
+			Field:  fmt.Sprintf("field_%d", r.Intn(3)),
+			Txn:    fmt.Sprintf("txn_%d", r.Intn(4)),
+			Score:  float64(r.Intn(5) + 1),
+		})
+	}
+	return ops
+}
+
+func applyORSetOps(cluster Cluster, key string, ops []orSetOp) {
+	for _, op := range ops {
+		var (
+			members = []selectors.KeyFieldScoreTxnValue{
+				selectors.KeyFieldScoreTxnValue{
+					Key:   bs.Key(key),
+					Field: bs.Key(op.Field),
+					Score: op.Score,
+					Txn:   bs.Key(op.Txn),
+					Value: fmt.Sprintf("%s:%f", op.Txn, op.Score),
+				},
+			}
+			sizeExpiry = selectors.KeySizeExpiry{
+				bs.Key(key): selectors.SizeExpiry{
+					Size:   int64(len(ops)) + 1,
+					Expiry: time.Hour,
+				},
+			}
+			dst <-chan c.Element
+		)
+
+		if op.Insert {
+			dst = cluster.Insert(context.Background(), members, sizeExpiry)
+		} else {
+			dst = cluster.Delete(context.Background(), members, sizeExpiry)
+		}
+
+		for e := range dst {
+			// Rejected operations are reported as partial, which is expected
+			// when replaying the same operations in a different order.
+			if err := c.ErrorFromElement(e); err != nil && err != c.ErrPartialInsertions {
+				typex.Fatal(err)
+			}
+		}
+	}
+}
+
+func snapshotORSet(cluster Cluster, key string) map[string]selectors.KeyFieldScoreTxnValue {
+	result := map[string]selectors.KeyFieldScoreTxnValue{}
+	for e := range cluster.Members(context.Background(), bs.Key(key)) {
+		if err := c.ErrorFromElement(e); err != nil {
+			typex.Fatal(err)
+		}
+		for _, field := range c.KeysFromElement(e) {
+			for v := range cluster.Select(context.Background(), bs.Key(key), field) {
+				if err := c.ErrorFromElement(v); err != nil {
+					typex.Fatal(err)
+				}
+				for _, value := range c.ValuesFromElement(v) {
+					value.Key = ""
+					result[field.String()] = value
+				}
+			}
+		}
+	}
+	return result
+}
+
+func TestORSetInsert(t *testing.T) {
+	var creator p.RedisCreator
+	if defaultUseStubs {
+		creator = stubs.SendAndReceive(
+			func(name string, args ...interface{}) error {
+				return nil
+			},
+			func() (interface{}, error) {
+				return int64(1), nil
+			},
+		)
+	}
+
+	var (
+		amount  = rand.Intn(5) + 1
+		cluster = newORSetCluster(creator)
+		in      = insert(cluster, amount)
+		pool    = getIdentPool()
+
+		f = func(field, txn, value string, duration time.Duration) bool {
+			key, err := b.Bson(pool.Get())
+			if err != nil {
+				typex.Fatal(err)
+			}
+
+			dst := in(key.Hex(), field, txn, value, duration)
+
+			result := 0
+			for e := range dst {
+				if err := c.ErrorFromElement(e); err != nil {
+					typex.Fatal(err)
+				}
+				result += c.AmountFromElement(e)
+			}
+			return result == amount
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestORSetDelete(t *testing.T) {
+	var creator p.RedisCreator
+	if defaultUseStubs {
+		creator = stubs.SendAndReceive(
+			func(name string, args ...interface{}) error {
+				return nil
+			},
+			func() (interface{}, error) {
+				return int64(1), nil
+			},
+		)
+	}
+
+	var (
+		amount  = rand.Intn(5) + 1
+		cluster = newORSetCluster(creator)
+		in      = insert(cluster, amount)
+		del     = remove(cluster, amount)
+		pool    = getIdentPool()
+
+		f = func(field, txn, value string, duration time.Duration) bool {
+			key, err := b.Bson(pool.Get())
+			if err != nil {
+				typex.Fatal(err)
+			}
+
+			in(key.Hex(), field, txn, value, duration)
+			dst := del(key.Hex(), field, txn, value, duration)
+
+			result := 0
+			for e := range dst {
+				if err := c.ErrorFromElement(e); err != nil {
+					typex.Fatal(err)
+				}
+				result += c.AmountFromElement(e)
+			}
+			return result == amount
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestORSetAddWins(t *testing.T) {
+	if defaultUseStubs {
+		t.Skip("Unable to test because of concurency.")
+	}
+
+	var (
+		cluster = newORSetCluster(nil)
+		pool    = getIdentPool()
+
+		f = func(score uint8) bool {
+			key, err := b.Bson(pool.Get())
+			if err != nil {
+				typex.Fatal(err)
+			}
+
+			// A cancellation of the first reservation that is concurrent with a
+			// re-reservation must not swallow the re-reservation.
+			applyORSetOps(cluster, key.Hex(), []orSetOp{
+				orSetOp{Insert: true, Field: "field", Txn: "first", Score: float64(score) + 1},
+				orSetOp{Insert: true, Field: "field", Txn: "second", Score: 1},
+				orSetOp{Insert: false, Field: "field", Txn: "first", Score: float64(score) + 2},
+			})
+
+			result := snapshotORSet(cluster, key.Hex())
+			value, ok := result["field"]
+			return ok && value.Txn.String() == "second"
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestORSetDeleteThenReinsert(t *testing.T) {
+	if defaultUseStubs {
+		t.Skip("Unable to test because of concurency.")
+	}
+
+	var (
+		cluster = newORSetCluster(nil)
+		pool    = getIdentPool()
+
+		f = func(score uint8) bool {
+			key, err := b.Bson(pool.Get())
+			if err != nil {
+				typex.Fatal(err)
+			}
+
+			// Adding the field back with the same transaction after it's been
+			// deleted must not be rejected by the tombstone, but replaying the
+			// insertion that was deleted must be.
+			applyORSetOps(cluster, key.Hex(), []orSetOp{
+				orSetOp{Insert: true, Field: "field", Txn: "txn", Score: float64(score) + 1},
+				orSetOp{Insert: false, Field: "field", Txn: "txn", Score: float64(score) + 2},
+				orSetOp{Insert: true, Field: "field", Txn: "txn", Score: float64(score) + 3},
+				orSetOp{Insert: true, Field: "field", Txn: "txn", Score: float64(score) + 1},
+			})
+
+			result := snapshotORSet(cluster, key.Hex())
+			value, ok := result["field"]
+			return ok && value.Txn.String() == "txn" && value.Score == float64(score)+3
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestORSetConvergence(t *testing.T) {
+	if defaultUseStubs {
+		t.Skip("Unable to test because of concurency.")
+	}
+
+	var (
+		cluster = newORSetCluster(nil)
+		pool    = getIdentPool()
+
+		f = func(seed int64, amount uint8) bool {
+			var (
+				r   = rand.New(rand.NewSource(seed))
+				ops = randORSetOps(r, int(amount%32)+1)
+
+				shuffled = make([]orSetOp, len(ops))
+			)
+			for k, v := range r.Perm(len(ops)) {
+				shuffled[k] = ops[v]
+			}
+
+			a, err := b.Bson(pool.Get())
+			if err != nil {
+				typex.Fatal(err)
+			}
+			z, err := b.Bson(pool.Get())
+			if err != nil {
+				typex.Fatal(err)
+			}
+
+			applyORSetOps(cluster, a.Hex(), ops)
+			applyORSetOps(cluster, z.Hex(), shuffled)
+
+			x, y := snapshotORSet(cluster, a.Hex()), snapshotORSet(cluster, z.Hex())
+			if len(x) != len(y) {
+				return false
+			}
+			for k, v := range x {
+				if w, ok := y[k]; !ok || v != w {
+					return false
+				}
+			}
+			return true
+		}
+	)
+
+	if err := quick.Check(f, tests.Config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestORSetRejectsIndexesAndSchedule(t *testing.T) {
+	cluster := NewORSet(nil)
+
+	if _, err := cluster.Indexed(bs.Key("key"), selectors.OwnerIndex, bs.Key("owner")); err != ErrNotIndexed {
+		t.Errorf("Expected %v, got %v", ErrNotIndexed, err)
+	}
+	if _, err := cluster.Reindex(bs.Key("key")); err != ErrNotIndexed {
+		t.Errorf("Expected %v, got %v", ErrNotIndexed, err)
+	}
+	if _, err := cluster.Due(time.Now(), 10); err != ErrNotScheduled {
+		t.Errorf("Expected %v, got %v", ErrNotScheduled, err)
+	}
+	if err := cluster.Postpone([]selectors.KeyField{}, time.Now()); err != ErrNotScheduled {
+		t.Errorf("Expected %v, got %v", ErrNotScheduled, err)
+	}
+}
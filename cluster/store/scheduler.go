@@ -0,0 +1,122 @@
+package store
+
+import (
+	"time"
+
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	s "github.com/SimonRichardson/echelon/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+// ErrNotScheduled defines an error where the cluster doesn't maintain the
+// expiry schedule, so the members have to be found by a full sweep instead.
+var (
+	ErrNotScheduled = typex.Errorf(errors.Source, errors.NoCaseFound, "Not Scheduled")
+)
+
+// due reads the members of the schedule that have expired by now, in the order
+// that they expired. A member that's no longer inserted (or can't be read) is
+// removed from the schedule, rather than returned.
+func due(conn redis.Conn, now time.Time, limit int) ([]s.KeyFieldScoreTxnValueExpiry, error) {
+	values, err := redis.Strings(conn.Do("ZRANGEBYSCORE", scheduleKey,
+		"-inf", now.UnixNano(),
+		"LIMIT", 0, limit,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		stale   = []interface{}{scheduleKey}
+		members = make([]string, 0, len(values))
+		fields  = make([]s.KeyField, 0, len(values))
+	)
+	for _, v := range values {
+		key, field, err := extractScheduleMember(v)
+		if err != nil {
+			stale = append(stale, v)
+			continue
+		}
+
+		if err := conn.Send("HGET", prefix+key.String()+insertSuffix, field.String()); err != nil {
+			return nil, err
+		}
+
+		members = append(members, v)
+		fields = append(fields, s.KeyField{Key: key, Field: field})
+	}
+
+	result := make([]s.KeyFieldScoreTxnValueExpiry, 0, len(fields))
+	if len(fields) > 0 {
+		if err := conn.Flush(); err != nil {
+			return nil, err
+		}
+
+		for k, v := range fields {
+			value, err := redis.String(conn.Receive())
+			if err == redis.ErrNil {
+				stale = append(stale, members[k])
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			score, txn, expiry, data, err := ExtractScoreTxnExpiryValue(value)
+			if err != nil {
+				stale = append(stale, members[k])
+				continue
+			}
+
+			// The member has been inserted again since it was read, so make
+			// sure it's not held up by a postponement.
+			if expiry > now.UnixNano() {
+				if _, err := conn.Do("ZADD", scheduleKey, "XX", expiry, members[k]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			result = append(result, s.KeyFieldScoreTxnValueExpiry{
+				Key:    v.Key,
+				Field:  v.Field,
+				Score:  score,
+				Txn:    bs.Key(txn),
+				Value:  data,
+				Expiry: time.Unix(0, expiry),
+			})
+		}
+	}
+
+	if len(stale) > 1 {
+		if _, err := conn.Do("ZREM", stale...); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// postpone moves a member of the schedule until a later time, a member that's
+// no longer scheduled isn't added back.
+func postpone(conn redis.Conn, member s.KeyField, until time.Time) error {
+	_, err := conn.Do("ZADD", scheduleKey, "XX", until.UnixNano(), scheduleMember(member.Key, member.Field))
+	return err
+}
+
+// sendUnschedule queues the removal of the fields of a key from the schedule,
+// so that it can be part of a transaction.
+func sendUnschedule(conn redis.Conn, key bs.Key, fields []string) error {
+	if len(fields) < 1 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(fields)+1)
+	args = append(args, scheduleKey)
+	for _, field := range fields {
+		args = append(args, scheduleMember(key, bs.Key(field)))
+	}
+
+	return conn.Send("ZREM", args...)
+}
@@ -22,8 +22,18 @@ const (
 
 	insertSuffixLen = len(insertSuffix)
 	deleteSuffixLen = len(deleteSuffix)
+
+	indexSuffix = "~"
+	ownerSuffix = "~o:"
+	txnSuffix   = "~t:"
+
+	scheduleKey = prefix + "^"
 )
 
+// sendScript defines a way to pipeline a script for a member, so that the
+// insertion and deletion of members can be shared between set semantics.
+type sendScript func(redis.Conn, bs.Key, bs.Key, float64, int64, bs.Key, string) error
+
 var (
 	genericScript string
 	insertScript  *redis.Script
@@ -41,6 +51,10 @@ func init() {
 		"SEPARATOR", separator,
 		"INSERTSUFFIX", insertSuffix,
 		"DELETESUFFIX", deleteSuffix,
+		"INDEXSUFFIX", indexSuffix,
+		"OWNERSUFFIX", ownerSuffix,
+		"TXNSUFFIX", txnSuffix,
+		"SCHEDULEKEY", scheduleKey,
 	).Replace(script)
 
 	insertScript = redis.NewScript(1, strings.NewReplacer(
@@ -67,6 +81,10 @@ func doInsertScript(conn redis.Conn,
 		score,
 		txn.String(),
 		PackageScoreTxnExpiryValue(score, txn, expiry, value),
+		ownerOf(value),
+		expiry,
+		scheduleMember(key, field),
+		"",
 	)
 }
 
@@ -83,6 +101,33 @@ func sendInsertScript(conn redis.Conn,
 		score,
 		txn.String(),
 		PackageScoreTxnExpiryValue(score, txn, expiry, value),
+		ownerOf(value),
+		expiry,
+		scheduleMember(key, field),
+		"",
+	)
+}
+
+// doSwapScript inserts the member, only if the insertion that's stored is still
+// the version that was read.
+func doSwapScript(conn redis.Conn,
+	key, field bs.Key,
+	score float64,
+	expiry int64,
+	txn bs.Key,
+	value string,
+	version float64,
+) (interface{}, error) {
+	return insertScript.Do(conn,
+		prefix+key.String(),
+		field.String(),
+		score,
+		txn.String(),
+		PackageScoreTxnExpiryValue(score, txn, expiry, value),
+		ownerOf(value),
+		expiry,
+		scheduleMember(key, field),
+		packageVersion(version),
 	)
 }
 
@@ -99,6 +144,10 @@ func doDeleteScript(conn redis.Conn,
 		score,
 		txn.String(),
 		PackageScoreTxnExpiryValue(score, txn, expiry, value),
+		"",
+		expiry,
+		scheduleMember(key, field),
+		"",
 	)
 }
 
@@ -115,9 +164,43 @@ func sendDeleteScript(conn redis.Conn,
 		score,
 		txn.String(),
 		PackageScoreTxnExpiryValue(score, txn, expiry, value),
+		"",
+		expiry,
+		scheduleMember(key, field),
+		"",
 	)
 }
 
+// scheduleMember packages the key and field as a member of the schedule. The
+// key is prefixed by it's length, as either of them could hold the separator.
+func scheduleMember(key, field bs.Key) string {
+	return fmt.Sprintf("%d%s%s%s", len(key), separator, key.String(), field.String())
+}
+
+// extractScheduleMember extracts the key and field from a member of the
+// schedule.
+func extractScheduleMember(member string) (bs.Key, bs.Key, error) {
+	parts := strings.SplitN(member, separator, 2)
+	if num := len(parts); num != 2 {
+		return "", "", typex.Errorf(errors.Source, errors.UnexpectedResults, "Received %d parts, expected 2", num)
+	}
+
+	size, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", "", err
+	}
+	if size < 0 || size > len(parts[1]) {
+		return "", "", typex.Errorf(errors.Source, errors.UnexpectedResults, "Invalid key length %d", size)
+	}
+	return bs.Key(parts[1][:size]), bs.Key(parts[1][size:]), nil
+}
+
+// packageVersion formats the version in the same way as the score is stored, so
+// that the script can compare them as they are.
+func packageVersion(version float64) string {
+	return fmt.Sprintf("%f", version)
+}
+
 func PackageScoreTxnExpiryValue(score float64, txn bs.Key, expiry int64, value string) string {
 	return fmt.Sprintf("%f%s%s%s%d%s%s",
 		score, separator,
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/merkle"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/scripts"
+	s "github.com/SimonRichardson/echelon/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+var (
+	summaryScript *redis.Script
+	membersScript *redis.Script
+)
+
+func init() {
+	replacer := strings.NewReplacer(
+		"SEPARATOR", separator,
+		"INSERTSUFFIX", insertSuffix,
+		"DELETESUFFIX", deleteSuffix,
+	)
+
+	raw, err := scripts.Asset("../scripts/merkle/summary.lua")
+	if err != nil {
+		typex.Fatal(err)
+	}
+	summaryScript = redis.NewScript(1, replacer.Replace(string(raw)))
+
+	if raw, err = scripts.Asset("../scripts/merkle/members.lua"); err != nil {
+		typex.Fatal(err)
+	}
+	membersScript = redis.NewScript(1, replacer.Replace(string(raw)))
+}
+
+func summary(conn redis.Conn, key bs.Key, buckets int) (merkle.Tree, error) {
+	leaves, err := redis.Strings(summaryScript.Do(conn, prefix+key.String(), buckets))
+	if err != nil {
+		return merkle.Tree{}, err
+	}
+
+	if num := len(leaves); num != buckets {
+		return merkle.Tree{}, typex.Errorf(errors.Source, errors.UnexpectedResults,
+			"Received %d buckets from redis, expected %d", num, buckets)
+	}
+
+	return merkle.New(leaves)
+}
+
+func divergent(conn redis.Conn, key bs.Key, buckets int, indices []int) ([]s.KeyFieldScoreTxnValue, error) {
+	args := []interface{}{prefix + key.String(), buckets}
+	for _, v := range indices {
+		args = append(args, v)
+	}
+
+	values, err := redis.Strings(membersScript.Do(conn, args...))
+	if err != nil {
+		return nil, err
+	}
+
+	if num := len(values); num%2 != 0 {
+		return nil, typex.Errorf(errors.Source, errors.UnexpectedResults,
+			"Received an odd number of values (%d) from redis", num)
+	}
+
+	result := make([]s.KeyFieldScoreTxnValue, 0, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		score, txn, _, value, err := ExtractScoreTxnExpiryValue(values[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, s.KeyFieldScoreTxnValue{
+			Key:   key,
+			Field: bs.Key(values[i]),
+			Score: score,
+			Txn:   bs.Key(txn),
+			Value: value,
+		})
+	}
+
+	return result, nil
+}
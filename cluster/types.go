@@ -1,44 +1,59 @@
 package cluster
 
 import (
+	"context"
+	"time"
+
+	"github.com/SimonRichardson/echelon/internal/merkle"
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	s "github.com/SimonRichardson/echelon/selectors"
 )
 
+// The channel returning methods take a context, once it's done the cluster
+// stops sending requests and the channel is closed, without waiting for the
+// requests that are still outstanding.
+
 // Inserter represents a way to insert a mass collection of members in to the
 // store. This is slightly different setup to the selectors interface to enable
 // better concurrency.
 type Inserter interface {
-	Insert([]s.KeyFieldScoreTxnValue, s.KeySizeExpiry) <-chan Element
+	Insert(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) <-chan Element
 }
 
 // Modifier represents a way to insert a mass collection of members in to the
 // store. This is slightly different setup to the selectors interface to enable
 // better concurrency.
 type Modifier interface {
-	Modify([]s.KeyFieldScoreTxnValue, s.KeySizeExpiry) <-chan Element
+	Modify(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) <-chan Element
+}
+
+// Swapper represents a way to over write a member in the store, only if it's
+// still the version (score) that was read. This makes a change that was checked
+// against what was read a compare-and-set.
+type Swapper interface {
+	Swap(context.Context, s.KeyFieldScoreTxnValue, float64, s.KeySizeExpiry) <-chan Element
 }
 
 // Deleter represents a way to delete a mass collection of members in to the
 // store. This is slightly different setup to the selectors interface to enable
 // better concurrency.
 type Deleter interface {
-	Delete([]s.KeyFieldScoreTxnValue, s.KeySizeExpiry) <-chan Element
-	Rollback([]s.KeyFieldScoreTxnValue, s.KeySizeExpiry) <-chan Element
+	Delete(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) <-chan Element
+	Rollback(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) <-chan Element
 }
 
 // Selector defines a way to select members from the store.
 type Selector interface {
-	Select(bs.Key, bs.Key) <-chan Element
-	SelectRange(bs.Key, int, s.KeySizeExpiry) <-chan Element
+	Select(context.Context, bs.Key, bs.Key) <-chan Element
+	SelectRange(context.Context, bs.Key, int, s.KeySizeExpiry) <-chan Element
 }
 
 // Scanner represents a way to introspect the store to help understand what the
 // store has with in it's collections.
 type Scanner interface {
-	Keys() <-chan Element
-	Size(bs.Key) <-chan Element
-	Members(bs.Key) <-chan Element
+	Keys(context.Context) <-chan Element
+	Size(context.Context, bs.Key) <-chan Element
+	Members(context.Context, bs.Key) <-chan Element
 }
 
 // Scorer defines a way to score members with in the collection.
@@ -46,16 +61,54 @@ type Scorer interface {
 	Score([]s.KeyFieldTxnValue) (map[s.KeyFieldTxnValue]s.Presence, error)
 }
 
+// Summarizer defines a way to summarize the members of a collection as a hash
+// tree, so that collections can be compared without reading every member.
+type Summarizer interface {
+	Summary(bs.Key, int) (merkle.Tree, error)
+	Divergent(bs.Key, int, []int) ([]s.KeyFieldScoreTxnValue, error)
+}
+
+// Indexer defines a way to look up the fields of a collection via a secondary
+// index, rather than reading every member of the collection.
+type Indexer interface {
+	Indexed(bs.Key, s.Index, bs.Key) ([]bs.Key, error)
+	Reindex(bs.Key) (int, error)
+}
+
+// Scheduler defines a way to find the members of the collections that are due
+// to expire, without having to scan every collection.
+type Scheduler interface {
+	Due(time.Time, int) ([]s.KeyFieldScoreTxnValueExpiry, error)
+	Postpone([]s.KeyField, time.Time) error
+}
+
 // Repairer defines a way to *attempt* to repair the collection, if possible.
 type Repairer interface {
-	Repair([]s.KeyFieldScoreTxnValue, s.KeySizeExpiry) <-chan Element
+	Repair(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) <-chan Element
 }
 
 // Notifier defines a way to publish various messages to a channel
 type Notifier interface {
-	Publish(s.Channel, []s.KeyFieldScoreSizeExpiry) <-chan Element
-	Unpublish(s.Channel, []s.KeyFieldScoreSizeExpiry) <-chan Element
-	Subscribe(s.Channel) <-chan Element
+	Publish(context.Context, s.Channel, []s.KeyFieldScoreSizeExpiry) <-chan Element
+	Unpublish(context.Context, s.Channel, []s.KeyFieldScoreSizeExpiry) <-chan Element
+	Subscribe(context.Context, s.Channel) <-chan Element
+}
+
+// Streamer defines a way to append messages to a durable stream and consume
+// them as part of a consumer group. Consumed messages are acknowledged by the
+// Ack of their element once they've been handled, so messages that weren't are
+// replayed.
+type Streamer interface {
+	Append(context.Context, s.Channel, []s.KeyFieldScoreSizeExpiry, int) <-chan Element
+	Consume(context.Context, s.Channel, s.ConsumerGroup) <-chan Element
+	Rewind(context.Context, s.Channel, s.ConsumerGroup) <-chan Element
+}
+
+// Changer defines a way to record the changes of a collection, so that they can
+// be read back in the order of their score.
+type Changer interface {
+	Record(context.Context, []s.Change, int) <-chan Element
+	Changes(context.Context, bs.Key, float64, int) <-chan Element
 }
 
 // Closer closes the current cluster along with any underlying pools.
@@ -0,0 +1,222 @@
+package waitlist
+
+import (
+	"encoding/json"
+	"time"
+
+	p "github.com/SimonRichardson/echelon/internal/redis"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+const (
+	// The order of the waiting list is kept in a sorted set, whilst the entries
+	// are kept in a hash. Both are routed by the same key, so that they're on
+	// the same instance.
+	orderPrefix   = "w:"
+	entriesPrefix = "w:e:"
+
+	// Only one promoter offers holds for a key at a time, which is held by a
+	// lock that expires. A promote that's asked for whilst the lock is held
+	// is kept as pending, so that the holder goes round again.
+	lockPrefix    = "w:l:"
+	pendingPrefix = "w:p:"
+)
+
+var (
+	// joinScript adds the entry to the waiting list, unless it's already
+	// waiting, and returns the position of the entry.
+	joinScript = redis.NewScript(2, `
+		if redis.call("ZSCORE", KEYS[1], ARGV[1]) == false then
+			redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+			redis.call("HSET", KEYS[2], ARGV[1], ARGV[3])
+		end
+		return redis.call("ZRANK", KEYS[1], ARGV[1])
+	`)
+
+	// peekScript returns the head of the waiting list, leaving it in place.
+	peekScript = redis.NewScript(2, `
+		local head = redis.call("ZRANGE", KEYS[1], 0, 0)
+		if #head < 1 then
+			return false
+		end
+		return redis.call("HGET", KEYS[2], head[1])
+	`)
+
+	// acquireScript takes or renews the lock of the promoter. If someone else
+	// holds it, the promote is marked as pending for the holder instead.
+	acquireScript = redis.NewScript(2, `
+		local holder = redis.call("GET", KEYS[1])
+		if holder == false or holder == ARGV[1] then
+			redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+			redis.call("DEL", KEYS[2])
+			return 1
+		end
+		redis.call("SET", KEYS[2], 1, "PX", ARGV[2])
+		return 0
+	`)
+
+	// releaseScript gives up the lock of the promoter, if it's still held, and
+	// returns if a promote was marked as pending whilst it was.
+	releaseScript = redis.NewScript(2, `
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			redis.call("DEL", KEYS[1])
+		end
+		return redis.call("DEL", KEYS[2])
+	`)
+)
+
+// Cluster defines a way to hold the waiting lists of the keys, where the
+// entries are kept in the order that they joined.
+type Cluster interface {
+	// Join adds the entry to the waiting list of the key, returning the
+	// position of the entry (starting at 1). Joining again keeps the position.
+	Join(bs.Key, s.WaitlistEntry) (int, error)
+
+	// Leave removes the entry from the waiting list of the key.
+	Leave(bs.Key, bs.Key) (bool, error)
+
+	// Position returns the position of the entry (starting at 1), along with
+	// how many entries are waiting.
+	Position(bs.Key, bs.Key) (int, int, bool, error)
+
+	// Peek returns the head of the waiting list, without removing it. The head
+	// is removed with Leave once it's been offered a hold.
+	Peek(bs.Key) (s.WaitlistEntry, bool, error)
+
+	// Acquire takes the lock of the promoter of the key for the token, or
+	// renews it if the token already holds it. If another token holds it, the
+	// promote is marked as pending for the holder and false is returned.
+	Acquire(bs.Key, string, time.Duration) (bool, error)
+
+	// Release gives up the lock of the promoter of the key, returning if a
+	// promote was marked as pending whilst it was held.
+	Release(bs.Key, string) (bool, error)
+
+	Close() error
+}
+
+type cluster struct {
+	pool *p.Pool
+}
+
+// New creates a cluster using a pool to hold the waiting lists in redis.
+func New(pool *p.Pool) Cluster {
+	return &cluster{
+		pool: pool,
+	}
+}
+
+func (c *cluster) Join(key bs.Key, entry s.WaitlistEntry) (position int, err error) {
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		rank, err := redis.Int(joinScript.Do(conn,
+			orderPrefix+key.String(),
+			entriesPrefix+key.String(),
+			entry.Id.String(),
+			entry.Score,
+			bytes,
+		))
+		position = rank + 1
+		return err
+	})
+	return
+}
+
+func (c *cluster) Leave(key, id bs.Key) (ok bool, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		conn.Send("MULTI")
+		conn.Send("ZREM", orderPrefix+key.String(), id.String())
+		conn.Send("HDEL", entriesPrefix+key.String(), id.String())
+		values, err := redis.Ints(conn.Do("EXEC"))
+		if err != nil {
+			return err
+		}
+		ok = len(values) > 0 && values[0] > 0
+		return nil
+	})
+	return
+}
+
+func (c *cluster) Position(key, id bs.Key) (position, total int, ok bool, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		name := orderPrefix + key.String()
+		if total, err = redis.Int(conn.Do("ZCARD", name)); err != nil {
+			return err
+		}
+
+		rank, err := redis.Int(conn.Do("ZRANK", name, id.String()))
+		if err == redis.ErrNil {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		position, ok = rank+1, true
+		return nil
+	})
+	return
+}
+
+func (c *cluster) Peek(key bs.Key) (entry s.WaitlistEntry, ok bool, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		bytes, err := redis.Bytes(peekScript.Do(conn,
+			orderPrefix+key.String(),
+			entriesPrefix+key.String(),
+		))
+		if err == redis.ErrNil {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		ok = true
+		return json.Unmarshal(bytes, &entry)
+	})
+	return
+}
+
+func (c *cluster) Acquire(key bs.Key, token string, expiry time.Duration) (ok bool, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		ok, err = redis.Bool(acquireScript.Do(conn,
+			lockPrefix+key.String(),
+			pendingPrefix+key.String(),
+			token,
+			milliseconds(expiry),
+		))
+		return err
+	})
+	return
+}
+
+func (c *cluster) Release(key bs.Key, token string) (pending bool, err error) {
+	err = c.pool.With(key.String(), func(conn redis.Conn) error {
+		pending, err = redis.Bool(releaseScript.Do(conn,
+			lockPrefix+key.String(),
+			pendingPrefix+key.String(),
+			token,
+		))
+		return err
+	})
+	return
+}
+
+func (c *cluster) Close() error {
+	c.pool.Close()
+	return nil
+}
+
+// milliseconds rounds the expiry up, so that it's never less than a
+// millisecond, which redis would refuse.
+func milliseconds(d time.Duration) int64 {
+	ms := int64(d / time.Millisecond)
+	if d%time.Millisecond != 0 || ms < 1 {
+		ms++
+	}
+	return ms
+}
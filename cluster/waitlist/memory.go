@@ -0,0 +1,156 @@
+package waitlist
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+var (
+	memoryMutex = &sync.Mutex{}
+	memories    = map[string]*memoryLists{}
+)
+
+type memoryLists struct {
+	mutex   *sync.Mutex
+	lists   map[bs.Key][]s.WaitlistEntry
+	locks   map[bs.Key]*memoryLock
+	pending map[bs.Key]time.Time
+}
+
+type memoryLock struct {
+	token   string
+	expires time.Time
+}
+
+type memory struct {
+	*memoryLists
+}
+
+// NewMemory creates a cluster that is held in memory, but has identical
+// semantics to the redis cluster. Clusters that share the same name also share
+// the same waiting lists, much like pointing at the same redis instance.
+func NewMemory(name string) Cluster {
+	memoryMutex.Lock()
+	defer memoryMutex.Unlock()
+
+	lists, ok := memories[name]
+	if !ok {
+		lists = &memoryLists{
+			mutex:   &sync.Mutex{},
+			lists:   map[bs.Key][]s.WaitlistEntry{},
+			locks:   map[bs.Key]*memoryLock{},
+			pending: map[bs.Key]time.Time{},
+		}
+		memories[name] = lists
+	}
+	return &memory{lists}
+}
+
+func (c *memory) Join(key bs.Key, entry s.WaitlistEntry) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if index := c.index(key, entry.Id); index >= 0 {
+		return index + 1, nil
+	}
+
+	c.insert(key, entry)
+	return c.index(key, entry.Id) + 1, nil
+}
+
+func (c *memory) Leave(key, id bs.Key) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	index := c.index(key, id)
+	if index < 0 {
+		return false, nil
+	}
+
+	list := c.lists[key]
+	c.lists[key] = append(list[:index], list[index+1:]...)
+	return true, nil
+}
+
+func (c *memory) Position(key, id bs.Key) (int, int, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	total := len(c.lists[key])
+	if index := c.index(key, id); index >= 0 {
+		return index + 1, total, true, nil
+	}
+	return 0, total, false, nil
+}
+
+func (c *memory) Peek(key bs.Key) (s.WaitlistEntry, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	list := c.lists[key]
+	if len(list) < 1 {
+		return s.WaitlistEntry{}, false, nil
+	}
+	return list[0], true, nil
+}
+
+func (c *memory) Acquire(key bs.Key, token string, expiry time.Duration) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	if lock, ok := c.locks[key]; ok && lock.token != token && now.Before(lock.expires) {
+		c.pending[key] = now.Add(expiry)
+		return false, nil
+	}
+
+	c.locks[key] = &memoryLock{
+		token:   token,
+		expires: now.Add(expiry),
+	}
+	delete(c.pending, key)
+	return true, nil
+}
+
+func (c *memory) Release(key bs.Key, token string) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if lock, ok := c.locks[key]; ok && lock.token == token {
+		delete(c.locks, key)
+	}
+
+	expires, ok := c.pending[key]
+	delete(c.pending, key)
+	return ok && time.Now().Before(expires), nil
+}
+
+func (c *memory) Close() error {
+	return nil
+}
+
+func (c *memory) index(key, id bs.Key) int {
+	for k, v := range c.lists[key] {
+		if v.Id == id {
+			return k
+		}
+	}
+	return -1
+}
+
+// insert keeps the list ordered by the score and then the id, much like a
+// sorted set.
+func (c *memory) insert(key bs.Key, entry s.WaitlistEntry) {
+	list := append(c.lists[key], entry)
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Score == list[j].Score {
+			return list[i].Id.String() < list[j].Id.String()
+		}
+		return list[i].Score < list[j].Score
+	})
+	c.lists[key] = list
+}
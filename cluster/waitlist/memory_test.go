@@ -0,0 +1,191 @@
+package waitlist
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+func newMemoryCluster() Cluster {
+	return NewMemory(fmt.Sprintf("memory_%d", rand.Int63()))
+}
+
+func newEntry(id string, score float64) s.WaitlistEntry {
+	return s.WaitlistEntry{
+		Id:    bs.Key(id),
+		Score: score,
+	}
+}
+
+func TestMemoryJoin(t *testing.T) {
+	var (
+		cluster = newMemoryCluster()
+		key     = bs.Key("key")
+	)
+
+	for k, v := range []s.WaitlistEntry{
+		newEntry("a", 1),
+		newEntry("b", 2),
+		newEntry("c", 3),
+	} {
+		position, err := cluster.Join(key, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := k+1, position; expected != actual {
+			t.Errorf("Expected: %v, Actual: %v", expected, actual)
+		}
+	}
+
+	// Joining again keeps the position.
+	position, err := cluster.Join(key, newEntry("a", 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 1, position; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	position, total, ok, err := cluster.Position(key, bs.Key("c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := true, ok; expected != actual {
+		t.Fatalf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 3, position; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 3, total; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemoryPeek(t *testing.T) {
+	var (
+		cluster = newMemoryCluster()
+		key     = bs.Key("key")
+	)
+
+	cluster.Join(key, newEntry("b", 2))
+	cluster.Join(key, newEntry("a", 1))
+
+	for i := 0; i < 2; i++ {
+		entry, ok, err := cluster.Peek(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := true, ok; expected != actual {
+			t.Fatalf("Expected: %v, Actual: %v", expected, actual)
+		}
+		if expected, actual := bs.Key("a"), entry.Id; expected != actual {
+			t.Errorf("Expected: %v, Actual: %v", expected, actual)
+		}
+	}
+
+	// The head is still waiting until it leaves.
+	position, total, ok, err := cluster.Position(key, bs.Key("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := true, ok; expected != actual {
+		t.Fatalf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 1, position; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 2, total; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemoryAcquire(t *testing.T) {
+	var (
+		cluster = newMemoryCluster()
+		key     = bs.Key("key")
+	)
+
+	for k, v := range []struct {
+		token    string
+		acquired bool
+	}{
+		{"a", true},
+		{"a", true},
+		{"b", false},
+	} {
+		ok, err := cluster.Acquire(key, v.token, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := v.acquired, ok; expected != actual {
+			t.Errorf("%d: Expected: %v, Actual: %v", k, expected, actual)
+		}
+	}
+
+	// The promote that was turned away is pending for the holder.
+	for _, expected := range []bool{true, false} {
+		pending, err := cluster.Release(key, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := pending; expected != actual {
+			t.Errorf("Expected: %v, Actual: %v", expected, actual)
+		}
+	}
+
+	ok, err := cluster.Acquire(key, "b", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := true, ok; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemoryAcquireExpired(t *testing.T) {
+	var (
+		cluster = newMemoryCluster()
+		key     = bs.Key("key")
+	)
+
+	if _, err := cluster.Acquire(key, "a", time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+
+	ok, err := cluster.Acquire(key, "b", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := true, ok; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestMemoryLeave(t *testing.T) {
+	var (
+		cluster = newMemoryCluster()
+		key     = bs.Key("key")
+	)
+
+	cluster.Join(key, newEntry("a", 1))
+
+	for _, expected := range []bool{true, false} {
+		ok, err := cluster.Leave(key, bs.Key("a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := ok; expected != actual {
+			t.Errorf("Expected: %v, Actual: %v", expected, actual)
+		}
+	}
+
+	if _, ok, _ := cluster.Peek(key); ok {
+		t.Errorf("Expected: %v, Actual: %v", false, ok)
+	}
+}
@@ -0,0 +1,280 @@
+package coordinator
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/SimonRichardson/echelon/cluster/intent"
+	"github.com/SimonRichardson/echelon/coordinator/strategies"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/farm/counter"
+	"github.com/SimonRichardson/echelon/farm/notifier"
+	"github.com/SimonRichardson/echelon/farm/store"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+// ErrBatchRejected and ErrBatchAborted define the errors where a batch wasn't
+// inserted, either because it was rejected up front or because it was rolled
+// back after a failure.
+var (
+	ErrBatchRejected = typex.Errorf(errors.Source, errors.MaxSize, "Batch Rejected")
+	ErrBatchAborted  = typex.Errorf(errors.Source, errors.Complete, "Batch Aborted")
+)
+
+const (
+	// defaultIntentDeadline is how long a batch has to complete before its
+	// intent is recovered by another instance, which is well past how long
+	// the batch is detached for.
+	defaultIntentDeadline = defaultDetachTimeout * 3
+
+	defaultIntentSize = 100
+)
+
+type batcher struct {
+	s.LifeCycleManager
+
+	co       *Coordinator
+	counter  *counter.Farm
+	store    *store.Farm
+	notifier *notifier.Farm
+	intents  intent.Cluster
+	strategy strategies.InsertStrategy
+}
+
+func newBatcher(co *Coordinator,
+	counter *counter.Farm,
+	store *store.Farm,
+	notifier *notifier.Farm,
+	intents intent.Cluster,
+	strategy strategies.InsertStrategy,
+) *batcher {
+	return &batcher{
+		LifeCycleManager: newLifeCycleService(),
+
+		co:       co,
+		counter:  counter,
+		store:    store,
+		notifier: notifier,
+		intents:  intents,
+		strategy: strategy,
+	}
+}
+
+// Batch inserts the members of multiple keys, so that either all of them are
+// inserted or none of them are. It's a two phase protocol:
+//
+//  1. The intent, every key is checked to make sure it can fit all of its
+//     members, before the members are written to the store.
+//  2. The commit, the members are then written to the counter.
+//
+// If either phase fails, then the members that have already been written are
+// rolled back from both the store and the counter. The intent is held outside
+// of the instance until the batch completes, so that if the instance goes away
+// part way through, the batch is rolled back by another one.
+func (b *batcher) Batch(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) (int, error) {
+	var (
+		instr   = b.co.instrumentation
+		buckets = s.KeyFieldScoreTxnValues(members).Bucketize()
+		keys    = sortedBucketKeys(buckets)
+	)
+
+	// A batch can't be partially accepted, so every key has to fit all of its
+	// members.
+	sized, err := b.strategy(ctx, b.counter, buckets, sizeExpiry)
+	if err != nil {
+		return 0, typex.Errorf(errors.Source, errors.MaxSize,
+			"Batch Rejected (%s)", err.Error())
+	}
+	for _, k := range keys {
+		if len(sized[k]) != len(buckets[k]) {
+			return 0, ErrBatchRejected
+		}
+	}
+
+	// From here on the batch is writing, so it has to run to the end even if
+	// the caller goes away.
+	ctx, cancel := detach(ctx)
+	defer cancel()
+
+	id := bs.Key(bson.NewObjectId().Hex())
+	if err := b.intents.Write(intent.Intent{
+		Id:      id,
+		Members: members,
+		MaxSize: sizeExpiry,
+	}, time.Now().Add(defaultIntentDeadline)); err != nil {
+		return 0, err
+	}
+
+	var (
+		stored  = []bs.Key{}
+		counted = []bs.Key{}
+	)
+	abort := func(key bs.Key, reason string) (int, error) {
+		teleprinter.L.Error().Printf("Batch Aborted (%s, %s)\n", key.String(), reason)
+
+		go instr.InsertPartialFailure()
+
+		// Anything that can't be rolled back now is left to the recovery of
+		// the intent.
+		if b.rollback(buckets, stored, counted, sizeExpiry) {
+			if _, err := b.intents.Remove(id); err != nil {
+				teleprinter.L.Error().Printf("Batch Intent Failure (%s, %s)\n", id.String(), err.Error())
+			}
+		}
+		return 0, ErrBatchAborted
+	}
+
+	// Intent
+	for _, k := range keys {
+		v := buckets[k]
+
+		// The insert could have been written to some of the store even if it
+		// failed, so it's always rolled back.
+		stored = append(stored, k)
+
+		res, err := b.store.Insert(ctx, v, sizeExpiry)
+		if err != nil {
+			return abort(k, err.Error())
+		}
+		if res != len(v) {
+			return abort(k, "partial store insertion")
+		}
+	}
+
+	// Commit
+	result := 0
+	for _, k := range keys {
+		var (
+			v       = buckets[k]
+			updated = false
+		)
+		for j := 0; j < defaultRetryAmount; j++ {
+			if amount, err := b.counter.Insert(ctx, v, sizeExpiry); err == nil && amount == len(v) {
+				updated = true
+				break
+			}
+		}
+		counted = append(counted, k)
+
+		if !updated {
+			return abort(k, "partial counter insertion")
+		}
+		result += len(v)
+	}
+
+	// Removing the intent is what commits the batch, if it's already been
+	// removed then the batch has been recovered in the meantime.
+	if ok, err := b.intents.Remove(id); err != nil {
+		return abort(id, err.Error())
+	} else if !ok {
+		return abort(id, "intent recovered")
+	}
+
+	go b.notifier.Publish(context.Background(), defaultInsertChannel, s.KeyFieldScoreTxnValues(members).KeyFieldScoreSizeExpiry(sizeExpiry))
+	b.co.record(s.ChangeInsert, members)
+
+	return result, nil
+}
+
+// Recover rolls back the batches whose intent has passed its deadline, which
+// only happens if the instance that was writing them went away part way
+// through. A batch that can't be rolled back is claimed again once the lease
+// of the intent is up.
+func (b *batcher) Recover(ctx context.Context) (int, error) {
+	intents, err := b.intents.Due(time.Now(), defaultIntentDeadline, defaultIntentSize)
+	if err != nil {
+		return 0, err
+	}
+
+	recovered := 0
+	for _, v := range intents {
+		var (
+			buckets = s.KeyFieldScoreTxnValues(v.Members).Bucketize()
+			keys    = sortedBucketKeys(buckets)
+		)
+		if !b.rollback(buckets, keys, keys, v.MaxSize) {
+			continue
+		}
+		if _, err := b.intents.Remove(v.Id); err != nil {
+			return recovered, err
+		}
+
+		teleprinter.L.Info().Printf("Batch Recovered (%s)\n", v.Id.String())
+		recovered++
+	}
+	return recovered, nil
+}
+
+// rollback deletes the members that have been written, the score is nudged
+// forward so that the deletion wins over the insertion it's undoing. The
+// rollback has to run even if the batch was cancelled, so it isn't bound to the
+// context of the caller. It returns false if any of it failed.
+func (b *batcher) rollback(buckets map[bs.Key][]s.KeyFieldScoreTxnValue,
+	stored, counted []bs.Key,
+	sizeExpiry s.KeySizeExpiry,
+) bool {
+	var (
+		instr = b.co.instrumentation
+		ok    = true
+	)
+
+	for _, k := range stored {
+		if _, err := b.store.Delete(context.Background(), undo(buckets[k]), sizeExpiry); err != nil {
+			teleprinter.L.Error().Printf("Batch Store Rollback Failure (%s, %s)\n", k.String(), err.Error())
+			go instr.RollbackPartialFailure()
+			ok = false
+		}
+	}
+	for _, k := range counted {
+		if _, err := b.counter.Delete(context.Background(), undo(buckets[k]), sizeExpiry); err != nil {
+			teleprinter.L.Error().Printf("Batch Counter Rollback Failure (%s, %s)\n", k.String(), err.Error())
+			go instr.RollbackPartialFailure()
+			ok = false
+		}
+	}
+	return ok
+}
+
+// recoverBatches recovers the batches that were left part way through, it's
+// run periodically by the manager.
+func (co *Coordinator) recoverBatches() {
+	if err := handle(co, co.batcher, func() {
+		if _, err := co.batcher.Recover(context.Background()); err != nil {
+			teleprinter.L.Error().Printf("Failed to recover batches: %s\n", err.Error())
+		}
+	}); err != nil {
+		teleprinter.L.Error().Printf("Failed to recover batches: %s\n", err.Error())
+	}
+}
+
+func undo(members []s.KeyFieldScoreTxnValue) []s.KeyFieldScoreTxnValue {
+	res := make([]s.KeyFieldScoreTxnValue, 0, len(members))
+	for _, v := range members {
+		v.Score = math.Nextafter(v.Score, math.Inf(1))
+		res = append(res, v)
+	}
+	return res
+}
+
+// sortedBucketKeys returns the keys in a stable order, so that concurrent
+// batches always write the keys in the same order.
+func sortedBucketKeys(buckets map[bs.Key][]s.KeyFieldScoreTxnValue) []bs.Key {
+	keys := make([]string, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+
+	res := make([]bs.Key, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, bs.Key(k))
+	}
+	return res
+}
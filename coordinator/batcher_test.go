@@ -0,0 +1,227 @@
+package coordinator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	alerts "github.com/SimonRichardson/echelon/alertmanager/noop"
+	"github.com/SimonRichardson/echelon/cluster"
+	cc "github.com/SimonRichardson/echelon/cluster/counter"
+	"github.com/SimonRichardson/echelon/cluster/intent"
+	cs "github.com/SimonRichardson/echelon/cluster/store"
+	"github.com/SimonRichardson/echelon/coordinator/strategies"
+	"github.com/SimonRichardson/echelon/env"
+	"github.com/SimonRichardson/echelon/instrumentation/noop"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+type batchBackends struct {
+	batcher *batcher
+	store   cs.Cluster
+	counter cc.Cluster
+	intents intent.Cluster
+}
+
+// newBatchBackends creates a batcher on top of the mem:// backends, named after
+// the test so that every test has its own storage.
+func newBatchBackends(t *testing.T) batchBackends {
+	name := "batcher-" + t.Name()
+
+	e := env.New(nil)
+	e.StoreInstances = "mem://" + name
+	e.CounterInstances = "mem://" + name
+	e.IntentInstances = "mem://" + name
+
+	var (
+		instr = noop.New()
+		alert = alerts.New()
+	)
+
+	store, err := newStoreFarm(e, instr, alert, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter, err := newCounterFarm(e, instr, alert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intents, err := newIntentCluster(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	strategy, err := strategies.NewInsertStrategy(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mutex := &sync.Mutex{}
+	co := &Coordinator{
+		mutex: mutex,
+		cond:  sync.NewCond(mutex),
+
+		instrumentation: instr,
+	}
+
+	return batchBackends{
+		batcher: newBatcher(co, counter, store, nil, intents, strategy),
+		store:   cs.NewMemory(name),
+		counter: cc.NewMemory(name),
+		intents: intents,
+	}
+}
+
+func batchMembers(keys ...string) []s.KeyFieldScoreTxnValue {
+	res := make([]s.KeyFieldScoreTxnValue, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, s.KeyFieldScoreTxnValue{
+			Key:   bs.Key(k),
+			Field: bs.Key("field"),
+			Score: 1,
+			Txn:   bs.Key("txn"),
+			Value: "value",
+		})
+	}
+	return res
+}
+
+func batchSizeExpiry(keys ...string) s.KeySizeExpiry {
+	res := s.KeySizeExpiry{}
+	for _, k := range keys {
+		res[bs.Key(k)] = s.SizeExpiry{Size: 10, Expiry: time.Hour}
+	}
+	return res
+}
+
+// reject deletes the member with a later score, so that the last write wins
+// over any insertion of it.
+func reject(t *testing.T, deleter interface {
+	Delete(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) <-chan cluster.Element
+}, key string) {
+	member := batchMembers(key)
+	member[0].Score = 100
+
+	for e := range deleter.Delete(context.Background(), member, batchSizeExpiry(key)) {
+		if err := cluster.ErrorFromElement(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func inserted(t *testing.T, scorer interface {
+	Score([]s.KeyFieldTxnValue) (map[s.KeyFieldTxnValue]s.Presence, error)
+}, key string) bool {
+	members := s.KeyFieldScoreTxnValues(batchMembers(key)).KeyFieldTxnValues()
+
+	presence, err := scorer.Score(members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return presence[members[0]].Inserted
+}
+
+func TestBatchRollsBackEveryKeyOnAFailedStoreWrite(t *testing.T) {
+	b := newBatchBackends(t)
+	reject(t, b.store, "c")
+
+	keys := []string{"a", "b", "c"}
+	if _, err := b.batcher.Batch(context.Background(), batchMembers(keys...), batchSizeExpiry(keys...)); err != ErrBatchAborted {
+		t.Fatalf("Expected: %v, Actual: %v", ErrBatchAborted, err)
+	}
+
+	for _, k := range keys {
+		if inserted(t, b.store, k) {
+			t.Errorf("Expected %q to be rolled back from the store", k)
+		}
+		if inserted(t, b.counter, k) {
+			t.Errorf("Expected %q to be rolled back from the counter", k)
+		}
+	}
+}
+
+func TestBatchRollsBackEveryKeyOnAFailedCounterWrite(t *testing.T) {
+	b := newBatchBackends(t)
+	reject(t, b.counter, "c")
+
+	keys := []string{"a", "b", "c"}
+	if _, err := b.batcher.Batch(context.Background(), batchMembers(keys...), batchSizeExpiry(keys...)); err != ErrBatchAborted {
+		t.Fatalf("Expected: %v, Actual: %v", ErrBatchAborted, err)
+	}
+
+	for _, k := range keys {
+		if inserted(t, b.store, k) {
+			t.Errorf("Expected %q to be rolled back from the store", k)
+		}
+		if inserted(t, b.counter, k) {
+			t.Errorf("Expected %q to be rolled back from the counter", k)
+		}
+	}
+
+	// The batch was rolled back, so the intent is no longer needed.
+	due, err := b.intents.Due(time.Now().Add(defaultIntentDeadline*2), time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 0, len(due); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestBatchRecoverRollsBackAnExpiredIntent(t *testing.T) {
+	b := newBatchBackends(t)
+
+	var (
+		keys       = []string{"a", "b"}
+		members    = batchMembers(keys...)
+		sizeExpiry = batchSizeExpiry(keys...)
+	)
+
+	// The instance went away after writing the batch, but before committing
+	// it.
+	if err := b.intents.Write(intent.Intent{
+		Id:      bs.Key(bson.NewObjectId().Hex()),
+		Members: members,
+		MaxSize: sizeExpiry,
+	}, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []<-chan cluster.Element{
+		b.store.Insert(context.Background(), members, sizeExpiry),
+		b.counter.Insert(context.Background(), members, sizeExpiry),
+	} {
+		for element := range e {
+			if err := cluster.ErrorFromElement(element); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	recovered, err := b.batcher.Recover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 1, recovered; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	for _, k := range keys {
+		if inserted(t, b.store, k) {
+			t.Errorf("Expected %q to be rolled back from the store", k)
+		}
+		if inserted(t, b.counter, k) {
+			t.Errorf("Expected %q to be rolled back from the counter", k)
+		}
+	}
+
+	// Once recovered, the intent isn't due again.
+	if recovered, err = b.batcher.Recover(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 0, recovered; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
@@ -0,0 +1,56 @@
+package coordinator
+
+import (
+	"context"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	"github.com/SimonRichardson/echelon/schemas/records"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+// record writes the changes to the change feed in the background, so that the
+// feed never slows down or fails a write. As it outlives the write, it's not
+// bound to the context of the caller.
+func (co *Coordinator) record(change s.ChangeType, members []s.KeyFieldScoreTxnValue) {
+	var (
+		changes = s.KeyFieldScoreTxnValues(members).Changes(change)
+		maxSize = co.changesSize
+	)
+	go func() {
+		if err := co.notifier.Record(context.Background(), changes, maxSize); err != nil {
+			teleprinter.L.Error().Printf("Failed to record %s changes: %s\n",
+				change.String(), err.Error())
+		}
+	}()
+}
+
+// transition publishes the member on the channel of the state it moved to and
+// records the change, so that anything waiting on a reservation (e.g. a
+// confirmation) can be told about it.
+func (co *Coordinator) transition(state records.State, member s.KeyFieldScoreTxnValue, maxSize s.SizeExpiry) {
+	var (
+		members = []s.KeyFieldScoreTxnValue{member}
+		changes = s.KeyFieldScoreTxnValues(members).Changes(s.ChangeState)
+		size    = s.KeySizeExpiry{member.Key: maxSize}
+	)
+	for k := range changes {
+		changes[k].State = state.String()
+	}
+
+	go func() {
+		ctx := context.Background()
+		if err := co.notifier.Publish(ctx, stateChannel(state), s.KeyFieldScoreTxnValues(members).KeyFieldScoreSizeExpiry(size)); err != nil {
+			teleprinter.L.Error().Printf("Failed to publish %s state: %s\n",
+				state.String(), err.Error())
+		}
+		if err := co.notifier.Record(ctx, changes, co.changesSize); err != nil {
+			teleprinter.L.Error().Printf("Failed to record %s changes: %s\n",
+				s.ChangeState.String(), err.Error())
+		}
+	}()
+}
+
+// stateChannel returns the channel that members are published on, when they
+// move to the state.
+func stateChannel(state records.State) s.Channel {
+	return s.Channel("state:" + state.String())
+}
@@ -0,0 +1,22 @@
+package coordinator
+
+import (
+	"github.com/SimonRichardson/echelon/coordinator/strategies"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+// stampValues runs the scores of all the values through the clock strategy, so
+// that they're either stamped or validated before they hit the stores.
+func stampValues(strategy strategies.ClockStrategy, values []s.KeyFieldScoreTxnValue) ([]s.KeyFieldScoreTxnValue, error) {
+	result := make([]s.KeyFieldScoreTxnValue, 0, len(values))
+	for _, v := range values {
+		score, err := strategy.Stamp(v.Score)
+		if err != nil {
+			return nil, err
+		}
+
+		v.Score = score
+		result = append(result, v)
+	}
+	return result, nil
+}
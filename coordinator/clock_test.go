@@ -0,0 +1,158 @@
+package coordinator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SimonRichardson/echelon/coordinator/strategies"
+	"github.com/SimonRichardson/echelon/env"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/instrumentation/noop"
+	"github.com/SimonRichardson/echelon/internal/clocks"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+type recorder struct {
+	values []s.KeyFieldScoreTxnValue
+}
+
+func (r *recorder) Insert(ctx context.Context, values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	r.values = append(r.values, values...)
+	return len(values), nil
+}
+
+func (r *recorder) Delete(ctx context.Context, values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	r.values = append(r.values, values...)
+	return len(values), nil
+}
+
+func (r *recorder) Rollback(ctx context.Context, values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
+	r.values = append(r.values, values...)
+	return nil
+}
+
+func newClockCoordinator(t *testing.T, strategy, maxSkew string) (*Coordinator, *recorder) {
+	e := env.New(nil)
+	e.ClockStrategy = strategy
+	e.ClockMaxSkew = maxSkew
+
+	clock, err := strategies.NewClockStrategy(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mutex = &sync.Mutex{}
+		rec   = &recorder{}
+	)
+	return &Coordinator{
+		mutex: mutex,
+		cond:  sync.NewCond(mutex),
+
+		inserter: rec,
+		deleter:  rec,
+
+		clock: clock,
+
+		instrumentation: noop.New(),
+	}, rec
+}
+
+func member(score float64) []s.KeyFieldScoreTxnValue {
+	return []s.KeyFieldScoreTxnValue{
+		s.KeyFieldScoreTxnValue{
+			Key:   bs.Key("key"),
+			Field: bs.Key("field"),
+			Score: score,
+			Txn:   bs.Key("txn"),
+			Value: "value",
+		},
+	}
+}
+
+func isClockSkew(err error) bool {
+	e, ok := err.(*typex.Error)
+	return ok && e.Is(errors.ClockSkew.Name())
+}
+
+func TestCoordinatorClockStampReplacesClientScores(t *testing.T) {
+	co, rec := newClockCoordinator(t, "Stamp", "1s")
+
+	if _, err := co.Insert(context.Background(), member(1), s.MakeKeySizeExpiry()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.values) != 1 {
+		t.Fatalf("Expected 1 value, got %d", len(rec.values))
+	}
+	if stamped := clocks.FromScore(rec.values[0].Score).Time(); time.Since(stamped) > time.Second {
+		t.Errorf("Expected score to be stamped with now, got %s", stamped)
+	}
+}
+
+func TestCoordinatorClockStampKeepsExpiredScores(t *testing.T) {
+	co, rec := newClockCoordinator(t, "Stamp", "1s")
+
+	if _, err := co.Expire(context.Background(), member(2), s.MakeKeySizeExpiry()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.values) != 1 || rec.values[0].Score != 2 {
+		t.Errorf("Expected the expired score to be kept, got %v", rec.values)
+	}
+}
+
+func TestCoordinatorClockValidateAcceptsScoresWithinSkew(t *testing.T) {
+	co, rec := newClockCoordinator(t, "Validate", "1s")
+
+	score := clocks.New().Now().Score()
+	if _, err := co.Insert(context.Background(), member(score), s.MakeKeySizeExpiry()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.values) != 1 || rec.values[0].Score != score {
+		t.Errorf("Expected the score to be kept, got %v", rec.values)
+	}
+}
+
+func TestCoordinatorClockValidateRejectsDriftedScores(t *testing.T) {
+	co, rec := newClockCoordinator(t, "Validate", "1s")
+
+	var (
+		past   = clocks.Timestamp{Physical: time.Now().Add(-time.Minute).UnixNano() / int64(time.Millisecond)}
+		future = float64(time.Now().UnixNano())
+	)
+	for _, score := range []float64{past.Score(), future} {
+		if _, err := co.Delete(context.Background(), member(score), s.MakeKeySizeExpiry()); !isClockSkew(err) {
+			t.Errorf("Expected clock skew error, got %v", err)
+		}
+	}
+
+	if len(rec.values) != 0 {
+		t.Errorf("Expected no values to be written, got %v", rec.values)
+	}
+}
+
+func TestCoordinatorClockValidateAcceptsInternalWrites(t *testing.T) {
+	co, rec := newClockCoordinator(t, "Validate", "1s")
+
+	// The sweeper deletes with the stored score plus one, which is by now older
+	// than the max skew.
+	past := clocks.Timestamp{Physical: time.Now().Add(-time.Hour).UnixNano() / int64(time.Millisecond)}
+	if _, err := co.Expire(context.Background(), member(past.Score()+1), s.MakeKeySizeExpiry()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The holds that are offered to a waiting list are scored by the clock.
+	if _, err := co.Insert(context.Background(), member(co.clock.Now()), s.MakeKeySizeExpiry()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.values) != 2 {
+		t.Errorf("Expected 2 values to be written, got %v", rec.values)
+	}
+}
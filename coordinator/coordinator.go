@@ -1,6 +1,7 @@
 package coordinator
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -8,6 +9,8 @@ import (
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	"github.com/SimonRichardson/echelon/internal/services/consul"
 	"github.com/SimonRichardson/echelon/alertmanager"
+	"github.com/SimonRichardson/echelon/cluster/intent"
+	"github.com/SimonRichardson/echelon/cluster/waitlist"
 	"github.com/SimonRichardson/echelon/coordinator/strategies"
 	"github.com/SimonRichardson/echelon/env"
 	"github.com/SimonRichardson/echelon/errors"
@@ -24,10 +27,17 @@ import (
 const (
 	defaultDebugExeceptions = false
 
-	defaultInsertChannel = s.Channel("insert")
+	defaultInsertChannel  = s.Channel("insert")
+	defaultPromoteChannel = s.Channel("promote")
 
 	defaultQuitTicker  = time.Millisecond * 10
 	defaultQuitTimeout = time.Second * 30
+
+	defaultDetachTimeout = time.Second * 10
+
+	// defaultPromoteExpiry is how long the lock of a promoter outlives it, if
+	// it goes away whilst offering a hold.
+	defaultPromoteExpiry = defaultDetachTimeout * 3
 )
 
 var (
@@ -62,19 +72,25 @@ type Coordinator struct {
 
 	storeOpts *r.Options
 
-	selector  s.Selector
-	inserter  s.Inserter
-	modifier  s.Modifier
-	deleter   s.Deleter
-	repairer  s.Repairer
-	scanner   s.Scanner
-	inspector s.Inspector
-	manager   s.Manager
-	service   s.Manager
+	changesSize int
+
+	selector   s.Selector
+	inserter   s.Inserter
+	batcher    s.Batcher
+	modifier   s.Modifier
+	deleter    s.Deleter
+	repairer   s.Repairer
+	scanner    s.Scanner
+	inspector  s.Inspector
+	waitlister s.Waitlister
+	manager    s.Manager
+	service    s.Manager
 
 	accessor    s.Accessor
 	transformer s.Transformer
 
+	clock strategies.ClockStrategy
+
 	managers []s.LifeCycleManager
 
 	instrumentation instrumentation.Instrumentation
@@ -134,9 +150,13 @@ func (co *Coordinator) init(e *env.Env) error {
 
 		storeOpts *r.Options
 
+		waitlist waitlist.Cluster
+		intents  intent.Cluster
+
 		insertStrategy  strategies.InsertStrategy
 		repairStrategy  strategies.RepairStrategy
 		managerStrategy strategies.ManagerStrategyCreator
+		clockStrategy   strategies.ClockStrategy
 
 		err error
 	)
@@ -145,21 +165,29 @@ func (co *Coordinator) init(e *env.Env) error {
 		return err
 	}
 
-	if counter, err = newCounterFarm(e, co.instrumentation); err != nil {
+	if counter, err = newCounterFarm(e, co.instrumentation, co.alertmanager); err != nil {
 		return err
 	}
 
 	storeOpts = newStoreOptions(e, consul)
 
-	if store, err = newStoreFarm(e, co.instrumentation, storeOpts); err != nil {
+	if store, err = newStoreFarm(e, co.instrumentation, co.alertmanager, storeOpts); err != nil {
+		return err
+	}
+
+	if notifier, err = newNotifierFarm(e, co.instrumentation, co.alertmanager); err != nil {
 		return err
 	}
 
-	if notifier, err = newNotifierFarm(e, co.instrumentation); err != nil {
+	if persistence, err = newPersistenceFarm(e, co.instrumentation, co.alertmanager, co.transformer); err != nil {
 		return err
 	}
 
-	if persistence, err = newPersistenceFarm(e, co.instrumentation, co.transformer); err != nil {
+	if waitlist, err = newWaitlistCluster(e); err != nil {
+		return err
+	}
+
+	if intents, err = newIntentCluster(e); err != nil {
 		return err
 	}
 
@@ -175,6 +203,10 @@ func (co *Coordinator) init(e *env.Env) error {
 		return err
 	}
 
+	if clockStrategy, err = strategies.NewClockStrategy(e); err != nil {
+		return err
+	}
+
 	co.consul = consul
 
 	co.counter = counter
@@ -184,15 +216,22 @@ func (co *Coordinator) init(e *env.Env) error {
 
 	co.storeOpts = storeOpts
 
+	co.changesSize = e.NotifierChangesSize
+
+	co.clock = clockStrategy
+
 	var (
 		selector  = newSelector(co, store)
 		inserter  = newInserter(co, counter, store, notifier, insertStrategy)
+		batcher   = newBatcher(co, counter, store, notifier, intents, insertStrategy)
 		modifier  = newModifier(co, store, persistence, co.accessor)
 		deleter   = newDeleter(co, counter, store)
 		repairer  = newRepairer(co, store, repairStrategy)
 		scanner   = newScanner(co, counter)
 		inspector = newInspector(co, store, co.transformer)
 
+		waitlister = newWaitlister(co, counter, waitlist, e.WaitlistHoldExpiry)
+
 		manager = newManager(co, counter, store, notifier, managerStrategy)
 
 		service = newService(co, consul, e.ConsulHeartbeatFrequency)
@@ -200,11 +239,13 @@ func (co *Coordinator) init(e *env.Env) error {
 
 	co.selector = selector
 	co.inserter = inserter
+	co.batcher = batcher
 	co.modifier = modifier
 	co.deleter = deleter
 	co.repairer = repairer
 	co.scanner = counter
 	co.inspector = inspector
+	co.waitlister = waitlister
 
 	co.manager = manager
 	go co.manager.Start()
@@ -215,11 +256,13 @@ func (co *Coordinator) init(e *env.Env) error {
 	co.managers = []s.LifeCycleManager{
 		selector,
 		inserter,
+		batcher,
 		modifier,
 		deleter,
 		repairer,
 		scanner,
 		inspector,
+		waitlister,
 	}
 
 	co.paused = false
@@ -265,13 +308,36 @@ func handle(co *Coordinator, cycle interface{}, f func()) (err error) {
 }
 
 // Insert represents a way to insert various values into the store.
-func (co *Coordinator) Insert(values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (res int, err error) {
+func (co *Coordinator) Insert(ctx context.Context, values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (res int, err error) {
 	if e := handle(co, co.inserter, func() {
 		began := time.Now()
 		go co.instrumentation.AInsertCall()
 		defer func() { go co.instrumentation.AInsertDuration(time.Since(began)) }()
 
-		res, err = co.inserter.Insert(values, maxSize)
+		if values, err = stampValues(co.clock, values); err != nil {
+			return
+		}
+
+		res, err = co.inserter.Insert(ctx, values, maxSize)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// Batch represents a way to insert various values over multiple keys into the
+// store, either all of the values are inserted or none of them are.
+func (co *Coordinator) Batch(ctx context.Context, values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (res int, err error) {
+	if e := handle(co, co.batcher, func() {
+		began := time.Now()
+		go co.instrumentation.ABatchCall()
+		defer func() { go co.instrumentation.ABatchDuration(time.Since(began)) }()
+
+		if values, err = stampValues(co.clock, values); err != nil {
+			return
+		}
+
+		res, err = co.batcher.Batch(ctx, values, maxSize)
 	}); e != nil {
 		err = e
 	}
@@ -279,13 +345,17 @@ func (co *Coordinator) Insert(values []s.KeyFieldScoreTxnValue, maxSize s.KeySiz
 }
 
 // Modify represents a way to modify various values into the store.
-func (co *Coordinator) Modify(values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (res int, err error) {
+func (co *Coordinator) Modify(ctx context.Context, values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (res int, err error) {
 	if e := handle(co, co.modifier, func() {
 		began := time.Now()
 		go co.instrumentation.AModifyCall()
 		defer func() { go co.instrumentation.AModifyDuration(time.Since(began)) }()
 
-		res, err = co.modifier.Modify(values, maxSize)
+		if values, err = stampValues(co.clock, values); err != nil {
+			return
+		}
+
+		res, err = co.modifier.Modify(ctx, values, maxSize)
 	}); e != nil {
 		err = e
 	}
@@ -294,13 +364,17 @@ func (co *Coordinator) Modify(values []s.KeyFieldScoreTxnValue, maxSize s.KeySiz
 
 // ModifyWithOperations represents a way to modify various values into the
 // store.
-func (co *Coordinator) ModifyWithOperations(key, id bs.Key, ops []s.Operation, score float64, maxSize s.SizeExpiry) (res int, err error) {
+func (co *Coordinator) ModifyWithOperations(ctx context.Context, key, id bs.Key, ops []s.Operation, score float64, maxSize s.SizeExpiry) (res int, err error) {
 	if e := handle(co, co.modifier, func() {
 		began := time.Now()
 		go co.instrumentation.AModifyWithOperationsCall()
 		defer func() { go co.instrumentation.AModifyWithOperationsDuration(time.Since(began)) }()
 
-		res, err = co.modifier.ModifyWithOperations(key, id, ops, score, maxSize)
+		if score, err = co.clock.Stamp(score); err != nil {
+			return
+		}
+
+		res, err = co.modifier.ModifyWithOperations(ctx, key, id, ops, score, maxSize)
 	}); e != nil {
 		err = e
 	}
@@ -308,13 +382,34 @@ func (co *Coordinator) ModifyWithOperations(key, id bs.Key, ops []s.Operation, s
 }
 
 // Delete represents a way to delete various values into the store.
-func (co *Coordinator) Delete(values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (res int, err error) {
+func (co *Coordinator) Delete(ctx context.Context, values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (res int, err error) {
 	if e := handle(co, co.deleter, func() {
 		began := time.Now()
 		go co.instrumentation.ADeleteCall()
 		defer func() { go co.instrumentation.ADeleteDuration(time.Since(began)) }()
 
-		res, err = co.deleter.Delete(values, maxSize)
+		if values, err = stampValues(co.clock, values); err != nil {
+			return
+		}
+
+		res, err = co.deleter.Delete(ctx, values, maxSize)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// Expire deletes the values that the manager has found to be expired. Unlike
+// Delete the values keep their scores, which are one more than the score that
+// was read, as they're not written by a client and would otherwise be rejected
+// by the clock strategy for having drifted.
+func (co *Coordinator) Expire(ctx context.Context, values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (res int, err error) {
+	if e := handle(co, co.deleter, func() {
+		began := time.Now()
+		go co.instrumentation.ADeleteCall()
+		defer func() { go co.instrumentation.ADeleteDuration(time.Since(began)) }()
+
+		res, err = co.deleter.Delete(ctx, values, maxSize)
 	}); e != nil {
 		err = e
 	}
@@ -322,13 +417,17 @@ func (co *Coordinator) Delete(values []s.KeyFieldScoreTxnValue, maxSize s.KeySiz
 }
 
 // Rollback represents a way to rollback various values into the store.
-func (co *Coordinator) Rollback(values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (err error) {
+func (co *Coordinator) Rollback(ctx context.Context, values []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (err error) {
 	if e := handle(co, co.deleter, func() {
 		began := time.Now()
 		go co.instrumentation.ARollbackCall()
 		defer func() { go co.instrumentation.ARollbackDuration(time.Since(began)) }()
 
-		err = co.deleter.Rollback(values, maxSize)
+		if values, err = stampValues(co.clock, values); err != nil {
+			return
+		}
+
+		err = co.deleter.Rollback(ctx, values, maxSize)
 	}); e != nil {
 		err = e
 	}
@@ -336,13 +435,13 @@ func (co *Coordinator) Rollback(values []s.KeyFieldScoreTxnValue, maxSize s.KeyS
 }
 
 // Select represents a way to request and select a member from the store.
-func (co *Coordinator) Select(key, field bs.Key) (res s.KeyFieldScoreTxnValue, err error) {
+func (co *Coordinator) Select(ctx context.Context, key, field bs.Key) (res s.KeyFieldScoreTxnValue, err error) {
 	if e := handle(co, co.selector, func() {
 		began := time.Now()
 		go co.instrumentation.ASelectCall()
 		defer func() { go co.instrumentation.ASelectDuration(time.Since(began)) }()
 
-		res, err = co.selector.Select(key, field)
+		res, err = co.selector.Select(ctx, key, field)
 	}); e != nil {
 		err = e
 	}
@@ -351,13 +450,13 @@ func (co *Coordinator) Select(key, field bs.Key) (res s.KeyFieldScoreTxnValue, e
 
 // SelectRange represents a way to request and select a range of members from
 // the store that are under a certain limit.
-func (co *Coordinator) SelectRange(key bs.Key, limit int, maxSize s.KeySizeExpiry) (res []s.KeyFieldScoreTxnValue, err error) {
+func (co *Coordinator) SelectRange(ctx context.Context, key bs.Key, limit int, maxSize s.KeySizeExpiry) (res []s.KeyFieldScoreTxnValue, err error) {
 	if e := handle(co, co.selector, func() {
 		began := time.Now()
 		go co.instrumentation.ASelectRangeCall()
 		defer func() { go co.instrumentation.ASelectRangeDuration(time.Since(began)) }()
 
-		res, err = co.selector.SelectRange(key, limit, maxSize)
+		res, err = co.selector.SelectRange(ctx, key, limit, maxSize)
 	}); e != nil {
 		err = e
 	}
@@ -365,13 +464,13 @@ func (co *Coordinator) SelectRange(key bs.Key, limit int, maxSize s.KeySizeExpir
 }
 
 // Keys defines a way to query the store for all the keys with in it.
-func (co *Coordinator) Keys() (res []bs.Key, err error) {
+func (co *Coordinator) Keys(ctx context.Context) (res []bs.Key, err error) {
 	if e := handle(co, co.scanner, func() {
 		began := time.Now()
 		go co.instrumentation.AKeysCall()
 		defer func() { go co.instrumentation.AKeysDuration(time.Since(began)) }()
 
-		res, err = co.scanner.Keys()
+		res, err = co.scanner.Keys(ctx)
 	}); e != nil {
 		err = e
 	}
@@ -379,13 +478,13 @@ func (co *Coordinator) Keys() (res []bs.Key, err error) {
 }
 
 // Size returns the size of the collection with in the store.
-func (co *Coordinator) Size(key bs.Key) (res int, err error) {
+func (co *Coordinator) Size(ctx context.Context, key bs.Key) (res int, err error) {
 	if e := handle(co, co.scanner, func() {
 		began := time.Now()
 		go co.instrumentation.ASizeCall()
 		defer func() { go co.instrumentation.ASizeDuration(time.Since(began)) }()
 
-		res, err = co.scanner.Size(key)
+		res, err = co.scanner.Size(ctx, key)
 	}); e != nil {
 		err = e
 	}
@@ -393,13 +492,13 @@ func (co *Coordinator) Size(key bs.Key) (res int, err error) {
 }
 
 // Members represents all the items with in the store for a particular key.
-func (co *Coordinator) Members(key bs.Key) (res []bs.Key, err error) {
+func (co *Coordinator) Members(ctx context.Context, key bs.Key) (res []bs.Key, err error) {
 	if e := handle(co, co.scanner, func() {
 		began := time.Now()
 		go co.instrumentation.AMembersCall()
 		defer func() { go co.instrumentation.AMembersDuration(time.Since(began)) }()
 
-		res, err = co.scanner.Members(key)
+		res, err = co.scanner.Members(ctx, key)
 	}); e != nil {
 		err = e
 	}
@@ -408,13 +507,86 @@ func (co *Coordinator) Members(key bs.Key) (res []bs.Key, err error) {
 
 // Repair defines a way to request a possible repair of the store of a
 // particular key.
-func (co *Coordinator) Repair(elements []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) (err error) {
+func (co *Coordinator) Repair(ctx context.Context, elements []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) (err error) {
 	if e := handle(co, co.repairer, func() {
 		began := time.Now()
 		go co.instrumentation.ARepairCall()
 		defer func() { go co.instrumentation.ARepairDuration(time.Since(began)) }()
 
-		err = co.repairer.Repair(elements, maxSize)
+		err = co.repairer.Repair(ctx, elements, maxSize)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// Sync performs anti-entropy on a key with in the store, by comparing the hash
+// trees of every cluster and only repairing the buckets that differ.
+func (co *Coordinator) Sync(ctx context.Context, key bs.Key, buckets int, maxSize s.KeySizeExpiry) (res int, err error) {
+	if e := handle(co, co.store, func() {
+		began := time.Now()
+		go co.instrumentation.ASyncCall()
+		defer func() { go co.instrumentation.ASyncDuration(time.Since(began)) }()
+
+		res, err = co.store.Sync(ctx, key, buckets, maxSize)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// Reindex rebuilds the secondary indexes of a key that are missing or stale,
+// returning the number of fields that were rebuilt.
+func (co *Coordinator) Reindex(ctx context.Context, key bs.Key) (res int, err error) {
+	if e := handle(co, co.store, func() {
+		began := time.Now()
+		go co.instrumentation.AReindexCall()
+		defer func() { go co.instrumentation.AReindexDuration(time.Since(began)) }()
+
+		res, err = co.store.Reindex(ctx, key)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// RollbackTransaction rolls back all the members of a key that were inserted
+// by the transaction, which are resolved via the transaction index. It returns
+// the number of members that were rolled back.
+func (co *Coordinator) RollbackTransaction(ctx context.Context, key, txn bs.Key,
+	score float64,
+	maxSize s.KeySizeExpiry,
+) (res int, err error) {
+	var values []s.KeyFieldScoreTxnValue
+	if e := handle(co, co.store, func() {
+		var members []s.KeyFieldScoreTxnValue
+		if members, err = indexedMembers(ctx, co.store, key, s.TransactionIndex, txn, maxSize[key]); err != nil {
+			return
+		}
+
+		values, err = rollbackMembers(members, score)
+	}); e != nil {
+		err = e
+	}
+	if err != nil || len(values) < 1 {
+		return
+	}
+
+	if err = co.Rollback(ctx, values, maxSize); err != nil {
+		return
+	}
+	return len(values), nil
+}
+
+// Changes returns the changes (inserts, deletes and rollbacks) of a key that
+// have a score greater than since, ordered by their score.
+func (co *Coordinator) Changes(ctx context.Context, key bs.Key, since float64, limit int) (res []s.Change, err error) {
+	if e := handle(co, co.notifier, func() {
+		began := time.Now()
+		go co.instrumentation.AChangesCall()
+		defer func() { go co.instrumentation.AChangesDuration(time.Since(began)) }()
+
+		res, err = co.notifier.Changes(ctx, key, since, limit)
 	}); e != nil {
 		err = e
 	}
@@ -423,7 +595,7 @@ func (co *Coordinator) Repair(elements []s.KeyFieldTxnValue, maxSize s.KeySizeEx
 
 // Query defines a way to request a possible query of the store of a
 // particular key.
-func (co *Coordinator) Query(key bs.Key,
+func (co *Coordinator) Query(ctx context.Context, key bs.Key,
 	options s.QueryOptions,
 	maxSize s.SizeExpiry,
 ) (res []s.QueryRecord, err error) {
@@ -432,14 +604,74 @@ func (co *Coordinator) Query(key bs.Key,
 		go co.instrumentation.AQueryCall()
 		defer func() { go co.instrumentation.AQueryDuration(time.Since(began)) }()
 
-		res, err = co.inspector.Query(key, options, maxSize)
+		res, err = co.inspector.Query(ctx, key, options, maxSize)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// Join adds the entry to the waiting list of a key, returning the position of
+// the entry. An entry that was offered a hold straight away has a position of
+// 0.
+func (co *Coordinator) Join(ctx context.Context, key bs.Key, entry s.WaitlistEntry) (res int, err error) {
+	if e := handle(co, co.waitlister, func() {
+		began := time.Now()
+		go co.instrumentation.AWaitlistCall()
+		defer func() { go co.instrumentation.AWaitlistDuration(time.Since(began)) }()
+
+		res, err = co.waitlister.Join(ctx, key, entry)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// Leave removes the entry from the waiting list of a key.
+func (co *Coordinator) Leave(ctx context.Context, key, id bs.Key) (res bool, err error) {
+	if e := handle(co, co.waitlister, func() {
+		began := time.Now()
+		go co.instrumentation.AWaitlistCall()
+		defer func() { go co.instrumentation.AWaitlistDuration(time.Since(began)) }()
+
+		res, err = co.waitlister.Leave(ctx, key, id)
 	}); e != nil {
 		err = e
 	}
 	return
 }
 
-func (co *Coordinator) Lock(ns b.Namespace) (b.SemaphoreUnlock, error) {
+// Position returns the position of the entry with in the waiting list of a
+// key, along with how many entries are waiting.
+func (co *Coordinator) Position(ctx context.Context, key, id bs.Key) (position, total int, ok bool, err error) {
+	if e := handle(co, co.waitlister, func() {
+		began := time.Now()
+		go co.instrumentation.AWaitlistCall()
+		defer func() { go co.instrumentation.AWaitlistDuration(time.Since(began)) }()
+
+		position, total, ok, err = co.waitlister.Position(ctx, key, id)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// Promote offers the room of a key to the head of its waiting list, for as long
+// as there is room, returning how many entries were offered a hold.
+func (co *Coordinator) Promote(ctx context.Context, key bs.Key) (res int, err error) {
+	if e := handle(co, co.waitlister, func() {
+		began := time.Now()
+		go co.instrumentation.AWaitlistCall()
+		defer func() { go co.instrumentation.AWaitlistDuration(time.Since(began)) }()
+
+		res, err = co.waitlister.Promote(ctx, key)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+func (co *Coordinator) Lock(ns b.Namespace) (b.SemaphoreUnlock, b.SemaphoreLost, error) {
 	return co.consul.Lock(ns)
 }
 
@@ -473,6 +705,7 @@ func (co *Coordinator) Resume() {
 
 	if co.paused {
 		co.paused = false
+		co.manager.Start()
 		co.cond.Signal()
 	}
 }
@@ -507,6 +740,8 @@ func (co *Coordinator) Topology(e *env.Env) error {
 		return err
 	}
 
+	co.changesSize = e.NotifierChangesSize
+
 	if clusters, err := newPersistenceClusters(e, co.transformer); err == nil {
 		if err := co.persistence.Topology(clusters); err != nil {
 			return err
@@ -1,6 +1,9 @@
 package coordinator
 
 import (
+	"context"
+	"time"
+
 	"github.com/SimonRichardson/echelon/farm/counter"
 	"github.com/SimonRichardson/echelon/farm/store"
 	s "github.com/SimonRichardson/echelon/selectors"
@@ -28,7 +31,12 @@ func newDeleter(co *Coordinator, counter *counter.Farm, store *store.Farm) *dele
 	}
 }
 
-func (i *deleter) Delete(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+func (i *deleter) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	// The store and the counter have to agree, so the caller going away can't
+	// stop the deletion half way through.
+	ctx, cancel := detach(ctx)
+	defer cancel()
+
 	// Bucketize the members so that we can effiecently call all the storage
 	// collections.
 	var (
@@ -41,8 +49,8 @@ func (i *deleter) Delete(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExp
 		result         = 0
 		partialFailure = false
 	)
-	for _, v := range buckets {
-		res, err := i.store.Delete(v, maxSize)
+	for k, v := range buckets {
+		res, err := i.store.Delete(ctx, v, maxSize)
 		if err != nil {
 			go instr.DeletePartialFailure()
 			partialFailure = true
@@ -52,7 +60,7 @@ func (i *deleter) Delete(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExp
 		// The counter needs to retry as much as possible to
 		updated := false
 		for j := 0; j < defaultRetryAmount; j++ {
-			if amount, err := i.counter.Delete(v, maxSize); err == nil && res == amount {
+			if amount, err := i.counter.Delete(ctx, v, maxSize); err == nil && res == amount {
 				updated = true
 				break
 			} else if res != amount {
@@ -66,6 +74,8 @@ func (i *deleter) Delete(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExp
 		// Don't report non-updated requests.
 		if updated {
 			result += res
+			i.co.record(s.ChangeDelete, v)
+			i.co.promote(k)
 		}
 	}
 
@@ -76,7 +86,12 @@ func (i *deleter) Delete(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExp
 	return result, nil
 }
 
-func (i *deleter) Rollback(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
+func (i *deleter) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
+	// A rollback is undoing a write, so it has to run to the end even if the
+	// caller goes away.
+	ctx, cancel := detach(ctx)
+	defer cancel()
+
 	var (
 		instr   = i.co.instrumentation
 		buckets = s.KeyFieldScoreTxnValues(members).Bucketize()
@@ -84,24 +99,28 @@ func (i *deleter) Rollback(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeE
 		partialFailure = false
 	)
 
-	for _, v := range buckets {
-		if _, err := i.store.Delete(v, maxSize); err != nil {
+	for k, v := range buckets {
+		if _, err := i.store.Delete(ctx, v, maxSize); err != nil {
 			partialFailure = true
 			go instr.RollbackPartialFailure()
+		} else {
+			i.co.record(s.ChangeRollback, v)
 		}
 
-		if _, err := i.counter.Delete(v, maxSize); err != nil {
+		if _, err := i.counter.Delete(ctx, v, maxSize); err != nil {
 			partialFailure = true
 			go instr.RollbackPartialFailure()
+		} else {
+			i.co.promote(k)
 		}
 
 		values := s.KeyFieldScoreTxnValues(v).KeyFieldScoreSizeExpiry(maxSize)
-		if err := i.co.notifier.Unpublish(defaultInsertChannel, values); err != nil {
+		if err := i.co.notifier.Unpublish(ctx, defaultInsertChannel, values); err != nil {
 			partialFailure = true
 			go instr.RollbackPartialFailure()
 		}
 
-		if err := i.co.persistence.Rollback(v, maxSize); err != nil {
+		if err := i.co.persistence.Rollback(ctx, v, maxSize); err != nil {
 			partialFailure = true
 		}
 	}
@@ -111,3 +130,18 @@ func (i *deleter) Rollback(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeE
 	}
 	return nil
 }
+
+// detach returns a context that's no longer cancelled when the caller goes
+// away, as a write that's been started has to be finished across all of the
+// farms, otherwise the store and the counter no longer agree. It keeps the
+// deadline of the caller if that's longer, otherwise it has one of its own, so
+// that it can't run forever.
+func detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := defaultDetachTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining > timeout {
+			timeout = remaining
+		}
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
@@ -0,0 +1,125 @@
+package coordinator
+
+import (
+	"context"
+	"time"
+
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/farm/store"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/pool"
+	"github.com/SimonRichardson/echelon/schemas/records"
+	s "github.com/SimonRichardson/echelon/selectors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// indexedMembers resolves the members of a key via a secondary index. Every
+// field is selected to make sure it still matches, as the index of a cluster
+// can lag behind. If the store isn't indexed, all the members are read and
+// matched instead.
+func indexedMembers(ctx context.Context, farm *store.Farm,
+	key bs.Key,
+	index s.Index,
+	value bs.Key,
+	sizeExpiry s.SizeExpiry,
+) ([]s.KeyFieldScoreTxnValue, error) {
+	fields, err := farm.Indexed(ctx, key, index, value)
+	if err == store.ErrNotIndexed {
+		members, err := rangeMembers(ctx, farm, key, sizeExpiry)
+		if err != nil {
+			return nil, err
+		}
+		return matchIndex(members, index, value), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	members := make([]s.KeyFieldScoreTxnValue, 0, len(fields))
+	for _, field := range fields {
+		member, err := farm.Select(ctx, key, field)
+		if err != nil {
+			// The field has either expired or been removed since it was
+			// indexed.
+			continue
+		}
+		members = append(members, member)
+	}
+	return matchIndex(members, index, value), nil
+}
+
+// rangeMembers reads all the members of a key.
+func rangeMembers(ctx context.Context, farm *store.Farm, key bs.Key, sizeExpiry s.SizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
+	size, err := farm.Size(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return farm.SelectRange(ctx, key, size, s.KeySizeExpiry{
+		key: sizeExpiry,
+	})
+}
+
+func matchIndex(members []s.KeyFieldScoreTxnValue, index s.Index, value bs.Key) []s.KeyFieldScoreTxnValue {
+	result := make([]s.KeyFieldScoreTxnValue, 0, len(members))
+	for _, v := range members {
+		switch index {
+		case s.OwnerIndex:
+			if ownerId, err := records.ReadOwnerId(v.Value); err != nil || ownerId != value {
+				continue
+			}
+		case s.TransactionIndex:
+			if v.Txn != value {
+				continue
+			}
+		default:
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// rollbackMembers creates the rollback records for the members, so that they
+// can be rolled back with the score.
+func rollbackMembers(members []s.KeyFieldScoreTxnValue, score float64) ([]s.KeyFieldScoreTxnValue, error) {
+	fb := pool.Get()
+	defer pool.Put(fb)
+
+	var (
+		now    = time.Now()
+		result = make([]s.KeyFieldScoreTxnValue, 0, len(members))
+	)
+	for _, v := range members {
+		ownerId, err := records.ReadOwnerId(v.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bson.IsObjectIdHex(v.Field.String()) || !bson.IsObjectIdHex(v.Txn.String()) {
+			return nil, typex.Errorf(errors.Source, errors.UnexpectedArgument,
+				"Invalid member %s", v.Field)
+		}
+
+		fb.Reset()
+
+		value, err := records.RollbackRecord{
+			Id:            bson.ObjectIdHex(v.Field.String()),
+			Updated:       now,
+			OwnerId:       bson.ObjectIdHex(ownerId.String()),
+			TransactionId: bson.ObjectIdHex(v.Txn.String()),
+		}.Write(fb)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, s.KeyFieldScoreTxnValue{
+			Key:   v.Key,
+			Field: v.Field,
+			Score: score,
+			Txn:   v.Txn,
+			Value: records.PackageRollbackRecord(value),
+		})
+	}
+	return result, nil
+}
@@ -1,6 +1,7 @@
 package coordinator
 
 import (
+	"context"
 	"github.com/SimonRichardson/echelon/coordinator/strategies"
 	"github.com/SimonRichardson/echelon/errors"
 	"github.com/SimonRichardson/echelon/farm/counter"
@@ -38,26 +39,32 @@ func newInserter(co *Coordinator,
 	}
 }
 
-func (i *inserter) Insert(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) (int, error) {
+func (i *inserter) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySizeExpiry) (int, error) {
 	// Bucketize the members so that we can effiecently call all the storage
 	// collections.
 	var (
 		instr      = i.co.instrumentation
 		buckets    = s.KeyFieldScoreTxnValues(members).Bucketize()
-		sized, err = i.strategy(i.counter, buckets, sizeExpiry)
+		sized, err = i.strategy(ctx, i.counter, buckets, sizeExpiry)
 	)
 
 	if err != nil {
 		return 0, err
 	}
 
+	// Once the first member is written to the store, the counter has to be
+	// written as well, so the caller going away can't stop the insertion half
+	// way through.
+	ctx, cancel := detach(ctx)
+	defer cancel()
+
 	// We actually only care about this value, the rest is superfluous
 	var (
 		result         = 0
 		partialFailure = false
 	)
 	for k, v := range sized {
-		res, err := i.store.Insert(v, sizeExpiry)
+		res, err := i.store.Insert(ctx, v, sizeExpiry)
 		if err != nil {
 
 			teleprinter.L.Error().Printf("Store Insert Partial Failure (%s, %d:%d)",
@@ -80,7 +87,7 @@ func (i *inserter) Insert(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySiz
 
 		updated := false
 		for j := 0; j < defaultRetryAmount; j++ {
-			amount, err := i.counter.Insert(v, sizeExpiry)
+			amount, err := i.counter.Insert(ctx, v, sizeExpiry)
 			if err == nil && res == amount {
 				updated = true
 				break
@@ -106,7 +113,8 @@ func (i *inserter) Insert(members []s.KeyFieldScoreTxnValue, sizeExpiry s.KeySiz
 		return result, ErrPartialInsertionFailure
 	}
 
-	go i.notifier.Publish(defaultInsertChannel, s.KeyFieldScoreTxnValues(members).KeyFieldScoreSizeExpiry(sizeExpiry))
+	go i.notifier.Publish(context.Background(), defaultInsertChannel, s.KeyFieldScoreTxnValues(members).KeyFieldScoreSizeExpiry(sizeExpiry))
+	i.co.record(s.ChangeInsert, members)
 
 	return result, nil
 }
@@ -1,7 +1,8 @@
 package coordinator
 
 import (
-	"gopkg.in/mgo.v2/bson"
+	"context"
+	"time"
 
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	"github.com/SimonRichardson/echelon/farm/store"
@@ -28,43 +29,44 @@ func newInspector(co *Coordinator, store *store.Farm, transformer s.Transformer)
 	}
 }
 
-func (i *inspector) Query(key bs.Key,
+func (i *inspector) Query(ctx context.Context, key bs.Key,
 	options s.QueryOptions,
 	sizeExpiry s.SizeExpiry,
 ) ([]s.QueryRecord, error) {
-	size, err := i.store.Size(key)
+	members, err := i.members(ctx, key, options, sizeExpiry)
 	if err != nil {
 		return nil, err
 	}
 
-	members, err := i.store.SelectRange(key, size, s.KeySizeExpiry{
-		key: sizeExpiry,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var (
-		ownerId  = options.OwnerId.String()
-		filtered = make([]s.QueryRecord, 0)
-	)
+	candidates := make([]s.QueryRecord, 0, len(members))
 	for _, v := range members {
-		if header, err := records.ReadType(v.Value); err != nil || header != schema.TypePost {
+		// Both the held and purchased records are queried, so that the state
+		// of a reservation can be found wherever it is.
+		if header, err := records.ReadType(v.Value); err != nil ||
+			(header != schema.TypePost && header != schema.TypePut) {
 			continue
 		}
 
 		if record, err := i.transformer(v); err == nil {
-			if id, ok := record["owner_id"].(bson.ObjectId); ok {
-				if ownerId == id.Hex() {
-					filtered = append(filtered, s.QueryRecord{
-						Key:    v.Key,
-						Field:  v.Field,
-						Record: record,
-					})
-				}
-			}
+			candidates = append(candidates, s.QueryRecord{
+				Key:    v.Key,
+				Field:  v.Field,
+				Record: record,
+			})
 		}
 	}
 
-	return filtered, nil
+	return options.Filter(candidates, time.Now())
+}
+
+// members resolves the members via a secondary index if the options allow it,
+// otherwise all the members of the key are read.
+func (i *inspector) members(ctx context.Context, key bs.Key,
+	options s.QueryOptions,
+	sizeExpiry s.SizeExpiry,
+) ([]s.KeyFieldScoreTxnValue, error) {
+	if index, value, ok := options.Index(); ok {
+		return indexedMembers(ctx, i.store, key, index, bs.Key(value), sizeExpiry)
+	}
+	return rangeMembers(ctx, i.store, key, sizeExpiry)
 }
@@ -1,13 +1,22 @@
 package coordinator
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/SimonRichardson/echelon/coordinator/strategies"
 	"github.com/SimonRichardson/echelon/farm/counter"
 	"github.com/SimonRichardson/echelon/farm/notifier"
 	"github.com/SimonRichardson/echelon/farm/store"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
 	s "github.com/SimonRichardson/echelon/selectors"
 )
 
+// defaultRecoverInterval is how often the intents of the batches are checked
+// for any that have been left part way through.
+const defaultRecoverInterval = time.Second * 5
+
 type manager struct {
 	s.LifeCycleManager
 
@@ -15,6 +24,9 @@ type manager struct {
 	counter  *counter.Farm
 	store    *store.Farm
 	notifier *notifier.Farm
+	creator  strategies.ManagerStrategyCreator
+
+	mutex    sync.Mutex
 	strategy strategies.ManagerStrategy
 	quit     chan struct{}
 }
@@ -32,26 +44,53 @@ func newManager(co *Coordinator,
 		counter:  cf,
 		store:    sf,
 		notifier: nf,
-
-		strategy: strategy(co, sf),
-		quit:     make(chan struct{}),
+		creator:  strategy,
 	}
 }
 
+// Start the manager, a manager that's been stopped (e.g. when the coordinator
+// is paused) can be started again, with a new strategy.
 func (m *manager) Start() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.quit != nil {
+		return nil
+	}
+
+	var (
+		strategy = m.creator(m.co, m.store, m.co.instrumentation)
+		quit     = make(chan struct{})
+	)
+	m.strategy = strategy
+	m.quit = quit
+
 	go func() {
-		channel := m.notifier.Subscribe(defaultInsertChannel)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		recoverTicker := time.NewTicker(defaultRecoverInterval)
+		defer recoverTicker.Stop()
+
+		channel := m.notifier.Subscribe(ctx, defaultInsertChannel)
 		for {
 			select {
-			case keyFieldSize := <-channel:
+			case <-recoverTicker.C:
+				go m.co.recoverBatches()
+			case delivery := <-channel:
 				go func() {
-					if err := m.strategy(keyFieldSize); err != nil {
-						if err == strategies.ErrFatal {
-							m.quit <- struct{}{}
+					// The delivery is only acknowledged once it's been
+					// collected, so that it's delivered again otherwise.
+					switch err := strategy.Collect(delivery.KeyFieldScoreSizeExpiry); err {
+					case nil:
+						if err := delivery.Ack(); err != nil {
+							teleprinter.L.Error().Printf("Notifier Ack Failure (%s, %s)\n", delivery.Key.String(), err.Error())
 						}
+					case strategies.ErrFatal:
+						m.Stop()
 					}
 				}()
-			case <-m.quit:
+			case <-quit:
 				return
 			}
 		}
@@ -60,6 +99,14 @@ func (m *manager) Start() error {
 }
 
 func (m *manager) Stop() error {
-	m.quit <- struct{}{}
-	return nil
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.quit == nil {
+		return nil
+	}
+
+	close(m.quit)
+	m.quit = nil
+	return m.strategy.Stop()
 }
@@ -1,8 +1,10 @@
 package coordinator
 
 import (
+	"context"
 	"strings"
 	"sync"
+	"time"
 
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	"github.com/SimonRichardson/echelon/common"
@@ -48,17 +50,17 @@ func newModifier(co *Coordinator,
 	}
 }
 
-func (m *modifier) Modify(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+func (m *modifier) Modify(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
 	var (
 		buckets      = s.KeyFieldScoreTxnValues(members).Bucketize()
-		changes, err = persist(m.persistence, buckets, maxSize)
+		changes, err = persist(ctx, m.persistence, buckets, maxSize)
 	)
 	if err != nil {
 		return changes, err
 	}
 
 	instr := m.co.instrumentation
-	if err := removeFromStore(instr, m.store, buckets, maxSize); err != nil {
+	if err := removeFromStore(ctx, instr, m.store, buckets, maxSize); err != nil {
 		return 0, err
 	}
 	return changes, nil
@@ -73,7 +75,7 @@ func newTuple(total, changes int, err error) tuple {
 	return tuple{total, changes, err}
 }
 
-func persist(persistence *persistence.Farm,
+func persist(ctx context.Context, persistence *persistence.Farm,
 	buckets map[bs.Key][]s.KeyFieldScoreTxnValue,
 	maxSize s.KeySizeExpiry,
 ) (int, error) {
@@ -90,7 +92,7 @@ func persist(persistence *persistence.Farm,
 		go func(elements []s.KeyFieldScoreTxnValue) {
 			defer wg.Done()
 
-			changes, err := persistence.Insert(elements, maxSize)
+			changes, err := persistence.Insert(ctx, elements, maxSize)
 			resp <- newTuple(len(elements), changes, err)
 		}(v)
 	}
@@ -100,7 +102,7 @@ func persist(persistence *persistence.Farm,
 		var errs []error
 		for _, members := range buckets {
 			values := s.KeyFieldScoreTxnValues(members).KeyFieldTxnValues()
-			if err := persistence.Repair(values, maxSize); err != nil {
+			if err := persistence.Repair(ctx, values, maxSize); err != nil {
 				errs = append(errs, err)
 			}
 		}
@@ -118,7 +120,7 @@ func persist(persistence *persistence.Farm,
 	return changes, nil
 }
 
-func removeFromStore(instr instrumentation.Instrumentation,
+func removeFromStore(ctx context.Context, instr instrumentation.Instrumentation,
 	store *store.Farm,
 	buckets map[bs.Key][]s.KeyFieldScoreTxnValue,
 	maxSize s.KeySizeExpiry,
@@ -136,7 +138,7 @@ func removeFromStore(instr instrumentation.Instrumentation,
 		go func(elements []s.KeyFieldScoreTxnValue) {
 			defer wg.Done()
 
-			changes, err := store.Delete(elements, maxSize)
+			changes, err := store.Delete(ctx, elements, maxSize)
 			resp <- newTuple(len(elements), changes, err)
 		}(v)
 	}
@@ -151,7 +153,7 @@ func removeFromStore(instr instrumentation.Instrumentation,
 		go func() {
 			for _, members := range buckets {
 				values := s.KeyFieldScoreTxnValues(members).KeyFieldTxnValues()
-				if err := store.Repair(values, maxSize); err != nil {
+				if err := store.Repair(context.Background(), values, maxSize); err != nil {
 					instr.RepairError(1)
 				}
 			}
@@ -184,7 +186,7 @@ func repairsRequired(values <-chan tuple) (bool, int, []error) {
 	return repair, changes, errors
 }
 
-func (m *modifier) ModifyWithOperations(key, id bs.Key,
+func (m *modifier) ModifyWithOperations(ctx context.Context, key, id bs.Key,
 	operations []s.Operation,
 	score float64,
 	maxSize s.SizeExpiry,
@@ -194,7 +196,7 @@ func (m *modifier) ModifyWithOperations(key, id bs.Key,
 		return -1, err
 	}
 
-	res, err := m.store.Select(key, id)
+	res, err := m.store.Select(ctx, key, id)
 	if err != nil {
 		return -1, err
 	}
@@ -222,10 +224,13 @@ func (m *modifier) ModifyWithOperations(key, id bs.Key,
 			"Unable to parse record.")
 	}
 
-	fb := pool.Get()
-	defer pool.Put(fb)
-
-loop:
+	// The operations are applied to the record before anything is written, so
+	// that either all of them are written or none of them are.
+	var (
+		replaced bool
+		state    = record.State
+		expiry   = record.Expiry
+	)
 	for k, op := range operations {
 		var (
 			t = targets[k]
@@ -233,58 +238,75 @@ loop:
 		)
 		switch op.Op {
 		case Match:
-			if res, err := accessor.GetFieldValue(record, a); err == nil && res == op.Value {
-				if k == num-1 {
-					return k, nil
-				}
-				continue loop
-			} else {
+			if res, err := accessor.GetFieldValue(record, a); err != nil || res != op.Value {
 				return k, typex.Errorf(errors.Source, errors.UnexpectedResults,
 					"Nothing matched for match.")
 			}
 		case Replace:
-			if _, err := accessor.GetFieldValue(record, a); err == nil {
-				if err := accessor.SetFieldValue(record, a, op.Value); err != nil {
-					return k, err
-				}
-
-				fb.Reset()
-
-				value, err := record.Write(fb)
-				if err != nil {
-					return k, err
-				}
-
-				// Insert should cause an over write of existing data
-				if _, err := m.store.Insert([]s.KeyFieldScoreTxnValue{
-					s.KeyFieldScoreTxnValue{
-						Key:   key,
-						Field: id,
-						Score: score,
-						Txn:   bs.Key(record.TransactionId.Hex()),
-						Value: records.PackagePostRecord(value),
-					},
-				}, s.KeySizeExpiry{
-					key: maxSize,
-				}); err != nil {
-					return k, err
-				}
-
-				if k == num-1 {
-					return k, nil
-				}
-				continue loop
-			} else {
+			if _, err := accessor.GetFieldValue(record, a); err != nil {
 				return k, typex.Errorf(errors.Source, errors.UnexpectedResults,
 					"Nothing matched for replace.")
 			}
+			if err := accessor.SetFieldValue(record, a, op.Value); err != nil {
+				return k, err
+			}
+			replaced = true
 		default:
 			return k, typex.Errorf(errors.Source, errors.UnexpectedResults,
 				"Missing implementation.")
 		}
 	}
-	return -1, typex.Errorf(errors.Source, errors.UnexpectedResults,
-		"Nothing matched.")
+
+	if num < 1 {
+		return -1, typex.Errorf(errors.Source, errors.UnexpectedResults,
+			"Nothing matched.")
+	}
+	if !replaced {
+		return num - 1, nil
+	}
+
+	// The transition is checked against the record as it was stored, so that
+	// an expired hold can't be confirmed by replacing the expiry at the same
+	// time.
+	if err := state.Transition(record.State, expiry, time.Now()); err != nil {
+		return num - 1, err
+	}
+
+	fb := pool.Get()
+	defer pool.Put(fb)
+
+	value, err := record.Write(fb)
+	if err != nil {
+		return num - 1, err
+	}
+
+	member := s.KeyFieldScoreTxnValue{
+		Key:   key,
+		Field: id,
+		Score: score,
+		Txn:   bs.Key(record.TransactionId.Hex()),
+		Value: records.PackagePostRecord(value),
+	}
+
+	// The record is only over written if it's still the version that was
+	// read, otherwise the transition was checked against a state that's
+	// since changed (e.g. it's been swept or confirmed by someone else).
+	swapped, err := m.store.Swap(ctx, member, res.Score, s.KeySizeExpiry{
+		key: maxSize,
+	})
+	if err != nil {
+		return num - 1, err
+	}
+	if swapped < 1 {
+		return num - 1, typex.Errorf(errors.Source, errors.ConcurrentModification,
+			"Concurrent Modification (%s)", id.String())
+	}
+
+	if state != record.State {
+		m.co.transition(record.State, member, maxSize)
+	}
+
+	return num - 1, nil
 }
 
 type target struct {
@@ -2,17 +2,22 @@ package coordinator
 
 import (
 	"io"
+	"time"
 
 	blist "github.com/SimonRichardson/echelon/internal/selectors"
 	"github.com/SimonRichardson/echelon/internal/services/consul"
 	a "github.com/SimonRichardson/echelon/alertmanager"
 	ap "github.com/SimonRichardson/echelon/alertmanager/parse"
 	"github.com/SimonRichardson/echelon/cluster/counter"
+	"github.com/SimonRichardson/echelon/cluster/intent"
 	"github.com/SimonRichardson/echelon/cluster/notifier"
 	"github.com/SimonRichardson/echelon/cluster/persistence"
 	"github.com/SimonRichardson/echelon/cluster/store"
+	"github.com/SimonRichardson/echelon/cluster/waitlist"
 	"github.com/SimonRichardson/echelon/common"
 	"github.com/SimonRichardson/echelon/env"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/farm"
 	c "github.com/SimonRichardson/echelon/farm/counter"
 	n "github.com/SimonRichardson/echelon/farm/notifier"
 	p "github.com/SimonRichardson/echelon/farm/persistence"
@@ -22,6 +27,7 @@ import (
 	"github.com/SimonRichardson/echelon/selectors"
 	r "github.com/SimonRichardson/echelon/internal/redis"
 	fs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
 )
 
 func newInstrumentation(e *env.Env, writer io.Writer) (i.Instrumentation, error) {
@@ -43,6 +49,35 @@ func newAlertManager(e *env.Env) (a.AlertManager, error) {
 	)
 }
 
+func newBreakers(e *env.Env, name string, alert a.AlertManager) (*farm.Breakers, error) {
+	var (
+		opts = farm.BreakerOptions{
+			ErrorRate:   e.BreakerErrorRate,
+			MinRequests: e.BreakerMinRequests,
+		}
+		err error
+	)
+
+	if opts.Latency, err = time.ParseDuration(e.BreakerLatency); err != nil {
+		return nil, err
+	}
+
+	if opts.Window, err = time.ParseDuration(e.BreakerWindow); err != nil {
+		return nil, err
+	}
+
+	if opts.Cooldown, err = time.ParseDuration(e.BreakerCooldown); err != nil {
+		return nil, err
+	}
+
+	if opts.ErrorRate < 0 || opts.ErrorRate > 1 {
+		return nil, typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
+			"Invalid breaker error rate")
+	}
+
+	return farm.NewBreakers(name, opts, alert), nil
+}
+
 func newCounterClusters(e *env.Env) ([]counter.Cluster, error) {
 	clusters, err := c.ParseString(
 		e.CounterInstances,
@@ -61,8 +96,10 @@ func newCounterClusters(e *env.Env) ([]counter.Cluster, error) {
 
 func newCounterFarm(e *env.Env,
 	instr i.Instrumentation,
+	alert a.AlertManager,
 ) (*c.Farm, error) {
 	var (
+		breakers    *farm.Breakers
 		err         error
 		clusters    []counter.Cluster
 		insStrategy c.InsertCreator
@@ -91,11 +128,16 @@ func newCounterFarm(e *env.Env,
 		return nil, err
 	}
 
+	if breakers, err = newBreakers(e, "counter", alert); err != nil {
+		return nil, err
+	}
+
 	return c.New(clusters,
 		insStrategy,
 		delStrategy,
 		scaStrategy,
 		repStrategy,
+		breakers,
 		instr,
 	), nil
 }
@@ -128,6 +170,7 @@ func newStoreClusters(e *env.Env, opts *s.Options) ([]store.Cluster, error) {
 		e.StoreInstances,
 		e.StoreConnectTimeout, e.StoreReadTimeout, e.StoreWriteTimeout,
 		e.StorePoolRoutingStrategy,
+		e.StoreSetStrategy,
 		e.StoreMaxSize,
 		newRedisOptions(opts),
 		e.RedisCreator,
@@ -142,9 +185,11 @@ func newStoreClusters(e *env.Env, opts *s.Options) ([]store.Cluster, error) {
 
 func newStoreFarm(e *env.Env,
 	instr i.Instrumentation,
+	alert a.AlertManager,
 	opts *s.Options,
 ) (*s.Farm, error) {
 	var (
+		breakers    *farm.Breakers
 		err         error
 		clusters    []store.Cluster
 		selStrategy s.SelectCreator
@@ -158,7 +203,9 @@ func newStoreFarm(e *env.Env,
 		return nil, err
 	}
 
-	if selStrategy, err = s.ParseSelectStrategy(e.GetSelectOptions(env.Store)); err != nil {
+	if selStrategy, err = s.ParseSelectStrategy(e.GetSelectOptions(env.Store),
+		e.GetSelectRepairOptions(env.Store),
+	); err != nil {
 		return nil, err
 	}
 
@@ -178,12 +225,17 @@ func newStoreFarm(e *env.Env,
 		return nil, err
 	}
 
+	if breakers, err = newBreakers(e, "store", alert); err != nil {
+		return nil, err
+	}
+
 	return s.New(clusters,
 		selStrategy,
 		insStrategy,
 		delStrategy,
 		scaStrategy,
 		repStrategy,
+		breakers,
 		instr,
 	), nil
 }
@@ -208,9 +260,11 @@ func newPersistenceClusters(e *env.Env, transformer selectors.Transformer) ([]pe
 
 func newPersistenceFarm(e *env.Env,
 	instr i.Instrumentation,
+	alert a.AlertManager,
 	transformer selectors.Transformer,
 ) (*p.Farm, error) {
 	var (
+		breakers    *farm.Breakers
 		err         error
 		clusters    []persistence.Cluster
 		insStrategy p.InsertCreator
@@ -234,10 +288,15 @@ func newPersistenceFarm(e *env.Env,
 		return nil, err
 	}
 
+	if breakers, err = newBreakers(e, "persistence", alert); err != nil {
+		return nil, err
+	}
+
 	return p.New(clusters,
 		insStrategy,
 		delStrategy,
 		repStrategy,
+		breakers,
 		instr,
 	), nil
 }
@@ -258,10 +317,32 @@ func newNotifierClusters(e *env.Env) ([]notifier.Cluster, error) {
 	return clusters, err
 }
 
+func newWaitlistCluster(e *env.Env) (waitlist.Cluster, error) {
+	return waitlist.ParseString(
+		e.WaitlistInstances,
+		e.NotifierConnectTimeout, e.NotifierReadTimeout, e.NotifierWriteTimeout,
+		e.NotifierPoolRoutingStrategy,
+		e.WaitlistMaxSize,
+		e.RedisCreator,
+	)
+}
+
+func newIntentCluster(e *env.Env) (intent.Cluster, error) {
+	return intent.ParseString(
+		e.IntentInstances,
+		e.NotifierConnectTimeout, e.NotifierReadTimeout, e.NotifierWriteTimeout,
+		e.NotifierPoolRoutingStrategy,
+		e.IntentMaxSize,
+		e.RedisCreator,
+	)
+}
+
 func newNotifierFarm(e *env.Env,
 	instr i.Instrumentation,
+	alert a.AlertManager,
 ) (*n.Farm, error) {
 	var (
+		breakers    *farm.Breakers
 		err         error
 		clusters    []notifier.Cluster
 		notStrategy n.NotifyCreator
@@ -271,12 +352,19 @@ func newNotifierFarm(e *env.Env,
 		return nil, err
 	}
 
-	if notStrategy, err = n.ParseNotifyStrategy(e.GetNotifyOptions(env.Notifier)); err != nil {
+	if notStrategy, err = n.ParseNotifyStrategy(e.GetNotifyOptions(env.Notifier),
+		e.GetStreamOptions(env.Notifier),
+	); err != nil {
+		return nil, err
+	}
+
+	if breakers, err = newBreakers(e, "notifier", alert); err != nil {
 		return nil, err
 	}
 
 	return n.New(clusters,
 		notStrategy,
+		breakers,
 		instr,
 	), nil
 }
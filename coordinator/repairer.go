@@ -1,6 +1,7 @@
 package coordinator
 
 import (
+	"context"
 	"github.com/SimonRichardson/echelon/coordinator/strategies"
 	"github.com/SimonRichardson/echelon/farm/store"
 	s "github.com/SimonRichardson/echelon/selectors"
@@ -24,6 +25,6 @@ func newRepairer(co *Coordinator, store *store.Farm, strategy strategies.RepairS
 	}
 }
 
-func (s *repairer) Repair(members []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
-	return s.strategy(s.store, members, maxSize)
+func (s *repairer) Repair(ctx context.Context, members []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
+	return s.strategy(ctx, s.store, members, maxSize)
 }
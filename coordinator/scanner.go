@@ -1,6 +1,7 @@
 package coordinator
 
 import (
+	"context"
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	"github.com/SimonRichardson/echelon/farm/counter"
 	s "github.com/SimonRichardson/echelon/selectors"
@@ -22,14 +23,14 @@ func newScanner(co *Coordinator, counter *counter.Farm) *scanner {
 	}
 }
 
-func (s *scanner) Keys() ([]bs.Key, error) {
-	return s.counter.Keys()
+func (s *scanner) Keys(ctx context.Context) ([]bs.Key, error) {
+	return s.counter.Keys(ctx)
 }
 
-func (s *scanner) Size(key bs.Key) (int, error) {
-	return s.counter.Size(key)
+func (s *scanner) Size(ctx context.Context, key bs.Key) (int, error) {
+	return s.counter.Size(ctx, key)
 }
 
-func (s *scanner) Members(key bs.Key) ([]bs.Key, error) {
-	return s.counter.Members(key)
+func (s *scanner) Members(ctx context.Context, key bs.Key) ([]bs.Key, error) {
+	return s.counter.Members(ctx, key)
 }
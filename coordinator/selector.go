@@ -1,6 +1,7 @@
 package coordinator
 
 import (
+	"context"
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	"github.com/SimonRichardson/echelon/farm/store"
 	s "github.com/SimonRichardson/echelon/selectors"
@@ -22,10 +23,10 @@ func newSelector(co *Coordinator, store *store.Farm) *selector {
 	}
 }
 
-func (s *selector) Select(key, field bs.Key) (s.KeyFieldScoreTxnValue, error) {
-	return s.store.Select(key, field)
+func (s *selector) Select(ctx context.Context, key, field bs.Key) (s.KeyFieldScoreTxnValue, error) {
+	return s.store.Select(ctx, key, field)
 }
 
-func (s *selector) SelectRange(key bs.Key, limit int, maxSize s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
-	return s.store.SelectRange(key, limit, maxSize)
+func (s *selector) SelectRange(ctx context.Context, key bs.Key, limit int, maxSize s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
+	return s.store.SelectRange(ctx, key, limit, maxSize)
 }
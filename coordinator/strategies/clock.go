@@ -0,0 +1,58 @@
+package strategies
+
+import (
+	"time"
+
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/clocks"
+	"github.com/SimonRichardson/echelon/internal/typex"
+)
+
+// ClockStrategy defines how a score supplied by a client is treated before it
+// is written to the stores, and how the writes that the coordinator makes on
+// its own behalf are scored.
+type ClockStrategy interface {
+	// Stamp either stamps or validates a score supplied by a client.
+	Stamp(float64) (float64, error)
+
+	// Now returns a score for a write that the coordinator makes itself, which
+	// is always accepted by Stamp.
+	Now() float64
+}
+
+type clockNoop struct{}
+
+func (clockNoop) Stamp(score float64) (float64, error) { return score, nil }
+func (clockNoop) Now() float64                         { return float64(time.Now().UnixNano()) }
+
+// clockStamp ignores the score supplied by the client and replaces it with a
+// hybrid logical clock timestamp, so that writers with skewed clocks can't
+// silently lose writes.
+type clockStamp struct {
+	clock *clocks.Clock
+}
+
+func (c clockStamp) Stamp(float64) (float64, error) { return c.Now(), nil }
+func (c clockStamp) Now() float64                   { return c.clock.Now().Score() }
+
+// clockValidate expects the score supplied by the client to be a packed hybrid
+// logical clock timestamp and rejects it if it has drifted further than the
+// max skew allows.
+type clockValidate struct {
+	clock   *clocks.Clock
+	maxSkew time.Duration
+}
+
+func (c clockValidate) Stamp(score float64) (float64, error) {
+	timestamp := clocks.FromScore(score)
+	if drift := c.clock.Drift(timestamp); drift > c.maxSkew || drift < -c.maxSkew {
+		return score, typex.Errorf(errors.Source, errors.ClockSkew,
+			"Score exceeds max skew (%s, %s)", drift, c.maxSkew)
+	}
+
+	c.clock.Update(timestamp)
+
+	return score, nil
+}
+
+func (c clockValidate) Now() float64 { return c.clock.Now().Score() }
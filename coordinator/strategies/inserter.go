@@ -1,6 +1,7 @@
 package strategies
 
 import (
+	"context"
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	"github.com/SimonRichardson/echelon/errors"
 	"github.com/SimonRichardson/echelon/farm/counter"
@@ -9,26 +10,26 @@ import (
 )
 
 // InsertStrategy defines if we can insert a new item.
-type InsertStrategy func(*counter.Farm,
+type InsertStrategy func(context.Context, *counter.Farm,
 	map[bs.Key][]s.KeyFieldScoreTxnValue,
 	s.KeySizeExpiry,
 ) (map[bs.Key][]s.KeyFieldScoreTxnValue, error)
 
-func insertNoop(c *counter.Farm,
+func insertNoop(ctx context.Context, c *counter.Farm,
 	buckets map[bs.Key][]s.KeyFieldScoreTxnValue,
 	sizeExpiry s.KeySizeExpiry,
 ) (map[bs.Key][]s.KeyFieldScoreTxnValue, error) {
 	return buckets, nil
 }
 
-func insertCounter(c *counter.Farm,
+func insertCounter(ctx context.Context, c *counter.Farm,
 	buckets map[bs.Key][]s.KeyFieldScoreTxnValue,
 	sizeExpiry s.KeySizeExpiry,
 ) (map[bs.Key][]s.KeyFieldScoreTxnValue, error) {
 	sized := map[bs.Key][]s.KeyFieldScoreTxnValue{}
 
 	for k, v := range buckets {
-		size, err := c.Size(k)
+		size, err := c.Size(ctx, k)
 		if err != nil {
 			return sized, err
 		}
@@ -0,0 +1,215 @@
+package strategies
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SimonRichardson/echelon/instrumentation"
+	"github.com/SimonRichardson/echelon/internal/cribs"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+)
+
+const (
+	defaultSweepNamespace bs.Namespace = "sweep"
+
+	// defaultLeaseTerms defines how many lease durations a lease is held for,
+	// before it's handed over to another instance.
+	defaultLeaseTerms = 4
+)
+
+var (
+	defaultLeaseBackoff  = time.Second
+	defaultLeaseStepBack = time.Second * 5
+)
+
+// Locker defines a way to lock a namespace between the instances.
+type Locker interface {
+	Lock(bs.Namespace) (bs.SemaphoreUnlock, bs.SemaphoreLost, error)
+}
+
+type lease struct {
+	unlock bs.SemaphoreUnlock
+	swept  time.Time
+}
+
+// leases splits the keys into shards and contends for the lease of every shard
+// through the semaphore, so that every shard is only swept by the instance that
+// holds the lease for it.
+//
+// A lease expires as soon as the lock is lost, which happens when the session
+// that holds it is invalidated. After a number of terms the lease is handed
+// over, by releasing the lock and stepping back, so that the shards are spread
+// between the instances over time.
+type leases struct {
+	mutex    sync.Mutex
+	locker   Locker
+	instr    instrumentation.SweepInstrumentation
+	duration time.Duration
+	shards   []*lease
+	stopped  bool
+	quit     chan struct{}
+	held     sync.WaitGroup
+}
+
+func newLeases(locker Locker, instr instrumentation.SweepInstrumentation, shards int, duration time.Duration) *leases {
+	l := &leases{
+		locker:   locker,
+		instr:    instr,
+		duration: duration,
+		shards:   make([]*lease, shards),
+		quit:     make(chan struct{}),
+	}
+
+	for k := range l.shards {
+		go l.contend(k)
+	}
+	return l
+}
+
+// Len returns how many shards there are.
+func (l *leases) Len() int {
+	return len(l.shards)
+}
+
+// Shard returns the shard of a key.
+func (l *leases) Shard(key bs.Key) int {
+	return int(cribs.New(key.String()) % uint32(len(l.shards)))
+}
+
+// Held returns if the lease of the shard is held.
+func (l *leases) Held(shard int) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.shards[shard] != nil
+}
+
+// Renew the lease of the shard once it's been swept, it returns how long it's
+// been since the shard was last swept.
+func (l *leases) Renew(shard int, now time.Time) (time.Duration, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	v := l.shards[shard]
+	if v == nil {
+		return 0, false
+	}
+
+	lag := now.Sub(v.swept)
+	v.swept = now
+	return lag, true
+}
+
+// Stop hands over all the leases that are held, any shard that's still being
+// contended for is released as soon as it's acquired.
+func (l *leases) Stop() {
+	l.mutex.Lock()
+	if l.stopped {
+		l.mutex.Unlock()
+		return
+	}
+	l.stopped = true
+	close(l.quit)
+	l.mutex.Unlock()
+
+	l.held.Wait()
+}
+
+func (l *leases) contend(shard int) {
+	ns := bs.Namespace(strconv.Itoa(shard)).Prefix(defaultSweepNamespace)
+	for {
+		// The lock blocks until it's held, so the lease is only ever acquired
+		// once the previous holder has released it.
+		unlock, lost, err := l.locker.Lock(ns)
+		if err != nil {
+			teleprinter.L.Error().Printf("Unable to lock shard %d : %s\n", shard, err.Error())
+			if !l.wait(defaultLeaseBackoff) {
+				return
+			}
+			continue
+		}
+
+		if !l.acquire(shard, unlock) {
+			l.unlock(shard, unlock)
+			return
+		}
+		expired := l.hold(lost)
+		l.release(shard)
+
+		if expired {
+			go l.instr.SweepExpired()
+		} else {
+			go l.instr.SweepHandover()
+		}
+
+		// Step back so that another instance can take over the shard.
+		if !l.wait(defaultLeaseStepBack + time.Duration(rand.Int63n(int64(defaultLeaseStepBack)))) {
+			return
+		}
+	}
+}
+
+func (l *leases) acquire(shard int, unlock bs.SemaphoreUnlock) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.stopped {
+		return false
+	}
+	l.held.Add(1)
+
+	l.shards[shard] = &lease{
+		unlock: unlock,
+		swept:  time.Now(),
+	}
+
+	go l.instr.SweepAcquired()
+	return true
+}
+
+// hold blocks until the lease either expires, because the lock is lost, or
+// it's term has ended. It returns true if the lease expired.
+func (l *leases) hold(lost bs.SemaphoreLost) bool {
+	timer := time.NewTimer(l.duration * defaultLeaseTerms)
+	defer timer.Stop()
+
+	select {
+	case <-lost:
+		return true
+	case <-timer.C:
+		return false
+	case <-l.quit:
+		return false
+	}
+}
+
+func (l *leases) release(shard int) {
+	l.mutex.Lock()
+	v := l.shards[shard]
+	l.shards[shard] = nil
+	l.mutex.Unlock()
+
+	l.unlock(shard, v.unlock)
+	l.held.Done()
+}
+
+func (l *leases) unlock(shard int, unlock bs.SemaphoreUnlock) {
+	if err := unlock(); err != nil {
+		teleprinter.L.Error().Printf("Unable to unlock shard %d : %s\n", shard, err.Error())
+	}
+}
+
+func (l *leases) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-l.quit:
+		return false
+	}
+}
@@ -1,8 +1,8 @@
 package strategies
 
 import (
+	"context"
 	"log"
-	"math"
 	"math/rand"
 	"sync"
 	"time"
@@ -11,188 +11,282 @@ import (
 	cs "github.com/SimonRichardson/echelon/cluster/store"
 	"github.com/SimonRichardson/echelon/errors"
 	"github.com/SimonRichardson/echelon/farm/store"
+	"github.com/SimonRichardson/echelon/instrumentation"
 	s "github.com/SimonRichardson/echelon/selectors"
 	"github.com/SimonRichardson/echelon/internal/typex"
 )
 
 const (
 	defaultManagerSize = 1000
+
+	// defaultManagerBackoff is how long an item that couldn't be deleted is
+	// held back, so that it doesn't hold up the items that are due after it.
+	defaultManagerBackoff = time.Minute
 )
 
 // ErrFatal defines if the manager should be shut down or not
 var (
 	ErrFatal = typex.Errorf(errors.Source, errors.Fatal, "Fatal error")
 
-	defaultStep          = time.Minute.Nanoseconds()
-	defaultIntervalSweep = time.Minute.Nanoseconds()
+	defaultIntervalSweep = time.Second.Nanoseconds()
 	defaultFullSweep     = (time.Minute * 10).Nanoseconds()
 )
 
 type Manager interface {
-	s.Deleter
+	Expirer
 	s.Scanner
+	Promoter
+	Locker
 }
 
-// ManagerStrategyCreator creates a ManagerStrategy
-type ManagerStrategyCreator func(Manager, *store.Farm) ManagerStrategy
-
-// ManagerStrategy defines how
-type ManagerStrategy func(s.KeyFieldScoreSizeExpiry) error
-
-func managerNoopStrategy(Manager, *store.Farm) ManagerStrategy {
-	return func(kfs s.KeyFieldScoreSizeExpiry) error {
-		return nil
-	}
-}
-
-func managerCollectStrategy(duration time.Duration) ManagerStrategyCreator {
-	return func(co Manager, sf *store.Farm) ManagerStrategy {
-		var (
-			// Run slightly head of speed so we don't miss time anything
-			percentage    = int64(float64(defaultIntervalSweep) * 0.9)
-			intervalTimer = time.NewTicker(time.Duration(percentage))
-			fullTimer     = time.NewTicker(time.Duration(defaultFullSweep))
-
-			slots = newTimeSlots()
-		)
-
-		go func() {
-			for {
-				select {
-				case <-intervalTimer.C:
-					// Check to see if the item has expired, if it has delete it!
-					var (
-						// Attempt to make the score be in the future
-						items = slots.Peek()
-
-						now    = time.Now()
-						values = []s.KeyFieldScoreTxnValue{}
-					)
-
-					for _, v := range items {
-						if item, ok := selectItem(sf, now, v.Key, v.Field); ok {
-							values = append(values, item)
-						}
-					}
-
-					if _, err := co.Delete(values, s.MakeKeySizeExpiry()); err != nil {
-						log.Println("Partial failure", err)
-					}
-
-				case <-fullTimer.C:
-					// Get all the keys then all the fields and then check to
-					// see if the item has expired, if it has delete it!
-
-					// TODO : How do we know another echelon isn't doing this
-					// at the same time?
-					keys, err := co.Keys()
-					if err != nil {
-						continue
-					}
-
-					shuffle(keys)
-
-					var (
-						now    = time.Now()
-						values = []s.KeyFieldScoreTxnValue{}
-					)
-
-					for _, key := range keys {
-						fields, err := co.Members(key)
-						if err != nil {
-							continue
-						}
-
-						for _, field := range fields {
-							if item, ok := selectItem(sf, now, key, field); ok {
-								values = append(values, item)
-							}
-						}
-					}
-
-					if _, err := co.Delete(values, s.MakeKeySizeExpiry()); err != nil {
-						log.Println("Partial failure", err)
-					}
-				}
-			}
-		}()
+// Expirer defines a way to delete the items that have expired, without running
+// their scores through the clock strategy.
+type Expirer interface {
+	Expire(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) (int, error)
+}
 
-		return func(kfs s.KeyFieldScoreSizeExpiry) error {
-			return slots.Add(kfs)
-		}
-	}
+// Promoter defines a way to offer the room of a key to its waiting list.
+type Promoter interface {
+	Promote(context.Context, bs.Key) (int, error)
 }
 
-func selectItem(sf *store.Farm, now time.Time, key, field bs.Key) (s.KeyFieldScoreTxnValue, bool) {
-	item, err := sf.Select(key, field)
-	if err != nil {
-		if err != cs.ErrExpiredNode {
-			return s.KeyFieldScoreTxnValue{}, false
-		}
-	}
+// ManagerStrategyCreator creates a ManagerStrategy, a strategy can't be
+// started again once it's stopped, so a new one is created every time.
+type ManagerStrategyCreator func(Manager, *store.Farm, instrumentation.SweepInstrumentation) ManagerStrategy
 
-	return s.KeyFieldScoreTxnValue{
-		Key:   item.Key,
-		Field: item.Field,
-		Score: item.Score + 1,
-		Txn:   item.Txn,
-		Value: item.Value,
-	}, true
+// ManagerStrategy defines how expired items are collected.
+type ManagerStrategy interface {
+	// Collect schedules an item that's been inserted, to be swept once it's
+	// expired.
+	Collect(s.KeyFieldScoreSizeExpiry) error
+
+	// Stop sweeping and hand over anything that's held to another instance.
+	Stop() error
+}
+
+type managerNoop struct{}
+
+func (managerNoop) Collect(s.KeyFieldScoreSizeExpiry) error { return nil }
+func (managerNoop) Stop() error                             { return nil }
+
+func managerNoopStrategy(Manager, *store.Farm, instrumentation.SweepInstrumentation) ManagerStrategy {
+	return managerNoop{}
 }
 
-type timeRange struct {
-	start, end int64
+type managerCollect struct {
+	co     Manager
+	sf     *store.Farm
+	instr  instrumentation.SweepInstrumentation
+	leases *leases
+	once   sync.Once
+	quit   chan struct{}
 }
 
-func makeTimeRange(interval int64, offset int64) timeRange {
-	return timeRange{interval, interval + offset}
+// managerCollectStrategy sweeps the expired items of the shards that this
+// instance holds the lease for, so that every key is only swept by one
+// instance at a time. The items are swept as soon as they're due, via the
+// schedule that the store keeps of when every item expires.
+func managerCollectStrategy(duration time.Duration, shards int, lease time.Duration) ManagerStrategyCreator {
+	return func(co Manager, sf *store.Farm, instr instrumentation.SweepInstrumentation) ManagerStrategy {
+		m := &managerCollect{
+			co:     co,
+			sf:     sf,
+			instr:  instr,
+			leases: newLeases(co, instr, shards, lease),
+			quit:   make(chan struct{}),
+		}
+		go m.run()
+		return m
+	}
 }
 
-type timeSlots struct {
-	mutex *sync.Mutex
-	slots map[timeRange][]s.KeyFieldScoreSizeExpiry
+// Collect doesn't need to do anything, as the store schedules the items as
+// they're inserted.
+func (m *managerCollect) Collect(kfs s.KeyFieldScoreSizeExpiry) error {
+	return nil
 }
 
-func newTimeSlots() *timeSlots {
-	return &timeSlots{
-		&sync.Mutex{},
-		map[timeRange][]s.KeyFieldScoreSizeExpiry{},
+func (m *managerCollect) Stop() error {
+	m.once.Do(func() {
+		close(m.quit)
+		m.leases.Stop()
+	})
+	return nil
+}
+
+func (m *managerCollect) run() {
+	var (
+		intervalTimer = time.NewTicker(time.Duration(defaultIntervalSweep))
+		fullTimer     = time.NewTicker(time.Duration(defaultFullSweep))
+	)
+	defer intervalTimer.Stop()
+	defer fullTimer.Stop()
+
+	for {
+		select {
+		case <-intervalTimer.C:
+			m.intervalSweep()
+		case <-fullTimer.C:
+			m.fullSweep()
+		case <-m.quit:
+			return
+		}
 	}
 }
 
-func (b *timeSlots) Add(k s.KeyFieldScoreSizeExpiry) error {
-	b.mutex.Lock()
-	defer b.mutex.Unlock()
+// intervalSweep pops the items that are due from the schedule, only the items
+// of the shards that are held are deleted, the rest are left for the instance
+// that holds them.
+func (m *managerCollect) intervalSweep() {
+	now := time.Now()
+
+	items, err := m.sf.Due(context.Background(), now, defaultManagerSize)
+	if err == store.ErrNotScheduled {
+		// The stores aren't scheduled (OR-Set), so the full sweep is the only
+		// way that the items are collected.
+		return
+	} else if err != nil {
+		log.Println("Schedule failure", err)
+		return
+	}
 
 	var (
-		now  = time.Now()
-		step = stepRound(now.Add(k.Expiry).UnixNano(), defaultStep)
-		slot = makeTimeRange(step, defaultIntervalSweep)
+		lag    time.Duration
+		values = []s.KeyFieldScoreTxnValue{}
 	)
-	b.slots[slot] = append(b.slots[slot], k)
+	for _, v := range items {
+		if !m.leases.Held(m.leases.Shard(v.Key)) {
+			continue
+		}
 
-	return nil
+		item := v.KeyFieldScoreTxnValue()
+		item.Score++
+		values = append(values, item)
+
+		if d := now.Sub(v.Expiry); d > lag {
+			lag = d
+		}
+	}
+
+	if len(values) < 1 {
+		m.delete(now, values)
+		return
+	}
+
+	go m.instr.SweepLag(lag)
+	m.delete(now, values)
+
+	// The items that were deleted are no longer scheduled, any item that's
+	// left (as its delete was rejected or failed) is moved back, otherwise it
+	// would be due at the head of the schedule on every sweep.
+	keyFields := make([]s.KeyField, 0, len(values))
+	for _, v := range values {
+		keyFields = append(keyFields, s.KeyField{Key: v.Key, Field: v.Field})
+	}
+	if err := m.sf.Postpone(context.Background(), keyFields, now.Add(defaultManagerBackoff)); err != nil {
+		log.Println("Schedule failure", err)
+	}
 }
 
-func (b *timeSlots) Peek() []s.KeyFieldScoreSizeExpiry {
-	b.mutex.Lock()
-	defer b.mutex.Unlock()
+// fullSweep gets all the keys of the shards that are held, then all the fields
+// and then checks to see if the item has expired, if it has delete it!
+func (m *managerCollect) fullSweep() {
+	ctx := context.Background()
+
+	keys, err := m.co.Keys(ctx)
+	if err != nil {
+		return
+	}
+
+	shuffle(keys)
 
 	var (
-		step = stepRound(time.Now().UnixNano(), defaultStep)
-		slot = makeTimeRange(step, defaultIntervalSweep)
+		now    = time.Now()
+		values = []s.KeyFieldScoreTxnValue{}
 	)
 
-	if slots, ok := b.slots[slot]; ok {
-		delete(b.slots, slot)
-		return slots
+	for _, key := range keys {
+		if !m.leases.Held(m.leases.Shard(key)) {
+			continue
+		}
+
+		fields, err := m.co.Members(ctx, key)
+		if err != nil {
+			continue
+		}
+
+		for _, field := range fields {
+			if item, ok := selectItem(ctx, m.sf, now, key, field); ok {
+				values = append(values, item)
+			}
+		}
+	}
+
+	if m.delete(now, values) {
+		// Renewing the leases of the shards that have been swept, tells how
+		// long an expired item could have waited to be swept.
+		for shard := 0; shard < m.leases.Len(); shard++ {
+			if lag, ok := m.leases.Renew(shard, now); ok {
+				go m.instr.SweepLag(lag)
+			}
+		}
+	}
+}
+
+func (m *managerCollect) delete(now time.Time, values []s.KeyFieldScoreTxnValue) bool {
+	go m.instr.SweepCall()
+	defer func() { go m.instr.SweepDuration(time.Since(now)) }()
+
+	if len(values) < 1 {
+		return true
+	}
+
+	// The room of an expired item is free whether or not it's deleted, so the
+	// waiting lists of the keys are offered it either way.
+	defer m.promote(values)
+
+	amount, err := m.co.Expire(context.Background(), values, s.MakeKeySizeExpiry())
+	if err != nil {
+		log.Println("Partial failure", err)
+		return false
 	}
 
-	return []s.KeyFieldScoreSizeExpiry{}
+	go m.instr.SweepDeleted(amount)
+	return true
 }
 
-func stepRound(v int64, d int64) int64 {
-	return int64(math.Ceil(float64(v/d))) * d
+// promote offers the room of every key that's been swept to its waiting list,
+// in the background so that it doesn't hold up the sweep.
+func (m *managerCollect) promote(values []s.KeyFieldScoreTxnValue) {
+	keys := map[bs.Key]struct{}{}
+	for _, v := range values {
+		keys[v.Key] = struct{}{}
+	}
+
+	for key := range keys {
+		go func(key bs.Key) {
+			if _, err := m.co.Promote(context.Background(), key); err != nil {
+				log.Printf("Promote failure %s: %s\n", key.String(), err)
+			}
+		}(key)
+	}
+}
+
+func selectItem(ctx context.Context, sf *store.Farm, now time.Time, key, field bs.Key) (s.KeyFieldScoreTxnValue, bool) {
+	item, err := sf.Select(ctx, key, field)
+	if err != nil {
+		if err != cs.ErrExpiredNode {
+			return s.KeyFieldScoreTxnValue{}, false
+		}
+	}
+
+	return s.KeyFieldScoreTxnValue{
+		Key:   item.Key,
+		Field: item.Field,
+		Score: item.Score + 1,
+		Txn:   item.Txn,
+		Value: item.Value,
+	}, true
 }
 
 func shuffle(keys []bs.Key) {
@@ -6,6 +6,7 @@ import (
 	"github.com/SimonRichardson/echelon/common"
 	"github.com/SimonRichardson/echelon/env"
 	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/clocks"
 	"github.com/SimonRichardson/echelon/internal/typex"
 )
 
@@ -18,7 +19,15 @@ func NewManagerStrategy(e *env.Env) (ManagerStrategyCreator, error) {
 		if err != nil {
 			return managerNoopStrategy, err
 		}
-		return managerCollectStrategy(dur), nil
+		lease, err := time.ParseDuration(e.ManagerSweepLease)
+		if err != nil {
+			return managerNoopStrategy, err
+		}
+		if e.ManagerSweepShards < 1 {
+			return managerNoopStrategy, typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
+				"Invalid sweep shards")
+		}
+		return managerCollectStrategy(dur, e.ManagerSweepShards, lease), nil
 	case "noop":
 		return managerNoopStrategy, nil
 	}
@@ -66,3 +75,23 @@ func NewInsertStrategy(e *env.Env) (InsertStrategy, error) {
 	return insertNoop, typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
 		"No tactic found")
 }
+
+func NewClockStrategy(e *env.Env) (ClockStrategy, error) {
+	options := e.GetClockOptions(env.Coordinator)
+
+	switch common.Normalise(options.Strategy) {
+	case "stamp":
+		return clockStamp{clocks.New()}, nil
+	case "validate":
+		maxSkew, err := time.ParseDuration(options.MaxSkew)
+		if err != nil {
+			return clockNoop{}, err
+		}
+		return clockValidate{clocks.New(), maxSkew}, nil
+	case "noop":
+		return clockNoop{}, nil
+	}
+
+	return clockNoop{}, typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
+		"No strategy found")
+}
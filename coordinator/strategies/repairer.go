@@ -1,6 +1,7 @@
 package strategies
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -14,9 +15,9 @@ import (
 )
 
 // RepairStrategy defines if a repair can actually happen or if it'll be refused
-type RepairStrategy func(*store.Farm, []s.KeyFieldTxnValue, s.KeySizeExpiry) error
+type RepairStrategy func(context.Context, *store.Farm, []s.KeyFieldTxnValue, s.KeySizeExpiry) error
 
-func repairNonBlocking(farm *store.Farm, members []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
+func repairNonBlocking(ctx context.Context, farm *store.Farm, members []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
 	buckets := map[bs.Key][]s.KeyFieldTxnValue{}
 	for _, v := range members {
 		buckets[v.Key] = append(buckets[v.Key], v)
@@ -30,7 +31,7 @@ func repairNonBlocking(farm *store.Farm, members []s.KeyFieldTxnValue, maxSize s
 
 	for _, v := range buckets {
 		go func(elements []s.KeyFieldTxnValue) {
-			if err := farm.Repair(elements, maxSize); err != nil {
+			if err := farm.Repair(ctx, elements, maxSize); err != nil {
 				responses <- err
 			}
 		}(v)
@@ -45,17 +46,17 @@ func repairNonBlocking(farm *store.Farm, members []s.KeyFieldTxnValue, maxSize s
 		"Error Repairing (%s)", common.SumErrors(errs).Error())
 }
 
-func repairNoopTactic(*store.Farm, []s.KeyFieldTxnValue, s.KeySizeExpiry) error {
+func repairNoopTactic(context.Context, *store.Farm, []s.KeyFieldTxnValue, s.KeySizeExpiry) error {
 	return nil
 }
 
 func repairRateLimited(maxElements int64, maxDuration time.Duration, strategy RepairStrategy) RepairStrategy {
 	permits := permitters.New(maxElements, maxDuration)
-	return func(farm *store.Farm, members []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
+	return func(ctx context.Context, farm *store.Farm, members []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
 		if num := len(members); !permits.Allowed(int64(num)) {
 			return nil
 		}
 
-		return strategy(farm, members, maxSize)
+		return strategy(ctx, farm, members, maxSize)
 	}
 }
@@ -0,0 +1,215 @@
+package coordinator
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/SimonRichardson/echelon/cluster/waitlist"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/farm/counter"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/pool"
+	"github.com/SimonRichardson/echelon/schemas/records"
+	"github.com/SimonRichardson/echelon/schemas/schema"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+type waitlister struct {
+	s.LifeCycleManager
+
+	co       *Coordinator
+	counter  *counter.Farm
+	waitlist waitlist.Cluster
+	hold     time.Duration
+}
+
+func newWaitlister(co *Coordinator,
+	counter *counter.Farm,
+	waitlist waitlist.Cluster,
+	hold time.Duration,
+) *waitlister {
+	return &waitlister{
+		LifeCycleManager: newLifeCycleService(),
+
+		co:       co,
+		counter:  counter,
+		waitlist: waitlist,
+		hold:     hold,
+	}
+}
+
+// Join adds the entry to the waiting list of the key. If there's already room,
+// the head of the waiting list is offered it straight away, in which case the
+// position of an entry that was offered a hold is 0.
+func (w *waitlister) Join(ctx context.Context, key bs.Key, entry s.WaitlistEntry) (int, error) {
+	if entry.Len() < 1 {
+		return 0, typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Nothing to wait for")
+	}
+	for _, v := range entry.Members {
+		if header, err := records.ReadType(v.Value); err != nil || header != schema.TypePost {
+			return 0, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Only holds can be waited for")
+		}
+	}
+
+	if _, err := w.waitlist.Join(key, entry); err != nil {
+		return 0, err
+	}
+
+	if _, err := w.Promote(ctx, key); err != nil {
+		return 0, err
+	}
+
+	position, _, _, err := w.waitlist.Position(key, entry.Id)
+	return position, err
+}
+
+func (w *waitlister) Leave(ctx context.Context, key, id bs.Key) (bool, error) {
+	return w.waitlist.Leave(key, id)
+}
+
+func (w *waitlister) Position(ctx context.Context, key, id bs.Key) (int, int, bool, error) {
+	return w.waitlist.Position(key, id)
+}
+
+// Promote offers a hold to the head of the waiting list, for as long as there
+// is room for it. The head blocks the rest of the waiting list, so that the
+// entries are always offered a hold in the order they joined.
+//
+// Only one promoter runs for a key at a time, a promote that's asked for
+// whilst another is running is left to the one that's running.
+func (w *waitlister) Promote(ctx context.Context, key bs.Key) (int, error) {
+	var (
+		token    = strconv.FormatInt(rand.Int63(), 36)
+		promoted = 0
+	)
+	for {
+		amount, err := w.promote(ctx, key, token)
+		promoted += amount
+
+		pending, e := w.waitlist.Release(key, token)
+		if err != nil {
+			return promoted, err
+		}
+		if e != nil || !pending {
+			return promoted, e
+		}
+	}
+}
+
+// promote offers holds whilst it holds the lock of the promoter. The head is
+// only removed once it's been offered the hold, so that it keeps its position
+// if the insert fails and it's never lost if the promoter goes away.
+func (w *waitlister) promote(ctx context.Context, key bs.Key, token string) (int, error) {
+	promoted := 0
+	for {
+		// Renew the lock for every entry, so that it only expires if the
+		// promoter goes away.
+		if ok, err := w.waitlist.Acquire(key, token, defaultPromoteExpiry); err != nil || !ok {
+			return promoted, err
+		}
+
+		size, err := w.counter.Size(ctx, key)
+		if err != nil {
+			return promoted, err
+		}
+
+		entry, ok, err := w.waitlist.Peek(key)
+		if err != nil || !ok {
+			return promoted, err
+		}
+
+		if int64(size+entry.Len()) > entry.MaxSize.Size {
+			return promoted, nil
+		}
+
+		members, err := hold(entry.Members, time.Now(), w.co.clock.Now(), w.hold)
+		if err != nil {
+			// The entry can't ever be offered a hold, so it's dropped rather
+			// than blocking the rest of the waiting list.
+			teleprinter.L.Error().Printf("Failed to promote %s: %s\n",
+				entry.Id.String(), err.Error())
+			if _, err := w.waitlist.Leave(key, entry.Id); err != nil {
+				return promoted, err
+			}
+			continue
+		}
+
+		sizeExpiry := s.KeySizeExpiry{key: entry.MaxSize}
+		if _, err := w.co.Insert(ctx, members, sizeExpiry); err != nil {
+			// Someone else took the room in the meantime.
+			if e, ok := err.(*typex.Error); ok && e.Is(errors.MaxSize.Name()) {
+				return promoted, nil
+			}
+			return promoted, err
+		}
+
+		left, err := w.waitlist.Leave(key, entry.Id)
+		if err != nil {
+			return promoted, err
+		}
+		if !left {
+			// The entry left whilst it was being offered the hold, so the hold
+			// is taken back.
+			if err := w.co.Rollback(ctx, members, sizeExpiry); err != nil {
+				return promoted, err
+			}
+			continue
+		}
+
+		go w.co.notifier.Publish(context.Background(), defaultPromoteChannel, s.KeyFieldScoreTxnValues(members).KeyFieldScoreSizeExpiry(sizeExpiry))
+		promoted++
+	}
+}
+
+// hold renews the members, so that the hold that's offered expires from now
+// rather than from when the entry joined. The members are scored by the clock of
+// the coordinator, as the hold is written on behalf of the entry.
+func hold(members []s.KeyFieldScoreTxnValue, now time.Time, score float64, expiry time.Duration) ([]s.KeyFieldScoreTxnValue, error) {
+	fb := pool.Get()
+	defer pool.Put(fb)
+
+	result := make([]s.KeyFieldScoreTxnValue, 0, len(members))
+	for _, v := range members {
+		body, err := records.ReadBody(v.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		record := &records.PostRecord{}
+		if err := record.Read(body); err != nil {
+			return nil, err
+		}
+		record.Expiry = now.Add(expiry)
+		record.State = records.Held
+
+		fb.Reset()
+
+		value, err := record.Write(fb)
+		if err != nil {
+			return nil, err
+		}
+
+		v.Score = score
+		v.Value = records.PackagePostRecord(value)
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// promote offers the room that's been freed up to the waiting list of the key
+// in the background. As it outlives the delete, it's not bound to the context
+// of the caller.
+func (co *Coordinator) promote(key bs.Key) {
+	go func() {
+		if _, err := co.waitlister.Promote(context.Background(), key); err != nil {
+			teleprinter.L.Error().Printf("Failed to promote %s: %s\n",
+				key.String(), err.Error())
+		}
+	}()
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/SimonRichardson/echelon/echelon-http/admission"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	admissionMetadata  = "admission-token"
+	retryAfterMetadata = "retry-after"
+)
+
+// admit checks that the call has an admission-token in its metadata that's
+// been admitted for the key, in the same way that the http api checks the
+// Admission-Token header. A call that's not been admitted yet is told when to
+// try again in the retry-after header.
+// The token is returned, so that its use can be given back if the call fails.
+func admit(ctx context.Context, room *admission.Room, key bs.Key) (string, error) {
+	if room == nil {
+		return "", nil
+	}
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md[admissionMetadata]; len(values) > 0 {
+			token = values[0]
+		}
+	}
+	if token == "" {
+		return "", typex.Errorf(errors.Source, errors.InvalidToken,
+			"Missing admission-token for %s", key.String())
+	}
+
+	ticket, err := room.Admit(key, token)
+	if err != nil {
+		if !ticket.Admitted() {
+			seconds := int64(math.Ceil(ticket.ETA.Seconds()))
+			grpc.SetHeader(ctx, metadata.Pairs(retryAfterMetadata, strconv.FormatInt(seconds, 10)))
+		}
+		return "", err
+	}
+	return token, nil
+}
+
+// release gives back the use of the token, logging if it can't be.
+func release(room *admission.Room, key bs.Key, token string) {
+	if room == nil || token == "" {
+		return
+	}
+
+	if err := room.Release(key, token); err != nil {
+		teleprinter.L.Error().Printf("Unable to release admission token for %s : %s\n", key.String(), err)
+	}
+}
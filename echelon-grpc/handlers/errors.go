@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BadRequest returns the error as a gRPC status, falling back to an invalid
+// argument if the error doesn't carry a code.
+func BadRequest(ctx context.Context, method string, err error) error {
+	return respondError(ctx, method, typex.BadRequest, err)
+}
+
+// InternalServerError returns the error as a gRPC status, falling back to an
+// internal error if the error doesn't carry a code.
+func InternalServerError(ctx context.Context, method string, err error) error {
+	return respondError(ctx, method, typex.InternalServerError, err)
+}
+
+func respondError(ctx context.Context, method string, fallback typex.ErrorCode, err error) error {
+	code := getCode(ctx, err, fallback)
+
+	teleprinter.L.Error().Printf("%s: gRPC %s: %s\n", method, code, typex.Inspect(err))
+
+	return status.Error(code, err.Error())
+}
+
+func getCode(ctx context.Context, err error, fallback typex.ErrorCode) codes.Code {
+	// A client that went away is told so, rather than being told the request
+	// timed out.
+	if ctx.Err() == context.Canceled {
+		return codes.Canceled
+	}
+
+	code := typex.ErrCode(err)
+	if code < 0 {
+		code = fallback.HTTPStatusCode()
+	}
+
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusUnprocessableEntity:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,583 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/SimonRichardson/echelon/coordinator"
+	"github.com/SimonRichardson/echelon/echelon-http/admission"
+	requests "github.com/SimonRichardson/echelon/echelon-http/handlers"
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/records"
+	"github.com/SimonRichardson/echelon/schemas/schema"
+	"github.com/SimonRichardson/echelon/selectors"
+	"github.com/google/flatbuffers/go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Service serves the operations of the coordinator over gRPC. The requests
+// carry the same flatbuffers as the bodies of the http api, along with the key
+// that's otherwise part of the url.
+type Service struct {
+	co       *coordinator.Coordinator
+	room     *admission.Room
+	interval time.Duration
+	limit    int
+}
+
+// New creates a Service for the coordinator. The changes are polled every
+// interval, reading at most limit changes at a time unless a call asks for
+// something else. If there is a room, then the inserts need an admitted token,
+// just like the inserts of the http api.
+func New(co *coordinator.Coordinator,
+	room *admission.Room,
+	interval time.Duration,
+	limit int,
+) *Service {
+	return &Service{
+		co:       co,
+		room:     room,
+		interval: interval,
+		limit:    limit,
+	}
+}
+
+// Insert adds items into the collection. If there is a room, then the call
+// needs an admitted token in its admission-token metadata.
+func (s *Service) Insert(ctx context.Context, call *schema.InsertCall) (*flatbuffers.Builder, error) {
+	began := time.Now()
+
+	key, err := readKey(call.Key())
+	if err != nil {
+		return nil, BadRequest(ctx, "Insert", err)
+	}
+
+	request := call.Request(nil)
+	if request == nil {
+		return nil, BadRequest(ctx, "Insert", errInvalidRequest())
+	}
+
+	fieldTxnValues, score, maxSize, expiry, err := requests.ReadPostRequest(request)
+	if err != nil {
+		return nil, BadRequest(ctx, "Insert", err)
+	}
+
+	token, err := admit(ctx, s.room, key)
+	if err != nil {
+		return nil, BadRequest(ctx, "Insert", err)
+	}
+
+	var (
+		maxSizeExpiry = selectors.MakeKeySizeSingleton(key, maxSize, expiry)
+		elements      = fieldTxnValues.KeyFieldScoreTxnValues(key, score)
+	)
+
+	results, err := s.co.Insert(ctx, elements, maxSizeExpiry)
+	if err != nil {
+		if code := typex.ErrCode(err); code < 0 || code >= http.StatusInternalServerError {
+			release(s.room, key, token)
+		}
+		return nil, InternalServerError(ctx, "Insert", err)
+	}
+
+	return respond(ctx, "Insert", records.OKInt{
+		Duration: time.Since(began),
+		Records:  results,
+	})
+}
+
+// Modify replaces items in the collection.
+func (s *Service) Modify(ctx context.Context, call *schema.ModifyCall) (*flatbuffers.Builder, error) {
+	began := time.Now()
+
+	key, err := readKey(call.Key())
+	if err != nil {
+		return nil, BadRequest(ctx, "Modify", err)
+	}
+
+	request := call.Request(nil)
+	if request == nil {
+		return nil, BadRequest(ctx, "Modify", errInvalidRequest())
+	}
+
+	fieldTxnValues, score, maxSize, expiry, err := requests.ReadPutRequest(request)
+	if err != nil {
+		return nil, BadRequest(ctx, "Modify", err)
+	}
+
+	var (
+		maxSizeExpiry = selectors.MakeKeySizeSingleton(key, maxSize, expiry)
+		elements      = fieldTxnValues.KeyFieldScoreTxnValues(key, score)
+	)
+
+	results, err := s.co.Modify(ctx, elements, maxSizeExpiry)
+	if err != nil {
+		return nil, InternalServerError(ctx, "Modify", err)
+	}
+
+	return respond(ctx, "Modify", records.OKInt{
+		Duration: time.Since(began),
+		Records:  results,
+	})
+}
+
+// ModifyWithOperations applies the operations to an item in the collection.
+func (s *Service) ModifyWithOperations(ctx context.Context, call *schema.ModifyWithOperationsCall) (*flatbuffers.Builder, error) {
+	began := time.Now()
+
+	key, err := readKey(call.Key())
+	if err != nil {
+		return nil, BadRequest(ctx, "ModifyWithOperations", err)
+	}
+
+	id, err := readId(call.Id())
+	if err != nil {
+		return nil, BadRequest(ctx, "ModifyWithOperations", err)
+	}
+
+	request := call.Request(nil)
+	if request == nil {
+		return nil, BadRequest(ctx, "ModifyWithOperations", errInvalidRequest())
+	}
+
+	operations, score, maxSize, expiry, err := requests.ReadPatchRequest(request)
+	if err != nil {
+		return nil, BadRequest(ctx, "ModifyWithOperations", err)
+	}
+
+	results, err := s.co.ModifyWithOperations(ctx, key,
+		id,
+		operations,
+		score,
+		selectors.SizeExpiry{
+			Size:   maxSize,
+			Expiry: expiry,
+		})
+	if err != nil {
+		return nil, InternalServerError(ctx, "ModifyWithOperations", err)
+	}
+
+	return respond(ctx, "ModifyWithOperations", records.OKInt{
+		Duration: time.Since(began),
+		Records:  results,
+	})
+}
+
+// Delete removes items from the collection.
+func (s *Service) Delete(ctx context.Context, call *schema.DeleteCall) (*flatbuffers.Builder, error) {
+	began := time.Now()
+
+	key, err := readKey(call.Key())
+	if err != nil {
+		return nil, BadRequest(ctx, "Delete", err)
+	}
+
+	request := call.Request(nil)
+	if request == nil {
+		return nil, BadRequest(ctx, "Delete", errInvalidRequest())
+	}
+
+	fieldTxnValues, score, maxSize, expiry, err := requests.ReadDeleteRequest(request)
+	if err != nil {
+		return nil, BadRequest(ctx, "Delete", err)
+	}
+
+	var (
+		maxSizeExpiry = selectors.MakeKeySizeSingleton(key, maxSize, expiry)
+		elements      = fieldTxnValues.KeyFieldScoreTxnValues(key, score)
+	)
+
+	results, err := s.co.Delete(ctx, elements, maxSizeExpiry)
+	if err != nil {
+		return nil, InternalServerError(ctx, "Delete", err)
+	}
+
+	return respond(ctx, "Delete", records.OKInt{
+		Duration: time.Since(began),
+		Records:  results,
+	})
+}
+
+// Rollback undoes the insertion of items into the collection.
+func (s *Service) Rollback(ctx context.Context, call *schema.RollbackCall) (*flatbuffers.Builder, error) {
+	began := time.Now()
+
+	key, err := readKey(call.Key())
+	if err != nil {
+		return nil, BadRequest(ctx, "Rollback", err)
+	}
+
+	request := call.Request(nil)
+	if request == nil {
+		return nil, BadRequest(ctx, "Rollback", errInvalidRequest())
+	}
+
+	fieldTxnValues, score, maxSize, expiry, err := requests.ReadRollbackRequest(request)
+	if err != nil {
+		return nil, BadRequest(ctx, "Rollback", err)
+	}
+
+	var (
+		maxSizeExpiry = selectors.MakeKeySizeSingleton(key, maxSize, expiry)
+		elements      = fieldTxnValues.KeyFieldScoreTxnValues(key, score)
+	)
+
+	if err := s.co.Rollback(ctx, elements, maxSizeExpiry); err != nil {
+		return nil, InternalServerError(ctx, "Rollback", err)
+	}
+
+	return respond(ctx, "Rollback", records.OKNoContent{
+		Duration: time.Since(began),
+	})
+}
+
+// Select returns an item of the collection.
+func (s *Service) Select(ctx context.Context, call *schema.SelectCall) (*flatbuffers.Builder, error) {
+	began := time.Now()
+
+	key, err := readKey(call.Key())
+	if err != nil {
+		return nil, BadRequest(ctx, "Select", err)
+	}
+
+	id, err := readId(call.Id())
+	if err != nil {
+		return nil, BadRequest(ctx, "Select", err)
+	}
+
+	result, err := s.co.Select(ctx, key, id)
+	if err != nil {
+		return nil, InternalServerError(ctx, "Select", err)
+	}
+
+	return respond(ctx, "Select", records.OKKeyFieldScoreTxnValue{
+		Duration: time.Since(began),
+		Records:  records.FromKeyFieldScoreTxnValue(result),
+	})
+}
+
+// SelectRange returns the items of the collection.
+func (s *Service) SelectRange(ctx context.Context, call *schema.SelectRangeCall) (*flatbuffers.Builder, error) {
+	began := time.Now()
+
+	key, limit, maxSizeExpiry, err := readSelectRange(call)
+	if err != nil {
+		return nil, BadRequest(ctx, "SelectRange", err)
+	}
+
+	results, err := s.co.SelectRange(ctx, key, limit, maxSizeExpiry)
+	if err != nil {
+		return nil, InternalServerError(ctx, "SelectRange", err)
+	}
+
+	return respond(ctx, "SelectRange", records.OKKeyFieldScoreTxnValues{
+		Duration: time.Since(began),
+		Records:  records.FromKeyFieldScoreTxnValues(results),
+	})
+}
+
+// StreamRange sends the items of the collection one at a time, so a client
+// can start working through a large range before it's been sent in full. Only
+// the fields are read up front, each item is then selected as it's sent.
+func (s *Service) StreamRange(call *schema.SelectRangeCall, stream schema.Echelon_StreamRangeServer) error {
+	ctx := stream.Context()
+
+	key, limit, _, err := readSelectRange(call)
+	if err != nil {
+		return BadRequest(ctx, "StreamRange", err)
+	}
+
+	fields, err := s.co.Members(ctx, key)
+	if err != nil {
+		return InternalServerError(ctx, "StreamRange", err)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i] < fields[j] })
+
+	sent := 0
+	for _, field := range fields {
+		if sent >= limit {
+			break
+		}
+
+		result, err := s.co.Select(ctx, key, field)
+		if err != nil {
+			if ctx.Err() != nil {
+				return InternalServerError(ctx, "StreamRange", err)
+			}
+			// The field has either expired or been removed since the fields
+			// were read.
+			continue
+		}
+
+		fb, err := build(records.FromKeyFieldScoreTxnValue(result))
+		if err != nil {
+			return InternalServerError(ctx, "StreamRange", err)
+		}
+		if err := stream.Send(fb); err != nil {
+			return err
+		}
+		sent++
+	}
+	return nil
+}
+
+// Query returns the items of the collection that match the predicates of the
+// call.
+func (s *Service) Query(ctx context.Context, call *schema.QueryCall) (*flatbuffers.Builder, error) {
+	began := time.Now()
+
+	key, err := readKey(call.Key())
+	if err != nil {
+		return nil, BadRequest(ctx, "Query", err)
+	}
+
+	var (
+		maxSize = call.MaxSize()
+		expiry  = call.Expiry()
+	)
+	if maxSize < 1 || expiry < 1 {
+		return nil, BadRequest(ctx, "Query", errInvalidParameter())
+	}
+
+	options, err := readQueryOptions(call)
+	if err != nil {
+		return nil, BadRequest(ctx, "Query", err)
+	}
+
+	results, err := s.co.Query(ctx, key, options, selectors.SizeExpiry{
+		Size:   int64(maxSize),
+		Expiry: time.Duration(expiry),
+	})
+	if err != nil {
+		return nil, InternalServerError(ctx, "Query", err)
+	}
+
+	recs, err := records.FromQueryRecords(results)
+	if err != nil {
+		return nil, BadRequest(ctx, "Query", err)
+	}
+
+	return respond(ctx, "Query", records.OKQuery{
+		Duration: time.Since(began),
+		Records:  recs,
+	})
+}
+
+// Size returns the number of items in the collection.
+func (s *Service) Size(ctx context.Context, call *schema.SizeCall) (*flatbuffers.Builder, error) {
+	began := time.Now()
+
+	key, err := readKey(call.Key())
+	if err != nil {
+		return nil, BadRequest(ctx, "Size", err)
+	}
+
+	size, err := s.co.Size(ctx, key)
+	if err != nil {
+		return nil, InternalServerError(ctx, "Size", err)
+	}
+
+	return respond(ctx, "Size", records.OKInt{
+		Duration: time.Since(began),
+		Records:  size,
+	})
+}
+
+// Keys returns all the keys of the collections.
+func (s *Service) Keys(ctx context.Context, call *schema.KeysCall) (*flatbuffers.Builder, error) {
+	began := time.Now()
+
+	keys, err := s.co.Keys(ctx)
+	if err != nil {
+		return nil, InternalServerError(ctx, "Keys", err)
+	}
+
+	return respond(ctx, "Keys", records.OKKeys{
+		Duration: time.Since(began),
+		Records:  keys,
+	})
+}
+
+// Changes streams the changes (inserts, deletes, rollbacks and states) of the
+// collection. The stream can be resumed by calling again with the score of the
+// last change that was received.
+func (s *Service) Changes(call *schema.ChangesCall, stream schema.Echelon_ChangesServer) error {
+	ctx := stream.Context()
+
+	key, err := readKey(call.Key())
+	if err != nil {
+		return BadRequest(ctx, "Changes", err)
+	}
+
+	var (
+		since = call.Since()
+		limit = s.limit
+	)
+	if amount := call.Limit(); amount < 0 {
+		return BadRequest(ctx, "Changes", errInvalidParameter())
+	} else if amount > 0 {
+		limit = int(amount)
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	var (
+		// Changes can share a score, so once a change has been sent the
+		// changes are read again from its score and the changes that have
+		// already been sent with that score are skipped.
+		last   float64
+		sent   = map[selectors.Change]struct{}{}
+		amount = limit
+	)
+	for {
+		changes, err := s.co.Changes(ctx, key, since, amount)
+		if err != nil {
+			return InternalServerError(ctx, "Changes", err)
+		}
+
+		fresh := 0
+		for _, v := range changes {
+			if _, ok := sent[v]; ok {
+				continue
+			}
+
+			fb, err := build(records.FromChange(v))
+			if err != nil {
+				return InternalServerError(ctx, "Changes", err)
+			}
+			if err := stream.Send(fb); err != nil {
+				return err
+			}
+
+			if len(sent) == 0 || v.Score != last {
+				last, sent = v.Score, map[selectors.Change]struct{}{}
+			}
+			sent[v] = struct{}{}
+			since = math.Nextafter(last, math.Inf(-1))
+			fresh++
+		}
+
+		// There might be more changes waiting, so don't wait for them. If
+		// the changes were all sent already, then there are more changes
+		// with the same score than fit in a read, so read more of them.
+		if len(changes) >= amount {
+			if fresh == 0 {
+				amount *= 2
+			} else {
+				amount = limit
+			}
+			continue
+		}
+		amount = limit
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// respond writes the record to a builder that's handed over to gRPC, so unlike
+// the http responses the builder isn't taken from the pool.
+func respond(ctx context.Context, method string, record records.Write) (*flatbuffers.Builder, error) {
+	fb, err := build(record)
+	if err != nil {
+		return nil, InternalServerError(ctx, method, typex.Errorf(errors.Source, typex.InternalServerError,
+			"Unable to create response for %s.", method).With(err))
+	}
+	return fb, nil
+}
+
+func build(record records.Write) (*flatbuffers.Builder, error) {
+	fb := flatbuffers.NewBuilder(0)
+	if _, err := record.Write(fb); err != nil {
+		return nil, err
+	}
+	return fb, nil
+}
+
+func readKey(key []byte) (bs.Key, error) {
+	if hex := string(key); bson.IsObjectIdHex(hex) {
+		return bs.Key(hex), nil
+	}
+	return bs.Key(""), typex.Errorf(errors.Source, errors.InvalidArgument,
+		"Invalid Key: %s", key)
+}
+
+func readId(id []byte) (bs.Key, error) {
+	if hex := string(id); bson.IsObjectIdHex(hex) {
+		return bs.Key(hex), nil
+	}
+	return bs.Key(""), typex.Errorf(errors.Source, errors.InvalidArgument,
+		"Invalid Id: %s", id)
+}
+
+func readSelectRange(call *schema.SelectRangeCall) (bs.Key, int, selectors.KeySizeExpiry, error) {
+	key, err := readKey(call.Key())
+	if err != nil {
+		return key, 0, nil, err
+	}
+
+	var (
+		limit   = call.Limit()
+		maxSize = call.MaxSize()
+		expiry  = call.Expiry()
+	)
+	if limit < 1 || maxSize < 1 || expiry < 1 {
+		return key, 0, nil, errInvalidParameter()
+	}
+
+	return key, int(limit), selectors.MakeKeySizeSingleton(key, int64(maxSize), time.Duration(expiry)), nil
+}
+
+// readQueryOptions reads the query options from the call, in the same form as
+// the http api accepts them (e.g. "expiry:lte:now+1m" and "-expiry").
+func readQueryOptions(call *schema.QueryCall) (selectors.QueryOptions, error) {
+	var options selectors.QueryOptions
+
+	if ownerId := string(call.OwnerId()); ownerId != "" {
+		if !bson.IsObjectIdHex(ownerId) {
+			return options, errInvalidParameter()
+		}
+		options.OwnerId = bs.Key(ownerId)
+	}
+
+	for i := 0; i < call.WhereLength(); i++ {
+		predicate, err := selectors.ParsePredicate(string(call.Where(i)))
+		if err != nil {
+			return options, err
+		}
+		options.Predicates = append(options.Predicates, predicate)
+	}
+
+	for i := 0; i < call.SortLength(); i++ {
+		sort, err := selectors.ParseQuerySort(string(call.Sort(i)))
+		if err != nil {
+			return options, err
+		}
+		options.Sort = append(options.Sort, sort)
+	}
+
+	if call.Limit() < 0 || call.Offset() < 0 {
+		return options, errInvalidParameter()
+	}
+	options.Limit = int(call.Limit())
+	options.Offset = int(call.Offset())
+
+	return options, nil
+}
+
+func errInvalidRequest() error {
+	return typex.Errorf(errors.Source, errors.InvalidArgument, "Invalid Request")
+}
+
+func errInvalidParameter() error {
+	return typex.Errorf(errors.Source, errors.InvalidArgument, "Invalid request parameter")
+}
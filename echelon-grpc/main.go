@@ -0,0 +1,154 @@
+package main
+
+import (
+	"log"
+	"math/rand"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	ca "github.com/SimonRichardson/echelon/cluster/admission"
+	"github.com/SimonRichardson/echelon/coordinator"
+	"github.com/SimonRichardson/echelon/echelon-grpc/handlers"
+	"github.com/SimonRichardson/echelon/echelon-http/admission"
+	"github.com/SimonRichardson/echelon/env"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	"github.com/SimonRichardson/echelon/internal/logs/parse"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/pool"
+	"github.com/SimonRichardson/echelon/schemas/records"
+	"github.com/SimonRichardson/echelon/schemas/schema"
+	"github.com/google/flatbuffers/go"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultTimeAfterReload = time.Second
+)
+
+type server struct {
+	GrpcAddress string
+	Server      *grpc.Server
+	co          *coordinator.Coordinator
+}
+
+func main() {
+	log.SetOutput(os.Stdout)
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+
+	rand.Seed(time.Now().UnixNano())
+	pool.SetMax(1000)
+
+	var (
+		e      = env.New(nil)
+		server = newServer(e)
+
+		accessor = coordinator.NewCoordinatorAccessor(server.co)
+		alerts   = accessor.AlertManager()
+	)
+
+	// hot reloading
+	go func() {
+		watcher := e.Watch()
+		for {
+			select {
+			case <-watcher:
+				// Pause the server
+				func() {
+					server.co.Pause()
+					defer server.co.Resume()
+
+					if err := server.co.Topology(e); err != nil {
+						typex.Fatal("PANIC: Disaster!", err)
+
+						alerts.TopologyPanic()
+					}
+
+					time.Sleep(defaultTimeAfterReload)
+				}()
+			}
+		}
+	}()
+
+	listener, err := net.Listen("tcp", server.GrpcAddress)
+	if err != nil {
+		typex.Fatal(err)
+	}
+
+	// Stop accepting calls once we're asked to quit, but let the calls that are
+	// in flight finish first.
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
+		<-signals
+
+		server.Server.GracefulStop()
+		server.co.Quit()
+	}()
+
+	log.Printf("listening on %s", server.GrpcAddress)
+	if err := server.Server.Serve(listener); err != nil {
+		typex.Fatal(err)
+	}
+}
+
+func setupLogging(e *env.Env) {
+	var err error
+	if teleprinter.L, err = parse.ParseString(e.Logs); err != nil {
+		typex.Fatal(err)
+	}
+}
+
+// newAdmission creates the same waiting room as the http server, sharing the
+// queues and the secret so that a token can be used with either of them. If
+// there is no secret then every insert is let through.
+func newAdmission(e *env.Env) *admission.Room {
+	if e.HttpAdmissionSecret == "" {
+		return nil
+	}
+
+	queue, err := ca.ParseString(
+		e.HttpAdmissionInstances,
+		e.NotifierConnectTimeout, e.NotifierReadTimeout, e.NotifierWriteTimeout,
+		e.NotifierPoolRoutingStrategy,
+		e.NotifierMaxSize,
+		e.RedisCreator,
+	)
+	if err != nil {
+		typex.Fatal(err)
+	}
+
+	return admission.New(e.HttpAdmissionSecret, queue, admission.Options{
+		PerDuration:      e.HttpAdmissionPerDuration,
+		Duration:         e.HttpAdmissionDuration,
+		Expiry:           e.HttpAdmissionExpiry,
+		Uses:             e.HttpAdmissionUses,
+		IssuePerDuration: e.HttpAdmissionIssuePerDuration,
+		IssueDuration:    e.HttpAdmissionIssueDuration,
+	})
+}
+
+func newServer(e *env.Env) server {
+	// Setup logging
+	setupLogging(e)
+
+	var (
+		co      = coordinator.New(e, records.Transform, records.Accessor{})
+		room    = newAdmission(e)
+		service = handlers.New(co, room, e.GrpcChangesInterval, e.GrpcChangesLimit)
+
+		// The messages are the same flatbuffers as the http api uses, so
+		// there's no need for protobuf.
+		s = grpc.NewServer(grpc.CustomCodec(flatbuffers.FlatbuffersCodec{}))
+	)
+
+	schema.RegisterEchelonServer(s, service)
+
+	return server{
+		e.GrpcAddress,
+		s,
+		co,
+	}
+}
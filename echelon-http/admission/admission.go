@@ -0,0 +1,207 @@
+package admission
+
+import (
+	"time"
+
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+)
+
+// Ticket describes where a token is in the queue of a key. A position of 0
+// means that the token has been admitted.
+type Ticket struct {
+	Token    string
+	Position int64
+	ETA      time.Duration
+}
+
+// Admitted returns if the token has been admitted.
+func (t Ticket) Admitted() bool {
+	return t.Position < 1
+}
+
+// Queue defines where the queues of the keys are held. The queues are shared
+// by every instance, so that it doesn't matter which instance a client is
+// routed to.
+type Queue interface {
+	// Issue issues the next sequence in the queue of the key, unless the
+	// client has already been issued limit sequences within the window.
+	Issue(key bs.Key, client string, limit int, window, expiry time.Duration) (int64, bool, error)
+
+	// Advance admits the sequences that are waiting at rate every duration,
+	// returning how many have been issued and how many have been admitted.
+	Advance(key bs.Key, rate int, duration, expiry time.Duration) (int64, int64, error)
+
+	// Use takes one of the uses of the token, returning false once all of them
+	// have been taken.
+	Use(key bs.Key, token string, uses int, expiry time.Duration) (bool, error)
+
+	// Release gives back a use of the token that was taken.
+	Release(key bs.Key, token string) error
+}
+
+// Options defines how the tokens of a room are issued and admitted.
+type Options struct {
+	// PerDuration is how many tokens are admitted every Duration for each key,
+	// 0 admits every token.
+	PerDuration int
+	Duration    time.Duration
+
+	// Expiry is how long a token is valid for after it was issued, so that a
+	// token can't be held on to for the next on-sale.
+	Expiry time.Duration
+
+	// Uses is how many inserts a token can be used for once it's admitted, 0
+	// lets it be used for any amount until it expires.
+	Uses int
+
+	// IssuePerDuration is how many tokens a client is issued every
+	// IssueDuration for each key, 0 issues any amount.
+	IssuePerDuration int
+	IssueDuration    time.Duration
+}
+
+// Room is a waiting room that sits in front of the inserts of a key. Every
+// client is issued an ordered token for the key, which is then admitted at a
+// rate per key, in the order it was issued.
+type Room struct {
+	secret []byte
+	queue  Queue
+	opts   Options
+}
+
+// New creates a room that holds its queues in the queue.
+func New(secret string, queue Queue, opts Options) *Room {
+	return &Room{
+		secret: []byte(secret),
+		queue:  queue,
+		opts:   opts,
+	}
+}
+
+// Issue issues the next token in the queue of the key to the client.
+func (r *Room) Issue(key bs.Key, client string) (Ticket, error) {
+	sequence, ok, err := r.queue.Issue(key, client,
+		r.opts.IssuePerDuration,
+		r.opts.IssueDuration,
+		r.opts.Expiry,
+	)
+	if err != nil {
+		return Ticket{}, err
+	}
+	if !ok {
+		return Ticket{}, typex.Errorf(errors.Source, errors.TooManyTokens,
+			"Too Many Tokens (%d every %s)", r.opts.IssuePerDuration, r.opts.IssueDuration)
+	}
+
+	return r.ticket(Token{
+		Key:      key,
+		Sequence: sequence,
+		Issued:   time.Now(),
+	})
+}
+
+// Status returns where the token is in the queue of the key.
+func (r *Room) Status(key bs.Key, token string) (Ticket, error) {
+	t, err := r.decode(key, token)
+	if err != nil {
+		return Ticket{}, err
+	}
+	return r.ticket(t)
+}
+
+// Admit checks that the token has been admitted for the key, taking one of the
+// uses of the token. The ticket is returned either way, so that a client
+// that's not been admitted yet can be told when to try again.
+func (r *Room) Admit(key bs.Key, token string) (Ticket, error) {
+	t, err := r.decode(key, token)
+	if err != nil {
+		return Ticket{}, err
+	}
+
+	ticket, err := r.ticket(t)
+	if err != nil {
+		return ticket, err
+	}
+	if !ticket.Admitted() {
+		return ticket, typex.Errorf(errors.Source, errors.NotAdmitted,
+			"Not Admitted (position %d)", ticket.Position)
+	}
+
+	if r.opts.Uses > 0 {
+		ok, err := r.queue.Use(key, t.payload(), r.opts.Uses, r.opts.Expiry)
+		if err != nil {
+			return ticket, err
+		}
+		if !ok {
+			return ticket, typex.Errorf(errors.Source, errors.InvalidToken,
+				"Invalid Token (used)")
+		}
+	}
+	return ticket, nil
+}
+
+// Release gives back the use of a token that was admitted, for when the insert
+// it was used for failed through no fault of the client.
+func (r *Room) Release(key bs.Key, token string) error {
+	if r.opts.Uses < 1 {
+		return nil
+	}
+
+	t, err := r.decode(key, token)
+	if err != nil {
+		return err
+	}
+	return r.queue.Release(key, t.payload())
+}
+
+func (r *Room) decode(key bs.Key, token string) (Token, error) {
+	t, err := decode(token, r.secret)
+	if err != nil {
+		return Token{}, err
+	}
+	if t.Key != key {
+		return Token{}, typex.Errorf(errors.Source, errors.InvalidToken,
+			"Invalid Token (key)")
+	}
+	if r.opts.Expiry > 0 && time.Since(t.Issued) > r.opts.Expiry {
+		return Token{}, typex.Errorf(errors.Source, errors.InvalidToken,
+			"Invalid Token (expired)")
+	}
+	return t, nil
+}
+
+// ticket admits as many tokens as the rate allows, before working out where
+// the token is in the queue. Admitting lazily means that there's nothing to do
+// for a key that no one is waiting on.
+func (r *Room) ticket(t Token) (Ticket, error) {
+	issued, admitted, err := r.queue.Advance(t.Key,
+		r.opts.PerDuration,
+		r.opts.Duration,
+		r.opts.Expiry,
+	)
+	if err != nil {
+		return Ticket{}, err
+	}
+	if t.Sequence > issued {
+		return Ticket{}, typex.Errorf(errors.Source, errors.InvalidToken,
+			"Invalid Token (sequence)")
+	}
+
+	position := t.Sequence - admitted
+	if position < 0 {
+		position = 0
+	}
+
+	var eta time.Duration
+	if r.opts.PerDuration > 0 {
+		eta = time.Duration(position) * r.opts.Duration / time.Duration(r.opts.PerDuration)
+	}
+
+	return Ticket{
+		Token:    t.encode(r.secret),
+		Position: position,
+		ETA:      eta,
+	}, nil
+}
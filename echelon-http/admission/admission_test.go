@@ -0,0 +1,232 @@
+package admission
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+)
+
+var defaultKey = bs.Key("5a0c5a0c5a0c5a0c5a0c5a0c")
+
+func isCode(err error, code typex.ErrorCode) bool {
+	e, ok := err.(*typex.Error)
+	return ok && e.Is(code.Name())
+}
+
+// queue admits the first admit sequences of every key, so that the tests can
+// decide who's been admitted.
+type queue struct {
+	admit   int64
+	issued  map[bs.Key]int64
+	clients map[string]int
+	uses    map[string]int
+}
+
+func newQueue(admit int64) *queue {
+	return &queue{
+		admit:   admit,
+		issued:  map[bs.Key]int64{},
+		clients: map[string]int{},
+		uses:    map[string]int{},
+	}
+}
+
+func (q *queue) Issue(key bs.Key, client string, limit int, window, expiry time.Duration) (int64, bool, error) {
+	if q.clients[client]++; limit > 0 && q.clients[client] > limit {
+		return 0, false, nil
+	}
+	q.issued[key]++
+	return q.issued[key], true, nil
+}
+
+func (q *queue) Advance(key bs.Key, rate int, duration, expiry time.Duration) (int64, int64, error) {
+	issued, admitted := q.issued[key], q.admit
+	if rate <= 0 || admitted > issued {
+		admitted = issued
+	}
+	return issued, admitted, nil
+}
+
+func (q *queue) Use(key bs.Key, token string, uses int, expiry time.Duration) (bool, error) {
+	if q.uses[token] >= uses {
+		return false, nil
+	}
+	q.uses[token]++
+	return true, nil
+}
+
+func (q *queue) Release(key bs.Key, token string) error {
+	if q.uses[token] > 0 {
+		q.uses[token]--
+	}
+	return nil
+}
+
+func newRoom(secret string, q Queue, perDuration int, expiry time.Duration) *Room {
+	return New(secret, q, Options{
+		PerDuration: perDuration,
+		Duration:    time.Hour,
+		Expiry:      expiry,
+		Uses:        1,
+	})
+}
+
+func issue(t *testing.T, room *Room, client string) Ticket {
+	ticket, err := room.Issue(defaultKey, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ticket
+}
+
+func TestRoomIssueOrder(t *testing.T) {
+	room := newRoom("secret", newQueue(1), 1, time.Hour)
+
+	for k, v := range []struct {
+		position int64
+		eta      time.Duration
+	}{
+		{0, 0},
+		{1, time.Hour},
+		{2, 2 * time.Hour},
+	} {
+		ticket := issue(t, room, "client")
+		if expected, actual := v.position, ticket.Position; expected != actual {
+			t.Errorf("%d: Expected: %v, Actual: %v", k, expected, actual)
+		}
+		if expected, actual := v.eta, ticket.ETA; expected != actual {
+			t.Errorf("%d: Expected: %v, Actual: %v", k, expected, actual)
+		}
+	}
+}
+
+func TestRoomSharedQueue(t *testing.T) {
+	var (
+		q      = newQueue(1)
+		first  = newRoom("secret", q, 1, time.Hour)
+		second = newRoom("secret", q, 1, time.Hour)
+	)
+
+	issue(t, first, "client")
+	ticket := issue(t, second, "client")
+
+	// Any room with the same secret can read the token of another.
+	status, err := first.Status(defaultKey, ticket.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := int64(1), status.Position; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestRoomIssueLimit(t *testing.T) {
+	room := New("secret", newQueue(0), Options{
+		IssuePerDuration: 1,
+		IssueDuration:    time.Hour,
+	})
+
+	issue(t, room, "client")
+
+	_, err := room.Issue(defaultKey, "client")
+	if expected, actual := true, isCode(err, errors.TooManyTokens); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	issue(t, room, "other")
+}
+
+func TestRoomAdmit(t *testing.T) {
+	var (
+		room   = newRoom("secret", newQueue(1), 1, time.Hour)
+		first  = issue(t, room, "client")
+		second = issue(t, room, "client")
+	)
+
+	if _, err := room.Admit(defaultKey, first.Token); err != nil {
+		t.Error(err)
+	}
+
+	ticket, err := room.Admit(defaultKey, second.Token)
+	if expected, actual := true, isCode(err, errors.NotAdmitted); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := int64(1), ticket.Position; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestRoomAdmitAll(t *testing.T) {
+	room := newRoom("secret", newQueue(0), 0, time.Hour)
+
+	for i := 0; i < 10; i++ {
+		ticket := issue(t, room, "client")
+		if _, err := room.Admit(defaultKey, ticket.Token); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestRoomAdmitOnce(t *testing.T) {
+	var (
+		room   = newRoom("secret", newQueue(1), 1, time.Hour)
+		ticket = issue(t, room, "client")
+	)
+
+	if _, err := room.Admit(defaultKey, ticket.Token); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := room.Admit(defaultKey, ticket.Token)
+	if expected, actual := true, isCode(err, errors.InvalidToken); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	// Releasing the use lets the token be used again.
+	if err := room.Release(defaultKey, ticket.Token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := room.Admit(defaultKey, ticket.Token); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRoomInvalidToken(t *testing.T) {
+	var (
+		room   = newRoom("secret", newQueue(1), 1, time.Hour)
+		ticket = issue(t, room, "client")
+	)
+
+	for _, v := range []struct {
+		name  string
+		key   bs.Key
+		token string
+	}{
+		{"empty", defaultKey, ""},
+		{"tampered", defaultKey, "0" + ticket.Token[1:]},
+		{"key", bs.Key("5a0c5a0c5a0c5a0c5a0c5a0d"), ticket.Token},
+		{"secret", defaultKey, issue(t, newRoom("other", newQueue(1), 1, time.Hour), "client").Token},
+	} {
+		_, err := room.Admit(v.key, v.token)
+		if expected, actual := true, isCode(err, errors.InvalidToken); expected != actual {
+			t.Errorf("%s: Expected: %v, Actual: %v", v.name, expected, actual)
+		}
+	}
+}
+
+func TestRoomExpiredToken(t *testing.T) {
+	var (
+		room   = newRoom("secret", newQueue(0), 0, time.Millisecond)
+		ticket = issue(t, room, "client")
+	)
+
+	time.Sleep(2 * time.Millisecond)
+
+	_, err := room.Admit(defaultKey, ticket.Token)
+	if expected, actual := true, isCode(err, errors.InvalidToken); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
@@ -0,0 +1,87 @@
+package admission
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+)
+
+const tokenSeparator = "."
+
+// Token is a place in the queue of a key. Tokens are signed, so that a client
+// can't jump the queue by making up a place of its own.
+type Token struct {
+	Key      bs.Key
+	Sequence int64
+	Issued   time.Time
+}
+
+// encode writes the token as "key.sequence.issued.signature".
+func (t Token) encode(secret []byte) string {
+	payload := t.payload()
+	return payload + tokenSeparator + hex.EncodeToString(sign(secret, payload))
+}
+
+func (t Token) payload() string {
+	return strings.Join([]string{
+		t.Key.String(),
+		strconv.FormatInt(t.Sequence, 10),
+		strconv.FormatInt(t.Issued.UnixNano(), 10),
+	}, tokenSeparator)
+}
+
+// decode reads the token, making sure that it was signed with the secret of
+// the room.
+func decode(token string, secret []byte) (Token, error) {
+	invalid := func(reason string) (Token, error) {
+		return Token{}, typex.Errorf(errors.Source, errors.InvalidToken,
+			"Invalid Token (%s)", reason)
+	}
+
+	parts := strings.Split(token, tokenSeparator)
+	if len(parts) != 4 {
+		return invalid("format")
+	}
+
+	signature, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return invalid("signature")
+	}
+
+	payload := strings.Join(parts[:3], tokenSeparator)
+	if !hmac.Equal(signature, sign(secret, payload)) {
+		return invalid("signature")
+	}
+
+	sequence, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return invalid("sequence")
+	}
+	issued, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return invalid("issued")
+	}
+
+	return Token{
+		Key:      bs.Key(parts[0]),
+		Sequence: sequence,
+		Issued:   time.Unix(0, issued),
+	}, nil
+}
+
+// sign signs the payload with the secret. The queues are shared by every
+// instance, so the secret is all that's needed for any instance to check the
+// token.
+func sign(secret []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	io.WriteString(mac, payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SimonRichardson/echelon/echelon-http/admission"
+	"github.com/SimonRichardson/echelon/echelon-http/responses"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	admissionHeader  = "Admission-Token"
+	retryAfterHeader = "Retry-After"
+)
+
+// Admitted rejects the inserts in to a collection, unless the request has an
+// Admission-Token header with a token that's been admitted for the key. A
+// request that's not been admitted yet is told when to try again. The use of
+// the token is given back if the insert fails with a server error, so that the
+// client can retry it.
+// If there is no room, then the handler is returned untouched.
+func Admitted(room *admission.Room, fn http.HandlerFunc) http.HandlerFunc {
+	if room == nil {
+		return fn
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		queryKey := r.URL.Query().Get(":key")
+		if !bson.IsObjectIdHex(queryKey) {
+			admissionError(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Key: %s", queryKey))
+			return
+		}
+
+		admitted, err := admit(room, w, r, []bs.Key{bs.Key(queryKey)})
+		if err != nil {
+			admissionError(w, r, err)
+			return
+		}
+
+		recorder := &responseRecorder{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+		fn(recorder, r)
+
+		if recorder.status >= http.StatusInternalServerError {
+			release(room, admitted)
+		}
+	}
+}
+
+// admit checks that there is an admitted token for every key, as a request
+// can insert in to more than one collection. A token names the key that it's
+// for, so the tokens can be sent in any order. The tokens that were admitted
+// are returned, so that their uses can be given back if the request fails.
+func admit(room *admission.Room,
+	w http.ResponseWriter,
+	r *http.Request,
+	keys []bs.Key,
+) (map[bs.Key]string, error) {
+	admitted := map[bs.Key]string{}
+	if room == nil {
+		return admitted, nil
+	}
+
+	tokens := readAdmissionTokens(r)
+	for _, key := range keys {
+		token, ok := tokens[key]
+		if !ok {
+			release(room, admitted)
+			return nil, typex.Errorf(errors.Source, errors.InvalidToken,
+				"Missing Admission-Token for %s", key.String())
+		}
+
+		ticket, err := room.Admit(key, token)
+		if err != nil {
+			release(room, admitted)
+			if !ticket.Admitted() {
+				seconds := int64(math.Ceil(ticket.ETA.Seconds()))
+				w.Header().Set(retryAfterHeader, strconv.FormatInt(seconds, 10))
+			}
+			return nil, err
+		}
+		admitted[key] = token
+	}
+	return admitted, nil
+}
+
+// release gives back the uses of the tokens, logging the ones that can't be.
+func release(room *admission.Room, tokens map[bs.Key]string) {
+	if room == nil {
+		return
+	}
+
+	for key, token := range tokens {
+		if err := room.Release(key, token); err != nil {
+			teleprinter.L.Error().Printf("Unable to release admission token for %s : %s\n", key.String(), err)
+		}
+	}
+}
+
+// readAdmissionClient identifies the client that a token is issued to, so that
+// a client can't take every place in a queue. If the instance is behind a
+// proxy, then the header is the one that the proxy names the client in.
+func readAdmissionClient(r *http.Request, header string) string {
+	if header != "" {
+		if value := r.Header.Get(header); value != "" {
+			return strings.TrimSpace(strings.Split(value, ",")[0])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// readAdmissionTokens reads the tokens of the request by the key that they're
+// for. The header can be repeated or comma separated.
+func readAdmissionTokens(r *http.Request) map[bs.Key]string {
+	tokens := map[bs.Key]string{}
+	for _, header := range r.Header[admissionHeader] {
+		for _, token := range strings.Split(header, ",") {
+			token = strings.TrimSpace(token)
+			if index := strings.Index(token, "."); index > 0 {
+				tokens[bs.Key(token[:index])] = token
+			}
+		}
+	}
+	return tokens
+}
+
+// admissionError responds with the error before the request has been guarded,
+// so the encoding has to be negotiated here.
+func admissionError(w http.ResponseWriter, r *http.Request, err error) {
+	accept, _ := responses.ParseAccept(r.Header.Get("Accept"))
+	responses.Error(responses.Negotiate(w, accept), r, err)
+}
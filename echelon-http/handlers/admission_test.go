@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SimonRichardson/echelon/echelon-http/admission"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+)
+
+var defaultAdmissionKey = bs.Key("5a0c5a0c5a0c5a0c5a0c5a0c")
+
+// admissionQueue admits every token straight away.
+type admissionQueue struct {
+	issued int64
+	uses   map[string]int
+}
+
+func newAdmissionQueue() *admissionQueue {
+	return &admissionQueue{uses: map[string]int{}}
+}
+
+func (q *admissionQueue) Issue(key bs.Key, client string, limit int, window, expiry time.Duration) (int64, bool, error) {
+	q.issued++
+	return q.issued, true, nil
+}
+
+func (q *admissionQueue) Advance(key bs.Key, rate int, duration, expiry time.Duration) (int64, int64, error) {
+	return q.issued, q.issued, nil
+}
+
+func (q *admissionQueue) Use(key bs.Key, token string, uses int, expiry time.Duration) (bool, error) {
+	if q.uses[token] >= uses {
+		return false, nil
+	}
+	q.uses[token]++
+	return true, nil
+}
+
+func (q *admissionQueue) Release(key bs.Key, token string) error {
+	if q.uses[token] > 0 {
+		q.uses[token]--
+	}
+	return nil
+}
+
+func newAdmittedRequest(token, key, body string) *http.Request {
+	r := httptest.NewRequest("POST", "/http/v1/"+defaultAdmissionKey.String()+"?:key="+defaultAdmissionKey.String(),
+		bytes.NewBufferString(body),
+	)
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(admissionHeader, token)
+	r.Header.Set(idempotencyHeader, key)
+	return r
+}
+
+func TestAdmittedIdempotentReplaysARetryWithAUsedToken(t *testing.T) {
+	var (
+		room = admission.New("secret", newAdmissionQueue(), admission.Options{
+			Expiry: time.Hour,
+			Uses:   1,
+		})
+
+		calls   int
+		handler = Idempotent(newIdempotencyCache(), Admitted(room, created(&calls)))
+	)
+
+	ticket, err := room.Issue(defaultAdmissionKey, "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := httptest.NewRecorder()
+	handler(first, newAdmittedRequest(ticket.Token, "key", `{"a":1}`))
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected %d, got %d", http.StatusCreated, first.Code)
+	}
+
+	// The token has been used, but the retry is replayed before it's checked.
+	retry := httptest.NewRecorder()
+	handler(retry, newAdmittedRequest(ticket.Token, "key", `{"a":1}`))
+
+	if calls != 1 {
+		t.Errorf("Expected the request to run once, ran %d times", calls)
+	}
+	if retry.Code != http.StatusCreated {
+		t.Errorf("Expected %d, got %d", http.StatusCreated, retry.Code)
+	}
+	if retry.Header().Get(idempotencyReplayedHeader) != "true" {
+		t.Errorf("Expected the response to be marked as replayed")
+	}
+
+	// A different request can't reuse the token.
+	other := httptest.NewRecorder()
+	handler(other, newAdmittedRequest(ticket.Token, "other", `{"a":1}`))
+	if other.Code != http.StatusUnauthorized {
+		t.Errorf("Expected %d, got %d", http.StatusUnauthorized, other.Code)
+	}
+}
@@ -1,25 +1,24 @@
 package handlers
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
-	"strings"
+	"time"
 
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	"github.com/SimonRichardson/echelon/echelon-http/responses"
 	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/schemas/records"
 	"github.com/SimonRichardson/echelon/schemas/schema"
 	"github.com/SimonRichardson/echelon/internal/logs/generic"
 	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/google/flatbuffers/go"
 	"gopkg.in/mgo.v2/bson"
 )
 
-const (
-	contentType = "application/octet-stream"
-)
-
 func handle(fn func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		teleprinter.L.Info().Printf("Requesting %s.\n", r.URL)
@@ -29,11 +28,14 @@ func handle(fn func(http.ResponseWriter, *http.Request)) func(http.ResponseWrite
 
 func accepts(fn func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
 	return handle(func(w http.ResponseWriter, r *http.Request) {
-		if strings.ToLower(r.Header.Get("Accept")) != contentType {
+		accept, ok := responses.ParseAccept(r.Header.Get("Accept"))
+		if !ok {
 			responses.BadRequest(w, r, fmt.Errorf("Invalid Content-Type"))
 			return
 		}
 
+		w = responses.Negotiate(w, accept)
+
 		if err := r.ParseForm(); err != nil {
 			responses.BadRequest(w, r, err)
 			return
@@ -45,12 +47,15 @@ func accepts(fn func(http.ResponseWriter, *http.Request)) func(http.ResponseWrit
 
 func guard(fn func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
 	return handle(func(w http.ResponseWriter, r *http.Request) {
-		if strings.ToLower(r.Header.Get("Accept")) != contentType {
+		accept, ok := responses.ParseAccept(r.Header.Get("Accept"))
+		if !ok {
 			responses.BadRequest(w, r, fmt.Errorf("Invalid Accept"))
 			return
 		}
 
-		if strings.ToLower(r.Header.Get("Content-Type")) != contentType {
+		w = responses.Negotiate(w, accept)
+
+		if _, ok := responses.ParseEncoding(r.Header.Get("Content-Type")); !ok {
 			responses.BadRequest(w, r, fmt.Errorf("Invalid Content-Type"))
 			return
 		}
@@ -69,6 +74,82 @@ func guard(fn func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter
 	})
 }
 
+// readBody reads the body of the request as flatbuffers. A body that's sent as
+// json or msgpack is decoded into the record first, which is then written as
+// flatbuffers, so that every body is validated the same way.
+func readBody(r *http.Request, record records.Write) ([]byte, error) {
+	var buffer bytes.Buffer
+	if _, err := buffer.ReadFrom(r.Body); err != nil {
+		return nil, typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid Body")
+	}
+
+	body := buffer.Bytes()
+	if len(body) < 1 {
+		return nil, typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid Body Length")
+	}
+
+	contentType, _ := responses.ParseEncoding(r.Header.Get("Content-Type"))
+	encoding, ok := contentType.Records()
+	if !ok {
+		return body, nil
+	}
+
+	if err := encoding.Unmarshal(body, record); err != nil {
+		return nil, typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid Body").With(err)
+	}
+	if err := checkSizeExpiry(record); err != nil {
+		return nil, err
+	}
+
+	written, err := record.Write(flatbuffers.NewBuilder(0))
+	if err != nil {
+		return nil, typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid Body").With(err)
+	}
+	return written, nil
+}
+
+// checkSizeExpiry rejects a negative max size or expiry of a decoded record, as
+// they would otherwise wrap around once written as flatbuffers.
+func checkSizeExpiry(record records.Write) error {
+	var (
+		maxSizes []int64
+		expiry   time.Duration
+	)
+	switch r := record.(type) {
+	case *records.PostRecords:
+		maxSizes, expiry = []int64{r.MaxSize}, r.Expiry
+	case *records.PutRecords:
+		maxSizes, expiry = []int64{r.MaxSize}, r.Expiry
+	case *records.DeleteRecords:
+		maxSizes, expiry = []int64{r.MaxSize}, r.Expiry
+	case *records.RollbackRecords:
+		maxSizes, expiry = []int64{r.MaxSize}, r.Expiry
+	case *records.PatchRecords:
+		maxSizes, expiry = []int64{r.MaxSize}, r.Expiry
+	case *records.BatchRecords:
+		for _, v := range r.Keys {
+			maxSizes = append(maxSizes, v.MaxSize)
+		}
+		expiry = r.Expiry
+	}
+
+	for _, maxSize := range maxSizes {
+		if maxSize < 0 {
+			return typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid MaxSize: %d", maxSize)
+		}
+	}
+	if expiry < 0 {
+		return typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid expiry: %d", expiry)
+	}
+	return nil
+}
+
 type recordWithId interface {
 	Id(obj *schema.Id) *schema.Id
 }
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SimonRichardson/echelon/echelon-http/responses"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+)
+
+const (
+	idempotencyHeader         = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	idempotencyPrefix         = "echelon_idempotency:"
+
+	// A stored response is the fingerprint of the request, followed by the
+	// status, the content type (ending in a new line, which a header can't
+	// hold) and then the body of the response.
+	fingerprintLen = sha1.Size * 2
+	statusLen      = 3
+	contentTypeEnd = '\n'
+
+	// A request that's running holds the key with an in flight status, which
+	// expires in case the request never finishes.
+	inFlightStatus = "000"
+	inFlightExpiry = time.Minute
+)
+
+// IdempotencyCache defines where the responses of the idempotent requests are
+// stored, a key is reserved whilst the request is running. The keys are always
+// read from where they were reserved, so that a retry sees the reservation.
+type IdempotencyCache interface {
+	bs.Encoder
+	bs.Reserver
+}
+
+// Idempotent de-duplicates the retries of a client, by storing the response of
+// a request that has an Idempotency-Key header with in the cache. A retry
+// with the same key is then replayed the stored response, rather than being
+// run again. Reusing a key for a different request is rejected, as is a retry
+// whilst the request is still running.
+// If there is no cache, then the handler is returned untouched.
+func Idempotent(cache IdempotencyCache, fn http.HandlerFunc) http.HandlerFunc {
+	if cache == nil {
+		return fn
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := bs.IdempotencyKey(r.Header.Get(idempotencyHeader))
+		if len(idempotencyKey) < 1 {
+			fn(w, r)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Body"))
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		var (
+			key         = bs.Key(idempotencyPrefix + idempotencyKey.String())
+			fingerprint = requestFingerprint(r, body)
+		)
+
+		// Failing to read from the cache (including a missing key) runs the
+		// request as normal.
+		if stored, err := cache.GetReservedBytes(key); err == nil && replay(w, r, stored, fingerprint, idempotencyKey) {
+			return
+		}
+
+		// Only the request that reserves the key is run, so that two retries
+		// racing each other can't both run.
+		reserved, err := cache.ReserveBytes(key, []byte(fingerprint+inFlightStatus), inFlightExpiry)
+		if err != nil {
+			teleprinter.L.Error().Printf("Unable to reserve idempotent request %s : %s\n", idempotencyKey, err)
+		} else if !reserved {
+			if stored, err := cache.GetReservedBytes(key); err == nil && replay(w, r, stored, fingerprint, idempotencyKey) {
+				return
+			}
+			responses.Error(w, r, typex.Errorf(errors.Source, errors.InProgress,
+				"Idempotency-Key %q is in use by a request that's running", idempotencyKey))
+			return
+		}
+
+		recorder := &responseRecorder{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+		fn(recorder, r)
+
+		// Server errors and refused admissions are never stored, so that the
+		// client can retry them.
+		if recorder.status >= http.StatusInternalServerError ||
+			recorder.status == http.StatusUnauthorized ||
+			recorder.status == http.StatusTooManyRequests {
+			if err := cache.DelBytes(key); err != nil {
+				teleprinter.L.Error().Printf("Unable to release idempotent request %s : %s\n", idempotencyKey, err)
+			}
+			return
+		}
+
+		stored := append([]byte(fmt.Sprintf("%s%03d%s%c",
+			fingerprint,
+			recorder.status,
+			recorder.Header().Get("Content-Type"),
+			contentTypeEnd,
+		)), recorder.body.Bytes()...)
+		if err := cache.SetBytes(key, stored); err != nil {
+			teleprinter.L.Error().Printf("Unable to store idempotent response %s : %s\n", idempotencyKey, err)
+		}
+	}
+}
+
+// replay writes the stored response, if there is one, returning false if the
+// request should be run. A request that's still running, or that was for a
+// different request, is refused.
+func replay(w http.ResponseWriter, r *http.Request,
+	stored []byte,
+	fingerprint string,
+	idempotencyKey bs.IdempotencyKey,
+) bool {
+	if len(stored) < fingerprintLen+statusLen {
+		return false
+	}
+
+	if string(stored[:fingerprintLen]) != fingerprint {
+		responses.Error(w, r, typex.Errorf(errors.Source, errors.IdempotencyMismatch,
+			"Idempotency-Key %q reused with a different request", idempotencyKey))
+		return true
+	}
+
+	status := string(stored[fingerprintLen : fingerprintLen+statusLen])
+	if status == inFlightStatus {
+		responses.Error(w, r, typex.Errorf(errors.Source, errors.InProgress,
+			"Idempotency-Key %q is in use by a request that's running", idempotencyKey))
+		return true
+	}
+
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return false
+	}
+
+	// The response is replayed in the content type it was written in, no
+	// matter what the retry accepts.
+	rest := stored[fingerprintLen+statusLen:]
+	end := bytes.IndexByte(rest, contentTypeEnd)
+	if end < 0 {
+		return false
+	}
+	if contentType := string(rest[:end]); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.Header().Set(idempotencyReplayedHeader, "true")
+	w.WriteHeader(code)
+	w.Write(rest[end+1:])
+	return true
+}
+
+// requestFingerprint identifies a request, so that the same key can't be used
+// for a different request. The content type is part of the request, as the same
+// body means something else in another encoding, but what the client accepts
+// isn't, as the stored response is replayed along with its content type.
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha1.New()
+	hash.Write([]byte(r.Method))
+	hash.Write([]byte(r.URL.Path))
+	hash.Write([]byte(r.Header.Get("Content-Type")))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder writes through to the underlying writer, whilst keeping a
+// copy of the status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
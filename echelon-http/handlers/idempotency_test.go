@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+// idempotencyCache holds the responses in memory.
+type idempotencyCache struct {
+	mutex  sync.Mutex
+	values map[bs.Key][]byte
+}
+
+func newIdempotencyCache() *idempotencyCache {
+	return &idempotencyCache{values: map[bs.Key][]byte{}}
+}
+
+func (c *idempotencyCache) GetBytes(key bs.Key) ([]byte, error) {
+	return c.GetReservedBytes(key)
+}
+
+func (c *idempotencyCache) SetBytes(key bs.Key, value []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.values[key] = value
+	return nil
+}
+
+func (c *idempotencyCache) DelBytes(key bs.Key) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.values, key)
+	return nil
+}
+
+func (c *idempotencyCache) ReserveBytes(key bs.Key, value []byte, expiry time.Duration) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	c.values[key] = value
+	return true, nil
+}
+
+func (c *idempotencyCache) GetReservedBytes(key bs.Key) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	value, ok := c.values[key]
+	if !ok {
+		return nil, redis.ErrNil
+	}
+	return value, nil
+}
+
+func newIdempotentRequest(key, body string) *http.Request {
+	r := httptest.NewRequest("POST", "/http/v1/5a0c5a0c5a0c5a0c5a0c5a0c", bytes.NewBufferString(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(idempotencyHeader, key)
+	return r
+}
+
+func created(calls *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"records":1}`))
+	}
+}
+
+func TestIdempotentReplaysTheStoredResponse(t *testing.T) {
+	var (
+		calls   int
+		handler = Idempotent(newIdempotencyCache(), created(&calls))
+	)
+
+	first := httptest.NewRecorder()
+	handler(first, newIdempotentRequest("key", `{"a":1}`))
+
+	retry := httptest.NewRecorder()
+	handler(retry, newIdempotentRequest("key", `{"a":1}`))
+
+	if calls != 1 {
+		t.Errorf("Expected the request to run once, ran %d times", calls)
+	}
+	if retry.Code != http.StatusCreated {
+		t.Errorf("Expected %d, got %d", http.StatusCreated, retry.Code)
+	}
+	if retry.Body.String() != first.Body.String() {
+		t.Errorf("Expected %q, got %q", first.Body.String(), retry.Body.String())
+	}
+	if retry.Header().Get(idempotencyReplayedHeader) != "true" {
+		t.Errorf("Expected the response to be marked as replayed")
+	}
+	if retry.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected the stored content type, got %q", retry.Header().Get("Content-Type"))
+	}
+}
+
+func TestIdempotentRejectsADifferentBody(t *testing.T) {
+	var (
+		calls   int
+		handler = Idempotent(newIdempotencyCache(), created(&calls))
+	)
+
+	handler(httptest.NewRecorder(), newIdempotentRequest("key", `{"a":1}`))
+
+	retry := httptest.NewRecorder()
+	handler(retry, newIdempotentRequest("key", `{"a":2}`))
+
+	if calls != 1 {
+		t.Errorf("Expected the request to run once, ran %d times", calls)
+	}
+	if retry.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected %d, got %d", http.StatusUnprocessableEntity, retry.Code)
+	}
+}
+
+func TestIdempotentRejectsARetryInFlight(t *testing.T) {
+	var (
+		calls   int
+		retry   = httptest.NewRecorder()
+		handler http.HandlerFunc
+	)
+	handler = Idempotent(newIdempotencyCache(), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// The client retries whilst the first request is still running.
+		handler(retry, newIdempotentRequest("key", `{"a":1}`))
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	handler(httptest.NewRecorder(), newIdempotentRequest("key", `{"a":1}`))
+
+	if calls != 1 {
+		t.Errorf("Expected the request to run once, ran %d times", calls)
+	}
+	if retry.Code != http.StatusConflict {
+		t.Errorf("Expected %d, got %d", http.StatusConflict, retry.Code)
+	}
+}
+
+func TestIdempotentRunsServerErrorsAgain(t *testing.T) {
+	var (
+		calls   int
+		handler = Idempotent(newIdempotencyCache(), func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+	)
+
+	handler(httptest.NewRecorder(), newIdempotentRequest("key", `{"a":1}`))
+	handler(httptest.NewRecorder(), newIdempotentRequest("key", `{"a":1}`))
+
+	if calls != 2 {
+		t.Errorf("Expected the request to run twice, ran %d times", calls)
+	}
+}
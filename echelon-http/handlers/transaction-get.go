@@ -34,7 +34,7 @@ func TransactionGet(co *coordinator.Coordinator) http.HandlerFunc {
 			key = bs.Key(queryKey)
 			id  = bs.Key(queryId)
 
-			results, resultsErr = co.Select(key, id)
+			results, resultsErr = co.Select(r.Context(), key, id)
 		)
 		if resultsErr != nil {
 			responses.Error(w, r, resultsErr)
@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"bytes"
-	"io"
 	"net/http"
 	"time"
 
@@ -13,6 +11,7 @@ import (
 	"github.com/SimonRichardson/echelon/coordinator"
 	"github.com/SimonRichardson/echelon/errors"
 	"github.com/SimonRichardson/echelon/schemas/pool"
+	"github.com/SimonRichardson/echelon/schemas/records"
 	"github.com/SimonRichardson/echelon/schemas/schema"
 	"github.com/SimonRichardson/echelon/selectors"
 	"github.com/SimonRichardson/echelon/internal/typex"
@@ -37,7 +36,7 @@ func TransactionPatch(co *coordinator.Coordinator) http.HandlerFunc {
 			return
 		}
 
-		operations, score, maxSize, expiry, err := readPatchRecords(r.Body)
+		operations, score, maxSize, expiry, err := readPatchRecords(r)
 		if err != nil {
 			responses.BadRequest(w, r, err)
 			return
@@ -47,7 +46,7 @@ func TransactionPatch(co *coordinator.Coordinator) http.HandlerFunc {
 			key = bs.Key(queryKey)
 			id  = bs.Key(queryId)
 
-			results, resultsErr = co.ModifyWithOperations(key,
+			results, resultsErr = co.ModifyWithOperations(r.Context(), key,
 				id,
 				operations,
 				score,
@@ -66,27 +65,27 @@ func TransactionPatch(co *coordinator.Coordinator) http.HandlerFunc {
 	})
 }
 
-func readPatchRecords(read io.ReadCloser) ([]selectors.Operation, float64, int64, time.Duration, error) {
-	var (
-		buffer bytes.Buffer
-		fail   = func(err error) ([]selectors.Operation, float64, int64, time.Duration, error) {
-			return nil, 0, 0, 0, err
-		}
-	)
-	if _, err := buffer.ReadFrom(read); err != nil {
-		return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
-			"Invalid Body"))
+func readPatchRecords(r *http.Request) ([]selectors.Operation, float64, int64, time.Duration, error) {
+	fail := func(err error) ([]selectors.Operation, float64, int64, time.Duration, error) {
+		return nil, 0, 0, 0, err
 	}
 
-	body := buffer.Bytes()
-	if len(body) < 1 {
-		return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
-			"Invalid Body Length"))
+	body, err := readBody(r, &records.PatchRecords{})
+	if err != nil {
+		return fail(err)
 	}
 
-	var (
-		request = schema.GetRootAsPatchRequest(body, 0)
+	return ReadPatchRequest(schema.GetRootAsPatchRequest(body, 0))
+}
+
+// ReadPatchRequest reads the operations of a patch request, so that they can be
+// applied.
+func ReadPatchRequest(request *schema.PatchRequest) ([]selectors.Operation, float64, int64, time.Duration, error) {
+	fail := func(err error) ([]selectors.Operation, float64, int64, time.Duration, error) {
+		return nil, 0, 0, 0, err
+	}
 
+	var (
 		score   = request.Score()
 		maxSize = request.MaxSize()
 		expiry  = request.Expiry()
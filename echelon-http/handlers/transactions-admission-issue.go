@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SimonRichardson/echelon/echelon-http/admission"
+	"github.com/SimonRichardson/echelon/echelon-http/responses"
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/records"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TransactionsAdmissionIssue issues the next admission token for the
+// collection, along with where it is in the queue. The client is read from the
+// clientHeader if there is one, otherwise it's the remote address.
+func TransactionsAdmissionIssue(room *admission.Room, clientHeader string) http.HandlerFunc {
+	return accepts(func(w http.ResponseWriter, r *http.Request) {
+		began := time.Now()
+
+		key, err := readAdmissionKey(r, room)
+		if err != nil {
+			responses.Error(w, r, err)
+			return
+		}
+
+		ticket, err := room.Issue(key, readAdmissionClient(r, clientHeader))
+		if err != nil {
+			responses.Error(w, r, err)
+			return
+		}
+
+		responses.OKAdmission(w, fromTicket(ticket), time.Since(began))
+		return
+	})
+}
+
+func readAdmissionKey(r *http.Request, room *admission.Room) (bs.Key, error) {
+	if room == nil {
+		return bs.Key(""), typex.Errorf(errors.Source, errors.MissingContent,
+			"Admission Disabled")
+	}
+
+	queryKey := r.URL.Query().Get(":key")
+	if !bson.IsObjectIdHex(queryKey) {
+		return bs.Key(""), typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid Key: %s", queryKey)
+	}
+	return bs.Key(queryKey), nil
+}
+
+func fromTicket(ticket admission.Ticket) records.Admission {
+	return records.Admission{
+		Token:    ticket.Token,
+		Position: ticket.Position,
+		ETA:      ticket.ETA,
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SimonRichardson/echelon/echelon-http/admission"
+	"github.com/SimonRichardson/echelon/echelon-http/responses"
+)
+
+// TransactionsAdmissionStatus returns where the admission token of the
+// Admission-Token header is in the queue of the collection, along with how long
+// it's expected to wait for.
+func TransactionsAdmissionStatus(room *admission.Room) http.HandlerFunc {
+	return accepts(func(w http.ResponseWriter, r *http.Request) {
+		began := time.Now()
+
+		key, err := readAdmissionKey(r, room)
+		if err != nil {
+			responses.Error(w, r, err)
+			return
+		}
+
+		ticket, err := room.Status(key, r.Header.Get(admissionHeader))
+		if err != nil {
+			responses.Error(w, r, err)
+			return
+		}
+
+		responses.OKAdmission(w, fromTicket(ticket), time.Since(began))
+		return
+	})
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SimonRichardson/echelon/coordinator"
+	"github.com/SimonRichardson/echelon/echelon-http/admission"
+	"github.com/SimonRichardson/echelon/echelon-http/responses"
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/pool"
+	"github.com/SimonRichardson/echelon/schemas/records"
+	"github.com/SimonRichardson/echelon/schemas/schema"
+	"github.com/SimonRichardson/echelon/selectors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TransactionsBatch adds items into multiple collections, either all of the
+// items are added or none of them are. If there is a room, then every
+// collection needs an admitted token, the uses of which are given back if the
+// batch fails with a server error.
+func TransactionsBatch(co *coordinator.Coordinator, room *admission.Room) http.HandlerFunc {
+	return guard(func(w http.ResponseWriter, r *http.Request) {
+		began := time.Now()
+
+		elements, maxSizeExpiry, err := readBatchRecords(r)
+		if err != nil {
+			responses.BadRequest(w, r, err)
+			return
+		}
+
+		keys := make([]bs.Key, 0, len(maxSizeExpiry))
+		for k := range maxSizeExpiry {
+			keys = append(keys, k)
+		}
+		admitted, err := admit(room, w, r, keys)
+		if err != nil {
+			responses.Error(w, r, err)
+			return
+		}
+
+		results, batchErr := co.Batch(r.Context(), elements, maxSizeExpiry)
+		if batchErr != nil {
+			if code := typex.ErrCode(batchErr); code < 0 || code >= http.StatusInternalServerError {
+				release(room, admitted)
+			}
+			responses.Error(w, r, batchErr)
+			return
+		}
+
+		responses.OKInt(w, results, time.Since(began))
+		return
+	})
+}
+
+func readBatchRecords(r *http.Request) ([]selectors.KeyFieldScoreTxnValue, selectors.KeySizeExpiry, error) {
+	fail := func(err error) ([]selectors.KeyFieldScoreTxnValue, selectors.KeySizeExpiry, error) {
+		return nil, nil, err
+	}
+
+	body, err := readBody(r, &records.BatchRecords{})
+	if err != nil {
+		return fail(err)
+	}
+
+	var (
+		request = schema.GetRootAsBatchRequest(body, 0)
+		score   = request.Score()
+		expiry  = request.Expiry()
+	)
+	if expiry < 1 {
+		return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid expiry: %d", expiry))
+	}
+
+	var (
+		num           = request.KeysLength()
+		result        = []selectors.KeyFieldScoreTxnValue{}
+		maxSizeExpiry = selectors.KeySizeExpiry{}
+		fb            = pool.Get()
+	)
+	defer pool.Put(fb)
+
+	if num < 1 {
+		return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid Keys Length"))
+	}
+
+	for i := 0; i < num; i++ {
+		batch := &schema.BatchKey{}
+		if !request.Keys(batch, i) {
+			return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Key: %d", i))
+		}
+
+		id := batch.Key(nil)
+		if id == nil || !bson.IsObjectIdHex(string(id.Hex())) {
+			return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Key: %d", i))
+		}
+
+		key := bs.Key(id.Hex())
+		if _, ok := maxSizeExpiry[key]; ok {
+			return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Duplicate Key: %s", key))
+		}
+
+		maxSize := batch.MaxSize()
+		if maxSize < 1 {
+			return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid MaxSize: %d", maxSize))
+		}
+		maxSizeExpiry[key] = selectors.SizeExpiry{
+			Size:   int64(maxSize),
+			Expiry: time.Duration(expiry),
+		}
+
+		for j := 0; j < batch.RecordsLength(); j++ {
+			record := &schema.PostRecord{}
+			if !batch.Records(record, j) {
+				return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
+					"Invalid Record: %d:%d", i, j))
+			}
+
+			field, err := readRecordId(record)
+			if err != nil {
+				return fail(err)
+			}
+
+			transaction, err := readRecordTransactionId(record)
+			if err != nil {
+				return fail(err)
+			}
+
+			fb.Reset()
+
+			value, err := records.PostRecordFromSchemaToByte(fb, record)
+			if err != nil {
+				return fail(err)
+			}
+			result = append(result, selectors.KeyFieldScoreTxnValue{
+				Key:   key,
+				Field: field,
+				Score: score,
+				Txn:   transaction,
+				Value: records.PackagePostRecord(value),
+			})
+		}
+	}
+
+	return result, maxSizeExpiry, nil
+}
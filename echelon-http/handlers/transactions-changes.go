@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SimonRichardson/echelon/coordinator"
+	"github.com/SimonRichardson/echelon/echelon-http/responses"
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TransactionsChanges represents the end point for streaming the changes
+// (inserts, deletes and rollbacks) of a key as server sent events. The stream
+// can be resumed from a score, either by the "since" parameter or by the
+// "Last-Event-ID" header.
+func TransactionsChanges(co *coordinator.Coordinator, interval time.Duration, defaultLimit int) http.HandlerFunc {
+	return handle(func(w http.ResponseWriter, r *http.Request) {
+		if !responses.AcceptsStream(r.Header.Get("Accept")) {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Accept"))
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			responses.BadRequest(w, r, err)
+			return
+		}
+
+		queryKey := r.URL.Query().Get(":key")
+		if !bson.IsObjectIdHex(queryKey) {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Key: %s", queryKey))
+			return
+		}
+
+		since, ok := parseSince(r)
+		if !ok {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid resume token"))
+			return
+		}
+
+		limit, ok := parseInt(r.Form, "limit", defaultLimit)
+		if r.Form.Get("limit") != "" && (!ok || limit < 1) {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid request parameter"))
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			responses.InternalServerError(w, r, typex.Errorf(errors.Source, errors.UnexpectedArgument,
+				"Streaming is not supported"))
+			return
+		}
+
+		var (
+			key    = bs.Key(queryKey)
+			ticker = time.NewTicker(interval)
+		)
+		defer ticker.Stop()
+
+		responses.Stream(w)
+		flusher.Flush()
+
+		for {
+			changes, err := co.Changes(r.Context(), key, since, limit)
+			if err != nil {
+				responses.StreamError(w, err)
+				return
+			}
+
+			// A read never splits the changes that share a score, so the
+			// last change of a score is known from the next one.
+			for k, v := range changes {
+				last := k == len(changes)-1 || changes[k+1].Score != v.Score
+				if err := responses.StreamChange(w, v, last); err != nil {
+					return
+				}
+				since = v.Score
+			}
+			flusher.Flush()
+
+			// There might be more changes waiting, so don't wait for them.
+			if len(changes) >= limit {
+				continue
+			}
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+func parseSince(r *http.Request) (float64, bool) {
+	token := r.Form.Get("since")
+	if token == "" {
+		token = r.Header.Get("Last-Event-ID")
+	}
+	if token == "" {
+		return 0, true
+	}
+
+	since, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return 0, false
+	}
+	return since, true
+}
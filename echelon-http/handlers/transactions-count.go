@@ -25,7 +25,7 @@ func TransactionsCount(co *coordinator.Coordinator) http.HandlerFunc {
 			return
 		}
 
-		counts, err := co.Size(bs.Key(key))
+		counts, err := co.Size(r.Context(), bs.Key(key))
 		if err != nil {
 			responses.InternalServerError(w, r, err)
 			return
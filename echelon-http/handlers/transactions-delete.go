@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"bytes"
-	"io"
 	"net/http"
 	"time"
 
@@ -30,7 +28,7 @@ func TransactionsDelete(co *coordinator.Coordinator) http.HandlerFunc {
 			return
 		}
 
-		fieldValues, score, maxSize, expiry, err := readDeleteRecords(r.Body)
+		fieldValues, score, maxSize, expiry, err := readDeleteRecords(r)
 		if err != nil {
 			responses.BadRequest(w, r, err)
 			return
@@ -41,7 +39,7 @@ func TransactionsDelete(co *coordinator.Coordinator) http.HandlerFunc {
 			maxSizeExpiry = selectors.MakeKeySizeSingleton(key, maxSize, expiry)
 
 			elements           = fieldValues.KeyFieldScoreTxnValues(key, score)
-			results, deleteErr = co.Delete(elements, maxSizeExpiry)
+			results, deleteErr = co.Delete(r.Context(), elements, maxSizeExpiry)
 		)
 		if deleteErr != nil {
 			responses.Error(w, r, deleteErr)
@@ -53,26 +51,27 @@ func TransactionsDelete(co *coordinator.Coordinator) http.HandlerFunc {
 	})
 }
 
-func readDeleteRecords(read io.ReadCloser) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
-	var (
-		buffer bytes.Buffer
-		fail   = func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
-			return nil, 0, 0, time.Duration(0), err
-		}
-	)
-	if _, err := buffer.ReadFrom(read); err != nil {
-		return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
-			"Invalid Body"))
+func readDeleteRecords(r *http.Request) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+	fail := func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+		return nil, 0, 0, time.Duration(0), err
 	}
 
-	body := buffer.Bytes()
-	if len(body) < 1 {
-		return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
-			"Invalid Body Length"))
+	body, err := readBody(r, &records.DeleteRecords{})
+	if err != nil {
+		return fail(err)
+	}
+
+	return ReadDeleteRequest(schema.GetRootAsDeleteRequest(body, 0))
+}
+
+// ReadDeleteRequest reads the records of a delete request, so that they can be
+// deleted.
+func ReadDeleteRequest(request *schema.DeleteRequest) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+	fail := func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+		return nil, 0, 0, time.Duration(0), err
 	}
 
 	var (
-		request = schema.GetRootAsDeleteRequest(body, 0)
 		score   = request.Score()
 		maxSize = request.MaxSize()
 		expiry  = request.Expiry()
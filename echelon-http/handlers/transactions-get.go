@@ -43,7 +43,7 @@ func TransactionsGet(co *coordinator.Coordinator) http.HandlerFunc {
 		var (
 			key          = bs.Key(queryKey)
 			sizeExpiry   = selectors.MakeKeySizeSingleton(key, int64(maxSize), time.Duration(expiry))
-			results, err = co.SelectRange(key, queryLimit, sizeExpiry)
+			results, err = co.SelectRange(r.Context(), key, queryLimit, sizeExpiry)
 		)
 		if err != nil {
 			responses.InternalServerError(w, r, err)
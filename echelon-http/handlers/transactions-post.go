@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"bytes"
-	"io"
 	"net/http"
 	"time"
 
@@ -30,7 +28,7 @@ func TransactionsPost(co *coordinator.Coordinator) http.HandlerFunc {
 			return
 		}
 
-		fieldTxnValues, score, maxSize, expiry, err := readPostRecords(r.Body)
+		fieldTxnValues, score, maxSize, expiry, err := readPostRecords(r)
 		if err != nil {
 			responses.BadRequest(w, r, err)
 			return
@@ -41,7 +39,7 @@ func TransactionsPost(co *coordinator.Coordinator) http.HandlerFunc {
 			maxSizeExpiry = selectors.MakeKeySizeSingleton(key, maxSize, expiry)
 
 			elements           = fieldTxnValues.KeyFieldScoreTxnValues(key, score)
-			results, insertErr = co.Insert(elements, maxSizeExpiry)
+			results, insertErr = co.Insert(r.Context(), elements, maxSizeExpiry)
 		)
 		if insertErr != nil {
 			responses.Error(w, r, insertErr)
@@ -53,26 +51,27 @@ func TransactionsPost(co *coordinator.Coordinator) http.HandlerFunc {
 	})
 }
 
-func readPostRecords(read io.ReadCloser) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
-	var (
-		buffer bytes.Buffer
-		fail   = func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
-			return nil, 0, 0, time.Duration(0), err
-		}
-	)
-	if _, err := buffer.ReadFrom(read); err != nil {
-		return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
-			"Invalid Body"))
+func readPostRecords(r *http.Request) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+	fail := func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+		return nil, 0, 0, time.Duration(0), err
 	}
 
-	body := buffer.Bytes()
-	if len(body) < 1 {
-		return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
-			"Invalid Body Length"))
+	body, err := readBody(r, &records.PostRecords{})
+	if err != nil {
+		return fail(err)
+	}
+
+	return ReadPostRequest(schema.GetRootAsPostRequest(body, 0))
+}
+
+// ReadPostRequest reads the records of a post request, so that they can be
+// inserted.
+func ReadPostRequest(request *schema.PostRequest) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+	fail := func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+		return nil, 0, 0, time.Duration(0), err
 	}
 
 	var (
-		request = schema.GetRootAsPostRequest(body, 0)
 		score   = request.Score()
 		maxSize = request.MaxSize()
 		expiry  = request.Expiry()
@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"bytes"
-	"io"
 	"net/http"
 	"time"
 
@@ -30,7 +28,7 @@ func TransactionsPut(co *coordinator.Coordinator) http.HandlerFunc {
 			return
 		}
 
-		fieldValues, score, maxSize, expiry, err := readPutRecords(r.Body)
+		fieldValues, score, maxSize, expiry, err := readPutRecords(r)
 		if err != nil {
 			responses.BadRequest(w, r, err)
 			return
@@ -41,7 +39,7 @@ func TransactionsPut(co *coordinator.Coordinator) http.HandlerFunc {
 			maxSizeExpiry = selectors.MakeKeySizeSingleton(key, maxSize, expiry)
 
 			elements           = fieldValues.KeyFieldScoreTxnValues(key, score)
-			results, modifyErr = co.Modify(elements, maxSizeExpiry)
+			results, modifyErr = co.Modify(r.Context(), elements, maxSizeExpiry)
 		)
 		if modifyErr != nil {
 			responses.Error(w, r, modifyErr)
@@ -53,26 +51,27 @@ func TransactionsPut(co *coordinator.Coordinator) http.HandlerFunc {
 	})
 }
 
-func readPutRecords(read io.ReadCloser) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
-	var (
-		buffer bytes.Buffer
-		fail   = func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
-			return nil, 0, 0, time.Duration(0), err
-		}
-	)
-	if _, err := buffer.ReadFrom(read); err != nil {
-		return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
-			"Invalid Body"))
+func readPutRecords(r *http.Request) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+	fail := func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+		return nil, 0, 0, time.Duration(0), err
 	}
 
-	body := buffer.Bytes()
-	if len(body) < 1 {
-		return fail(typex.Errorf(errors.Source, errors.InvalidArgument,
-			"Invalid Body Length"))
+	body, err := readBody(r, &records.PutRecords{})
+	if err != nil {
+		return fail(err)
+	}
+
+	return ReadPutRequest(schema.GetRootAsPutRequest(body, 0))
+}
+
+// ReadPutRequest reads the records of a put request, so that they can be
+// modified.
+func ReadPutRequest(request *schema.PutRequest) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+	fail := func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+		return nil, 0, 0, time.Duration(0), err
 	}
 
 	var (
-		request = schema.GetRootAsPutRequest(body, 0)
 		score   = request.Score()
 		maxSize = request.MaxSize()
 		expiry  = request.Expiry()
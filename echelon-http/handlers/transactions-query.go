@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"net/http"
+	"net/url"
 	"time"
 
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	"github.com/SimonRichardson/echelon/echelon-http/responses"
 	"github.com/SimonRichardson/echelon/coordinator"
 	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/schemas/records"
 	"github.com/SimonRichardson/echelon/selectors"
 	"github.com/SimonRichardson/echelon/internal/typex"
 	"gopkg.in/mgo.v2/bson"
@@ -35,17 +37,15 @@ func TransactionsQuery(co *coordinator.Coordinator) http.HandlerFunc {
 			return
 		}
 
-		ownerId := r.Form.Get("owner_id")
-		if !bson.IsObjectIdHex(ownerId) {
-			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument, "Invalid request parameter"))
+		options, err := parseQueryOptions(r.Form)
+		if err != nil {
+			responses.BadRequest(w, r, err)
 			return
 		}
 
 		var (
 			key                 = bs.Key(queryKey)
-			results, resultsErr = co.Query(key, selectors.QueryOptions{
-				OwnerId: bs.Key(ownerId),
-			}, selectors.SizeExpiry{
+			results, resultsErr = co.Query(r.Context(), key, options, selectors.SizeExpiry{
 				Size:   int64(maxSize),
 				Expiry: time.Duration(expiry),
 			})
@@ -59,3 +59,65 @@ func TransactionsQuery(co *coordinator.Coordinator) http.HandlerFunc {
 		return
 	})
 }
+
+// parseQueryOptions reads the query options from the form, "where" can be
+// repeated for every predicate (e.g. "where=expiry:lte:now+1m") and "sort" can
+// be repeated for every field to sort by (e.g. "sort=-expiry"). "state" can be
+// repeated for every state a record can be in (e.g. "state=charged").
+func parseQueryOptions(form url.Values) (selectors.QueryOptions, error) {
+	var options selectors.QueryOptions
+
+	if ownerId := form.Get("owner_id"); ownerId != "" {
+		if !bson.IsObjectIdHex(ownerId) {
+			return options, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid request parameter")
+		}
+		options.OwnerId = bs.Key(ownerId)
+	}
+
+	for _, v := range form["where"] {
+		predicate, err := selectors.ParsePredicate(v)
+		if err != nil {
+			return options, err
+		}
+		options.Predicates = append(options.Predicates, predicate)
+	}
+
+	if values := form["state"]; len(values) > 0 {
+		for _, v := range values {
+			if _, err := records.ParseState(v); err != nil {
+				return options, err
+			}
+		}
+		options.Predicates = append(options.Predicates, selectors.Predicate{
+			Field:      "state",
+			Comparison: selectors.In,
+			Values:     values,
+		})
+	}
+
+	for _, v := range form["sort"] {
+		sort, err := selectors.ParseQuerySort(v)
+		if err != nil {
+			return options, err
+		}
+		options.Sort = append(options.Sort, sort)
+	}
+
+	for name, value := range map[string]*int{
+		"limit":  &options.Limit,
+		"offset": &options.Offset,
+	} {
+		if form.Get(name) == "" {
+			continue
+		}
+		amount, ok := parseInt(form, name, 0)
+		if !ok || amount < 0 {
+			return options, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid request parameter")
+		}
+		*value = amount
+	}
+
+	return options, nil
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/SimonRichardson/echelon/coordinator"
+	"github.com/SimonRichardson/echelon/echelon-http/responses"
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/selectors"
+)
+
+// TransactionsRollbackTransaction rollbacks all the items of a transaction
+// from the collection, without having to know which items the transaction
+// inserted. It returns the number of items that were rolled back.
+func TransactionsRollbackTransaction(co *coordinator.Coordinator) http.HandlerFunc {
+	return accepts(func(w http.ResponseWriter, r *http.Request) {
+		began := time.Now()
+
+		var (
+			queryKey = r.URL.Query().Get(":key")
+			queryTxn = r.URL.Query().Get(":txn")
+		)
+		if !bson.IsObjectIdHex(queryKey) {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Key: %s", queryKey))
+			return
+		}
+		if !bson.IsObjectIdHex(queryTxn) {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Transaction: %s", queryTxn))
+			return
+		}
+
+		var (
+			maxSize, ok0 = parseInt(r.Form, "size", 0)
+			expiry, ok1  = parseInt(r.Form, "expiry", 0)
+			score, err   = strconv.ParseFloat(r.Form.Get("score"), 64)
+		)
+		if !ok0 || !ok1 || err != nil || maxSize < 1 || expiry < 1 {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument, "Invalid request parameter"))
+			return
+		}
+
+		var (
+			key           = bs.Key(queryKey)
+			maxSizeExpiry = selectors.MakeKeySizeSingleton(key, int64(maxSize), time.Duration(expiry))
+
+			amount, rollbackErr = co.RollbackTransaction(r.Context(), key, bs.Key(queryTxn), score, maxSizeExpiry)
+		)
+		if rollbackErr != nil {
+			responses.Error(w, r, rollbackErr)
+			return
+		}
+
+		responses.OKInt(w, amount, time.Since(began))
+		return
+	})
+}
@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"bytes"
-	"io"
 	"net/http"
 	"time"
 
@@ -32,7 +30,7 @@ func TransactionsRollback(co *coordinator.Coordinator) http.HandlerFunc {
 			return
 		}
 
-		fieldValues, score, maxSize, expiry, err := readRollbackRecords(r.Body)
+		fieldValues, score, maxSize, expiry, err := readRollbackRecords(r)
 		if err != nil {
 			responses.BadRequest(w, r, err)
 			return
@@ -43,7 +41,7 @@ func TransactionsRollback(co *coordinator.Coordinator) http.HandlerFunc {
 			maxSizeExpiry = selectors.MakeKeySizeSingleton(key, maxSize, expiry)
 
 			elements    = fieldValues.KeyFieldScoreTxnValues(key, score)
-			rollbackErr = co.Rollback(elements, maxSizeExpiry)
+			rollbackErr = co.Rollback(r.Context(), elements, maxSizeExpiry)
 		)
 		if rollbackErr != nil {
 			responses.Error(w, r, rollbackErr)
@@ -55,24 +53,27 @@ func TransactionsRollback(co *coordinator.Coordinator) http.HandlerFunc {
 	})
 }
 
-func readRollbackRecords(read io.ReadCloser) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
-	var (
-		buffer bytes.Buffer
-		fail   = func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
-			return nil, 0, 0, time.Duration(0), err
-		}
-	)
-	if _, err := buffer.ReadFrom(read); err != nil {
-		return fail(typex.Errorf(errors.Source, errors.InvalidArgument, "Invalid Body"))
+func readRollbackRecords(r *http.Request) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+	fail := func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+		return nil, 0, 0, time.Duration(0), err
 	}
 
-	body := buffer.Bytes()
-	if len(body) < 1 {
-		return fail(typex.Errorf(errors.Source, errors.InvalidArgument, "Invalid Body Length"))
+	body, err := readBody(r, &records.RollbackRecords{})
+	if err != nil {
+		return fail(err)
+	}
+
+	return ReadRollbackRequest(schema.GetRootAsRollbackRequest(body, 0))
+}
+
+// ReadRollbackRequest reads the records of a rollback request, so that they can be
+// rolled back.
+func ReadRollbackRequest(request *schema.RollbackRequest) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+	fail := func(err error) (selectors.FieldTxnValues, float64, int64, time.Duration, error) {
+		return nil, 0, 0, time.Duration(0), err
 	}
 
 	var (
-		request = schema.GetRootAsRollbackRequest(body, 0)
 		score   = request.Score()
 		maxSize = request.MaxSize()
 		expiry  = request.Expiry()
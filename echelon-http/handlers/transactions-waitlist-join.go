@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SimonRichardson/echelon/coordinator"
+	"github.com/SimonRichardson/echelon/echelon-http/responses"
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/selectors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TransactionsWaitlistJoin adds the items to the waiting list of the
+// collection, they're then inserted as a hold once there's room for them. The
+// items have to share a transaction, which identifies them with in the
+// waiting list. It returns the position with in the waiting list, or 0 if
+// they were offered a hold straight away.
+func TransactionsWaitlistJoin(co *coordinator.Coordinator) http.HandlerFunc {
+	return guard(func(w http.ResponseWriter, r *http.Request) {
+		began := time.Now()
+
+		queryKey := r.URL.Query().Get(":key")
+		if !bson.IsObjectIdHex(queryKey) {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Key: %s", queryKey))
+			return
+		}
+
+		fieldTxnValues, score, maxSize, expiry, err := readPostRecords(r)
+		if err != nil {
+			responses.BadRequest(w, r, err)
+			return
+		}
+
+		txn, err := readWaitlistTransaction(fieldTxnValues)
+		if err != nil {
+			responses.BadRequest(w, r, err)
+			return
+		}
+
+		var (
+			key   = bs.Key(queryKey)
+			entry = selectors.WaitlistEntry{
+				Id:      txn,
+				Score:   float64(began.UnixNano()),
+				Members: fieldTxnValues.KeyFieldScoreTxnValues(key, score),
+				MaxSize: selectors.SizeExpiry{
+					Size:   maxSize,
+					Expiry: expiry,
+				},
+			}
+
+			position, joinErr = co.Join(r.Context(), key, entry)
+		)
+		if joinErr != nil {
+			responses.Error(w, r, joinErr)
+			return
+		}
+
+		responses.OKInt(w, position, time.Since(began))
+		return
+	})
+}
+
+func readWaitlistTransaction(values selectors.FieldTxnValues) (bs.Key, error) {
+	if len(values) < 1 {
+		return bs.Key(""), typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid Records")
+	}
+
+	txn := values[0].Txn
+	for _, v := range values {
+		if v.Txn != txn {
+			return bs.Key(""), typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Transaction: %s", v.Txn.String())
+		}
+	}
+	return txn, nil
+}
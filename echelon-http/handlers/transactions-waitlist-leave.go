@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SimonRichardson/echelon/coordinator"
+	"github.com/SimonRichardson/echelon/echelon-http/responses"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+)
+
+// TransactionsWaitlistLeave removes a transaction from the waiting list of the
+// collection.
+func TransactionsWaitlistLeave(co *coordinator.Coordinator) http.HandlerFunc {
+	return accepts(func(w http.ResponseWriter, r *http.Request) {
+		began := time.Now()
+
+		key, txn, err := readWaitlistParams(r)
+		if err != nil {
+			responses.BadRequest(w, r, err)
+			return
+		}
+
+		ok, err := co.Leave(r.Context(), key, txn)
+		if err != nil {
+			responses.Error(w, r, err)
+			return
+		}
+		if !ok {
+			responses.NotFound(w, r, typex.Errorf(errors.Source, errors.MissingContent,
+				"Not Waiting: %s", txn.String()))
+			return
+		}
+
+		responses.OKNoCotent(w, time.Since(began))
+		return
+	})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SimonRichardson/echelon/coordinator"
+	"github.com/SimonRichardson/echelon/echelon-http/responses"
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TransactionsWaitlistPosition returns the position of a transaction with in
+// the waiting list of the collection.
+func TransactionsWaitlistPosition(co *coordinator.Coordinator) http.HandlerFunc {
+	return accepts(func(w http.ResponseWriter, r *http.Request) {
+		began := time.Now()
+
+		key, txn, err := readWaitlistParams(r)
+		if err != nil {
+			responses.BadRequest(w, r, err)
+			return
+		}
+
+		position, _, ok, err := co.Position(r.Context(), key, txn)
+		if err != nil {
+			responses.Error(w, r, err)
+			return
+		}
+		if !ok {
+			responses.NotFound(w, r, typex.Errorf(errors.Source, errors.MissingContent,
+				"Not Waiting: %s", txn.String()))
+			return
+		}
+
+		responses.OKInt(w, position, time.Since(began))
+		return
+	})
+}
+
+func readWaitlistParams(r *http.Request) (bs.Key, bs.Key, error) {
+	var (
+		queryKey = r.URL.Query().Get(":key")
+		queryTxn = r.URL.Query().Get(":txn")
+	)
+	if !bson.IsObjectIdHex(queryKey) {
+		return bs.Key(""), bs.Key(""), typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid Key: %s", queryKey)
+	}
+	if !bson.IsObjectIdHex(queryTxn) {
+		return bs.Key(""), bs.Key(""), typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid Transaction: %s", queryTxn)
+	}
+	return bs.Key(queryKey), bs.Key(queryTxn), nil
+}
@@ -6,22 +6,19 @@ import (
 	"math/rand"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
-	"gopkg.in/mgo.v2/bson"
-
+	ca "github.com/SimonRichardson/echelon/cluster/admission"
+	"github.com/SimonRichardson/echelon/echelon-http/admission"
 	"github.com/SimonRichardson/echelon/echelon-http/handlers"
 	"github.com/SimonRichardson/echelon/common"
 	"github.com/SimonRichardson/echelon/coordinator"
 	"github.com/SimonRichardson/echelon/env"
-	"github.com/SimonRichardson/echelon/errors"
 	"github.com/SimonRichardson/echelon/schemas/pool"
 	"github.com/SimonRichardson/echelon/schemas/records"
-	"github.com/SimonRichardson/echelon/schemas/schema"
-	s "github.com/SimonRichardson/echelon/selectors"
 	"github.com/SimonRichardson/echelon/internal/logs/generic"
 	"github.com/SimonRichardson/echelon/internal/logs/parse"
+	"github.com/SimonRichardson/echelon/internal/services/cache"
 	"github.com/SimonRichardson/echelon/internal/typex"
 	"github.com/gorilla/pat"
 	"github.com/prometheus/client_golang/prometheus"
@@ -95,12 +92,57 @@ func setupLogging(e *env.Env) {
 	}
 }
 
+// newIdempotency creates the cache for the idempotent responses, if there are
+// no instances then requests aren't de-duplicated.
+func newIdempotency(e *env.Env) handlers.IdempotencyCache {
+	if e.HttpIdempotencyInstances == "" {
+		return nil
+	}
+
+	service, err := cache.DefaultService(e.HttpIdempotencyInstances, e.HttpIdempotencyExpiry)
+	if err != nil {
+		typex.Fatal(err)
+	}
+	return service
+}
+
+// newAdmission creates the waiting room for the inserts, if there is no secret
+// then every insert is let through. The queues are held on the same redis as
+// the notifier by default, using the timeouts of the notifier.
+func newAdmission(e *env.Env) *admission.Room {
+	if e.HttpAdmissionSecret == "" {
+		return nil
+	}
+
+	queue, err := ca.ParseString(
+		e.HttpAdmissionInstances,
+		e.NotifierConnectTimeout, e.NotifierReadTimeout, e.NotifierWriteTimeout,
+		e.NotifierPoolRoutingStrategy,
+		e.NotifierMaxSize,
+		e.RedisCreator,
+	)
+	if err != nil {
+		typex.Fatal(err)
+	}
+
+	return admission.New(e.HttpAdmissionSecret, queue, admission.Options{
+		PerDuration:      e.HttpAdmissionPerDuration,
+		Duration:         e.HttpAdmissionDuration,
+		Expiry:           e.HttpAdmissionExpiry,
+		Uses:             e.HttpAdmissionUses,
+		IssuePerDuration: e.HttpAdmissionIssuePerDuration,
+		IssueDuration:    e.HttpAdmissionIssueDuration,
+	})
+}
+
 func newServer(e *env.Env) server {
 	// Setup logging
 	setupLogging(e)
 
 	var (
-		co = coordinator.New(e, transformer, accessor{})
+		co          = coordinator.New(e, records.Transform, records.Accessor{})
+		idempotency = newIdempotency(e)
+		room        = newAdmission(e)
 
 		path = func(p string) func(string) string {
 			return func(n string) string { return fmt.Sprintf("%s%s", p, n) }
@@ -119,9 +161,21 @@ func newServer(e *env.Env) server {
 
 	router.Get("/http/version", handlers.Version(e.Version))
 
+	router.Post(prefix("/transactions/batch"), handlers.Idempotent(idempotency, handlers.TransactionsBatch(co, room)))
+
 	router.Get(tprefix("/query"), handlers.TransactionsQuery(co))
 	router.Get(tprefix("/count"), handlers.TransactionsCount(co))
+	router.Delete(tprefix("/rollback/{txn}"), handlers.TransactionsRollbackTransaction(co))
 	router.Delete(tprefix("/rollback"), handlers.TransactionsRollback(co))
+	router.Get(tprefix("/changes"), handlers.TransactionsChanges(co,
+		e.HttpChangesInterval,
+		e.HttpChangesLimit,
+	))
+	router.Post(tprefix("/admission"), handlers.TransactionsAdmissionIssue(room, e.HttpAdmissionClientHeader))
+	router.Get(tprefix("/admission"), handlers.TransactionsAdmissionStatus(room))
+	router.Post(tprefix("/waitlist"), handlers.Admitted(room, handlers.TransactionsWaitlistJoin(co)))
+	router.Get(tprefix("/waitlist/{txn}"), handlers.TransactionsWaitlistPosition(co))
+	router.Delete(tprefix("/waitlist/{txn}"), handlers.TransactionsWaitlistLeave(co))
 
 	// Transaction
 	// The following are handlers for doing individual requests and
@@ -135,9 +189,9 @@ func newServer(e *env.Env) server {
 	// on a set (collection) of transactions
 
 	router.Get(tprefix(""), handlers.TransactionsGet(co))
-	router.Post(tprefix(""), handlers.TransactionsPost(co))
-	router.Put(tprefix(""), handlers.TransactionsPut(co))
-	router.Delete(tprefix(""), handlers.TransactionsDelete(co))
+	router.Post(tprefix(""), handlers.Idempotent(idempotency, handlers.Admitted(room, handlers.TransactionsPost(co))))
+	router.Put(tprefix(""), handlers.Idempotent(idempotency, handlers.TransactionsPut(co)))
+	router.Delete(tprefix(""), handlers.Idempotent(idempotency, handlers.TransactionsDelete(co)))
 
 	// Custom verbs.
 	router.Add("COUNT", tprefix(""), handlers.TransactionsCount(co))
@@ -152,145 +206,3 @@ func newServer(e *env.Env) server {
 		co,
 	}
 }
-
-type accessor struct{}
-
-func (a accessor) GetFieldValue(i interface{}, field string) (string, error) {
-	record, ok := i.(*records.PostRecord)
-	if !ok {
-		return "", typex.Errorf(errors.Source, errors.InvalidArgument, "Invalid Type")
-	}
-
-	switch field {
-	case "txn":
-		return record.TransactionId.Hex(), nil
-	case "owner_id":
-		return record.OwnerId.Hex(), nil
-	case "expiry_time":
-		return fmt.Sprintf("%d", record.Expiry.UnixNano()), nil
-	default:
-		return "", typex.Errorf(errors.Source, errors.UnexpectedResults,
-			"Invalid property %s", field)
-	}
-}
-
-func (a accessor) SetFieldValue(i interface{}, field, value string) error {
-	record, ok := i.(*records.PostRecord)
-	if !ok {
-		return typex.Errorf(errors.Source, errors.InvalidArgument, "Invalid Type")
-	}
-
-	switch field {
-	case "txn":
-		if !bson.IsObjectIdHex(value) {
-			return typex.Errorf(errors.Source, errors.InvalidArgument,
-				"Invalid ObjectId (%s)", value)
-		}
-		record.TransactionId = bson.ObjectIdHex(value)
-	case "owner_id":
-		if !bson.IsObjectIdHex(value) {
-			return typex.Errorf(errors.Source, errors.InvalidArgument,
-				"Invalid ObjectId (%s)", value)
-		}
-		record.OwnerId = bson.ObjectIdHex(value)
-	case "expiry_time":
-		s, err := strconv.Atoi(value)
-		if err != nil {
-			return typex.Errorf(errors.Source, errors.InvalidArgument,
-				"Invalid time (%s)", value)
-		}
-		record.Expiry = time.Unix(0, int64(s))
-	default:
-		return typex.Errorf(errors.Source, errors.InvalidArgument,
-			"Invalid property %s", field)
-	}
-	return nil
-}
-
-func transformer(value s.KeyFieldScoreTxnValue) (map[string]interface{}, error) {
-	header, err := records.ReadType(value.Value)
-	if err != nil {
-		return nil, err
-	}
-
-	var (
-		meta = func(updated time.Time) map[string]interface{} {
-			m := map[string]interface{}{
-				"model": map[string]interface{}{
-					"created_at": time.Now(),
-					"updated_at": updated,
-				},
-			}
-			return m
-		}
-		m = map[string]interface{}{}
-	)
-
-	switch header {
-	case schema.TypePost:
-		var (
-			record    = &records.PostRecord{}
-			body, err = records.ReadBody(value.Value)
-		)
-		if err != nil {
-			return nil, err
-		}
-		if err = record.Read(body); err != nil {
-			return nil, err
-		}
-
-		m["_id"] = bson.ObjectIdHex(value.Field.String())
-		m["owner_id"] = record.OwnerId
-		m["expiry_time"] = record.Expiry
-		m["reserved_at"] = record.Reserved
-		m["meta"] = meta(record.Updated)
-		m["txn"] = record.TransactionId
-
-		cost := record.Cost
-		m["cost"] = map[string]interface{}{
-			"currency": cost.Currency,
-			"price":    cost.Price,
-		}
-
-	case schema.TypePut:
-		var (
-			record    = &records.PutRecord{}
-			body, err = records.ReadBody(value.Value)
-		)
-		if err != nil {
-			return nil, err
-		}
-		if err = record.Read(body); err != nil {
-			return nil, err
-		}
-
-		m["_id"] = bson.ObjectIdHex(value.Field.String())
-		m["owner_id"] = record.OwnerId
-		m["purchased_at"] = record.Purchased
-		m["meta"] = meta(record.Updated)
-		m["txn"] = record.TransactionId
-
-		cost := record.EventCost
-		m["cost"] = map[string]interface{}{
-			"currency": cost.Currency,
-			"price":    cost.Price,
-		}
-
-		dates := record.EventDates
-		m["event_date"] = time.Unix(0, int64(dates.Start))
-		m["event_date_end"] = time.Unix(0, int64(dates.End))
-
-		codes := record.Codes
-		m["bar_code"] = map[string]interface{}{
-			"type":   codes.BarcodeType,
-			"origin": codes.BarcodeOrigin,
-			"source": codes.BarcodeSource,
-		}
-		m["qr_code"] = codes.QRCode
-
-	default:
-		return nil, typex.Errorf(errors.Source, errors.NoCaseFound, "Unknown Type")
-	}
-
-	return m, nil
-}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"math"
@@ -1020,7 +1021,7 @@ func testConsumptionWithAmount(t *testing.T,
 		f(key, amount)
 	}
 
-	size, err := co.Size(bs.Key(key.Hex()))
+	size, err := co.Size(context.Background(), bs.Key(key.Hex()))
 	if err != nil {
 		typex.Fatal(err)
 	}
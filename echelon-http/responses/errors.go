@@ -5,7 +5,6 @@ import (
 
 	"fmt"
 
-	"github.com/SimonRichardson/echelon/schemas/pool"
 	"github.com/SimonRichardson/echelon/schemas/records"
 	"github.com/SimonRichardson/echelon/internal/logs/generic"
 	"github.com/SimonRichardson/echelon/internal/typex"
@@ -27,7 +26,7 @@ func RespondError(w http.ResponseWriter,
 
 	teleprinter.L.Error().Println(trace)
 
-	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Type", encodingOf(w).ContentType())
 	w.WriteHeader(code)
 
 	record := records.Error{
@@ -36,15 +35,9 @@ func RespondError(w http.ResponseWriter,
 		Description: getDescription(trace),
 	}
 
-	fb := pool.Get()
-	defer pool.Put(fb)
-
-	bytes, err := record.Write(fb)
-	if err != nil {
+	if err := writeRecord(w, record); err != nil {
 		panic(err)
 	}
-
-	w.Write(bytes)
 }
 
 func Error(w http.ResponseWriter, r *http.Request, err error) {
@@ -0,0 +1,111 @@
+package responses
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/SimonRichardson/echelon/schemas/records"
+)
+
+// Encoding defines the encoding of a body, as negotiated with the client.
+type Encoding int
+
+const (
+	// Flatbuffers encodes the body with the schemas.
+	Flatbuffers Encoding = iota
+
+	// JSON encodes the body as json.
+	JSON
+
+	// MessagePack encodes the body as msgpack.
+	MessagePack
+)
+
+const (
+	// FlatbuffersContentType defines the content type of flatbuffers.
+	FlatbuffersContentType = "application/octet-stream"
+
+	// JSONContentType defines the content type of json.
+	JSONContentType = "application/json"
+
+	// MessagePackContentType defines the content type of msgpack.
+	MessagePackContentType = "application/msgpack"
+)
+
+// ParseEncoding returns the encoding of the content type, the parameters of
+// the content type (charset) are ignored.
+func ParseEncoding(contentType string) (Encoding, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return Flatbuffers, false
+	}
+
+	switch strings.ToLower(mediaType) {
+	case FlatbuffersContentType:
+		return Flatbuffers, true
+	case JSONContentType:
+		return JSON, true
+	case MessagePackContentType, "application/x-msgpack":
+		return MessagePack, true
+	}
+	return Flatbuffers, false
+}
+
+// ParseAccept returns the first encoding of the accept header that is
+// supported, quality values are ignored.
+func ParseAccept(accept string) (Encoding, bool) {
+	for _, contentType := range strings.Split(accept, ",") {
+		if encoding, ok := ParseEncoding(strings.TrimSpace(contentType)); ok {
+			return encoding, true
+		}
+	}
+	return Flatbuffers, false
+}
+
+// ContentType returns the content type of the encoding.
+func (e Encoding) ContentType() string {
+	switch e {
+	case JSON:
+		return JSONContentType
+	case MessagePack:
+		return MessagePackContentType
+	}
+	return FlatbuffersContentType
+}
+
+// Records returns the encoding of the records, if the encoding isn't
+// flatbuffers.
+func (e Encoding) Records() (records.Encoding, bool) {
+	switch e {
+	case JSON:
+		return records.JSON, true
+	case MessagePack:
+		return records.MessagePack, true
+	}
+	return records.JSON, false
+}
+
+// Negotiate returns a writer that the responses are written to with the
+// encoding.
+func Negotiate(w http.ResponseWriter, encoding Encoding) http.ResponseWriter {
+	return negotiated{w, encoding}
+}
+
+type negotiated struct {
+	http.ResponseWriter
+	encoding Encoding
+}
+
+func (n negotiated) Flush() {
+	if flusher, ok := n.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func encodingOf(w http.ResponseWriter) Encoding {
+	if n, ok := w.(negotiated); ok {
+		return n.encoding
+	}
+	return Flatbuffers
+}
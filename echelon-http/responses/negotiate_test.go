@@ -0,0 +1,67 @@
+package responses
+
+import "testing"
+
+func TestParseEncoding(t *testing.T) {
+	for contentType, expected := range map[string]struct {
+		success  bool
+		encoding Encoding
+	}{
+		"":                                {false, Flatbuffers},
+		"text/plain":                      {false, Flatbuffers},
+		"application/octet-stream":        {true, Flatbuffers},
+		"application/json":                {true, JSON},
+		"application/json; charset=utf-8": {true, JSON},
+		"Application/JSON":                {true, JSON},
+		"application/msgpack":             {true, MessagePack},
+		"application/x-msgpack":           {true, MessagePack},
+	} {
+		encoding, ok := ParseEncoding(contentType)
+		if expected, actual := expected.success, ok; expected != actual {
+			t.Errorf("%q: Expected: %v, Actual: %v", contentType, expected, actual)
+			continue
+		}
+		if expected, actual := expected.encoding, encoding; expected != actual {
+			t.Errorf("%q: Expected: %v, Actual: %v", contentType, expected, actual)
+		}
+	}
+}
+
+func TestParseAccept(t *testing.T) {
+	for accept, expected := range map[string]struct {
+		success  bool
+		encoding Encoding
+	}{
+		"":                                   {false, Flatbuffers},
+		"*/*":                                {false, Flatbuffers},
+		"application/json":                   {true, JSON},
+		"text/html, application/msgpack":     {true, MessagePack},
+		"application/json;q=0.9, text/plain": {true, JSON},
+	} {
+		encoding, ok := ParseAccept(accept)
+		if expected, actual := expected.success, ok; expected != actual {
+			t.Errorf("%q: Expected: %v, Actual: %v", accept, expected, actual)
+			continue
+		}
+		if expected, actual := expected.encoding, encoding; expected != actual {
+			t.Errorf("%q: Expected: %v, Actual: %v", accept, expected, actual)
+		}
+	}
+}
+
+func TestAcceptsStream(t *testing.T) {
+	for accept, expected := range map[string]bool{
+		"":                                    false,
+		"*/*":                                 false,
+		"application/json":                    false,
+		"text/event-stream":                   true,
+		"Text/Event-Stream":                   true,
+		"text/event-stream; charset=utf-8":    true,
+		"text/event-stream, */*":              true,
+		"application/json, text/event-stream": true,
+	} {
+		if actual := AcceptsStream(accept); expected != actual {
+			t.Errorf("%q: Expected: %v, Actual: %v", accept, expected, actual)
+		}
+	}
+}
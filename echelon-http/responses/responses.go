@@ -12,7 +12,7 @@ import (
 )
 
 func Respond(w http.ResponseWriter, status int, fn func(http.ResponseWriter), duration time.Duration) {
-	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Type", encodingOf(w).ContentType())
 	w.Header().Set("X-Duration", duration.String())
 	w.WriteHeader(status)
 
@@ -28,44 +28,23 @@ func OKNoCotent(w http.ResponseWriter, duration time.Duration) {
 }
 
 func OKVersion(w http.ResponseWriter, payload records.Version, duration time.Duration) {
-	Respond(w, http.StatusOK, func(w http.ResponseWriter) {
-		fb := pool.Get()
-		defer pool.Put(fb)
-
-		record, err := records.OKVersion{
-			Duration: duration,
-			Records:  payload,
-		}.Write(fb)
-
-		if err != nil {
-			owned := typex.Errorf(errors.Source, typex.InternalServerError,
-				"Unable to create response for OKVersion.").With(err)
-			RespondError(w, "Unknown", "Unknown", typex.InternalServerError, owned)
-			return
-		}
+	respondRecord(w, "OKVersion", records.OKVersion{
+		Duration: duration,
+		Records:  payload,
+	}, duration)
+}
 
-		w.Write(record)
+func OKAdmission(w http.ResponseWriter, payload records.Admission, duration time.Duration) {
+	respondRecord(w, "OKAdmission", records.OKAdmission{
+		Duration: duration,
+		Records:  payload,
 	}, duration)
 }
 
 func OKInt(w http.ResponseWriter, payload int, duration time.Duration) {
-	Respond(w, http.StatusOK, func(w http.ResponseWriter) {
-		fb := pool.Get()
-		defer pool.Put(fb)
-
-		record, err := records.OKInt{
-			Duration: duration,
-			Records:  payload,
-		}.Write(fb)
-
-		if err != nil {
-			owned := typex.Errorf(errors.Source, typex.InternalServerError,
-				"Unable to create response for OKInt.").With(err)
-			RespondError(w, "Unknown", "Unknown", typex.InternalServerError, owned)
-			return
-		}
-
-		w.Write(record)
+	respondRecord(w, "OKInt", records.OKInt{
+		Duration: duration,
+		Records:  payload,
 	}, duration)
 }
 
@@ -73,23 +52,9 @@ func OKKeyFieldScoreTxnValue(w http.ResponseWriter,
 	payload selectors.KeyFieldScoreTxnValue,
 	duration time.Duration,
 ) {
-	Respond(w, http.StatusOK, func(w http.ResponseWriter) {
-		fb := pool.Get()
-		defer pool.Put(fb)
-
-		record, err := records.OKKeyFieldScoreTxnValue{
-			Duration: duration,
-			Records:  records.FromKeyFieldScoreTxnValue(payload),
-		}.Write(fb)
-
-		if err != nil {
-			owned := typex.Errorf(errors.Source, typex.InternalServerError,
-				"Unable to create response for OKKeyFieldScoreTxnValue.").With(err)
-			RespondError(w, "Unknown", "Unknown", typex.InternalServerError, owned)
-			return
-		}
-
-		w.Write(record)
+	respondRecord(w, "OKKeyFieldScoreTxnValue", records.OKKeyFieldScoreTxnValue{
+		Duration: duration,
+		Records:  records.FromKeyFieldScoreTxnValue(payload),
 	}, duration)
 }
 
@@ -97,23 +62,9 @@ func OKKeyFieldScoreTxnValues(w http.ResponseWriter,
 	payload []selectors.KeyFieldScoreTxnValue,
 	duration time.Duration,
 ) {
-	Respond(w, http.StatusOK, func(w http.ResponseWriter) {
-		fb := pool.Get()
-		defer pool.Put(fb)
-
-		record, err := records.OKKeyFieldScoreTxnValues{
-			Duration: duration,
-			Records:  records.FromKeyFieldScoreTxnValues(payload),
-		}.Write(fb)
-
-		if err != nil {
-			owned := typex.Errorf(errors.Source, typex.InternalServerError,
-				"Unable to create response for OKKeyFieldScoreTxnValues.").With(err)
-			RespondError(w, "Unknown", "Unknown", typex.InternalServerError, owned)
-			return
-		}
-
-		w.Write(record)
+	respondRecord(w, "OKKeyFieldScoreTxnValues", records.OKKeyFieldScoreTxnValues{
+		Duration: duration,
+		Records:  records.FromKeyFieldScoreTxnValues(payload),
 	}, duration)
 }
 
@@ -121,34 +72,55 @@ func OKQuery(w http.ResponseWriter,
 	payload []selectors.QueryRecord,
 	duration time.Duration,
 ) {
-	Respond(w, http.StatusOK, func(w http.ResponseWriter) {
-		fb := pool.Get()
-		defer pool.Put(fb)
+	recs, err := records.FromQueryRecords(payload)
+	if err != nil {
+		owned := typex.Errorf(errors.Source, typex.BadRequest,
+			"Unable to read QueryRecords.").With(err)
+		RespondError(w, "Unknown", "Unknown", typex.BadRequest, owned)
+		return
+	}
 
-		recs, err := records.FromQueryRecords(payload)
-		if err != nil {
-			owned := typex.Errorf(errors.Source, typex.BadRequest,
-				"Unable to read QueryRecords.").With(err)
-			RespondError(w, "Unknown", "Unknown", typex.BadRequest, owned)
-			return
-		}
+	respondRecord(w, "OKQuery", records.OKQuery{
+		Duration: duration,
+		Records:  recs,
+	}, duration)
+}
 
-		record, err := records.OKQuery{
-			Duration: duration,
-			Records:  recs,
-		}.Write(fb)
+func NoContent(w http.ResponseWriter, duration time.Duration) {
+	Respond(w, http.StatusNoContent, nil, duration)
+}
 
-		if err != nil {
+// respondRecord writes the record with the encoding that was negotiated with
+// the client, which is flatbuffers unless the client asked otherwise.
+func respondRecord(w http.ResponseWriter, name string, record records.Write, duration time.Duration) {
+	Respond(w, http.StatusOK, func(w http.ResponseWriter) {
+		if err := writeRecord(w, record); err != nil {
 			owned := typex.Errorf(errors.Source, typex.InternalServerError,
-				"Unable to create response for OKQuery.").With(err)
+				"Unable to create response for %s.", name).With(err)
 			RespondError(w, "Unknown", "Unknown", typex.InternalServerError, owned)
-			return
 		}
-
-		w.Write(record)
 	}, duration)
 }
 
-func NoContent(w http.ResponseWriter, duration time.Duration) {
-	Respond(w, http.StatusNoContent, nil, duration)
+func writeRecord(w http.ResponseWriter, record records.Write) error {
+	if encoding, ok := encodingOf(w).Records(); ok {
+		bytes, err := encoding.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(bytes)
+		return err
+	}
+
+	fb := pool.Get()
+	defer pool.Put(fb)
+
+	bytes, err := record.Write(fb)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(bytes)
+	return err
 }
@@ -0,0 +1,79 @@
+package responses
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SimonRichardson/echelon/selectors"
+)
+
+// StreamContentType defines the content type of a stream of server sent events.
+const StreamContentType = "text/event-stream"
+
+type change struct {
+	Type  string  `json:"type"`
+	Key   string  `json:"key"`
+	Field string  `json:"field"`
+	Score float64 `json:"score"`
+	Txn   string  `json:"txn"`
+	State string  `json:"state,omitempty"`
+}
+
+// AcceptsStream returns if the accept header accepts a stream of server sent
+// events, the parameters (charset) and quality values are ignored.
+func AcceptsStream(accept string) bool {
+	for _, contentType := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(contentType))
+		if err == nil && strings.ToLower(mediaType) == StreamContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// Stream writes the headers for a stream of server sent events.
+func Stream(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", StreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+}
+
+// StreamChange writes a change as a server sent event. Changes can share a
+// score, so only the last change of a score carries the score as the id of the
+// event, a stream that's resumed from the id then doesn't lose any of them.
+func StreamChange(w http.ResponseWriter, payload selectors.Change, last bool) error {
+	data, err := json.Marshal(change{
+		Type:  payload.Type.String(),
+		Key:   payload.Key.String(),
+		Field: payload.Field.String(),
+		Score: payload.Score,
+		Txn:   payload.Txn.String(),
+		State: payload.State,
+	})
+	if err != nil {
+		return err
+	}
+
+	if last {
+		if _, err := fmt.Fprintf(w, "id: %s\n", strconv.FormatFloat(payload.Score, 'f', -1, 64)); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n",
+		payload.Type.String(),
+		data,
+	)
+	return err
+}
+
+// StreamError writes an error as a server sent event, after which the stream
+// is expected to be closed.
+func StreamError(w http.ResponseWriter, err error) {
+	fmt.Fprintf(w, "event: error\ndata: %s\n\n", strconv.Quote(err.Error()))
+}
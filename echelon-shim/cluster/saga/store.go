@@ -0,0 +1,131 @@
+package saga
+
+import (
+	"encoding/json"
+	"time"
+
+	s "github.com/SimonRichardson/echelon/echelon-shim/saga"
+	p "github.com/SimonRichardson/echelon/internal/redis"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/garyburd/redigo/redis"
+)
+
+const (
+	// All the sagas are routed with the same key, so that the pending set is
+	// on the same instance as the sagas.
+	routingKey = "saga"
+
+	sagaPrefix  = "saga:"
+	leasePrefix = "saga:lease:"
+	pendingKey  = "saga:pending"
+)
+
+var (
+	// leaseScript leases the saga, if it's not leased or the lease is already
+	// held by the owner.
+	leaseScript = redis.NewScript(1, `
+		local owner = redis.call("GET", KEYS[1])
+		if owner == false or owner == ARGV[1] then
+			redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+			return 1
+		end
+		return 0
+	`)
+
+	// unleaseScript releases the lease, only if it's held by the owner.
+	unleaseScript = redis.NewScript(1, `
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("DEL", KEYS[1])
+		end
+		return 0
+	`)
+)
+
+type store struct {
+	pool      *p.Pool
+	retention time.Duration
+}
+
+// New creates a Store of the sagas with in redis. The sagas that are terminal
+// are kept for the retention, before they expire.
+func New(pool *p.Pool, retention time.Duration) s.Store {
+	return &store{
+		pool:      pool,
+		retention: retention,
+	}
+}
+
+func (c *store) Save(saga s.Saga) error {
+	bytes, err := json.Marshal(saga)
+	if err != nil {
+		return err
+	}
+
+	return c.pool.With(routingKey, func(conn redis.Conn) error {
+		var (
+			id  = saga.Id.String()
+			key = sagaPrefix + id
+		)
+
+		conn.Send("MULTI")
+		conn.Send("SET", key, bytes)
+		if saga.State.Terminal() {
+			conn.Send("PEXPIRE", key, int64(c.retention/time.Millisecond))
+			conn.Send("SREM", pendingKey, id)
+		} else {
+			conn.Send("SADD", pendingKey, id)
+		}
+		_, err := conn.Do("EXEC")
+		return err
+	})
+}
+
+func (c *store) Load(id bs.Key) (saga s.Saga, ok bool, err error) {
+	err = c.pool.With(routingKey, func(conn redis.Conn) error {
+		bytes, err := redis.Bytes(conn.Do("GET", sagaPrefix+id.String()))
+		if err == redis.ErrNil {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		ok = true
+		return json.Unmarshal(bytes, &saga)
+	})
+	return
+}
+
+func (c *store) Pending() (res []bs.Key, err error) {
+	err = c.pool.With(routingKey, func(conn redis.Conn) error {
+		ids, err := redis.Strings(conn.Do("SMEMBERS", pendingKey))
+		if err != nil {
+			return err
+		}
+
+		res = make([]bs.Key, 0, len(ids))
+		for _, v := range ids {
+			res = append(res, bs.Key(v))
+		}
+		return nil
+	})
+	return
+}
+
+func (c *store) Lease(id bs.Key, owner string, duration time.Duration) (ok bool, err error) {
+	err = c.pool.With(routingKey, func(conn redis.Conn) error {
+		ok, err = redis.Bool(leaseScript.Do(conn,
+			leasePrefix+id.String(),
+			owner,
+			int64(duration/time.Millisecond),
+		))
+		return err
+	})
+	return
+}
+
+func (c *store) Unlease(id bs.Key, owner string) error {
+	return c.pool.With(routingKey, func(conn redis.Conn) error {
+		_, err := unleaseScript.Do(conn, leasePrefix+id.String(), owner)
+		return err
+	})
+}
@@ -57,6 +57,14 @@ func Post(url string, bytes []byte, fn func(http.Header)) ([]byte, error) {
 	return request("POST", url, bytes, fn)
 }
 
+func Put(url string, bytes []byte, fn func(http.Header)) ([]byte, error) {
+	return request("PUT", url, bytes, fn)
+}
+
+func Delete(url string, fn func(http.Header)) ([]byte, error) {
+	return request("DELETE", url, nil, fn)
+}
+
 func errored(s int) bool {
 	return !(s == http.StatusOK || s == http.StatusCreated || s == http.StatusNoContent)
 }
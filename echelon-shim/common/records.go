@@ -0,0 +1,16 @@
+package common
+
+import "time"
+
+const (
+	// DefaultMaxSize defines the max size of the records of a key.
+	DefaultMaxSize = 99999 // TODO - find this out, do we care!?
+
+	// DefaultExpiry defines how long the records of a key are held for.
+	DefaultExpiry = time.Minute * 5
+)
+
+var (
+	// DefaultTime defines the time that the scores are incremented from.
+	DefaultTime = time.Date(2016, 12, 1, 1, 1, 1, 1, time.UTC)
+)
@@ -4,12 +4,14 @@ import (
 	"sync"
 	"time"
 
-	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	"github.com/SimonRichardson/echelon/alertmanager"
 	"github.com/SimonRichardson/echelon/echelon-shim/env"
 	"github.com/SimonRichardson/echelon/echelon-shim/farm/score"
+	"github.com/SimonRichardson/echelon/echelon-shim/saga"
 	"github.com/SimonRichardson/echelon/instrumentation"
 	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/services/lorenz"
 	"github.com/SimonRichardson/echelon/internal/typex"
 )
 
@@ -24,7 +26,14 @@ type Coordinator struct {
 
 	paused bool
 
-	score *score.Farm
+	host   string
+	score  *score.Farm
+	lorenz *lorenz.Service
+	saga   *saga.Orchestrator
+
+	recovery     time.Duration
+	serviceToken string
+	quit         chan struct{}
 
 	instrumentation instrumentation.Instrumentation
 	alertmanager    alertmanager.AlertManager
@@ -54,6 +63,11 @@ func New(e *env.Env) *Coordinator {
 
 		paused: false,
 
+		host:         e.C.HttpAddress,
+		recovery:     e.SagaRecoveryInterval,
+		serviceToken: e.SagaServiceToken,
+		quit:         make(chan struct{}),
+
 		instrumentation: instr,
 		alertmanager:    alert,
 	}
@@ -62,12 +76,16 @@ func New(e *env.Env) *Coordinator {
 		typex.Fatal(err)
 	}
 
+	go co.recoverSagas()
+
 	return co
 }
 
 func (co *Coordinator) init(e *env.Env) error {
 	var (
-		score *score.Farm
+		score   *score.Farm
+		service *lorenz.Service
+		store   saga.Store
 
 		err error
 	)
@@ -76,7 +94,17 @@ func (co *Coordinator) init(e *env.Env) error {
 		return err
 	}
 
+	if service, err = newLorenzService(e); err != nil {
+		return err
+	}
+
+	if store, err = newSagaStore(e); err != nil {
+		return err
+	}
+
 	co.score = score
+	co.lorenz = service
+	co.saga = saga.New(store, participants{co}, newSagaOptions(e))
 
 	return nil
 }
@@ -120,5 +148,84 @@ func (co *Coordinator) Increment(key bs.Key, t time.Time) (res int, err error) {
 	return
 }
 
+// SelectEventByKey returns the event of the key from lorenz.
+func (co *Coordinator) SelectEventByKey(key bs.Key) (res bs.Event, err error) {
+	if e := handle(co, func() {
+		res, err = co.lorenz.SelectEventByKey(key)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// SelectCodeForEvent returns a new set of codes for a ticket of the event.
+func (co *Coordinator) SelectCodeForEvent(event bs.Event, user bs.User) (res bs.CodeSet, err error) {
+	if e := handle(co, func() {
+		res, err = co.lorenz.SelectCodeForEvent(event, user)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// Charge charges the user for the payment, returning the transaction of the
+// charge.
+func (co *Coordinator) Charge(event bs.Event, user bs.User, payment bs.Payment) (res bs.Key, err error) {
+	if e := handle(co, func() {
+		res, err = co.lorenz.Charge(event, user, payment)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// SelectHeldRecords returns the records of the owner that are held by, or
+// have been purchased for, the transaction.
+func (co *Coordinator) SelectHeldRecords(key, ownerId, txn bs.Key) (res []HeldRecord, err error) {
+	if e := handle(co, func() {
+		res, err = co.selectHeldRecords(key, ownerId, txn)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// Purchase runs the saga that charges for, and then purchases, the held
+// records. If any step fails, then the steps that have been made are
+// compensated for. Purchasing the same transaction again returns the outcome
+// of the first purchase.
+func (co *Coordinator) Purchase(s saga.Saga) (res saga.Saga, err error) {
+	if e := handle(co, func() {
+		res, err = co.saga.Begin(s)
+	}); e != nil {
+		err = e
+	}
+	return
+}
+
+// recoverSagas runs the sagas that were left pending, by an instance that
+// stopped or a step that failed, once on start up and then every interval.
+func (co *Coordinator) recoverSagas() {
+	ticker := time.NewTicker(co.recovery)
+	defer ticker.Stop()
+
+	for {
+		if e := handle(co, func() {
+			if amount, err := co.saga.Recover(); err != nil {
+				teleprinter.L.Error().Printf("Unable to recover sagas (%d recovered) : %s\n", amount, err.Error())
+			}
+		}); e != nil {
+			teleprinter.L.Error().Printf("Unable to recover sagas : %s\n", e.Error())
+		}
+
+		select {
+		case <-ticker.C:
+		case <-co.quit:
+			return
+		}
+	}
+}
+
 func (co *Coordinator) Quit() {
+	close(co.quit)
 }
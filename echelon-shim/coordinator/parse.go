@@ -2,14 +2,20 @@ package coordinator
 
 import (
 	"io"
+	"strings"
 
 	a "github.com/SimonRichardson/echelon/alertmanager"
 	ap "github.com/SimonRichardson/echelon/alertmanager/parse"
+	cs "github.com/SimonRichardson/echelon/echelon-shim/cluster/saga"
 	"github.com/SimonRichardson/echelon/echelon-shim/cluster/score"
 	"github.com/SimonRichardson/echelon/echelon-shim/env"
 	t "github.com/SimonRichardson/echelon/echelon-shim/farm/score"
+	"github.com/SimonRichardson/echelon/echelon-shim/saga"
+	"github.com/SimonRichardson/echelon/internal/common"
 	i "github.com/SimonRichardson/echelon/instrumentation"
 	ip "github.com/SimonRichardson/echelon/instrumentation/parse"
+	r "github.com/SimonRichardson/echelon/internal/redis"
+	"github.com/SimonRichardson/echelon/internal/services/lorenz"
 )
 
 func newInstrumentation(e *env.Env, writer io.Writer) (i.Instrumentation, error) {
@@ -67,3 +73,71 @@ func newScoreFarm(e *env.Env, instr i.Instrumentation) (*t.Farm, error) {
 		instr,
 	), nil
 }
+
+func newLorenzService(e *env.Env) (*lorenz.Service, error) {
+	clusters, err := lorenz.ParseString(e.LorenzInstances, e.LorenzVersion, e.LorenzTimeout, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	charger, err := lorenz.ParseChargeStrategy(e.GetLorenzOptions(e.LorenzChargeStrategy))
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := lorenz.ParseEventSelectStrategy(e.GetLorenzOptions(e.LorenzEventSelectStrategy))
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := lorenz.ParseCodeSetSelectStrategy(e.GetLorenzOptions(e.LorenzCodeSetSelectStrategy))
+	if err != nil {
+		return nil, err
+	}
+
+	inspector, err := lorenz.ParseInspectStrategy(e.GetLorenzOptions("Inspector"))
+	if err != nil {
+		return nil, err
+	}
+
+	return lorenz.New(clusters,
+		charger,
+		events,
+		codes,
+		inspector,
+		lorenz.NoopInstrumentation{},
+	), nil
+}
+
+// newSagaStore creates the store of the sagas, if there are no instances then
+// the sagas are only held in memory and aren't recovered after a restart.
+func newSagaStore(e *env.Env) (saga.Store, error) {
+	if e.SagaRedisInstances == "" {
+		return saga.NewMemoryStore(), nil
+	}
+
+	timeouts, strategy, err := r.Parse(e.ShimRedisConnectTimeout,
+		e.ShimRedisReadTimeout,
+		e.ShimRedisWriteTimeout,
+		e.ScorePoolRoutingStrategy,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := strings.Split(common.StripWhitespace(e.SagaRedisInstances), ",")
+	return cs.New(
+		r.New(hosts, strategy, timeouts, e.SagaMaxSize, e.C.RedisCreator),
+		e.SagaRetention,
+	), nil
+}
+
+func newSagaOptions(e *env.Env) saga.Options {
+	return saga.Options{
+		Retries:     e.SagaRetries,
+		Backoff:     e.SagaBackoff,
+		MaxAttempts: e.SagaMaxAttempts,
+		Lease:       e.SagaLease,
+	}
+}
@@ -0,0 +1,243 @@
+package coordinator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	c "github.com/SimonRichardson/echelon/echelon-shim/common"
+	"github.com/SimonRichardson/echelon/echelon-shim/saga"
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/records"
+	flatbuffers "github.com/google/flatbuffers/go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// HeldRecord is a record of a transaction, that's either held (reserved) or
+// has been purchased.
+type HeldRecord struct {
+	Id        bson.ObjectId
+	Purchased bool
+}
+
+type queryResponse struct {
+	Records []struct {
+		Record struct {
+			Id     bson.ObjectId `json:"_id"`
+			Expiry time.Time     `json:"expiry_time"`
+			State  records.State `json:"state"`
+		} `json:"record"`
+	} `json:"records"`
+}
+
+// participants runs the steps of the purchase sagas against echelon and
+// lorenz.
+type participants struct {
+	co *Coordinator
+}
+
+func (p participants) Holding(s saga.Saga) ([]bs.Key, error) {
+	held, err := p.co.selectHeldRecords(s.Key, s.Owner, s.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]bs.Key, 0, len(held))
+	for _, v := range held {
+		res = append(res, bs.Key(v.Id.Hex()))
+	}
+	return res, nil
+}
+
+func (p participants) Charge(s saga.Saga) (bs.Key, error) {
+	event, err := p.co.lorenz.SelectEventByKey(s.Key)
+	if err != nil {
+		return bs.Key(""), err
+	}
+	return p.co.lorenz.Charge(event, s.Credentials.User, s.Credentials.Payment)
+}
+
+func (p participants) Persist(s saga.Saga) error {
+	held, err := p.co.selectHeldRecords(s.Key, s.Owner, s.Id)
+	if err != nil {
+		return err
+	}
+
+	// Only the records that have yet to be purchased are persisted, so that a
+	// retry doesn't give the purchased records new codes.
+	ids := make([]bson.ObjectId, 0, len(held))
+	for _, v := range held {
+		if !v.Purchased {
+			ids = append(ids, v.Id)
+		}
+	}
+	if len(ids) < 1 {
+		return nil
+	}
+
+	event, err := p.co.lorenz.SelectEventByKey(s.Key)
+	if err != nil {
+		return err
+	}
+	return p.co.purchaseHeldRecords(s.Key, event, p.user(s), s.Id, ids)
+}
+
+func (p participants) Refund(s saga.Saga) error {
+	event, err := p.co.lorenz.SelectEventByKey(s.Key)
+	if err != nil {
+		return err
+	}
+	_, err = p.co.lorenz.Refund(event, p.user(s), p.payment(s))
+	return err
+}
+
+func (p participants) Release(s saga.Saga) error {
+	score, err := p.co.score.Increment(s.Key, c.DefaultTime)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("size", fmt.Sprintf("%d", c.DefaultMaxSize))
+	query.Set("expiry", fmt.Sprintf("%d", c.DefaultExpiry))
+	query.Set("score", fmt.Sprintf("%d", score))
+
+	_, err = c.Delete(fmt.Sprintf("%s/http/v1/%s/rollback/%s?%s", p.co.host, s.Key, s.Id, query.Encode()), func(headers http.Header) {
+		headers.Set("Accept", "application/json")
+	})
+	return err
+}
+
+// user returns the user of the saga. A saga that's been recovered has no
+// credentials, so lorenz is called with the token of the service instead.
+func (p participants) user(s saga.Saga) bs.User {
+	if !s.Credentials.Empty() {
+		return s.Credentials.User
+	}
+	return bs.User{
+		Id: s.Owner,
+		Access: bs.UserAccess{
+			Token: p.co.serviceToken,
+		},
+	}
+}
+
+// payment returns the payment of the saga. A saga that's been recovered only
+// knows the transaction of the payment, which is all that a refund needs.
+func (p participants) payment(s saga.Saga) bs.Payment {
+	if !s.Credentials.Empty() {
+		return s.Credentials.Payment
+	}
+	return bs.Payment{
+		Key:            s.Id,
+		Txn:            s.Id,
+		IdempotencyKey: bs.IdempotencyKey(s.Id),
+	}
+}
+
+// selectHeldRecords queries echelon for the records of the owner that are held
+// by the transaction and haven't expired, along with the records that have
+// already been purchased by the transaction.
+func (co *Coordinator) selectHeldRecords(key, ownerId, txn bs.Key) ([]HeldRecord, error) {
+	query := url.Values{}
+	query.Set("size", fmt.Sprintf("%d", c.DefaultMaxSize))
+	query.Set("expiry", fmt.Sprintf("%d", c.DefaultExpiry))
+	query.Set("owner_id", ownerId.String())
+	query.Add("where", fmt.Sprintf("txn:eq:%s", txn.String()))
+
+	bytes, err := c.Get(fmt.Sprintf("%s/http/v1/%s/query?%s", co.host, key, query.Encode()), func(headers http.Header) {
+		headers.Set("Accept", "application/json")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response queryResponse
+	if err := json.Unmarshal(bytes, &response); err != nil {
+		return nil, typex.Errorf(errors.Source, errors.UnexpectedResults,
+			"Invalid query response").With(err)
+	}
+
+	var (
+		now  = time.Now()
+		held = make([]HeldRecord, 0, len(response.Records))
+	)
+	for _, v := range response.Records {
+		purchased := v.Record.State == records.Confirmed
+		if v.Record.State.Hold() && v.Record.Expiry.Before(now) {
+			continue
+		} else if v.Record.State == records.Refunded {
+			continue
+		}
+		held = append(held, HeldRecord{
+			Id:        v.Record.Id,
+			Purchased: purchased,
+		})
+	}
+	return held, nil
+}
+
+// purchaseHeldRecords replaces the held records with purchased records, each of
+// which is given a new set of codes.
+func (co *Coordinator) purchaseHeldRecords(key bs.Key,
+	event bs.Event,
+	user bs.User,
+	txn bs.Key,
+	ids []bson.ObjectId,
+) error {
+	var (
+		cost   = event.Tickets.Cost
+		values = make([]records.PutRecord, 0, len(ids))
+	)
+	for _, v := range ids {
+		codes, err := co.lorenz.SelectCodeForEvent(event, user)
+		if err != nil {
+			return err
+		}
+
+		values = append(values, records.PutRecord{
+			Id:            v,
+			OwnerId:       bson.ObjectIdHex(user.Id.String()),
+			TransactionId: bson.ObjectIdHex(txn.String()),
+			EventCost: records.Cost{
+				Currency: cost.Currency,
+				Price:    cost.Amount,
+			},
+			EventDates: records.Dates{
+				Start: uint64(event.Dates.Start.UnixNano()),
+				End:   uint64(event.Dates.End.UnixNano()),
+			},
+			Codes: records.Codes{
+				BarcodeType:   codes.Barcode.Type.String(),
+				BarcodeOrigin: codes.Barcode.Origin,
+				BarcodeSource: codes.Barcode.Source,
+				QRCode:        codes.QRCode.Source,
+			},
+		})
+	}
+
+	score, err := co.score.Increment(key, c.DefaultTime)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := records.PutRecords{
+		Records: values,
+		Score:   float64(score),
+		MaxSize: c.DefaultMaxSize,
+		Expiry:  c.DefaultExpiry,
+	}.Write(flatbuffers.NewBuilder(0))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Put(fmt.Sprintf("%s/http/v1/%s", co.host, key), bytes, func(headers http.Header) {
+		headers.Set("Accept", "application/octet-stream")
+		headers.Set("Content-Type", "application/octet-stream")
+	})
+	return err
+}
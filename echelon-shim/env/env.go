@@ -1,7 +1,10 @@
 package env
 
 import (
+	"time"
+
 	c "github.com/SimonRichardson/echelon/env"
+	"github.com/SimonRichardson/echelon/internal/services/lorenz"
 	"github.com/SimonRichardson/echelon/internal/typex"
 	"github.com/spf13/viper"
 )
@@ -32,6 +35,30 @@ type Env struct {
 	ShimRedisConnectTimeout string
 	ShimRedisReadTimeout    string
 	ShimRedisWriteTimeout   string
+
+	// Lorenz
+
+	LorenzInstances             string
+	LorenzVersion               string
+	LorenzTimeout               string
+	LorenzChargeStrategy        string
+	LorenzEventSelectStrategy   string
+	LorenzCodeSetSelectStrategy string
+	LorenzTactic                string
+	LorenzRequestsPerDuration   int
+	LorenzRequestsDuration      string
+
+	// Saga
+
+	SagaRedisInstances   string
+	SagaMaxSize          int
+	SagaRetention        time.Duration
+	SagaRetries          int
+	SagaBackoff          time.Duration
+	SagaMaxAttempts      int
+	SagaLease            time.Duration
+	SagaRecoveryInterval time.Duration
+	SagaServiceToken     string
 }
 
 // Type describes what stragegy options are available
@@ -72,6 +99,26 @@ func New(paths []string) *Env {
 	v.SetDefault("shim_redis_read_timeout", "30s")
 	v.SetDefault("shim_redis_write_timeout", "30s")
 
+	v.SetDefault("lorenz_instances", "http://lorenz:8080")
+	v.SetDefault("lorenz_version", "v1")
+	v.SetDefault("lorenz_timeout", "30s")
+	v.SetDefault("lorenz_charge_strategy", "Charger")
+	v.SetDefault("lorenz_event_select_strategy", "EventCacheSelector")
+	v.SetDefault("lorenz_code_set_select_strategy", "CodeSetSelector")
+	v.SetDefault("lorenz_tactic", "NonBlocking")
+	v.SetDefault("lorenz_requests_per_duration", 0)
+	v.SetDefault("lorenz_requests_duration", "1s")
+
+	v.SetDefault("saga_redis_instances", "tcp://notifier:6379")
+	v.SetDefault("saga_max_size", 100)
+	v.SetDefault("saga_retention", "24h")
+	v.SetDefault("saga_retries", 3)
+	v.SetDefault("saga_backoff", "100ms")
+	v.SetDefault("saga_max_attempts", 5)
+	v.SetDefault("saga_lease", "5m")
+	v.SetDefault("saga_recovery_interval", "1m")
+	v.SetDefault("saga_service_token", "")
+
 	e := &Env{
 		source: v,
 		C:      c.New(paths),
@@ -98,6 +145,26 @@ func (e *Env) read() {
 	e.ShimRedisConnectTimeout = e.source.GetString("shim_redis_connect_timeout")
 	e.ShimRedisReadTimeout = e.source.GetString("shim_redis_read_timeout")
 	e.ShimRedisWriteTimeout = e.source.GetString("shim_redis_write_timeout")
+
+	e.LorenzInstances = e.source.GetString("lorenz_instances")
+	e.LorenzVersion = e.source.GetString("lorenz_version")
+	e.LorenzTimeout = e.source.GetString("lorenz_timeout")
+	e.LorenzChargeStrategy = e.source.GetString("lorenz_charge_strategy")
+	e.LorenzEventSelectStrategy = e.source.GetString("lorenz_event_select_strategy")
+	e.LorenzCodeSetSelectStrategy = e.source.GetString("lorenz_code_set_select_strategy")
+	e.LorenzTactic = e.source.GetString("lorenz_tactic")
+	e.LorenzRequestsPerDuration = e.source.GetInt("lorenz_requests_per_duration")
+	e.LorenzRequestsDuration = e.source.GetString("lorenz_requests_duration")
+
+	e.SagaRedisInstances = e.source.GetString("saga_redis_instances")
+	e.SagaMaxSize = e.source.GetInt("saga_max_size")
+	e.SagaRetention = e.source.GetDuration("saga_retention")
+	e.SagaRetries = e.source.GetInt("saga_retries")
+	e.SagaBackoff = e.source.GetDuration("saga_backoff")
+	e.SagaMaxAttempts = e.source.GetInt("saga_max_attempts")
+	e.SagaLease = e.source.GetDuration("saga_lease")
+	e.SagaRecoveryInterval = e.source.GetDuration("saga_recovery_interval")
+	e.SagaServiceToken = e.source.GetString("saga_service_token")
 }
 
 // GetIncrementOptions returns all the increments options required to run a
@@ -116,3 +183,15 @@ func (e *Env) GetIncrementOptions(t Type) c.StrategyOptions {
 	}
 	return c.StrategyOptions{}
 }
+
+// GetLorenzOptions returns the options of a lorenz strategy, all the
+// strategies share the same tactic.
+func (e *Env) GetLorenzOptions(strategy string) lorenz.StrategyOptions {
+	return lorenz.StrategyOptions{
+		strategy,
+		e.LorenzTactic,
+		e.LorenzRequestsPerDuration,
+		e.LorenzRequestsDuration,
+		0,
+	}
+}
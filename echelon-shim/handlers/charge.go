@@ -1,8 +1,149 @@
 package handlers
 
-import "net/http"
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
 
-func Charge() http.HandlerFunc {
+	"github.com/SimonRichardson/echelon/echelon-shim/coordinator"
+	"github.com/SimonRichardson/echelon/echelon-shim/responses"
+	"github.com/SimonRichardson/echelon/echelon-shim/saga"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/models"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type chargeRequest struct {
+	OwnerId       string `json:"owner_id"`
+	TransactionId string `json:"transaction_id"`
+	Method        struct {
+		Type  string `json:"type"`
+		Token string `json:"token"`
+	} `json:"method"`
+	UserInfo struct {
+		FullName   string `json:"full_name"`
+		PostalCode string `json:"postal_code"`
+	} `json:"user_info"`
+}
+
+// Charge turns the records held by a transaction into a purchase. The event is
+// charged for every held record (including the fees), using the transaction
+// as the idempotency key of the charge, so that a retry is never charged twice.
+// Once charged, the held records are marked as purchased. The purchase is run
+// as a saga, so if the hold expires or the records can't be purchased, then
+// the charge is refunded and the held records are released.
+func Charge(co *coordinator.Coordinator) http.HandlerFunc {
 	return handle(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			began = time.Now()
+
+			queryKey = r.URL.Query().Get(":key")
+		)
+		if !bson.IsObjectIdHex(queryKey) {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Key: %s", queryKey))
+			return
+		}
+
+		var request chargeRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Body"))
+			return
+		}
+		if !bson.IsObjectIdHex(request.OwnerId) {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Owner Id: %s", request.OwnerId))
+			return
+		}
+		if !bson.IsObjectIdHex(request.TransactionId) {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Transaction Id: %s", request.TransactionId))
+			return
+		}
+		if len(request.Method.Type) < 1 {
+			responses.BadRequest(w, r, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid Payment Method"))
+			return
+		}
+
+		var (
+			key  = bs.Key(queryKey)
+			txn  = bs.Key(request.TransactionId)
+			user = bs.User{
+				Id: bs.Key(request.OwnerId),
+				Access: bs.UserAccess{
+					Token: strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+				},
+			}
+		)
+
+		held, err := co.SelectHeldRecords(key, user.Id, txn)
+		if err != nil {
+			responses.Error(w, r, err)
+			return
+		}
+		if len(held) < 1 {
+			responses.NotFound(w, r, typex.Errorf(errors.Source, errors.MissingContent,
+				"No held records for transaction: %s", txn))
+			return
+		}
+
+		event, err := co.SelectEventByKey(key)
+		if err != nil {
+			responses.Error(w, r, err)
+			return
+		}
+
+		var (
+			cost    = event.Tickets.Cost
+			count   = uint64(len(held))
+			payment = bs.Payment{
+				Key:   txn,
+				Txn:   txn,
+				Cost:  cost,
+				Count: len(held),
+				Method: bs.PaymentMethod{
+					Type:  bs.PaymentMethodType(request.Method.Type),
+					Token: bs.Key(request.Method.Token),
+				},
+				UserInfo: bs.PaymentUserInfo{
+					FullName:   request.UserInfo.FullName,
+					PostalCode: request.UserInfo.PostalCode,
+				},
+				IdempotencyKey: bs.IdempotencyKey(txn),
+			}
+			ids = make([]bs.Key, 0, len(held))
+		)
+		for _, v := range held {
+			ids = append(ids, bs.Key(v.Id.Hex()))
+		}
+
+		purchase, err := co.Purchase(saga.Saga{
+			Id:      txn,
+			Key:     key,
+			Owner:   user.Id,
+			Records: ids,
+			Credentials: saga.Credentials{
+				User:    user,
+				Payment: payment,
+			},
+		})
+		if err != nil {
+			responses.Error(w, r, err)
+			return
+		}
+
+		responses.OK(w, map[string]interface{}{
+			"transaction": purchase.Charge,
+			"count":       count,
+			"currency":    cost.Currency,
+			"fees":        models.CalculateTotalFee(cost, count),
+			"amount":      models.CalculateCost(cost, count),
+		}, time.Since(began))
+		return
 	})
 }
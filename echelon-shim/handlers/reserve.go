@@ -19,10 +19,6 @@ import (
 	"gopkg.in/mgo.v2/bson"
 )
 
-var (
-	defaultTime = time.Date(2016, 12, 1, 1, 1, 1, 1, time.UTC)
-)
-
 func Reserve(co *coordinator.Coordinator, host string) http.HandlerFunc {
 	return handle(func(w http.ResponseWriter, r *http.Request) {
 		var (
@@ -47,7 +43,7 @@ func Reserve(co *coordinator.Coordinator, host string) http.HandlerFunc {
 			return
 		}
 
-		score, err = co.Increment(bs.Key(queryKey), defaultTime)
+		score, err = co.Increment(bs.Key(queryKey), c.DefaultTime)
 		if err != nil {
 			responses.InternalServerError(w, r, typex.Errorf(errors.Source, errors.Fatal,
 				"Error: %s", err.Error()))
@@ -59,8 +55,8 @@ func Reserve(co *coordinator.Coordinator, host string) http.HandlerFunc {
 			record = records.PostRecords{
 				Records: values,
 				Score:   float64(score),
-				MaxSize: 99999, // TODO - find this out, do we care!?
-				Expiry:  time.Minute * 5,
+				MaxSize: c.DefaultMaxSize,
+				Expiry:  c.DefaultExpiry,
 			}
 			bytes, writeErr = record.Write(flatbuffers.NewBuilder(0))
 		)
@@ -73,6 +69,12 @@ func Reserve(co *coordinator.Coordinator, host string) http.HandlerFunc {
 		bytes, err = c.Post(fmt.Sprintf("%s/http/v1/%s", host, queryKey), bytes, func(headers http.Header) {
 			headers.Set("Accept", "application/octet-stream")
 			headers.Set("Content-Type", "application/octet-stream")
+
+			// The admission token of the client is passed on, so that the
+			// client keeps its place in the waiting room.
+			if token := r.Header.Get("Admission-Token"); token != "" {
+				headers.Set("Admission-Token", token)
+			}
 		})
 
 		if err != nil {
@@ -99,7 +101,7 @@ func makePostRecords(amount int) []records.PostRecord {
 			Id:       bson.NewObjectId(),
 			Updated:  now,
 			Reserved: now,
-			Expiry:   now.Add(time.Minute * 5),
+			Expiry:   now.Add(c.DefaultExpiry),
 			Cost: records.Cost{
 				Currency: "GBP",
 				Price:    uint64(0),
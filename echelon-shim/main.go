@@ -76,7 +76,7 @@ func newServer(e *env.Env) server {
 
 	router.Post("/events/{key}/tickets/reserve/{amount}", handlers.Reserve(co, host))
 	router.Post("/events/{key}/tickets/unreserve", handlers.Unreserve())
-	router.Post("/events/{key}/tickets/charge", handlers.Charge())
+	router.Post("/events/{key}/tickets/charge", handlers.Charge(co))
 
 	router.NotFoundHandler = http.HandlerFunc(handlers.NotFound())
 
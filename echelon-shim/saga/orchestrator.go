@@ -0,0 +1,296 @@
+package saga
+
+import (
+	"time"
+
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Options defines how the steps of a saga are retried and recovered.
+type Options struct {
+	// Retries is how many times a step is retried, before it's given up on.
+	Retries int
+
+	// Backoff is how long to wait before the first retry of a step, which is
+	// doubled for every retry.
+	Backoff time.Duration
+
+	// MaxAttempts is how many times a saga is run (including the recoveries)
+	// before it's compensated, or if it's compensating, before it's failed.
+	MaxAttempts int
+
+	// Lease is how long a saga is held by an instance, whilst it's being run.
+	Lease time.Duration
+}
+
+// Orchestrator runs the steps of the sagas, compensating for the steps that
+// have been made when a step fails. The state of every saga is stored before
+// and after every step, so that a saga can be recovered by any instance.
+type Orchestrator struct {
+	store        Store
+	participants Participants
+	opts         Options
+	owner        string
+}
+
+// New creates an Orchestrator for the participants, which stores the sagas in
+// the store.
+func New(store Store, participants Participants, opts Options) *Orchestrator {
+	return &Orchestrator{
+		store:        store,
+		participants: participants,
+		opts:         opts,
+		owner:        bson.NewObjectId().Hex(),
+	}
+}
+
+// Begin runs the saga until it's terminal. If the saga has already been
+// begun, then the stored saga is run instead, so a retry never charges twice.
+func (o *Orchestrator) Begin(s Saga) (Saga, error) {
+	ok, err := o.store.Lease(s.Id, o.owner, o.opts.Lease)
+	if err != nil {
+		return s, err
+	}
+	if !ok {
+		return s, typex.Errorf(errors.Source, errors.InProgress,
+			"Transaction %s is in progress", s.Id)
+	}
+	defer o.store.Unlease(s.Id, o.owner)
+
+	stored, ok, err := o.store.Load(s.Id)
+	if err != nil {
+		return s, err
+	}
+
+	// The stored saga has no credentials, so the ones it's begun with are used.
+	if ok {
+		credentials := s.Credentials
+		s = stored
+		s.Credentials = credentials
+	} else {
+		s.State = Started
+		s.Created = time.Now()
+		if err := o.save(&s); err != nil {
+			return s, err
+		}
+	}
+
+	return o.run(s)
+}
+
+// Recover runs every saga that's pending, which isn't leased by another
+// instance. The sagas that are unable to be run are left pending for the
+// next recovery.
+func (o *Orchestrator) Recover() (int, error) {
+	ids, err := o.store.Pending()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		recovered = 0
+		errs      = []error{}
+	)
+	for _, id := range ids {
+		if err := o.recoverSaga(id); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		recovered++
+	}
+
+	if len(errs) > 0 {
+		return recovered, typex.Errorf(errors.Source, errors.Partial,
+			"Partial failure: recovered %d of %d", recovered, len(ids)).With(errs...)
+	}
+	return recovered, nil
+}
+
+func (o *Orchestrator) recoverSaga(id bs.Key) error {
+	ok, err := o.store.Lease(id, o.owner, o.opts.Lease)
+	if err != nil || !ok {
+		return err
+	}
+	defer o.store.Unlease(id, o.owner)
+
+	s, ok, err := o.store.Load(id)
+	if err != nil || !ok {
+		return err
+	}
+
+	// Being compensated is the saga working as expected.
+	if s, err = o.run(s); err != nil && s.State != Compensated {
+		return err
+	}
+	return nil
+}
+
+func (o *Orchestrator) run(s Saga) (Saga, error) {
+	for {
+		var err error
+		switch s.State {
+		case Started, Charging:
+			err = o.charge(&s)
+		case Charged:
+			err = o.persist(&s)
+		case Compensating:
+			err = o.compensate(&s)
+		case Completed:
+			return s, nil
+		case Compensated:
+			return s, typex.Errorf(errors.Source, errors.Compensated,
+				"Transaction %s was compensated (%s)", s.Id, s.Reason)
+		case Failed:
+			return s, typex.Errorf(errors.Source, errors.Fatal,
+				"Transaction %s failed to compensate (%s)", s.Id, s.Reason)
+		default:
+			return s, typex.Errorf(errors.Source, errors.NoCaseFound,
+				"Invalid state %q for transaction %s", s.State, s.Id)
+		}
+
+		if err != nil {
+			return o.fail(s, err)
+		}
+	}
+}
+
+func (o *Orchestrator) charge(s *Saga) error {
+	if ok, err := o.holding(*s); err != nil {
+		return err
+	} else if !ok {
+		return o.compensating(s, "Hold expired before charging")
+	}
+
+	// A saga that's been recovered has nothing to charge with, so rather than
+	// charging it's compensated (refunding it, if it was possibly charged).
+	if s.Credentials.Empty() {
+		return o.compensating(s, "No credentials to charge with")
+	}
+
+	// Store that the charge is possibly made, before it's made.
+	s.State = Charging
+	s.Refundable = true
+	if err := o.save(s); err != nil {
+		return err
+	}
+
+	var charge bs.Key
+	if err := o.retry(func() (err error) {
+		charge, err = o.participants.Charge(*s)
+		return
+	}); err != nil {
+		return o.compensating(s, "Unable to charge: "+err.Error())
+	}
+
+	s.Charge = charge
+	s.State = Charged
+	return o.save(s)
+}
+
+func (o *Orchestrator) persist(s *Saga) error {
+	if ok, err := o.holding(*s); err != nil {
+		return err
+	} else if !ok {
+		return o.compensating(s, "Hold expired after charging")
+	}
+
+	if err := o.retry(func() error {
+		return o.participants.Persist(*s)
+	}); err != nil {
+		return o.compensating(s, "Unable to persist: "+err.Error())
+	}
+
+	s.State = Completed
+	return o.save(s)
+}
+
+func (o *Orchestrator) compensate(s *Saga) error {
+	if s.Refundable {
+		if err := o.retry(func() error {
+			return o.participants.Refund(*s)
+		}); err != nil {
+			return err
+		}
+
+		s.Refundable = false
+		if err := o.save(s); err != nil {
+			return err
+		}
+	}
+
+	if err := o.retry(func() error {
+		return o.participants.Release(*s)
+	}); err != nil {
+		return err
+	}
+
+	s.State = Compensated
+	return o.save(s)
+}
+
+// holding returns true if every record of the saga is still held (or has
+// been purchased) by the transaction.
+func (o *Orchestrator) holding(s Saga) (bool, error) {
+	var records []bs.Key
+	if err := o.retry(func() (err error) {
+		records, err = o.participants.Holding(s)
+		return
+	}); err != nil {
+		return false, err
+	}
+
+	held := make(map[bs.Key]struct{}, len(records))
+	for _, v := range records {
+		held[v] = struct{}{}
+	}
+	for _, v := range s.Records {
+		if _, ok := held[v]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (o *Orchestrator) compensating(s *Saga, reason string) error {
+	s.State = Compensating
+	s.Reason = reason
+	return o.save(s)
+}
+
+// fail records the failed attempt of the saga. Once the saga runs out of
+// attempts it's compensated, or if it's already compensating, failed.
+func (o *Orchestrator) fail(s Saga, err error) (Saga, error) {
+	s.Attempts++
+	if s.Attempts >= o.opts.MaxAttempts {
+		if s.State == Compensating {
+			s.State = Failed
+		} else {
+			s.State = Compensating
+			s.Reason = "Too many attempts: " + err.Error()
+		}
+	}
+
+	// If the saga can't be saved, then the previous state is recovered.
+	o.save(&s)
+	return s, err
+}
+
+func (o *Orchestrator) save(s *Saga) error {
+	s.Updated = time.Now()
+	return o.store.Save(*s)
+}
+
+func (o *Orchestrator) retry(fn func() error) (err error) {
+	backoff := o.opts.Backoff
+	for i := 0; ; i++ {
+		if err = fn(); err == nil || i >= o.opts.Retries {
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
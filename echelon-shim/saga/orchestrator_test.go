@@ -0,0 +1,346 @@
+package saga
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+)
+
+type participants struct {
+	holding []bs.Key
+
+	chargeErr, persistErr, refundErr, releaseErr error
+
+	charges, persists, refunds, releases int
+
+	refunded []Saga
+}
+
+func (p *participants) Holding(Saga) ([]bs.Key, error) {
+	return p.holding, nil
+}
+
+func (p *participants) Charge(Saga) (bs.Key, error) {
+	p.charges++
+	return bs.Key("charge"), p.chargeErr
+}
+
+func (p *participants) Persist(Saga) error {
+	p.persists++
+	return p.persistErr
+}
+
+func (p *participants) Refund(s Saga) error {
+	p.refunds++
+	p.refunded = append(p.refunded, s)
+	return p.refundErr
+}
+
+func (p *participants) Release(Saga) error {
+	p.releases++
+	return p.releaseErr
+}
+
+func newSaga() Saga {
+	return Saga{
+		Id:      bs.Key("txn"),
+		Key:     bs.Key("key"),
+		Owner:   bs.Key("owner"),
+		Records: []bs.Key{"a", "b"},
+		Credentials: Credentials{
+			User: bs.User{
+				Id:     bs.Key("owner"),
+				Access: bs.UserAccess{Token: "access"},
+			},
+			Payment: bs.Payment{
+				Key: bs.Key("txn"),
+				Method: bs.PaymentMethod{
+					Type:  bs.PaymentMethodType("card"),
+					Token: bs.Key("card"),
+				},
+			},
+		},
+	}
+}
+
+func newOrchestrator(store Store, p Participants) *Orchestrator {
+	return New(store, p, Options{
+		Retries:     1,
+		Backoff:     time.Millisecond,
+		MaxAttempts: 2,
+		Lease:       time.Minute,
+	})
+}
+
+func TestBeginCompletes(t *testing.T) {
+	var (
+		p = &participants{holding: []bs.Key{"a", "b"}}
+		o = newOrchestrator(NewMemoryStore(), p)
+	)
+
+	s, err := o.Begin(newSaga())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, actual := Completed, s.State; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := bs.Key("charge"), s.Charge; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 0, p.refunds+p.releases; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestBeginTwiceChargesOnce(t *testing.T) {
+	var (
+		p = &participants{holding: []bs.Key{"a", "b"}}
+		o = newOrchestrator(NewMemoryStore(), p)
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := o.Begin(newSaga()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expected, actual := 1, p.charges; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestBeginWithExpiredHold(t *testing.T) {
+	var (
+		p = &participants{holding: []bs.Key{"a"}}
+		o = newOrchestrator(NewMemoryStore(), p)
+	)
+
+	s, err := o.Begin(newSaga())
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+
+	if expected, actual := Compensated, s.State; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 0, p.charges+p.refunds; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 1, p.releases; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestBeginRefundsFailedPersist(t *testing.T) {
+	var (
+		p = &participants{
+			holding:    []bs.Key{"a", "b"},
+			persistErr: fmt.Errorf("bad"),
+		}
+		o = newOrchestrator(NewMemoryStore(), p)
+	)
+
+	s, err := o.Begin(newSaga())
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+
+	if expected, actual := Compensated, s.State; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 2, p.persists; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 1, p.refunds; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := false, s.Refundable; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestRecoverCharging(t *testing.T) {
+	var (
+		p     = &participants{holding: []bs.Key{"a", "b"}}
+		store = NewMemoryStore()
+		o     = newOrchestrator(store, p)
+
+		s = newSaga()
+	)
+
+	// A saga that stopped whilst it was being charged.
+	s.State = Charging
+	s.Refundable = true
+	store.Save(s)
+
+	recovered, err := o.Recover()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 1, recovered; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	// Without the credentials it's never charged again, instead the possible
+	// charge is refunded and the records are released.
+	stored, _, _ := store.Load(s.Id)
+	if expected, actual := Compensated, stored.State; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 0, p.charges; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 1, p.refunds; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 1, p.releases; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	pending, _ := store.Pending()
+	if expected, actual := 0, len(pending); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestRecoverChargingThenRefund(t *testing.T) {
+	var (
+		p = &participants{
+			holding:   []bs.Key{"a", "b"},
+			chargeErr: fmt.Errorf("bad"),
+		}
+		store = NewMemoryStore()
+		o     = newOrchestrator(store, p)
+	)
+
+	// The charge fails and so does the refund, leaving the saga to be
+	// recovered whilst compensating.
+	p.refundErr = fmt.Errorf("bad")
+	s, err := o.Begin(newSaga())
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	if expected, actual := Compensating, s.State; expected != actual {
+		t.Fatalf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	p.refundErr, p.refunded = nil, nil
+	if _, err := o.Recover(); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _, _ := store.Load(s.Id)
+	if expected, actual := Compensated, stored.State; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 1, len(p.refunded); expected != actual {
+		t.Fatalf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	// The refund is made for the transaction, without the credentials.
+	refunded := p.refunded[0]
+	if expected, actual := bs.Key("txn"), refunded.Id; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := true, refunded.Credentials.Empty(); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestRecoverStartedReleases(t *testing.T) {
+	var (
+		p     = &participants{holding: []bs.Key{"a", "b"}}
+		store = NewMemoryStore()
+		o     = newOrchestrator(store, p)
+
+		s = newSaga()
+	)
+
+	s.State = Started
+	store.Save(s)
+
+	if _, err := o.Recover(); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _, _ := store.Load(s.Id)
+	if expected, actual := Compensated, stored.State; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 0, p.charges+p.refunds; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := 1, p.releases; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestSaveDropsCredentials(t *testing.T) {
+	store := NewMemoryStore()
+	store.Save(newSaga())
+
+	stored, _, _ := store.Load(bs.Key("txn"))
+	if expected, actual := true, stored.Credentials.Empty(); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := "", stored.Credentials.User.Access.Token; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestRecoverSkipsLeased(t *testing.T) {
+	var (
+		p     = &participants{holding: []bs.Key{"a", "b"}}
+		store = NewMemoryStore()
+		o     = newOrchestrator(store, p)
+
+		s = newSaga()
+	)
+
+	s.State = Started
+	store.Save(s)
+	store.Lease(s.Id, "other", time.Minute)
+
+	if _, err := o.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 0, p.charges; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestFailedCompensation(t *testing.T) {
+	var (
+		p = &participants{
+			holding:    []bs.Key{"a", "b"},
+			persistErr: fmt.Errorf("bad"),
+			refundErr:  fmt.Errorf("bad"),
+		}
+		store = NewMemoryStore()
+		o     = newOrchestrator(store, p)
+	)
+
+	s, err := o.Begin(newSaga())
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	if expected, actual := Compensating, s.State; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+
+	if _, err := o.Recover(); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	stored, _, _ := store.Load(s.Id)
+	if expected, actual := Failed, stored.State; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := true, stored.Refundable; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
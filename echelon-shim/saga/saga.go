@@ -0,0 +1,111 @@
+package saga
+
+import (
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+)
+
+// State defines the step that a saga has reached.
+type State string
+
+const (
+	// Started is a saga that holds the records, but hasn't been charged.
+	Started State = "started"
+
+	// Charging is a saga that's (possibly) being charged. It's stored before
+	// the charge is made, so that a saga recovered in this state is known to
+	// possibly have been charged.
+	Charging State = "charging"
+
+	// Charged is a saga that's been charged, but the records have yet to be
+	// purchased.
+	Charged State = "charged"
+
+	// Completed is a saga where the records have been purchased.
+	Completed State = "completed"
+
+	// Compensating is a saga that's undoing the steps it's made, by refunding
+	// the charge and releasing the held records.
+	Compensating State = "compensating"
+
+	// Compensated is a saga that's been undone.
+	Compensated State = "compensated"
+
+	// Failed is a saga that was unable to be undone, with in the number of
+	// attempts, so it requires someone to look at it.
+	Failed State = "failed"
+)
+
+func (s State) String() string {
+	return string(s)
+}
+
+// Terminal returns true if the saga has no more steps to run.
+func (s State) Terminal() bool {
+	switch s {
+	case Completed, Compensated, Failed:
+		return true
+	}
+	return false
+}
+
+// Saga defines the durable state of a purchase, which spans the held records,
+// the charge from lorenz and the persisting of the purchased records. The
+// saga is keyed by the transaction of the held records.
+type Saga struct {
+	Id      bs.Key   `json:"id"`
+	Key     bs.Key   `json:"key"`
+	Owner   bs.Key   `json:"owner"`
+	Records []bs.Key `json:"records"`
+	Charge  bs.Key   `json:"charge,omitempty"`
+	State   State    `json:"state"`
+
+	// Refundable is true when a charge has (possibly) been made and not yet
+	// refunded.
+	Refundable bool   `json:"refundable"`
+	Reason     string `json:"reason,omitempty"`
+	Attempts   int    `json:"attempts"`
+
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+
+	// Credentials are what the saga was begun with, they're never stored so a
+	// saga that's been recovered has none.
+	Credentials Credentials `json:"-"`
+}
+
+// Credentials hold the user (with their access token) and the payment (with
+// the token of the payment method) that a saga is charged with. They're only
+// ever held in memory, so that the tokens can't be read or replayed from the
+// store.
+type Credentials struct {
+	User    bs.User
+	Payment bs.Payment
+}
+
+// Empty returns true if there are no credentials, which is the case for a saga
+// that's been recovered.
+func (c Credentials) Empty() bool {
+	return c.Payment.Method.Type == ""
+}
+
+// Participants defines the services that take part in the saga. Every step is
+// expected to be idempotent, so that it can be retried and recovered.
+type Participants interface {
+	// Holding returns the records of the saga that are still held by, or have
+	// already been purchased for, the transaction.
+	Holding(Saga) ([]bs.Key, error)
+
+	// Charge charges the payment of the saga, returning the charge.
+	Charge(Saga) (bs.Key, error)
+
+	// Persist purchases the held records of the saga.
+	Persist(Saga) error
+
+	// Refund refunds the charge of the saga.
+	Refund(Saga) error
+
+	// Release rolls back the records of the transaction.
+	Release(Saga) error
+}
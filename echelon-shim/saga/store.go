@@ -0,0 +1,103 @@
+package saga
+
+import (
+	"sync"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+)
+
+// Store defines where the state of the sagas is stored, so that a saga can be
+// recovered after a restart.
+type Store interface {
+	// Save stores the saga, a saga that's not terminal is pending until it's
+	// saved as terminal. The credentials of the saga are never stored.
+	Save(Saga) error
+
+	// Load returns the saga, if it's been stored.
+	Load(bs.Key) (Saga, bool, error)
+
+	// Pending returns the ids of every saga that's not terminal.
+	Pending() ([]bs.Key, error)
+
+	// Lease leases the saga to the owner for the duration, it returns false if
+	// another owner holds the lease.
+	Lease(bs.Key, string, time.Duration) (bool, error)
+
+	// Unlease releases the lease of the owner.
+	Unlease(bs.Key, string) error
+}
+
+type lease struct {
+	owner   string
+	expires time.Time
+}
+
+type memory struct {
+	mutex  sync.Mutex
+	sagas  map[bs.Key]Saga
+	leases map[bs.Key]lease
+}
+
+// NewMemoryStore creates a Store that's held in memory, so the sagas aren't
+// recovered after a restart.
+func NewMemoryStore() Store {
+	return &memory{
+		sagas:  make(map[bs.Key]Saga),
+		leases: make(map[bs.Key]lease),
+	}
+}
+
+func (m *memory) Save(s Saga) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// The same as any other store, the credentials are never kept.
+	s.Credentials = Credentials{}
+	m.sagas[s.Id] = s
+	return nil
+}
+
+func (m *memory) Load(id bs.Key) (Saga, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	s, ok := m.sagas[id]
+	return s, ok, nil
+}
+
+func (m *memory) Pending() ([]bs.Key, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	res := make([]bs.Key, 0, len(m.sagas))
+	for k, v := range m.sagas {
+		if !v.State.Terminal() {
+			res = append(res, k)
+		}
+	}
+	return res, nil
+}
+
+func (m *memory) Lease(id bs.Key, owner string, duration time.Duration) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	if v, ok := m.leases[id]; ok && v.owner != owner && v.expires.After(now) {
+		return false, nil
+	}
+
+	m.leases[id] = lease{owner, now.Add(duration)}
+	return true, nil
+}
+
+func (m *memory) Unlease(id bs.Key, owner string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if v, ok := m.leases[id]; ok && v.owner == owner {
+		delete(m.leases, id)
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package agents
 
-import "github.com/SimonRichardson/echelon/coordinator"
+import (
+	"github.com/SimonRichardson/echelon/coordinator"
+	"github.com/SimonRichardson/echelon/env"
+)
 
 // Agent defines a interface for creating agents that are *currently*
 // unsupervised.
@@ -10,5 +13,6 @@ type Agent interface {
 
 type AgentOptions struct {
 	Coordinator *coordinator.Coordinator
+	Env         *env.Env
 	HttpAddress string
 }
@@ -0,0 +1,114 @@
+package agents
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SimonRichardson/echelon/farm/store"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	"github.com/SimonRichardson/echelon/internal/permitters"
+	"github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+const (
+	defaultSyncNamespace = selectors.Namespace("echelon_sync")
+)
+
+// Sync defines an agent that performs anti-entropy over all the keys with in
+// the store, rebuilding the secondary indexes of the key as it goes (unless the
+// store isn't indexed). Only the keys allowed by the rate budget are synced each
+// time, the next time carries on from where the last one stopped.
+type Sync struct{}
+
+func (a Sync) Init(opts AgentOptions) error {
+	var (
+		co = opts.Coordinator
+		e  = opts.Env
+	)
+
+	duration, err := time.ParseDuration(e.StoreSyncDuration)
+	if err != nil {
+		return err
+	}
+
+	var (
+		permitter = permitters.New(int64(e.StoreSyncPerDuration), duration)
+		timer     = time.NewTicker(duration)
+		cursor    = 0
+	)
+
+	go func() {
+		for range timer.C {
+			keys, err := co.Keys(context.Background())
+			if err != nil {
+				teleprinter.L.Error().Printf("Error processing sync with : %s\n", err)
+				continue
+			}
+			if len(keys) < 1 {
+				continue
+			}
+
+			// Keep the keys in a stable order, so the cursor can be used to
+			// carry on from where the last sync ran out of budget.
+			sort.Sort(keySlice(keys))
+
+			processed := 0
+			for ; processed < len(keys); processed++ {
+				if !permitter.Allowed(1) {
+					break
+				}
+
+				var (
+					key         = keys[(cursor+processed)%len(keys)]
+					unlock, _, err = co.Lock(key.Namespace().Prefix(defaultSyncNamespace))
+				)
+				if err != nil {
+					teleprinter.L.Info().Printf("Unable to process sync, as event is locked : %s\n", err)
+					continue
+				}
+
+				repaired, err := co.Sync(context.Background(), key, e.StoreSyncBuckets, s.KeySizeExpiry{
+					key: s.SizeExpiry{
+						Size:   defaultMaxSize,
+						Expiry: defaultExpiry,
+					},
+				})
+				if err != nil {
+					unlock()
+					teleprinter.L.Error().Printf("Error syncing %s with : %s\n", key, err)
+					continue
+				}
+				if repaired > 0 {
+					teleprinter.L.Info().Printf("Synced %s, repaired %d members\n", key, repaired)
+				}
+
+				// Rebuild any secondary indexes that are missing, which can
+				// happen for members that were written before the indexes.
+				reindexed, err := co.Reindex(context.Background(), key)
+				unlock()
+
+				if err == store.ErrNotIndexed {
+					continue
+				} else if err != nil {
+					teleprinter.L.Error().Printf("Error reindexing %s with : %s\n", key, err)
+					continue
+				}
+				if reindexed > 0 {
+					teleprinter.L.Info().Printf("Reindexed %s, rebuilt %d members\n", key, reindexed)
+				}
+			}
+
+			cursor = (cursor + processed) % len(keys)
+		}
+	}()
+
+	return nil
+}
+
+type keySlice []selectors.Key
+
+func (k keySlice) Len() int           { return len(k) }
+func (k keySlice) Less(i, j int) bool { return k[i] < k[j] }
+func (k keySlice) Swap(i, j int)      { k[i], k[j] = k[j], k[i] }
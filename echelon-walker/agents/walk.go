@@ -1,6 +1,7 @@
 package agents
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -33,7 +34,7 @@ func (a Walk) Init(opts AgentOptions) error {
 
 			select {
 			case <-timer.C:
-				keys, err := co.Keys()
+				keys, err := co.Keys(context.Background())
 				if err != nil {
 					continue loop
 				}
@@ -45,7 +46,7 @@ func (a Walk) Init(opts AgentOptions) error {
 
 					var (
 						ns          = key.Namespace()
-						unlock, err = co.Lock(ns.Prefix(defaultWalkerNamespace))
+						unlock, _, err = co.Lock(ns.Prefix(defaultWalkerNamespace))
 					)
 					if err != nil {
 						teleprinter.L.Info().Printf("Unable to process repair walker, as event is locked : %s\n", err)
@@ -60,6 +60,7 @@ type server struct {
 	HttpAddress string
 	Handler     http.Handler
 	co          *coordinator.Coordinator
+	env         *env.Env
 	agents      []agents.Agent
 }
 
@@ -67,6 +68,7 @@ func (s server) Daemon() error {
 	// TODO: We should supervise the agents!
 	opts := agents.AgentOptions{
 		Coordinator: s.co,
+		Env:         s.env,
 		HttpAddress: s.HttpAddress,
 	}
 
@@ -116,8 +118,10 @@ func newServer(e *env.Env) server {
 		e.HttpAddress,
 		http.Handler(router),
 		co,
+		e,
 		[]agents.Agent{
 			agents.Walk{},
+			agents.Sync{},
 		},
 	}
 }
@@ -21,6 +21,26 @@ type Env struct {
 	HttpReadTimeout  time.Duration
 	HttpWriteTimeout time.Duration
 
+	HttpChangesInterval time.Duration
+	HttpChangesLimit    int
+
+	HttpIdempotencyInstances string
+	HttpIdempotencyExpiry    time.Duration
+
+	HttpAdmissionSecret           string
+	HttpAdmissionInstances        string
+	HttpAdmissionPerDuration      int
+	HttpAdmissionDuration         time.Duration
+	HttpAdmissionExpiry           time.Duration
+	HttpAdmissionUses             int
+	HttpAdmissionIssuePerDuration int
+	HttpAdmissionIssueDuration    time.Duration
+	HttpAdmissionClientHeader     string
+
+	GrpcAddress         string
+	GrpcChangesInterval time.Duration
+	GrpcChangesLimit    int
+
 	Version string
 
 	Instrumentation string
@@ -44,6 +64,9 @@ type Env struct {
 	RepairStrategyPerDuration int
 	RepairStrategyDuration    string
 
+	ClockStrategy string
+	ClockMaxSkew  string
+
 	// Redis
 
 	RedisCreator redis.RedisCreator
@@ -98,6 +121,7 @@ type Env struct {
 
 	StoreMaxSize             int
 	StorePoolRoutingStrategy string
+	StoreSetStrategy         string
 	StoreKeyStorePrefix      string
 	StoreKeyStoreDelay       time.Duration
 
@@ -107,6 +131,9 @@ type Env struct {
 	StoreSelectDuration    string
 	StoreSelectQuorum      float64
 
+	StoreSelectRepairPerDuration int
+	StoreSelectRepairDuration    string
+
 	StoreInsertStrategy    string
 	StoreInsertTactic      string
 	StoreInsertPerDuration int
@@ -131,6 +158,10 @@ type Env struct {
 	StoreRepairDuration    string
 	StoreRepairQuorum      float64
 
+	StoreSyncBuckets     int
+	StoreSyncPerDuration int
+	StoreSyncDuration    string
+
 	// Notifier
 
 	NotifierInstances      string
@@ -147,6 +178,26 @@ type Env struct {
 	NotifierNotifyDuration    string
 	NotifierNotifyQuorum      float64
 
+	NotifierChangesSize int
+
+	NotifierStreamGroup    string
+	NotifierStreamConsumer string
+	NotifierStreamOffset   string
+	NotifierStreamMaxSize  int
+
+	// Waitlist
+	// The waiting lists are held on the same redis as the notifier by
+	// default, using the timeouts of the notifier.
+	WaitlistInstances  string
+	WaitlistMaxSize    int
+	WaitlistHoldExpiry time.Duration
+
+	// Intent
+	// The intents of the batches are held on the same redis as the notifier by
+	// default, using the timeouts of the notifier.
+	IntentInstances string
+	IntentMaxSize   int
+
 	// Persistence
 
 	PersistenceDbName              string
@@ -179,6 +230,17 @@ type Env struct {
 	ManagerRepairPerDuration int
 	ManagerRepairDuration    string
 
+	ManagerSweepShards int
+	ManagerSweepLease  string
+
+	// Breaker
+
+	BreakerErrorRate   float64
+	BreakerLatency     string
+	BreakerMinRequests int
+	BreakerWindow      string
+	BreakerCooldown    string
+
 	// Consul
 
 	ConsulInstances string
@@ -229,6 +291,22 @@ type StrategyOptions struct {
 	Quorum              float64
 }
 
+// ClockOptions defines what options are available when stamping or validating
+// the scores of the writes.
+type ClockOptions struct {
+	Strategy string
+	MaxSkew  string
+}
+
+// StreamOptions defines what options are available when using a stream backed
+// strategy.
+type StreamOptions struct {
+	Group    string
+	Consumer string
+	Offset   string
+	MaxSize  int
+}
+
 // New returns a new Env object which contains all the environmental variables
 // in the object.
 func New(paths []string) *Env {
@@ -250,6 +328,26 @@ func New(paths []string) *Env {
 	v.SetDefault("http_read_timeout", "10s")
 	v.SetDefault("http_write_timeout", "30s")
 
+	v.SetDefault("http_changes_interval", "1s")
+	v.SetDefault("http_changes_limit", 100)
+
+	v.SetDefault("http_idempotency_instances", "")
+	v.SetDefault("http_idempotency_expiry", "24h")
+
+	v.SetDefault("http_admission_secret", "")
+	v.SetDefault("http_admission_instances", "tcp://notifier:6379")
+	v.SetDefault("http_admission_per_duration", 100)
+	v.SetDefault("http_admission_duration", "1s")
+	v.SetDefault("http_admission_expiry", "1h")
+	v.SetDefault("http_admission_uses", 1)
+	v.SetDefault("http_admission_issue_per_duration", 10)
+	v.SetDefault("http_admission_issue_duration", "1m")
+	v.SetDefault("http_admission_client_header", "")
+
+	v.SetDefault("grpc_address", ":9003")
+	v.SetDefault("grpc_changes_interval", "1s")
+	v.SetDefault("grpc_changes_limit", 100)
+
 	v.SetDefault("version", "0.0.1")
 
 	v.SetDefault("instrumentation", "PlainText")
@@ -270,6 +368,9 @@ func New(paths []string) *Env {
 	v.SetDefault("repair_strategy_per_duration", 100)
 	v.SetDefault("repair_strategy_duration", "1m")
 
+	v.SetDefault("clock_strategy", "Noop")
+	v.SetDefault("clock_max_skew", "500ms")
+
 	v.SetDefault("mongo_instances", "mongo:27017")
 	v.SetDefault("mongo_connect_timeout", "1m")
 
@@ -312,6 +413,7 @@ func New(paths []string) *Env {
 
 	v.SetDefault("store_max_size", 1000)
 	v.SetDefault("store_pool_routing_strategy", "Hash")
+	v.SetDefault("store_set_strategy", "LWWElementSet")
 	v.SetDefault("store_key_store_prefix", "echelon_store_kv")
 	v.SetDefault("store_key_store_delay", time.Minute)
 
@@ -320,6 +422,8 @@ func New(paths []string) *Env {
 	v.SetDefault("store_select_per_duration", 0)
 	v.SetDefault("store_select_duration", 0)
 	v.SetDefault("store_select_quorum", 0.51)
+	v.SetDefault("store_select_repair_per_duration", 100)
+	v.SetDefault("store_select_repair_duration", "1s")
 
 	v.SetDefault("store_insert_strategy", "InsertAllReadAll")
 	v.SetDefault("store_insert_tactic", "NonBlocking")
@@ -345,6 +449,10 @@ func New(paths []string) *Env {
 	v.SetDefault("store_repair_duration", "1m")
 	v.SetDefault("store_repair_quorum", 0.51)
 
+	v.SetDefault("store_sync_buckets", 256)
+	v.SetDefault("store_sync_per_duration", 100)
+	v.SetDefault("store_sync_duration", "1m")
+
 	v.SetDefault("notifier_instances", "tcp://notifier:6379")
 	v.SetDefault("notifier_connect_timeout", "1m")
 	v.SetDefault("notifier_read_timeout", "30s")
@@ -359,6 +467,20 @@ func New(paths []string) *Env {
 	v.SetDefault("notifier_notify_duration", 0)
 	v.SetDefault("notifier_notify_quorum", 0.51)
 
+	v.SetDefault("notifier_changes_size", 10000)
+
+	v.SetDefault("notifier_stream_group", "echelon")
+	v.SetDefault("notifier_stream_consumer", "")
+	v.SetDefault("notifier_stream_offset", "$")
+	v.SetDefault("notifier_stream_max_size", 100000)
+
+	v.SetDefault("waitlist_instances", "tcp://notifier:6379")
+	v.SetDefault("waitlist_max_size", 100)
+	v.SetDefault("waitlist_hold_expiry", "5m")
+
+	v.SetDefault("intent_instances", "tcp://notifier:6379")
+	v.SetDefault("intent_max_size", 100)
+
 	v.SetDefault("persistence_db_name", "db")
 	v.SetDefault("persistence_key_prefix", "tickets_")
 	v.SetDefault("persistence_max_size", 100)
@@ -387,6 +509,15 @@ func New(paths []string) *Env {
 	v.SetDefault("manager_repair_per_duration", 1)
 	v.SetDefault("manager_repair_duration", "1m")
 
+	v.SetDefault("manager_sweep_shards", 16)
+	v.SetDefault("manager_sweep_lease", "15m")
+
+	v.SetDefault("breaker_error_rate", 0.5)
+	v.SetDefault("breaker_latency", "2s")
+	v.SetDefault("breaker_min_requests", 20)
+	v.SetDefault("breaker_window", "10s")
+	v.SetDefault("breaker_cooldown", "5s")
+
 	v.SetDefault("consul_instances", "consul:8500")
 
 	v.SetDefault("consul_max_size", 1)
@@ -423,6 +554,26 @@ func (e *Env) read() {
 	e.HttpReadTimeout = e.source.GetDuration("http_read_timeout")
 	e.HttpWriteTimeout = e.source.GetDuration("http_write_timeout")
 
+	e.HttpChangesInterval = e.source.GetDuration("http_changes_interval")
+	e.HttpChangesLimit = e.source.GetInt("http_changes_limit")
+
+	e.HttpIdempotencyInstances = e.source.GetString("http_idempotency_instances")
+	e.HttpIdempotencyExpiry = e.source.GetDuration("http_idempotency_expiry")
+
+	e.HttpAdmissionSecret = e.source.GetString("http_admission_secret")
+	e.HttpAdmissionInstances = e.source.GetString("http_admission_instances")
+	e.HttpAdmissionPerDuration = e.source.GetInt("http_admission_per_duration")
+	e.HttpAdmissionDuration = e.source.GetDuration("http_admission_duration")
+	e.HttpAdmissionExpiry = e.source.GetDuration("http_admission_expiry")
+	e.HttpAdmissionUses = e.source.GetInt("http_admission_uses")
+	e.HttpAdmissionIssuePerDuration = e.source.GetInt("http_admission_issue_per_duration")
+	e.HttpAdmissionIssueDuration = e.source.GetDuration("http_admission_issue_duration")
+	e.HttpAdmissionClientHeader = e.source.GetString("http_admission_client_header")
+
+	e.GrpcAddress = e.source.GetString("grpc_address")
+	e.GrpcChangesInterval = e.source.GetDuration("grpc_changes_interval")
+	e.GrpcChangesLimit = e.source.GetInt("grpc_changes_limit")
+
 	e.Version = e.source.GetString("version")
 
 	e.Instrumentation = e.source.GetString("instrumentation")
@@ -443,6 +594,9 @@ func (e *Env) read() {
 	e.RepairStrategyPerDuration = e.source.GetInt("repair_strategy_per_duration")
 	e.RepairStrategyDuration = e.source.GetString("repair_strategy_duration")
 
+	e.ClockStrategy = e.source.GetString("clock_strategy")
+	e.ClockMaxSkew = e.source.GetString("clock_max_skew")
+
 	e.MongoInstances = e.source.GetString("mongo_instances")
 	e.MongoConnectTimeout = e.source.GetString("mongo_connect_timeout")
 
@@ -485,6 +639,7 @@ func (e *Env) read() {
 
 	e.StoreMaxSize = e.source.GetInt("store_max_size")
 	e.StorePoolRoutingStrategy = e.source.GetString("store_pool_routing_strategy")
+	e.StoreSetStrategy = e.source.GetString("store_set_strategy")
 	e.StoreKeyStorePrefix = e.source.GetString("store_key_store_prefix")
 	e.StoreKeyStoreDelay = e.source.GetDuration("store_key_store_delay")
 
@@ -493,6 +648,8 @@ func (e *Env) read() {
 	e.StoreSelectPerDuration = e.source.GetInt("store_select_per_duration")
 	e.StoreSelectDuration = e.source.GetString("store_select_duration")
 	e.StoreSelectQuorum = e.source.GetFloat64("store_select_quorum")
+	e.StoreSelectRepairPerDuration = e.source.GetInt("store_select_repair_per_duration")
+	e.StoreSelectRepairDuration = e.source.GetString("store_select_repair_duration")
 
 	e.StoreInsertStrategy = e.source.GetString("store_insert_strategy")
 	e.StoreInsertTactic = e.source.GetString("store_insert_tactic")
@@ -518,6 +675,10 @@ func (e *Env) read() {
 	e.StoreRepairDuration = e.source.GetString("store_repair_duration")
 	e.StoreRepairQuorum = e.source.GetFloat64("store_repair_quorum")
 
+	e.StoreSyncBuckets = e.source.GetInt("store_sync_buckets")
+	e.StoreSyncPerDuration = e.source.GetInt("store_sync_per_duration")
+	e.StoreSyncDuration = e.source.GetString("store_sync_duration")
+
 	e.NotifierInstances = e.source.GetString("notifier_instances")
 	e.NotifierConnectTimeout = e.source.GetString("notifier_connect_timeout")
 	e.NotifierReadTimeout = e.source.GetString("notifier_read_timeout")
@@ -532,6 +693,20 @@ func (e *Env) read() {
 	e.NotifierNotifyDuration = e.source.GetString("notifier_notify_duration")
 	e.NotifierNotifyQuorum = e.source.GetFloat64("notifier_notify_quorum")
 
+	e.NotifierChangesSize = e.source.GetInt("notifier_changes_size")
+
+	e.NotifierStreamGroup = e.source.GetString("notifier_stream_group")
+	e.NotifierStreamConsumer = e.source.GetString("notifier_stream_consumer")
+	e.NotifierStreamOffset = e.source.GetString("notifier_stream_offset")
+	e.NotifierStreamMaxSize = e.source.GetInt("notifier_stream_max_size")
+
+	e.WaitlistInstances = e.source.GetString("waitlist_instances")
+	e.WaitlistMaxSize = e.source.GetInt("waitlist_max_size")
+	e.WaitlistHoldExpiry = e.source.GetDuration("waitlist_hold_expiry")
+
+	e.IntentInstances = e.source.GetString("intent_instances")
+	e.IntentMaxSize = e.source.GetInt("intent_max_size")
+
 	e.PersistenceDbName = e.source.GetString("persistence_db_name")
 	e.PersistenceKeyPrefix = e.source.GetString("persistence_key_prefix")
 	e.PersistenceMaxSize = e.source.GetInt("persistence_max_size")
@@ -560,6 +735,15 @@ func (e *Env) read() {
 	e.ManagerRepairPerDuration = e.source.GetInt("manager_repair_per_duration")
 	e.ManagerRepairDuration = e.source.GetString("manager_repair_duration")
 
+	e.ManagerSweepShards = e.source.GetInt("manager_sweep_shards")
+	e.ManagerSweepLease = e.source.GetString("manager_sweep_lease")
+
+	e.BreakerErrorRate = e.source.GetFloat64("breaker_error_rate")
+	e.BreakerLatency = e.source.GetString("breaker_latency")
+	e.BreakerMinRequests = e.source.GetInt("breaker_min_requests")
+	e.BreakerWindow = e.source.GetString("breaker_window")
+	e.BreakerCooldown = e.source.GetString("breaker_cooldown")
+
 	e.ConsulInstances = e.source.GetString("consul_instances")
 
 	e.ConsulMaxSize = e.source.GetInt("consul_max_size")
@@ -615,6 +799,20 @@ func (e *Env) GetSelectOptions(t Type) StrategyOptions {
 	return StrategyOptions{}
 }
 
+// GetSelectRepairOptions returns the options required to rate limit the
+// repairs that a selection finds. It takes a Type argument to switch over the
+// storage strategy.
+func (e *Env) GetSelectRepairOptions(t Type) StrategyOptions {
+	switch t {
+	case Store:
+		return StrategyOptions{
+			RequestsPerDuration: e.StoreSelectRepairPerDuration,
+			RequestsDuration:    e.StoreSelectRepairDuration,
+		}
+	}
+	return StrategyOptions{}
+}
+
 // GetInsertOptions returns all the insertion options required to run a
 // insertion in the application. It takes a Type argument to switch over the
 // storage strategy.
@@ -756,6 +954,20 @@ func (e *Env) GetRepairOptions(t Type) StrategyOptions {
 	return StrategyOptions{}
 }
 
+// GetClockOptions returns all the clock options required to stamp or validate
+// scores in the application. It takes a Type argument to switch over the
+// storage strategy.
+func (e *Env) GetClockOptions(t Type) ClockOptions {
+	switch t {
+	case Coordinator:
+		return ClockOptions{
+			e.ClockStrategy,
+			e.ClockMaxSkew,
+		}
+	}
+	return ClockOptions{}
+}
+
 // GetNotifyOptions returns all the notifying options required to run a
 // notification in the application. It takes a Type argument to switch over the
 // storage strategy.
@@ -773,6 +985,22 @@ func (e *Env) GetNotifyOptions(t Type) StrategyOptions {
 	return StrategyOptions{}
 }
 
+// GetStreamOptions returns all the stream options required to run a stream
+// backed strategy in the application. It takes a Type argument to switch over
+// the storage strategy.
+func (e *Env) GetStreamOptions(t Type) StreamOptions {
+	switch t {
+	case Notifier:
+		return StreamOptions{
+			e.NotifierStreamGroup,
+			e.NotifierStreamConsumer,
+			e.NotifierStreamOffset,
+			e.NotifierStreamMaxSize,
+		}
+	}
+	return StreamOptions{}
+}
+
 // GetSemaphoreOptions returns all the selection options required to run a
 // selection in the application. It takes a Type argument to switch over the
 // storage strategy.
@@ -9,6 +9,7 @@ const (
 var (
 	InvalidContentType = typex.BadRequest.With("Invalid Content Type")
 	InvalidArgument    = typex.BadRequest.With("Invalid Argument")
+	ClockSkew          = typex.BadRequest.With("Clock Skew")
 
 	Fatal                   = typex.InternalServerError.With("Fatal")
 	Complete                = typex.InternalServerError.With("Complete")
@@ -21,7 +22,22 @@ var (
 	NoCaseFound             = typex.InternalServerError.With("No Case Found")
 	ExpiredNode             = typex.InternalServerError.With("Expired Node")
 	RateLimited             = typex.InternalServerError.With("Rate Limited")
+	BreakerOpen             = typex.InternalServerError.With("Breaker Open")
 	MaxSize                 = typex.InternalServerError.With("Max Size")
 
 	MissingContent = typex.NotFound.With("Missing Content")
+
+	IdempotencyMismatch = typex.UnprocessableEntity.With("Idempotency Mismatch")
+
+	Compensated = typex.Conflict.With("Compensated")
+	InProgress  = typex.Conflict.With("In Progress")
+
+	IllegalTransition      = typex.Conflict.With("Illegal Transition")
+	ConcurrentModification = typex.Conflict.With("Concurrent Modification")
+
+	InvalidToken  = typex.Unauthorized.With("Invalid Token")
+	NotAdmitted   = typex.TooManyRequests.With("Not Admitted")
+	TooManyTokens = typex.TooManyRequests.With("Too Many Tokens")
+
+	Timeout = typex.GatewayTimeout.With("Timeout")
 )
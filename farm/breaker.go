@@ -0,0 +1,313 @@
+package farm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/SimonRichardson/echelon/alertmanager"
+	t "github.com/SimonRichardson/echelon/cluster"
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/garyburd/redigo/redis"
+)
+
+// ErrBreakerOpen defines an error where a cluster isn't sent the request, as
+// it's breaker is open.
+var ErrBreakerOpen = typex.Errorf(errors.Source, errors.BreakerOpen, "Breaker Open")
+
+// BreakerState defines the state of a breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails every request, without sending it to the cluster.
+	BreakerOpen
+	// BreakerHalfOpen lets a single request through, to probe if the cluster
+	// has recovered.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// BreakerOptions defines when a breaker opens and for how long.
+type BreakerOptions struct {
+	// ErrorRate is the ratio of failed requests with in the window that opens
+	// the breaker, a rate of zero never opens the breaker.
+	ErrorRate float64
+	// Latency is how long a request can take before it counts as failed, a
+	// latency of zero never fails a request because it's slow.
+	Latency time.Duration
+	// MinRequests is how many requests the window has to have, before the
+	// error rate is considered.
+	MinRequests int
+	// Window is how long the requests are counted for.
+	Window time.Duration
+	// Cooldown is how long the breaker stays open, before it's half-open.
+	Cooldown time.Duration
+}
+
+// Breaker defines a circuit breaker for a cluster. Whilst it's closed every
+// request is sent to the cluster, once the requests fail at a high enough rate
+// it opens and the requests fail straight away. After a cooldown, the breaker
+// is half-open and lets a single request through, which either closes or opens
+// the breaker again.
+type Breaker struct {
+	mutex    sync.Mutex
+	name     string
+	options  BreakerOptions
+	alerts   alertmanager.BreakerAlertManager
+	state    BreakerState
+	opened   time.Time
+	began    time.Time
+	requests int
+	failures int
+	probing  bool
+}
+
+// NewBreaker creates a closed breaker.
+func NewBreaker(name string, options BreakerOptions, alerts alertmanager.BreakerAlertManager) *Breaker {
+	return &Breaker{
+		name:    name,
+		options: options,
+		alerts:  alerts,
+		state:   BreakerClosed,
+		began:   time.Now(),
+	}
+}
+
+// State returns the state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.state
+}
+
+// Allow returns if a request can be sent to the cluster. If it's allowed, the
+// outcome of the request is expected to be recorded.
+func (b *Breaker) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.opened) < b.options.Cooldown {
+			return false
+		}
+		b.transition(BreakerHalfOpen)
+		fallthrough
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// Record the outcome of a request that was allowed.
+func (b *Breaker) Record(err error, took time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	// A request that the caller gave up on says nothing about the cluster, so
+	// it's only the probe that's no longer in flight.
+	if abandoned(err) {
+		if b.state == BreakerHalfOpen {
+			b.probing = false
+		}
+		return
+	}
+
+	failed := Unavailable(err) || (b.options.Latency > 0 && took > b.options.Latency)
+
+	switch b.state {
+	case BreakerHalfOpen:
+		b.probing = false
+		if failed {
+			b.transition(BreakerOpen)
+		} else {
+			b.transition(BreakerClosed)
+		}
+
+	case BreakerClosed:
+		now := time.Now()
+		if now.Sub(b.began) > b.options.Window {
+			b.reset(now)
+		}
+
+		b.requests++
+		if failed {
+			b.failures++
+		}
+
+		if b.options.ErrorRate > 0 &&
+			b.requests >= b.options.MinRequests &&
+			float64(b.failures)/float64(b.requests) >= b.options.ErrorRate {
+			b.transition(BreakerOpen)
+		}
+	}
+}
+
+func (b *Breaker) transition(state BreakerState) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+
+	now := time.Now()
+	switch state {
+	case BreakerOpen:
+		b.opened = now
+		go b.alerts.BreakerOpen(b.name)
+	case BreakerHalfOpen:
+		go b.alerts.BreakerHalfOpen(b.name)
+	case BreakerClosed:
+		b.reset(now)
+		go b.alerts.BreakerClosed(b.name)
+	}
+}
+
+func (b *Breaker) reset(now time.Time) {
+	b.began = now
+	b.requests = 0
+	b.failures = 0
+}
+
+// Unavailable returns if the error means that the cluster couldn't be reached,
+// rather than the cluster rejecting the request.
+func Unavailable(err error) bool {
+	switch err {
+	case nil, context.Canceled, context.DeadlineExceeded:
+		return false
+	case io.EOF, io.ErrUnexpectedEOF, redis.ErrPoolExhausted:
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
+
+func abandoned(err error) bool {
+	return err == context.Canceled || err == context.DeadlineExceeded
+}
+
+// Breakers holds a breaker for every cluster of a farm.
+type Breakers struct {
+	mutex    sync.Mutex
+	name     string
+	options  BreakerOptions
+	alerts   alertmanager.BreakerAlertManager
+	breakers map[interface{}]*Breaker
+}
+
+// NewBreakers creates the breakers for a farm, the breakers are named after
+// the farm and the index of the cluster.
+func NewBreakers(name string, options BreakerOptions, alerts alertmanager.BreakerAlertManager) *Breakers {
+	return &Breakers{
+		name:     name,
+		options:  options,
+		alerts:   alerts,
+		breakers: map[interface{}]*Breaker{},
+	}
+}
+
+// Topology replaces the breakers with closed breakers for the clusters.
+func (b *Breakers) Topology(clusters []interface{}) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.breakers = make(map[interface{}]*Breaker, len(clusters))
+	for k, v := range clusters {
+		b.breakers[v] = NewBreaker(fmt.Sprintf("%s.%d", b.name, k), b.options, b.alerts)
+	}
+}
+
+// Breaker returns the breaker of a cluster, a cluster that's unknown is given
+// a new breaker.
+func (b *Breakers) Breaker(cluster interface{}) *Breaker {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if v, ok := b.breakers[cluster]; ok {
+		return v
+	}
+
+	v := NewBreaker(fmt.Sprintf("%s.%d", b.name, len(b.breakers)), b.options, b.alerts)
+	b.breakers[cluster] = v
+	return v
+}
+
+// Rekey moves the breaker of a cluster over to the cluster that replaces it,
+// so that unwrapping a cluster keeps the state of it's breaker.
+func (b *Breakers) Rekey(prev, next interface{}) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if v, ok := b.breakers[prev]; ok {
+		delete(b.breakers, prev)
+		b.breakers[next] = v
+	}
+}
+
+// Guard sends the request to the cluster through it's breaker. If the breaker
+// doesn't allow the request, it fails straight away with an error element,
+// rather than waiting on a cluster that's known to be failing.
+func (b *Breakers) Guard(ctx context.Context, cluster interface{}, fn func() <-chan t.Element) <-chan t.Element {
+	breaker := b.Breaker(cluster)
+	if !breaker.Allow() {
+		out := make(chan t.Element, 1)
+		out <- t.NewErrorElement(bs.Key(""), ErrBreakerOpen)
+		close(out)
+		return out
+	}
+
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		var (
+			began = time.Now()
+			sent  = true
+			err   error
+		)
+		for e := range fn() {
+			if elementErr := t.ErrorFromElement(e); elementErr != nil && err == nil {
+				err = elementErr
+			}
+			if sent {
+				sent = t.Send(ctx, out, e)
+			}
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil && err == nil {
+			err = ctxErr
+		}
+		breaker.Record(err, time.Since(began))
+	}()
+	return out
+}
+
+// Clusters converts a slice of clusters, so that the breakers can be given the
+// topology of any farm.
+func Clusters(n int, fn func(int) interface{}) []interface{} {
+	res := make([]interface{}, n)
+	for k := range res {
+		res[k] = fn(k)
+	}
+	return res
+}
@@ -0,0 +1,138 @@
+package farm
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/SimonRichardson/echelon/alertmanager/noop"
+	c "github.com/SimonRichardson/echelon/cluster"
+)
+
+func TestBreakerOpensOnErrorRate(t *testing.T) {
+	b := NewBreaker("test", BreakerOptions{
+		ErrorRate:   0.5,
+		MinRequests: 4,
+		Window:      time.Minute,
+		Cooldown:    time.Minute,
+	}, noop.New())
+
+	for i := 0; i < 3; i++ {
+		if !b.Allow() {
+			t.Fatalf("Expected closed breaker to allow request %d", i)
+		}
+		b.Record(io.EOF, 0)
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("Expected closed breaker before min requests, got %s", b.State())
+	}
+
+	b.Record(io.EOF, 0)
+	if b.State() != BreakerOpen {
+		t.Fatalf("Expected open breaker, got %s", b.State())
+	}
+	if b.Allow() {
+		t.Fatal("Expected open breaker to fail request")
+	}
+}
+
+func TestBreakerIgnoresRejections(t *testing.T) {
+	b := NewBreaker("test", BreakerOptions{
+		ErrorRate:   0.5,
+		MinRequests: 1,
+		Window:      time.Minute,
+		Cooldown:    time.Minute,
+	}, noop.New())
+
+	b.Record(ErrBreakerOpen, 0)
+	if b.State() != BreakerClosed {
+		t.Fatalf("Expected closed breaker, got %s", b.State())
+	}
+}
+
+func TestBreakerIgnoresAbandoned(t *testing.T) {
+	b := NewBreaker("test", BreakerOptions{
+		ErrorRate:   0.5,
+		Latency:     time.Millisecond,
+		MinRequests: 1,
+		Window:      time.Minute,
+		Cooldown:    time.Minute,
+	}, noop.New())
+
+	b.Record(context.DeadlineExceeded, time.Second)
+	b.Record(context.Canceled, time.Second)
+	if b.State() != BreakerClosed {
+		t.Fatalf("Expected closed breaker, got %s", b.State())
+	}
+}
+
+func TestBreakerOpensOnLatency(t *testing.T) {
+	b := NewBreaker("test", BreakerOptions{
+		ErrorRate:   1,
+		Latency:     time.Millisecond,
+		MinRequests: 1,
+		Window:      time.Minute,
+		Cooldown:    time.Minute,
+	}, noop.New())
+
+	b.Record(nil, time.Second)
+	if b.State() != BreakerOpen {
+		t.Fatalf("Expected open breaker, got %s", b.State())
+	}
+}
+
+func TestBreakerHalfOpenProbe(t *testing.T) {
+	b := NewBreaker("test", BreakerOptions{
+		ErrorRate:   1,
+		MinRequests: 1,
+		Window:      time.Minute,
+	}, noop.New())
+
+	b.Record(io.EOF, 0)
+	if b.State() != BreakerOpen {
+		t.Fatalf("Expected open breaker, got %s", b.State())
+	}
+
+	if !b.Allow() {
+		t.Fatal("Expected half-open breaker to allow a probe")
+	}
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("Expected half-open breaker, got %s", b.State())
+	}
+	if b.Allow() {
+		t.Fatal("Expected half-open breaker to allow a single probe")
+	}
+
+	b.Record(nil, 0)
+	if b.State() != BreakerClosed {
+		t.Fatalf("Expected closed breaker, got %s", b.State())
+	}
+}
+
+func TestBreakersGuard(t *testing.T) {
+	var (
+		cluster  = &struct{ name string }{"a"}
+		breakers = NewBreakers("test", BreakerOptions{
+			ErrorRate:   1,
+			MinRequests: 1,
+			Window:      time.Minute,
+			Cooldown:    time.Minute,
+		}, noop.New())
+	)
+	breakers.Topology([]interface{}{cluster})
+	breakers.Breaker(cluster).Record(io.EOF, 0)
+
+	called := false
+	for e := range breakers.Guard(context.Background(), cluster, func() <-chan c.Element {
+		called = true
+		return nil
+	}) {
+		if err := c.ErrorFromElement(e); err != ErrBreakerOpen {
+			t.Fatalf("Expected breaker open error, got %v", err)
+		}
+	}
+	if called {
+		t.Fatal("Expected open breaker to not send the request")
+	}
+}
@@ -0,0 +1,69 @@
+package farm
+
+import (
+	"context"
+
+	t "github.com/SimonRichardson/echelon/cluster"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+)
+
+var (
+	// ErrTimeout defines an error where the deadline of the request passed,
+	// before the clusters answered.
+	ErrTimeout = typex.Errorf(errors.Source, errors.Timeout, "Timeout")
+
+	// ErrCancelled defines an error where the request was cancelled, before the
+	// clusters answered.
+	ErrCancelled = typex.Errorf(errors.Source, errors.Timeout, "Cancelled")
+)
+
+// Gather forwards the elements until the context is done. Once it's done, the
+// rest of the elements are drained in the background, so that the clusters
+// never block on a farm that's no longer waiting for them.
+func Gather(ctx context.Context, elements <-chan t.Element) <-chan t.Element {
+	if ctx.Done() == nil {
+		return elements
+	}
+
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case element, ok := <-elements:
+				if !ok {
+					return
+				}
+				if !t.Send(ctx, out, element) {
+					go Drain(elements)
+					return
+				}
+			case <-ctx.Done():
+				go Drain(elements)
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Drain consumes the elements, without doing anything with them.
+func Drain(elements <-chan t.Element) {
+	for range elements {
+	}
+}
+
+// ContextError returns the error for a context that's done, which is nil if
+// it's not done.
+func ContextError(ctx context.Context) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return ErrTimeout
+	default:
+		return ErrCancelled
+	}
+}
@@ -0,0 +1,200 @@
+package counter
+
+import (
+	"context"
+	t "github.com/SimonRichardson/echelon/cluster"
+	r "github.com/SimonRichardson/echelon/cluster/counter"
+	"github.com/SimonRichardson/echelon/farm"
+	"github.com/SimonRichardson/echelon/instrumentation"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+// migrating defines a cluster that's moving the keys that changed host from
+// the previous cluster. Whilst a key is still to be moved, it's read from both
+// of the clusters.
+type migrating struct {
+	r.Cluster
+	prev      r.Cluster
+	migration *farm.Migration
+	instr     instrumentation.Instrumentation
+}
+
+func (m *migrating) Moved() ([]bs.Key, error) {
+	return r.Moved(m.prev, m.Cluster)
+}
+
+func (m *migrating) Migrate(key bs.Key) (int, error) {
+	return r.Migrate(m.prev, m.Cluster, key)
+}
+
+func (m *migrating) Size(ctx context.Context, key bs.Key) <-chan t.Element {
+	if !m.migration.Pending(key) {
+		return m.Cluster.Size(ctx, key)
+	}
+
+	go m.instr.MigrateDualRead()
+	return m.dual(ctx, key, func(members []bs.Key) t.Element {
+		return t.NewCountElement(key, len(members))
+	})
+}
+
+func (m *migrating) Members(ctx context.Context, key bs.Key) <-chan t.Element {
+	if !m.migration.Pending(key) {
+		return m.Cluster.Members(ctx, key)
+	}
+
+	go m.instr.MigrateDualRead()
+	return m.dual(ctx, key, func(members []bs.Key) t.Element {
+		return t.NewKeyElement(key, members)
+	})
+}
+
+// dual reads the members from both of the clusters. A member that's only held
+// by the previous cluster is dropped if the next cluster has since deleted it.
+func (m *migrating) dual(ctx context.Context, key bs.Key, fn func([]bs.Key) t.Element) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		next, nextErr := collect(m.Cluster.Members(ctx, key))
+		prev, prevErr := collect(m.prev.Members(ctx, key))
+
+		if nextErr != nil {
+			t.Send(ctx, out, t.NewErrorElement(key, nextErr))
+			return
+		}
+		if prevErr != nil {
+			teleprinter.L.Error().Printf("Unable to read migrating key %s : %s\n", key.String(), prevErr.Error())
+		}
+
+		var (
+			result  = make([]bs.Key, 0, len(next)+len(prev))
+			unique  = map[bs.Key]struct{}{}
+			missing = []s.KeyFieldTxnValue{}
+		)
+		for _, v := range next {
+			unique[v] = struct{}{}
+			result = append(result, v)
+		}
+		for _, v := range prev {
+			if _, ok := unique[v]; ok {
+				continue
+			}
+			missing = append(missing, s.KeyFieldTxnValue{Key: key, Field: v})
+		}
+
+		if len(missing) > 0 {
+			presence, err := m.Cluster.Score(missing)
+			for _, v := range missing {
+				if err == nil {
+					if p, ok := presence[v]; ok && p.Present {
+						continue
+					}
+				}
+				result = append(result, v.Field)
+			}
+		}
+
+		t.Send(ctx, out, fn(result))
+	}()
+	return out
+}
+
+func collect(elements <-chan t.Element) ([]bs.Key, error) {
+	var (
+		result = []bs.Key{}
+		err    error
+	)
+	// The channel is always drained, so that the cluster doesn't block.
+	for e := range elements {
+		if elementErr := t.ErrorFromElement(e); elementErr != nil {
+			err = elementErr
+			continue
+		}
+		result = append(result, t.KeysFromElement(e)...)
+	}
+	return result, err
+}
+
+// migrate wraps the clusters that have keys to move from the current clusters.
+// The current clusters that have nothing to move are closed straight away,
+// otherwise they're closed once the next topology change has settled them.
+func (f *Farm) migrate(clusters []r.Cluster) ([]r.Cluster, error) {
+	var (
+		prev       = f.clusters
+		next       = make([]r.Cluster, len(clusters))
+		migrations = []*migrating{}
+		closing    = []r.Cluster{}
+	)
+	for k, v := range clusters {
+		next[k] = v
+		if k >= len(prev) {
+			continue
+		}
+
+		m := &migrating{
+			Cluster: v,
+			prev:    prev[k],
+			instr:   f.instrumentation,
+		}
+		migration, err := farm.NewMigration(m)
+		if err != nil {
+			return nil, err
+		}
+
+		if migration.Len() < 1 {
+			closing = append(closing, prev[k])
+			continue
+		}
+
+		m.migration = migration
+		next[k] = m
+		migrations = append(migrations, m)
+	}
+	for k := len(clusters); k < len(prev); k++ {
+		closing = append(closing, prev[k])
+	}
+
+	for _, v := range closing {
+		if err := v.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(migrations) > 0 {
+		go f.instrumentation.MigrateCall()
+	}
+	for _, v := range migrations {
+		go v.migration.Run(v, f.instrumentation, teleprinter.L.Error())
+	}
+
+	f.migrations = migrations
+	return next, nil
+}
+
+// settle waits for the migrations of the last topology change to run, before
+// closing the previous clusters and unwrapping the current ones.
+func (f *Farm) settle() error {
+	for _, m := range f.migrations {
+		m.migration.Wait()
+
+		if num := m.migration.Len(); num > 0 {
+			teleprinter.L.Error().Printf("Abandoning migration of %d keys\n", num)
+		}
+
+		if err := m.prev.Close(); err != nil {
+			return err
+		}
+	}
+
+	for k, v := range f.clusters {
+		if m, ok := v.(*migrating); ok {
+			f.clusters[k] = m.Cluster
+			f.breakers.Rekey(m, m.Cluster)
+		}
+	}
+	f.migrations = nil
+	return nil
+}
@@ -19,7 +19,8 @@ var (
 
 // ParseString parses various inputs and returns a slice of clusters that we can
 // then use with in the farm.
-// - addresses is a semi-colon separated string of redis addresses
+// - addresses is a semi-colon separated string of redis addresses, or
+//   mem://name addresses for clusters that are held in memory
 // - connectTimeout, readTimeout and writeTimeout is a set of durations in
 //   string format
 // - poolRoutingStrategy defines a strategy for how the pool routing works
@@ -44,6 +45,13 @@ func ParseString(addresses string,
 	}
 
 	for i, address := range strings.Split(common.StripWhitespace(addresses), ";") {
+		if name, ok, err := r.MemoryHost(address); err != nil {
+			return empty, err
+		} else if ok {
+			clusters = append(clusters, c.NewMemory(name))
+			continue
+		}
+
 		hosts := []string{}
 		for _, host := range strings.Split(address, ",") {
 			if len(host) < 1 {
@@ -1,6 +1,7 @@
 package counter
 
 import (
+	"context"
 	"log"
 	"strings"
 	"sync"
@@ -27,7 +28,7 @@ type repair struct {
 	tactic Tactic
 }
 
-func (w repair) Repair(keyFieldTxnValue []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
+func (w repair) Repair(ctx context.Context, keyFieldTxnValue []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
 	var (
 		clusters      = w.Farm.clusters
 		numOfClusters = len(clusters)
@@ -121,7 +122,7 @@ func (w repair) Repair(keyFieldTxnValue []s.KeyFieldTxnValue, maxSize s.KeySizeE
 
 	errs := []string{}
 	for index, keyFieldScoreTxnValues := range inserts {
-		elements := clusters[index].Insert(keyFieldScoreTxnValues, maxSize)
+		elements := clusters[index].Insert(ctx, keyFieldScoreTxnValues, maxSize)
 		for e := range elements {
 			func(e t.Element) {
 				if err := t.ErrorFromElement(e); err != nil {
@@ -132,7 +133,7 @@ func (w repair) Repair(keyFieldTxnValue []s.KeyFieldTxnValue, maxSize s.KeySizeE
 	}
 
 	for index, keyFieldScoreTxnValues := range deletes {
-		elements := clusters[index].Delete(keyFieldScoreTxnValues, maxSize)
+		elements := clusters[index].Delete(ctx, keyFieldScoreTxnValues, maxSize)
 		for e := range elements {
 			func(e t.Element) {
 				defer wg.Done()
@@ -1,6 +1,7 @@
 package counter
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -25,25 +26,25 @@ type scanAllReadAll struct {
 	tactic Tactic
 }
 
-func (w scanAllReadAll) Keys() ([]bs.Key, error) {
-	return w.readKeys(func(c r.Cluster) <-chan t.Element {
-		return c.Keys()
+func (w scanAllReadAll) Keys(ctx context.Context) ([]bs.Key, error) {
+	return w.readKeys(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Keys(ctx)
 	})
 }
 
-func (w scanAllReadAll) Size(key bs.Key) (int, error) {
-	return w.readInt(func(c r.Cluster) <-chan t.Element {
-		return c.Size(key)
+func (w scanAllReadAll) Size(ctx context.Context, key bs.Key) (int, error) {
+	return w.readInt(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Size(ctx, key)
 	})
 }
 
-func (w scanAllReadAll) Members(key bs.Key) ([]bs.Key, error) {
-	return w.readKeys(func(c r.Cluster) <-chan t.Element {
-		return c.Members(key)
+func (w scanAllReadAll) Members(ctx context.Context, key bs.Key) ([]bs.Key, error) {
+	return w.readKeys(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Members(ctx, key)
 	})
 }
 
-func (w scanAllReadAll) readKeys(fn func(r.Cluster) <-chan t.Element) ([]bs.Key, error) {
+func (w scanAllReadAll) readKeys(ctx context.Context, fn func(context.Context, r.Cluster) <-chan t.Element) ([]bs.Key, error) {
 	var (
 		clusters      = w.Farm.clusters
 		numOfClusters = len(clusters)
@@ -65,11 +66,11 @@ func (w scanAllReadAll) readKeys(fn func(r.Cluster) <-chan t.Element) ([]bs.Key,
 	wg.Add(numOfClusters)
 	go func() { wg.Wait(); close(elements) }()
 
-	if err := scatterReads(w.tactic, w.instrumentation, clusters, fn, &wg, elements); err != nil {
+	if err := scatterReads(ctx, w.tactic, w.instrumentation, w.breakers, clusters, fn, &wg, elements); err != nil {
 		return nil, err
 	}
 
-	for element := range elements {
+	for element := range farm.Gather(ctx, elements) {
 		var (
 			keys      = t.KeysFromElement(element)
 			numOfKeys = len(keys)
@@ -87,11 +88,15 @@ func (w scanAllReadAll) readKeys(fn func(r.Cluster) <-chan t.Element) ([]bs.Key,
 		}
 	}
 
+	if err := farm.ContextError(ctx); err != nil {
+		return nil, err
+	}
+
 	resultsScan(w.Farm, retrieved, returned)
 	return responses, nil
 }
 
-func (w scanAllReadAll) readInt(fn func(r.Cluster) <-chan t.Element) (int, error) {
+func (w scanAllReadAll) readInt(ctx context.Context, fn func(context.Context, r.Cluster) <-chan t.Element) (int, error) {
 	var (
 		clusters      = w.Farm.clusters
 		numOfClusters = len(clusters)
@@ -113,11 +118,11 @@ func (w scanAllReadAll) readInt(fn func(r.Cluster) <-chan t.Element) (int, error
 	wg.Add(numOfClusters)
 	go func() { wg.Wait(); close(elements) }()
 
-	if err := scatterReads(w.tactic, w.instrumentation, clusters, fn, &wg, elements); err != nil {
+	if err := scatterReads(ctx, w.tactic, w.instrumentation, w.breakers, clusters, fn, &wg, elements); err != nil {
 		return -1, err
 	}
 
-	for element := range elements {
+	for element := range farm.Gather(ctx, elements) {
 		amount := t.AmountFromElement(element)
 		retrieved++
 
@@ -129,6 +134,10 @@ func (w scanAllReadAll) readInt(fn func(r.Cluster) <-chan t.Element) (int, error
 		responses = append(responses, amount)
 	}
 
+	if err := farm.ContextError(ctx); err != nil {
+		return -1, err
+	}
+
 	master := common.NewLargestInt()
 	for _, v := range responses {
 		master.Add(v)
@@ -172,10 +181,12 @@ func afterScan(f *Farm, began time.Time) {
 }
 
 func scatterReads(
+	ctx context.Context,
 	tactic Tactic,
 	instr instrumentation.Instrumentation,
+	breakers *farm.Breakers,
 	clusters []r.Cluster,
-	fn func(r.Cluster) <-chan t.Element,
+	fn func(context.Context, r.Cluster) <-chan t.Element,
 	wg *sync.WaitGroup,
 	dst chan t.Element,
 ) error {
@@ -187,7 +198,7 @@ func scatterReads(
 			go instr.ClusterDuration(k, time.Since(began))
 		}()
 
-		for e := range fn(c) {
+		for e := range breakers.Guard(ctx, c, func() <-chan t.Element { return fn(ctx, c) }) {
 			dst <- e
 		}
 	})
@@ -1,6 +1,7 @@
 package counter
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -40,24 +41,24 @@ type writeAllReadAll struct {
 	tactic Tactic
 }
 
-func (w writeAllReadAll) Insert(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
-	return w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Insert(members, maxSize)
+func (w writeAllReadAll) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Insert(ctx, members, maxSize)
 	})
 }
 
-func (w writeAllReadAll) Delete(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
-	return w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Delete(members, maxSize)
+func (w writeAllReadAll) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Delete(ctx, members, maxSize)
 	})
 }
 
-func (w writeAllReadAll) Rollback(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
-	_, err := w.Delete(members, maxSize)
+func (w writeAllReadAll) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
+	_, err := w.Delete(ctx, members, maxSize)
 	return err
 }
 
-func (w writeAllReadAll) write(fn func(r.Cluster) <-chan t.Element) (int, error) {
+func (w writeAllReadAll) write(ctx context.Context, fn func(context.Context, r.Cluster) <-chan t.Element) (int, error) {
 	var (
 		clusters      = w.Farm.clusters
 		numOfClusters = len(clusters)
@@ -83,12 +84,11 @@ func (w writeAllReadAll) write(fn func(r.Cluster) <-chan t.Element) (int, error)
 	wg.Add(numOfClusters)
 	go func() { wg.Wait(); close(elements) }()
 
-	if err := scatterWrites(w.tactic, w.instrumentation, clusters, fn, wg, elements); err != nil {
+	if err := scatterWrites(ctx, w.tactic, w.instrumentation, w.breakers, clusters, fn, wg, elements); err != nil {
 		return -1, err
 	}
 
-	for element := range elements {
-
+	for element := range farm.Gather(ctx, elements) {
 		amount := t.AmountFromElement(element)
 		retrieved += amount
 
@@ -104,6 +104,10 @@ func (w writeAllReadAll) write(fn func(r.Cluster) <-chan t.Element) (int, error)
 		changes = append(changes, amount)
 	}
 
+	if err := farm.ContextError(ctx); err != nil {
+		return -1, err
+	}
+
 	// If the repair is fale, then go through it
 	if len(errs) > 0 {
 		repairWrite(w.Farm, w.wtype)
@@ -133,10 +137,12 @@ func head(x []int) (int, error) {
 }
 
 func scatterWrites(
+	ctx context.Context,
 	tactic Tactic,
 	instr instrumentation.Instrumentation,
+	breakers *farm.Breakers,
 	clusters []r.Cluster,
-	fn func(r.Cluster) <-chan t.Element,
+	fn func(context.Context, r.Cluster) <-chan t.Element,
 	wg *sync.WaitGroup,
 	dst chan t.Element,
 ) error {
@@ -148,7 +154,7 @@ func scatterWrites(
 			go instr.ClusterDuration(k, time.Since(began))
 		}()
 
-		for e := range fn(c) {
+		for e := range breakers.Guard(ctx, c, func() <-chan t.Element { return fn(ctx, c) }) {
 			dst <- e
 		}
 	})
@@ -0,0 +1,124 @@
+package farm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/SimonRichardson/echelon/internal/logs"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+)
+
+const defaultMigrateRetries = 3
+
+// Migrator defines a way to move the keys of a cluster that are routed to a
+// different host, once the hosts of the cluster have changed.
+type Migrator interface {
+	// Moved returns the keys that are routed to a different host.
+	Moved() ([]bs.Key, error)
+
+	// Migrate moves a key to the host it's now routed to.
+	Migrate(bs.Key) (int, error)
+}
+
+// MigrateInstrumentation defines what a migration reports as it runs. It's
+// kept narrow, so that the farm doesn't depend on the instrumentation.
+type MigrateInstrumentation interface {
+	MigrateKeys(int)
+	MigrateMoved(int)
+	MigrateError(int)
+	MigrateDuration(time.Duration)
+}
+
+// Migration keeps track of the keys that are still to be moved, so that the
+// reads of those keys can be made from both the old and the new hosts.
+type Migration struct {
+	mutex   sync.RWMutex
+	pending map[bs.Key]struct{}
+	done    chan struct{}
+}
+
+// NewMigration creates a Migration for all the keys that the migrator has to
+// move.
+func NewMigration(migrator Migrator) (*Migration, error) {
+	keys, err := migrator.Moved()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[bs.Key]struct{}, len(keys))
+	for _, v := range keys {
+		pending[v] = struct{}{}
+	}
+
+	return &Migration{
+		pending: pending,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Len returns how many keys are still to be moved.
+func (m *Migration) Len() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return len(m.pending)
+}
+
+// Pending returns if the key is still to be moved.
+func (m *Migration) Pending(key bs.Key) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	_, ok := m.pending[key]
+	return ok
+}
+
+// Run moves every pending key, reporting the progress as it goes. A key that
+// fails to move is retried and if it still fails, it's left pending so that
+// it's still read from both hosts. Keys that fail to move are logged to the
+// logger.
+func (m *Migration) Run(migrator Migrator, instr MigrateInstrumentation, logger logs.Logger) {
+	defer close(m.done)
+
+	began := time.Now()
+	defer func() { go instr.MigrateDuration(time.Since(began)) }()
+
+	m.mutex.RLock()
+	keys := make([]bs.Key, 0, len(m.pending))
+	for k := range m.pending {
+		keys = append(keys, k)
+	}
+	m.mutex.RUnlock()
+
+	go instr.MigrateKeys(len(keys))
+
+	for _, key := range keys {
+		var err error
+		for i := 0; i < defaultMigrateRetries; i++ {
+			if _, err = migrator.Migrate(key); err == nil {
+				break
+			}
+		}
+
+		if err != nil {
+			logger.Printf("Unable to migrate key %s : %s\n", key.String(), err.Error())
+			go instr.MigrateError(1)
+			continue
+		}
+
+		m.mutex.Lock()
+		delete(m.pending, key)
+		m.mutex.Unlock()
+
+		go instr.MigrateMoved(1)
+	}
+
+	if num := m.Len(); num > 0 {
+		logger.Printf("Migration completed with %d keys left to move\n", num)
+	}
+}
+
+// Wait blocks until the migration has run.
+func (m *Migration) Wait() {
+	<-m.done
+}
@@ -0,0 +1,119 @@
+package notifier
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	t "github.com/SimonRichardson/echelon/cluster"
+	r "github.com/SimonRichardson/echelon/cluster/notifier"
+	"github.com/SimonRichardson/echelon/common"
+	"github.com/SimonRichardson/echelon/farm"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+// Record defines a way to record the changes that have happened to members, so
+// that they can be read back in order of their score. Unlike publishing, the
+// changes are recorded on every cluster, so that any of them can be read.
+func (f *Farm) Record(ctx context.Context, changes []s.Change, maxSize int) error {
+	if len(changes) < 1 {
+		return nil
+	}
+
+	errors := each(f.clusters, func(cluster r.Cluster) []error {
+		var errs []error
+		for element := range cluster.Record(ctx, changes, maxSize) {
+			if err := t.ErrorFromElement(element); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errs
+	})
+
+	if err := farm.ContextError(ctx); err != nil {
+		return err
+	}
+	if len(errors) > 0 {
+		return common.SumErrors(errors)
+	}
+	return nil
+}
+
+// Changes defines a way to read back the changes of a key, that have a score
+// greater than since. The changes of every cluster are merged, so that a
+// cluster that missed a change doesn't hide it.
+func (f *Farm) Changes(ctx context.Context, key bs.Key, since float64, limit int) ([]s.Change, error) {
+	var (
+		mutex  = sync.Mutex{}
+		unique = map[s.Change]struct{}{}
+	)
+
+	errors := each(f.clusters, func(cluster r.Cluster) []error {
+		var errs []error
+		for element := range cluster.Changes(ctx, key, since, limit) {
+			if err := t.ErrorFromElement(element); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			mutex.Lock()
+			for _, v := range t.ChangesFromElement(element) {
+				unique[v] = struct{}{}
+			}
+			mutex.Unlock()
+		}
+		return errs
+	})
+
+	if err := farm.ContextError(ctx); err != nil {
+		return nil, err
+	}
+
+	// Only fail if no cluster was able to return the changes.
+	if len(errors) >= len(f.clusters) {
+		return nil, common.SumErrors(errors)
+	}
+
+	result := make([]s.Change, 0, len(unique))
+	for v := range unique {
+		result = append(result, v)
+	}
+	sort.Sort(s.Changes(result))
+
+	// The changes that share the score of the last change aren't split, so
+	// that a read that resumes from that score doesn't lose any of them.
+	if len(result) > limit {
+		num := limit
+		for num < len(result) && result[num].Score == result[limit-1].Score {
+			num++
+		}
+		result = result[:num]
+	}
+	return result, nil
+}
+
+func each(clusters []r.Cluster, fn func(r.Cluster) []error) []error {
+	var (
+		mutex  = sync.Mutex{}
+		errors []error
+
+		wg = sync.WaitGroup{}
+	)
+
+	wg.Add(len(clusters))
+	for _, v := range clusters {
+		go func(cluster r.Cluster) {
+			defer wg.Done()
+
+			if errs := fn(cluster); len(errs) > 0 {
+				mutex.Lock()
+				errors = append(errors, errs...)
+				mutex.Unlock()
+			}
+		}(v)
+	}
+	wg.Wait()
+
+	return errors
+}
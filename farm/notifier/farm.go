@@ -1,8 +1,12 @@
 package notifier
 
 import (
+	"context"
 	c "github.com/SimonRichardson/echelon/cluster/notifier"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/farm"
 	"github.com/SimonRichardson/echelon/instrumentation"
+	"github.com/SimonRichardson/echelon/internal/typex"
 	s "github.com/SimonRichardson/echelon/selectors"
 )
 
@@ -32,16 +36,21 @@ type (
 type Farm struct {
 	clusters        []c.Cluster
 	notifier        s.Notifier
+	breakers        *farm.Breakers
 	instrumentation instrumentation.Instrumentation
 }
 
 // New defines a function for the creation of a farm.
 func New(clusters []c.Cluster,
 	not NotifyCreator,
+	breakers *farm.Breakers,
 	instr instrumentation.Instrumentation,
 ) *Farm {
+	breakers.Topology(topology(clusters))
+
 	farm := &Farm{
 		clusters:        clusters,
+		breakers:        breakers,
 		instrumentation: instr,
 	}
 	farm.notifier = not.Apply(farm)
@@ -49,19 +58,33 @@ func New(clusters []c.Cluster,
 }
 
 // Publish defines a way to publish some changes that has occured recently.
-func (f *Farm) Publish(channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
-	return f.notifier.Publish(channel, members)
+func (f *Farm) Publish(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
+	return f.notifier.Publish(ctx, channel, members)
 }
 
 // Unpublish defines a way to publish some changes that has occured recently.
-func (f *Farm) Unpublish(channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
-	return f.notifier.Unpublish(channel, members)
+func (f *Farm) Unpublish(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
+	return f.notifier.Unpublish(ctx, channel, members)
 }
 
 // Subscribe defines a way to recieve notifications that something has been
 // published to the system.
-func (f *Farm) Subscribe(channel s.Channel) <-chan s.KeyFieldScoreSizeExpiry {
-	return f.notifier.Subscribe(channel)
+func (f *Farm) Subscribe(ctx context.Context, channel s.Channel) <-chan s.Delivery {
+	return f.notifier.Subscribe(ctx, channel)
+}
+
+// Rewind defines a way to replay the messages of a channel from an offset, this
+// is only possible if the notifier is backed by a stream.
+func (f *Farm) Rewind(ctx context.Context, channel s.Channel, offset string) error {
+	if r, ok := f.notifier.(rewinder); ok {
+		return r.Rewind(ctx, channel, offset)
+	}
+	return typex.Errorf(errors.Source, errors.UnexpectedArgument,
+		"Notifier doesn't support rewinding")
+}
+
+type rewinder interface {
+	Rewind(context.Context, s.Channel, string) error
 }
 
 func (f *Farm) Topology(clusters []c.Cluster) error {
@@ -72,5 +95,12 @@ func (f *Farm) Topology(clusters []c.Cluster) error {
 	}
 
 	f.clusters = clusters
+	f.breakers.Topology(topology(clusters))
 	return nil
 }
+
+func topology(clusters []c.Cluster) []interface{} {
+	return farm.Clusters(len(clusters), func(k int) interface{} {
+		return clusters[k]
+	})
+}
@@ -1,6 +1,8 @@
 package notifier
 
 import (
+	"context"
+
 	s "github.com/SimonRichardson/echelon/selectors"
 )
 
@@ -12,10 +14,10 @@ type noop struct {
 	*Farm
 }
 
-func (n noop) Publish(s.Channel, []s.KeyFieldScoreSizeExpiry) error {
+func (n noop) Publish(context.Context, s.Channel, []s.KeyFieldScoreSizeExpiry) error {
 	return nil
 }
 
-func (n noop) Subscribe(s.Channel) <-chan s.KeyFieldScoreSizeExpiry {
+func (n noop) Subscribe(context.Context, s.Channel) <-chan s.Delivery {
 	return nil
 }
@@ -1,6 +1,7 @@
 package notifier
 
 import (
+	"context"
 	"log"
 	"math/rand"
 	"sync"
@@ -9,6 +10,7 @@ import (
 	t "github.com/SimonRichardson/echelon/cluster"
 	r "github.com/SimonRichardson/echelon/cluster/notifier"
 	"github.com/SimonRichardson/echelon/common"
+	"github.com/SimonRichardson/echelon/farm"
 	s "github.com/SimonRichardson/echelon/selectors"
 	"github.com/SimonRichardson/echelon/semaphore"
 )
@@ -33,7 +35,9 @@ func Bulk(f *Farm, t Tactic) s.Notifier {
 			m := toBulkItems(values).bucketize()
 			for _, v := range m {
 				for _, x := range v {
-					individual.Publish(x.channel, x.members)
+					// The members are published once the bulk is full, long
+					// after the callers have had their answer.
+					individual.Publish(context.Background(), x.channel, x.members)
 				}
 			}
 		}, defaultSize, defaultTimeout)
@@ -46,25 +50,25 @@ type individual struct {
 	tactic Tactic
 }
 
-func (w individual) Publish(channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
-	return w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Publish(channel, members)
+func (w individual) Publish(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Publish(ctx, channel, members)
 	})
 }
 
-func (w individual) Unpublish(channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
-	return w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Unpublish(channel, members)
+func (w individual) Unpublish(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Unpublish(ctx, channel, members)
 	})
 }
 
-func (w individual) Subscribe(channel s.Channel) <-chan s.KeyFieldScoreSizeExpiry {
-	return w.read(func(c r.Cluster) <-chan t.Element {
-		return c.Subscribe(channel)
+func (w individual) Subscribe(ctx context.Context, channel s.Channel) <-chan s.Delivery {
+	return w.read(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Subscribe(ctx, channel)
 	})
 }
 
-func (w individual) write(fn func(r.Cluster) <-chan t.Element) error {
+func (w individual) write(ctx context.Context, fn func(context.Context, r.Cluster) <-chan t.Element) error {
 	var (
 		clusters      = selectClusters(w.Farm.clusters)
 		numOfClusters = len(clusters)
@@ -86,9 +90,9 @@ func (w individual) write(fn func(r.Cluster) <-chan t.Element) error {
 	go func() { wg.Wait(); close(elements) }()
 
 	// distribute randomly across the cluster
-	scatterWrites(w.tactic, clusters, fn, &wg, elements)
+	scatterWrites(ctx, w.tactic, w.breakers, clusters, fn, &wg, elements)
 
-	for element := range elements {
+	for element := range farm.Gather(ctx, elements) {
 		retrieved++
 
 		if err := t.ErrorFromElement(element); err != nil {
@@ -99,6 +103,10 @@ func (w individual) write(fn func(r.Cluster) <-chan t.Element) error {
 		returned++
 	}
 
+	if err := farm.ContextError(ctx); err != nil {
+		return err
+	}
+
 	results(w.Farm, retrieved, returned)
 
 	if len(errors) > 0 {
@@ -107,12 +115,12 @@ func (w individual) write(fn func(r.Cluster) <-chan t.Element) error {
 	return nil
 }
 
-func (w individual) read(fn func(r.Cluster) <-chan t.Element) <-chan s.KeyFieldScoreSizeExpiry {
+func (w individual) read(ctx context.Context, fn func(context.Context, r.Cluster) <-chan t.Element) <-chan s.Delivery {
 	var (
 		clusters      = w.Farm.clusters
 		numOfClusters = len(clusters)
 
-		out = make(chan s.KeyFieldScoreSizeExpiry)
+		out = make(chan s.Delivery)
 	)
 
 	began := before(w.Farm, numOfClusters)
@@ -121,17 +129,21 @@ func (w individual) read(fn func(r.Cluster) <-chan t.Element) <-chan s.KeyFieldS
 	for _, v := range clusters {
 
 		go func(cluster r.Cluster) {
-			for element := range fn(cluster) {
+			for element := range fn(ctx, cluster) {
 				if err := t.ErrorFromElement(element); err != nil {
 					return
 				}
 
-				keyFieldScoreSizeExpiry := t.KeyFieldScoreSizeExpiryFromElement(element)
-				if keyFieldScoreSizeExpiry.Key.Len() < 1 {
+				delivery := t.DeliveryFromElement(element)
+				if delivery.Key.Len() < 1 {
 					continue
 				}
 
-				out <- keyFieldScoreSizeExpiry
+				select {
+				case out <- delivery:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}(v)
 	}
@@ -145,17 +157,17 @@ type bulk struct {
 	individual s.Notifier
 }
 
-func (w bulk) Publish(channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
+func (w bulk) Publish(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
 	return w.bulk.Add(bulkItem{channel, members})
 }
 
-func (w bulk) Unpublish(channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
+func (w bulk) Unpublish(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
 	go w.bulk.Remove(bulkItem{channel, members})
-	return w.individual.Unpublish(channel, members)
+	return w.individual.Unpublish(ctx, channel, members)
 }
 
-func (w bulk) Subscribe(channel s.Channel) <-chan s.KeyFieldScoreSizeExpiry {
-	return w.individual.Subscribe(channel)
+func (w bulk) Subscribe(ctx context.Context, channel s.Channel) <-chan s.Delivery {
+	return w.individual.Subscribe(ctx, channel)
 }
 
 type bulkItem struct {
@@ -239,15 +251,17 @@ func selectClusters(clusters []r.Cluster) []r.Cluster {
 }
 
 func scatterWrites(
+	ctx context.Context,
 	tactic Tactic,
+	breakers *farm.Breakers,
 	clusters []r.Cluster,
-	fn func(r.Cluster) <-chan t.Element,
+	fn func(context.Context, r.Cluster) <-chan t.Element,
 	wg *sync.WaitGroup,
 	dst chan t.Element,
 ) error {
 	return tactic(clusters, func(c r.Cluster) {
 		defer wg.Done()
-		for e := range fn(c) {
+		for e := range breakers.Guard(ctx, c, func() <-chan t.Element { return fn(ctx, c) }) {
 			dst <- e
 		}
 	})
@@ -1,6 +1,7 @@
 package notifier
 
 import (
+	"os"
 	"strings"
 	"time"
 
@@ -19,7 +20,8 @@ var (
 
 // ParseString parses various inputs and returns a slice of clusters that we can
 // then use with in the farm.
-// - addresses is a semi-colon separated string of redis addresses
+// - addresses is a semi-colon separated string of redis addresses, or
+//   mem://name addresses for clusters that are held in memory
 // - connectTimeout, readTimeout and writeTimeout is a set of durations in
 //   string format
 // - poolRoutingStrategy defines a strategy for how the pool routing works
@@ -44,6 +46,13 @@ func ParseString(addresses string,
 	}
 
 	for i, address := range strings.Split(common.StripWhitespace(addresses), ";") {
+		if name, ok, err := r.MemoryHost(address); err != nil {
+			return empty, err
+		} else if ok {
+			clusters = append(clusters, c.NewMemory(name))
+			continue
+		}
+
 		hosts := []string{}
 		for _, host := range strings.Split(address, ",") {
 			if len(host) < 1 {
@@ -80,14 +89,14 @@ type notifyStategyOpts struct {
 
 func (o notifyStategyOpts) Apply(f *Farm) s.Notifier { return o.Strategy(f, o.Tactic) }
 
-func ParseNotifyStrategy(opts env.StrategyOptions) (notifyStategyOpts, error) {
+func ParseNotifyStrategy(opts env.StrategyOptions, streamOpts env.StreamOptions) (notifyStategyOpts, error) {
 	var (
 		strategy notifierStrategy
 		tactic   Tactic
 		err      error
 	)
 
-	if strategy, err = parseNotifyStrategy(opts.Strategy, opts.Quorum); err != nil {
+	if strategy, err = parseNotifyStrategy(opts.Strategy, opts.Quorum, streamOpts); err != nil {
 		return notifyStategyOpts{}, err
 	}
 	if tactic, err = readTactic(opts.Tactic,
@@ -100,7 +109,7 @@ func ParseNotifyStrategy(opts env.StrategyOptions) (notifyStategyOpts, error) {
 	return notifyStategyOpts{strategy, tactic}, nil
 }
 
-func parseNotifyStrategy(strategy string, quorum float64) (notifierStrategy, error) {
+func parseNotifyStrategy(strategy string, quorum float64, streamOpts env.StreamOptions) (notifierStrategy, error) {
 	switch common.Normalise(strategy) {
 	case "noop":
 		return NoopNotifier, nil
@@ -108,11 +117,41 @@ func parseNotifyStrategy(strategy string, quorum float64) (notifierStrategy, err
 		return Individual, nil
 	case "bulk":
 		return Bulk, nil
+	case "stream":
+		group, err := parseConsumerGroup(streamOpts)
+		if err != nil {
+			return NoopNotifier, err
+		}
+		return Stream(group, streamOpts.MaxSize), nil
 	}
 	return NoopNotifier, typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
 		"Invalid insert notifier strategy %q", strategy)
 }
 
+func parseConsumerGroup(opts env.StreamOptions) (s.ConsumerGroup, error) {
+	if len(opts.Group) < 1 {
+		return s.ConsumerGroup{}, typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
+			"Invalid stream consumer group %q", opts.Group)
+	}
+
+	consumer := opts.Consumer
+	if len(consumer) < 1 {
+		// Default to the host name, so that a restarted consumer replays the
+		// messages it never acknowledged.
+		hostname, err := os.Hostname()
+		if err != nil {
+			return s.ConsumerGroup{}, err
+		}
+		consumer = hostname
+	}
+
+	return s.ConsumerGroup{
+		Group:    opts.Group,
+		Consumer: consumer,
+		Offset:   opts.Offset,
+	}, nil
+}
+
 func readTactic(tactic string,
 	requestsPerDuration int,
 	requestsDuration string,
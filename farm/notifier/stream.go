@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"context"
+	t "github.com/SimonRichardson/echelon/cluster"
+	r "github.com/SimonRichardson/echelon/cluster/notifier"
+	"github.com/SimonRichardson/echelon/common"
+	"github.com/SimonRichardson/echelon/farm"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+// Stream defines a strategy to publish to a durable stream, which is consumed
+// as part of a consumer group. Every group receives all the messages and any
+// message that was delivered, but never acknowledged is replayed to the same
+// consumer.
+func Stream(group s.ConsumerGroup, maxSize int) notifierStrategy {
+	return func(f *Farm, t Tactic) s.Notifier {
+		return stream{individual{f, t}, group, maxSize}
+	}
+}
+
+type stream struct {
+	individual
+	group   s.ConsumerGroup
+	maxSize int
+}
+
+func (w stream) Publish(ctx context.Context, channel s.Channel, members []s.KeyFieldScoreSizeExpiry) error {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Append(ctx, channel, members, w.maxSize)
+	})
+}
+
+func (w stream) Subscribe(ctx context.Context, channel s.Channel) <-chan s.Delivery {
+	return w.read(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Consume(ctx, channel, w.group)
+	})
+}
+
+// Rewind moves the consumer group back (or forward) to the offset, so that the
+// messages after it are replayed.
+func (w stream) Rewind(ctx context.Context, channel s.Channel, offset string) error {
+	group := w.group
+	group.Offset = offset
+
+	// Every cluster needs to be rewound, as publishing is spread across them.
+	errors := each(w.Farm.clusters, func(cluster r.Cluster) []error {
+		var errs []error
+		for element := range cluster.Rewind(ctx, channel, group) {
+			if err := t.ErrorFromElement(element); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errs
+	})
+
+	if err := farm.ContextError(ctx); err != nil {
+		return err
+	}
+	if len(errors) > 0 {
+		return common.SumErrors(errors)
+	}
+	return nil
+}
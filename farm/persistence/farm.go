@@ -1,7 +1,9 @@
 package persistence
 
 import (
+	"context"
 	p "github.com/SimonRichardson/echelon/cluster/persistence"
+	"github.com/SimonRichardson/echelon/farm"
 	"github.com/SimonRichardson/echelon/instrumentation"
 	s "github.com/SimonRichardson/echelon/selectors"
 )
@@ -32,6 +34,7 @@ type Farm struct {
 	inserter        s.Inserter
 	deleter         s.Deleter
 	repairer        s.Repairer
+	breakers        *farm.Breakers
 	instrumentation instrumentation.Instrumentation
 }
 
@@ -40,10 +43,14 @@ func New(clusters []p.Cluster,
 	ins InsertCreator,
 	del DeleteCreator,
 	rep RepairCreator,
+	breakers *farm.Breakers,
 	instr instrumentation.Instrumentation,
 ) *Farm {
+	breakers.Topology(topology(clusters))
+
 	farm := &Farm{
 		clusters:        clusters,
+		breakers:        breakers,
 		instrumentation: instr,
 	}
 	farm.inserter = ins.Apply(farm)
@@ -54,26 +61,26 @@ func New(clusters []p.Cluster,
 
 // Insert defines a way to insert some members into the store that's associated
 // with the key
-func (f *Farm) Insert(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
-	return f.inserter.Insert(members, maxSize)
+func (f *Farm) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	return f.inserter.Insert(ctx, members, maxSize)
 }
 
 // Delete defines a way to delete some members into the store that's associated
 // with the key
-func (f *Farm) Delete(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
-	return f.deleter.Delete(members, maxSize)
+func (f *Farm) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	return f.deleter.Delete(ctx, members, maxSize)
 }
 
 // Rollback defines a way to rollback some members into the store that's
 // associated with the key
-func (f *Farm) Rollback(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
-	_, err := f.deleter.Delete(members, maxSize)
+func (f *Farm) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
+	_, err := f.deleter.Delete(ctx, members, maxSize)
 	return err
 }
 
 // Repair attempts to repair the store depending on the elements
-func (f *Farm) Repair(elements []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
-	return f.repairer.Repair(elements, maxSize)
+func (f *Farm) Repair(ctx context.Context, elements []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
+	return f.repairer.Repair(ctx, elements, maxSize)
 }
 
 func (f *Farm) Topology(clusters []p.Cluster) error {
@@ -84,5 +91,12 @@ func (f *Farm) Topology(clusters []p.Cluster) error {
 	}
 
 	f.clusters = clusters
+	f.breakers.Topology(topology(clusters))
 	return nil
 }
+
+func topology(clusters []p.Cluster) []interface{} {
+	return farm.Clusters(len(clusters), func(k int) interface{} {
+		return clusters[k]
+	})
+}
@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	s "github.com/SimonRichardson/echelon/selectors"
 )
 
@@ -20,18 +21,18 @@ type noop struct {
 	*Farm
 }
 
-func (n noop) Insert([]s.KeyFieldScoreTxnValue, s.KeySizeExpiry) (int, error) {
+func (n noop) Insert(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) (int, error) {
 	return 0, nil
 }
 
-func (n noop) Delete([]s.KeyFieldScoreTxnValue, s.KeySizeExpiry) (int, error) {
+func (n noop) Delete(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) (int, error) {
 	return 0, nil
 }
 
-func (n noop) Rollback([]s.KeyFieldScoreTxnValue, s.KeySizeExpiry) error {
+func (n noop) Rollback(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) error {
 	return nil
 }
 
-func (n noop) Repair([]s.KeyFieldTxnValue, s.KeySizeExpiry) error {
+func (n noop) Repair(context.Context, []s.KeyFieldTxnValue, s.KeySizeExpiry) error {
 	return nil
 }
@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -46,34 +47,34 @@ type writeAllReadAll struct {
 	tactic Tactic
 }
 
-func (w writeAllReadAll) Insert(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
-	return w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Insert(members, maxSize)
+func (w writeAllReadAll) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Insert(ctx, members, maxSize)
 	})
 }
 
-func (w writeAllReadAll) Delete(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
-	return w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Delete(members, maxSize)
+func (w writeAllReadAll) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Delete(ctx, members, maxSize)
 	})
 }
 
-func (w writeAllReadAll) Rollback(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
-	_, err := w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Delete(members, maxSize)
+func (w writeAllReadAll) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
+	_, err := w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Delete(ctx, members, maxSize)
 	})
 	return err
 }
 
-func (w writeAllReadAll) Repair(members []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
+func (w writeAllReadAll) Repair(ctx context.Context, members []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
 	elements := s.KeyFieldTxnValues(members).KeyFieldScoreTxnValues(0)
-	_, err := w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Repair(elements, maxSize)
+	_, err := w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Repair(ctx, elements, maxSize)
 	})
 	return err
 }
 
-func (w writeAllReadAll) write(fn func(r.Cluster) <-chan t.Element) (int, error) {
+func (w writeAllReadAll) write(ctx context.Context, fn func(context.Context, r.Cluster) <-chan t.Element) (int, error) {
 	var (
 		clusters      = w.Farm.clusters
 		numOfClusters = len(clusters)
@@ -99,10 +100,9 @@ func (w writeAllReadAll) write(fn func(r.Cluster) <-chan t.Element) (int, error)
 	wg.Add(numOfClusters)
 	go func() { wg.Wait(); close(elements) }()
 
-	scatterWrites(w.tactic, clusters, fn, &wg, elements)
-
-	for element := range elements {
+	scatterWrites(ctx, w.tactic, w.breakers, clusters, fn, &wg, elements)
 
+	for element := range farm.Gather(ctx, elements) {
 		amount := t.AmountFromElement(element)
 		retrieved += amount
 
@@ -118,6 +118,10 @@ func (w writeAllReadAll) write(fn func(r.Cluster) <-chan t.Element) (int, error)
 		changes = append(changes, amount)
 	}
 
+	if err := farm.ContextError(ctx); err != nil {
+		return -1, err
+	}
+
 	// If the repair is fale, then go through it
 	if len(errs) > 0 {
 		repairWrite(w.Farm, w.wtype)
@@ -148,15 +152,17 @@ func head(x []int) (int, error) {
 }
 
 func scatterWrites(
+	ctx context.Context,
 	tactic Tactic,
+	breakers *farm.Breakers,
 	clusters []r.Cluster,
-	fn func(r.Cluster) <-chan t.Element,
+	fn func(context.Context, r.Cluster) <-chan t.Element,
 	wg *sync.WaitGroup,
 	dst chan t.Element,
 ) error {
 	return tactic(clusters, func(c r.Cluster) {
 		defer wg.Done()
-		for e := range fn(c) {
+		for e := range breakers.Guard(ctx, c, func() <-chan t.Element { return fn(ctx, c) }) {
 			dst <- e
 		}
 	})
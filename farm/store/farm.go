@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	c "github.com/SimonRichardson/echelon/cluster/store"
 	"github.com/SimonRichardson/echelon/farm"
@@ -61,6 +62,8 @@ type Farm struct {
 	deleter         s.Deleter
 	scanner         s.Scanner
 	repairer        s.Repairer
+	migrations      []*migrating
+	breakers        *farm.Breakers
 	instrumentation instrumentation.Instrumentation
 }
 
@@ -71,10 +74,14 @@ func New(clusters []c.Cluster,
 	del DeleteCreator,
 	sca ScanCreator,
 	rep RepairCreator,
+	breakers *farm.Breakers,
 	instr instrumentation.Instrumentation,
 ) *Farm {
+	breakers.Topology(topology(clusters))
+
 	farm := &Farm{
 		clusters:        clusters,
+		breakers:        breakers,
 		instrumentation: instr,
 	}
 
@@ -89,65 +96,103 @@ func New(clusters []c.Cluster,
 
 // Insert defines a way to insert some members into the store that's associated
 // with the key
-func (f *Farm) Insert(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+func (f *Farm) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
 	// TODO work out when to change strategies
-	res, err := f.inserter.Insert(members, maxSize)
+	res, err := f.inserter.Insert(ctx, members, maxSize)
 	return res, farm.PartialRepairError(err, func() {
-		f.Repair(s.KeyFieldScoreTxnValues(members).KeyFieldTxnValues(), maxSize)
+		f.Repair(context.Background(), s.KeyFieldScoreTxnValues(members).KeyFieldTxnValues(), maxSize)
+	})
+}
+
+// swapper defines a way to over write a member, only if it's still the version
+// that was read. Every insert strategy is also a swapper.
+type swapper interface {
+	Swap(context.Context, s.KeyFieldScoreTxnValue, float64, s.KeySizeExpiry) (int, error)
+}
+
+// Swap over writes the member, only if the member that's stored is still the
+// version (score) that was read. No changes are returned if it has since been
+// changed by something else.
+func (f *Farm) Swap(ctx context.Context, member s.KeyFieldScoreTxnValue, version float64, maxSize s.KeySizeExpiry) (int, error) {
+	sw, ok := f.inserter.(swapper)
+	if !ok {
+		return -1, c.ErrNotSwappable
+	}
+
+	res, err := sw.Swap(ctx, member, version, maxSize)
+	return res, farm.PartialRepairError(err, func() {
+		f.Repair(context.Background(), s.KeyFieldScoreTxnValues([]s.KeyFieldScoreTxnValue{member}).KeyFieldTxnValues(), maxSize)
 	})
 }
 
 // Delete removes a set of members associated with a key with in the store
-func (f *Farm) Delete(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+func (f *Farm) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
 	// TODO work out when to change strategies
-	res, err := f.deleter.Delete(members, maxSize)
+	res, err := f.deleter.Delete(ctx, members, maxSize)
 	return res, farm.PartialRepairError(err, func() {
-		f.Repair(s.KeyFieldScoreTxnValues(members).KeyFieldTxnValues(), maxSize)
+		f.Repair(context.Background(), s.KeyFieldScoreTxnValues(members).KeyFieldTxnValues(), maxSize)
 	})
 }
 
 // Select returns a member associated with a scire that's found with in the
 // storage
-func (f *Farm) Select(key bs.Key, field bs.Key) (s.KeyFieldScoreTxnValue, error) {
-	return f.selector.Select(key, field)
+func (f *Farm) Select(ctx context.Context, key bs.Key, field bs.Key) (s.KeyFieldScoreTxnValue, error) {
+	return f.selector.Select(ctx, key, field)
 }
 
 // SelectRange returns a list of members associated with a score that's found
 // with in the limit
-func (f *Farm) SelectRange(key bs.Key, limit int, maxSize s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
-	return f.selector.SelectRange(key, limit, maxSize)
+func (f *Farm) SelectRange(ctx context.Context, key bs.Key, limit int, maxSize s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
+	return f.selector.SelectRange(ctx, key, limit, maxSize)
 }
 
 // Keys returns all the keys with in the store
-func (f *Farm) Keys() ([]bs.Key, error) {
-	return f.scanner.Keys()
+func (f *Farm) Keys(ctx context.Context) ([]bs.Key, error) {
+	return f.scanner.Keys(ctx)
 }
 
 // Size defines a way to find the size associated with the key
-func (f *Farm) Size(key bs.Key) (int, error) {
-	res, err := f.scanner.Size(key)
+func (f *Farm) Size(ctx context.Context, key bs.Key) (int, error) {
+	res, err := f.scanner.Size(ctx, key)
 	return res, farm.PartialRepairError(err, func() {
 		//f.repairKey(key)
 	})
 }
 
 // Members defines a way to return all member keys associated with the key
-func (f *Farm) Members(key bs.Key) ([]bs.Key, error) {
-	return f.scanner.Members(key)
+func (f *Farm) Members(ctx context.Context, key bs.Key) ([]bs.Key, error) {
+	return f.scanner.Members(ctx, key)
 }
 
 // Repair attempts to repair the store depending on the elements
-func (f *Farm) Repair(elements []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
-	return f.repairer.Repair(elements, maxSize)
+func (f *Farm) Repair(ctx context.Context, elements []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
+	return f.repairer.Repair(ctx, elements, maxSize)
 }
 
+// Topology replaces the clusters of the farm, reporting the ranges of keys that
+// move between the hosts of a cluster. The keys that move are migrated to their
+// new hosts in the background, reading from both hosts until they have.
 func (f *Farm) Topology(clusters []c.Cluster) error {
-	for _, v := range f.clusters {
-		if err := v.Close(); err != nil {
-			return err
-		}
+	// The keys of the last migration are only held by the previous clusters,
+	// so it has to have run first.
+	if err := f.settle(); err != nil {
+		return err
+	}
+
+	reportMoved(f.Moved(clusters))
+
+	next, err := f.migrate(clusters)
+	if err != nil {
+		return err
 	}
 
-	f.clusters = clusters
+	f.clusters = next
+	f.breakers.Topology(topology(next))
 	return nil
 }
+
+func topology(clusters []c.Cluster) []interface{} {
+	return farm.Clusters(len(clusters), func(k int) interface{} {
+		return clusters[k]
+	})
+}
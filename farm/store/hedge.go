@@ -0,0 +1,225 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	t "github.com/SimonRichardson/echelon/cluster"
+	r "github.com/SimonRichardson/echelon/cluster/store"
+	"github.com/SimonRichardson/echelon/farm"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+const (
+	// defaultHedgePercentile defines the percentile of the observed latencies
+	// that a read waits for, before it's sent to a second cluster.
+	defaultHedgePercentile = 0.95
+
+	defaultHedgeSamples   = 1024
+	defaultHedgeMinimum   = 20
+	defaultHedgeRecompute = 64
+	defaultHedgeMinDelay  = time.Millisecond
+)
+
+// SelectHedgedReadOne defines a strategy to read from one cluster and, if it
+// hasn't answered by the time most reads have, to read from a second cluster
+// as well. The first valid answer is returned and the other read is abandoned.
+func SelectHedgedReadOne(f *Farm, t Tactic) s.Selector {
+	return selectHedgedReadOne{f, t, newLatencies(defaultHedgeSamples)}
+}
+
+type selectHedgedReadOne struct {
+	*Farm
+	tactic    Tactic
+	latencies *latencies
+}
+
+func (w selectHedgedReadOne) Select(ctx context.Context, key bs.Key, field bs.Key) (s.KeyFieldScoreTxnValue, error) {
+	return unwrapSelection(w.read(ctx, key, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Select(ctx, key, field)
+	}))
+}
+
+func (w selectHedgedReadOne) SelectRange(ctx context.Context, key bs.Key, limit int, maxSize s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
+	return w.read(ctx, key, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.SelectRange(ctx, key, limit, maxSize)
+	})
+}
+
+type hedgeResult struct {
+	members []s.KeyFieldScoreTxnValue
+	err     error
+	hedged  bool
+}
+
+func (w selectHedgedReadOne) read(ctx context.Context, key bs.Key,
+	fn func(context.Context, r.Cluster) <-chan t.Element,
+) ([]s.KeyFieldScoreTxnValue, error) {
+	clusters := w.Farm.clusters
+	if len(clusters) < 2 {
+		return selectOneReadOne{w.Farm, w.tactic}.read(ctx, key, fn)
+	}
+
+	began := beforeRead(w.Farm, 1, 1)
+	defer afterRead(w.Farm, began)
+
+	// The read that loses is cancelled once there's an answer.
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		selected, _ = selectClusters(clusters, 2)
+		delay       = w.latencies.Delay()
+
+		// The results are buffered, so that the read that's abandoned doesn't
+		// block once it does answer.
+		results = make(chan hedgeResult, len(selected))
+
+		pending = 0
+		fired   = false
+		err     error
+	)
+
+	dispatch := func(c r.Cluster, hedged bool) {
+		pending++
+		go func() {
+			var (
+				began    = time.Now()
+				elements = send(ctx, key, w.tactic, w.instrumentation, w.breakers, []r.Cluster{c}, 1, fn)
+				result   = hedgeResult{hedged: hedged}
+			)
+			for element := range elements {
+				if result.err = t.ErrorFromElement(element); result.err == nil {
+					result.members = t.ValuesFromElement(element)
+				}
+				break
+			}
+			go farm.Drain(elements)
+
+			switch elapsed := time.Since(began); {
+			case result.err == nil && ctx.Err() == nil:
+				w.latencies.Add(elapsed)
+			case ctx.Err() != nil && parent.Err() == nil:
+				// The read lost and was cancelled, it's still recorded as
+				// otherwise only the reads that won are observed and the
+				// delay keeps shrinking. How slow it really was isn't known,
+				// so it's capped at the delay.
+				if elapsed > delay {
+					elapsed = delay
+				}
+				w.latencies.Add(elapsed)
+			}
+			results <- result
+		}()
+	}
+	hedge := func() {
+		if fired {
+			return
+		}
+		fired = true
+		go w.instrumentation.HedgeFired()
+		dispatch(selected[1], true)
+	}
+
+	dispatch(selected[0], false)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for pending > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, farm.ContextError(ctx)
+
+		case <-timer.C:
+			hedge()
+
+		case result := <-results:
+			pending--
+			if err := farm.ContextError(ctx); err != nil {
+				return nil, err
+			}
+			if result.err != nil {
+				// Don't wait for the delay, if the first cluster has already
+				// failed.
+				err = result.err
+				hedge()
+				continue
+			}
+
+			if result.hedged {
+				go w.instrumentation.HedgeWon()
+			}
+
+			resultsRead(w.Farm, len(result.members), len(result.members))
+			return result.members, nil
+		}
+	}
+
+	return nil, err
+}
+
+// latencies holds the most recent latencies of the reads, from which the delay
+// before a read is hedged is derived.
+type latencies struct {
+	mutex   sync.Mutex
+	samples []time.Duration
+	next    int
+	added   int
+	delay   time.Duration
+}
+
+func newLatencies(size int) *latencies {
+	return &latencies{
+		samples: make([]time.Duration, 0, size),
+		delay:   defaultTimeoutLatency,
+	}
+}
+
+// Add a latency, replacing the oldest once there are enough of them.
+func (l *latencies) Add(d time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.samples) < cap(l.samples) {
+		l.samples = append(l.samples, d)
+	} else {
+		l.samples[l.next] = d
+		l.next = (l.next + 1) % len(l.samples)
+	}
+
+	// Sorting the samples for every read is wasteful, so the delay is only
+	// worked out every so often.
+	l.added++
+	if l.added == defaultHedgeMinimum ||
+		(l.added > defaultHedgeMinimum && l.added%defaultHedgeRecompute == 0) {
+		l.delay = l.percentile(defaultHedgePercentile)
+	}
+}
+
+// Delay returns how long to wait before a read is hedged. Until enough reads
+// have been observed, a read is hedged once the default timeout has passed.
+func (l *latencies) Delay() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.delay
+}
+
+func (l *latencies) percentile(p float64) time.Duration {
+	sorted := make([]time.Duration, len(l.samples))
+	copy(sorted, l.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	d := sorted[int(float64(len(sorted)-1)*p)]
+	if d < defaultHedgeMinDelay {
+		return defaultHedgeMinDelay
+	} else if d > defaultTimeoutLatency {
+		return defaultTimeoutLatency
+	}
+	return d
+}
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	alerts "github.com/SimonRichardson/echelon/alertmanager/noop"
+	"github.com/SimonRichardson/echelon/cluster"
+	r "github.com/SimonRichardson/echelon/cluster/store"
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/farm"
+	"github.com/SimonRichardson/echelon/instrumentation"
+	inoop "github.com/SimonRichardson/echelon/instrumentation/noop"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+// hedgeReads answers the reads in the order they're made, whichever cluster
+// they're made to, as the clusters that are read first are picked at random.
+type hedgeReads struct {
+	mutex sync.Mutex
+	reads []func(context.Context, bs.Key) cluster.Element
+}
+
+func (h *hedgeReads) next() func(context.Context, bs.Key) cluster.Element {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	fn := h.reads[0]
+	h.reads = h.reads[1:]
+	return fn
+}
+
+type hedgeCluster struct {
+	r.Cluster
+	reads *hedgeReads
+}
+
+func (c hedgeCluster) Select(ctx context.Context, key bs.Key, field bs.Key) <-chan cluster.Element {
+	var (
+		fn  = c.reads.next()
+		out = make(chan cluster.Element, 1)
+	)
+	go func() {
+		defer close(out)
+		out <- fn(ctx, key)
+	}()
+	return out
+}
+
+type hedgeInstrumentation struct {
+	instrumentation.Instrumentation
+	fired, won chan struct{}
+}
+
+func (i hedgeInstrumentation) HedgeFired() { i.fired <- struct{}{} }
+func (i hedgeInstrumentation) HedgeWon()   { i.won <- struct{}{} }
+
+func newHedgeSelector(delay time.Duration, reads ...func(context.Context, bs.Key) cluster.Element) (s.Selector, hedgeInstrumentation) {
+	var (
+		h     = &hedgeReads{reads: reads}
+		instr = hedgeInstrumentation{
+			Instrumentation: inoop.New(),
+			fired:           make(chan struct{}, 1),
+			won:             make(chan struct{}, 1),
+		}
+		f = &Farm{
+			clusters:        []r.Cluster{hedgeCluster{reads: h}, hedgeCluster{reads: h}},
+			breakers:        farm.NewBreakers("store", farm.BreakerOptions{}, alerts.New()),
+			instrumentation: instr,
+		}
+		l = newLatencies(defaultHedgeSamples)
+	)
+	l.delay = delay
+	return selectHedgedReadOne{f, nonBlocking, l}, instr
+}
+
+func answer(txn string) func(context.Context, bs.Key) cluster.Element {
+	return func(ctx context.Context, key bs.Key) cluster.Element {
+		return cluster.NewKeyFieldScoreTxnValue(key, []s.KeyFieldScoreTxnValue{
+			s.KeyFieldScoreTxnValue{Key: key, Field: "field", Score: 1, Txn: bs.Key(txn)},
+		})
+	}
+}
+
+func received(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	case <-time.After(time.Second):
+		return false
+	}
+}
+
+func TestLatenciesDelayBeforeMinimum(t *testing.T) {
+	l := newLatencies(defaultHedgeSamples)
+	for i := 1; i < defaultHedgeMinimum; i++ {
+		l.Add(time.Millisecond * 5)
+	}
+
+	if expected, actual := defaultTimeoutLatency, l.Delay(); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestLatenciesDelayPercentile(t *testing.T) {
+	l := newLatencies(defaultHedgeSamples)
+	for i := 1; i <= defaultHedgeRecompute; i++ {
+		l.Add(time.Millisecond * time.Duration(i))
+	}
+
+	if expected, actual := time.Millisecond*60, l.Delay(); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestLatenciesDelayBounds(t *testing.T) {
+	l := newLatencies(defaultHedgeSamples)
+	for i := 0; i < defaultHedgeMinimum; i++ {
+		l.Add(time.Microsecond)
+	}
+
+	if expected, actual := defaultHedgeMinDelay, l.Delay(); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestHedgedReadSlowFirstClusterIsHedged(t *testing.T) {
+	cancelled := make(chan struct{})
+	sel, instr := newHedgeSelector(time.Millisecond*10,
+		func(ctx context.Context, key bs.Key) cluster.Element {
+			// The first read never answers, until it's cancelled.
+			<-ctx.Done()
+			close(cancelled)
+			return cluster.NewErrorElement(key, ctx.Err())
+		},
+		answer("hedge"),
+	)
+
+	member, err := sel.Select(context.Background(), bs.Key("key"), bs.Key("field"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, actual := bs.Key("hedge"), member.Txn; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if !received(instr.fired) {
+		t.Error("Expected the hedge to be fired")
+	}
+	if !received(instr.won) {
+		t.Error("Expected the hedge to win")
+	}
+	if !received(cancelled) {
+		t.Error("Expected the losing read to be cancelled")
+	}
+}
+
+func TestHedgedReadFailedFirstClusterIsHedgedStraightAway(t *testing.T) {
+	var (
+		done   = make(chan struct{})
+		member s.KeyFieldScoreTxnValue
+		err    error
+	)
+
+	// The delay is far longer than the test, so the hedge is only fired in
+	// time if it doesn't wait for it.
+	sel, instr := newHedgeSelector(time.Hour,
+		func(ctx context.Context, key bs.Key) cluster.Element {
+			return cluster.NewErrorElement(key, typex.Errorf(errors.Source, errors.UnexpectedResults, "Failed"))
+		},
+		answer("hedge"),
+	)
+	go func() {
+		defer close(done)
+		member, err = sel.Select(context.Background(), bs.Key("key"), bs.Key("field"))
+	}()
+
+	if !received(done) {
+		t.Fatal("Expected the failed read to be hedged straight away")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, actual := bs.Key("hedge"), member.Txn; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if !received(instr.fired) {
+		t.Error("Expected the hedge to be fired")
+	}
+}
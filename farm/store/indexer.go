@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	t "github.com/SimonRichardson/echelon/cluster"
+	r "github.com/SimonRichardson/echelon/cluster/store"
+	"github.com/SimonRichardson/echelon/farm"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+// ErrNotIndexed defines an error where the clusters don't maintain the
+// secondary indexes.
+var ErrNotIndexed = r.ErrNotIndexed
+
+// Indexed looks up the fields of a key via a secondary index. The clusters are
+// read in parallel and their fields are merged, as a cluster that missed a
+// write can lag behind, so the fields are expected to be selected afterwards to
+// verify them. An error is only returned if every cluster failed.
+func (f *Farm) Indexed(ctx context.Context, key bs.Key, index s.Index, value bs.Key) ([]bs.Key, error) {
+	var (
+		clusters      = f.clusters
+		numOfClusters = len(clusters)
+
+		elements = make(chan t.Element, numOfClusters)
+		unique   = map[bs.Key]struct{}{}
+		result   = []bs.Key{}
+		errs     = []error{}
+
+		wg = sync.WaitGroup{}
+	)
+
+	wg.Add(numOfClusters)
+	go func() { wg.Wait(); close(elements) }()
+
+	// The breakers aren't used, as a cluster that isn't indexed rejects every
+	// lookup and that's not a failure of the cluster.
+	nonBlocking(clusters, func(k int, c r.Cluster) {
+		defer wg.Done()
+
+		fields, err := c.Indexed(key, index, value)
+		if err != nil {
+			elements <- t.NewErrorElement(key, err)
+			return
+		}
+		elements <- t.NewKeyElement(key, fields)
+	})
+
+	for element := range farm.Gather(ctx, elements) {
+		if err := t.ErrorFromElement(element); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, field := range t.KeysFromElement(element) {
+			if _, ok := unique[field]; ok {
+				continue
+			}
+			unique[field] = struct{}{}
+			result = append(result, field)
+		}
+	}
+
+	if err := farm.ContextError(ctx); err != nil {
+		return nil, err
+	}
+	if numOfClusters > 0 && len(errs) == numOfClusters {
+		return nil, errs[0]
+	}
+	return result, nil
+}
+
+// Reindex rebuilds the secondary indexes of a key that are missing or stale on
+// every cluster. It returns the number of fields that were rebuilt.
+func (f *Farm) Reindex(ctx context.Context, key bs.Key) (int, error) {
+	total := 0
+	for _, cluster := range f.clusters {
+		if err := farm.ContextError(ctx); err != nil {
+			return total, err
+		}
+
+		amount, err := cluster.Reindex(key)
+		if err != nil {
+			return total, err
+		}
+		total += amount
+	}
+	return total, nil
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	r "github.com/SimonRichardson/echelon/cluster/store"
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+type indexedCluster struct {
+	r.Cluster
+	fields []bs.Key
+	err    error
+}
+
+func (c indexedCluster) Indexed(bs.Key, s.Index, bs.Key) ([]bs.Key, error) {
+	return c.fields, c.err
+}
+
+func TestFarmIndexedMergesFields(t *testing.T) {
+	f := &Farm{clusters: []r.Cluster{
+		indexedCluster{fields: []bs.Key{"a", "b"}},
+		indexedCluster{fields: []bs.Key{"b", "c"}},
+	}}
+
+	fields, err := f.Indexed(context.Background(), bs.Key("key"), s.OwnerIndex, bs.Key("owner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, actual := 3, len(fields); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestFarmIndexedToleratesAFailedCluster(t *testing.T) {
+	f := &Farm{clusters: []r.Cluster{
+		indexedCluster{err: typex.Errorf(errors.Source, errors.UnexpectedResults, "Failed")},
+		indexedCluster{fields: []bs.Key{"a"}},
+	}}
+
+	fields, err := f.Indexed(context.Background(), bs.Key("key"), s.OwnerIndex, bs.Key("owner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, actual := 1, len(fields); expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestFarmIndexedFailsIfEveryClusterFails(t *testing.T) {
+	f := &Farm{clusters: []r.Cluster{
+		indexedCluster{err: ErrNotIndexed},
+		indexedCluster{err: ErrNotIndexed},
+	}}
+
+	if _, err := f.Indexed(context.Background(), bs.Key("key"), s.OwnerIndex, bs.Key("owner")); err != ErrNotIndexed {
+		t.Errorf("Expected: %v, Actual: %v", ErrNotIndexed, err)
+	}
+}
@@ -0,0 +1,364 @@
+package store
+
+import (
+	"context"
+	t "github.com/SimonRichardson/echelon/cluster"
+	r "github.com/SimonRichardson/echelon/cluster/store"
+	"github.com/SimonRichardson/echelon/farm"
+	"github.com/SimonRichardson/echelon/instrumentation"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+// migrating defines a cluster that's moving the keys that changed host from
+// the previous cluster. Whilst a key is still to be moved, it's read from both
+// of the clusters.
+type migrating struct {
+	r.Cluster
+	prev      r.Cluster
+	migration *farm.Migration
+	instr     instrumentation.Instrumentation
+}
+
+func (m *migrating) Moved() ([]bs.Key, error) {
+	return r.Moved(m.prev, m.Cluster)
+}
+
+func (m *migrating) Migrate(key bs.Key) (int, error) {
+	return r.Migrate(m.prev, m.Cluster, key)
+}
+
+func (m *migrating) Select(ctx context.Context, key bs.Key, field bs.Key) <-chan t.Element {
+	if !m.migration.Pending(key) {
+		return m.Cluster.Select(ctx, key, field)
+	}
+
+	go m.instr.MigrateDualRead()
+	return m.dual(ctx, key, 1, m.Cluster.Select(ctx, key, field), m.prev.Select(ctx, key, field))
+}
+
+func (m *migrating) SelectRange(ctx context.Context, key bs.Key, limit int, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	if !m.migration.Pending(key) {
+		return m.Cluster.SelectRange(ctx, key, limit, sizeExpiry)
+	}
+
+	go m.instr.MigrateDualRead()
+	return m.dual(ctx, key, limit, m.Cluster.SelectRange(ctx, key, limit, sizeExpiry), m.prev.SelectRange(ctx, key, limit, sizeExpiry))
+}
+
+func (m *migrating) Size(ctx context.Context, key bs.Key) <-chan t.Element {
+	if !m.migration.Pending(key) {
+		return m.Cluster.Size(ctx, key)
+	}
+
+	go m.instr.MigrateDualRead()
+	return m.dualFields(ctx, key, func(fields []bs.Key) t.Element {
+		return t.NewCountElement(key, len(fields))
+	})
+}
+
+func (m *migrating) Members(ctx context.Context, key bs.Key) <-chan t.Element {
+	if !m.migration.Pending(key) {
+		return m.Cluster.Members(ctx, key)
+	}
+
+	go m.instr.MigrateDualRead()
+	return m.dualFields(ctx, key, func(fields []bs.Key) t.Element {
+		return t.NewKeyElement(key, fields)
+	})
+}
+
+// Score uses the presence of the previous cluster for the members of a key
+// that's still to be moved, if it's newer than the presence of the next
+// cluster.
+func (m *migrating) Score(members []s.KeyFieldTxnValue) (map[s.KeyFieldTxnValue]s.Presence, error) {
+	result, err := m.Cluster.Score(members)
+	if err != nil {
+		return result, err
+	}
+
+	pending := make([]s.KeyFieldTxnValue, 0, len(members))
+	for _, v := range members {
+		if m.migration.Pending(v.Key) {
+			pending = append(pending, v)
+		}
+	}
+	if len(pending) < 1 {
+		return result, nil
+	}
+
+	go m.instr.MigrateDualRead()
+
+	prev, err := m.prev.Score(pending)
+	if err != nil {
+		teleprinter.L.Error().Printf("Unable to score migrating members : %s\n", err.Error())
+		return result, nil
+	}
+	for _, v := range pending {
+		if p, ok := prev[v]; ok && p.Present {
+			if n := result[v]; !n.Present || p.Score > n.Score {
+				result[v] = p
+			}
+		}
+	}
+	return result, nil
+}
+
+// Swap moves the key first if it's still to be moved, as the version that was
+// read might only be held by the previous cluster.
+func (m *migrating) Swap(ctx context.Context, member s.KeyFieldScoreTxnValue, version float64, sizeExpiry s.KeySizeExpiry) <-chan t.Element {
+	if m.migration.Pending(member.Key) {
+		if _, err := m.Migrate(member.Key); err != nil {
+			out := make(chan t.Element, 1)
+			out <- t.NewErrorElement(member.Key, err)
+			close(out)
+			return out
+		}
+	}
+	return m.Cluster.Swap(ctx, member, version, sizeExpiry)
+}
+
+// dual merges the members read from both of the clusters. The previous cluster
+// is only a fallback, so an error is only returned if the next cluster failed
+// and the previous cluster didn't have anything to make up for it.
+func (m *migrating) dual(ctx context.Context, key bs.Key, limit int, next, prev <-chan t.Element) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		nextValues, nextErr := collect(next)
+		prevValues, prevErr := collect(prev)
+
+		if prevErr != nil {
+			teleprinter.L.Error().Printf("Unable to read migrating key %s : %s\n", key.String(), prevErr.Error())
+		}
+		if nextErr != nil && (prevErr != nil || len(prevValues) < 1) {
+			t.Send(ctx, out, t.NewErrorElement(key, nextErr))
+			return
+		}
+
+		t.Send(ctx, out, t.NewKeyFieldScoreTxnValue(key, m.merge(nextValues, prevValues, limit)))
+	}()
+	return out
+}
+
+// dualFields merges the fields read from both of the clusters, in the same way
+// as dual merges the members.
+func (m *migrating) dualFields(ctx context.Context, key bs.Key, fn func([]bs.Key) t.Element) <-chan t.Element {
+	out := make(chan t.Element)
+	go func() {
+		defer close(out)
+
+		nextFields, nextErr := collectKeys(m.Cluster.Members(ctx, key))
+		prevFields, prevErr := collectKeys(m.prev.Members(ctx, key))
+
+		if prevErr != nil {
+			teleprinter.L.Error().Printf("Unable to read migrating key %s : %s\n", key.String(), prevErr.Error())
+		}
+		if nextErr != nil && (prevErr != nil || len(prevFields) < 1) {
+			t.Send(ctx, out, t.NewErrorElement(key, nextErr))
+			return
+		}
+
+		t.Send(ctx, out, fn(m.mergeFields(key, nextFields, prevFields)))
+	}()
+	return out
+}
+
+// mergeFields merges the fields of the clusters, a field that's only held by
+// the previous cluster is checked against the next cluster, as it might have
+// been deleted there since.
+func (m *migrating) mergeFields(key bs.Key, next, prev []bs.Key) []bs.Key {
+	var (
+		result  = make([]bs.Key, 0, len(next)+len(prev))
+		seen    = map[bs.Key]struct{}{}
+		missing = []s.KeyFieldTxnValue{}
+	)
+	for _, v := range next {
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	for _, v := range prev {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		missing = append(missing, s.KeyFieldTxnValue{Key: key, Field: v})
+	}
+
+	if len(missing) > 0 {
+		var (
+			prevPresence, prevErr = m.prev.Score(missing)
+			nextPresence, nextErr = m.Cluster.Score(missing)
+		)
+		for _, v := range missing {
+			if prevErr == nil && nextErr == nil {
+				if p, n := prevPresence[v], nextPresence[v]; n.Present && n.Score >= p.Score {
+					continue
+				}
+			}
+			result = append(result, v.Field)
+		}
+	}
+	return result
+}
+
+// merge the members of the clusters, a member that's only held by the previous
+// cluster is checked against the next cluster, as it might have been deleted
+// there since.
+func (m *migrating) merge(next, prev []s.KeyFieldScoreTxnValue, limit int) []s.KeyFieldScoreTxnValue {
+	var (
+		result  = make([]s.KeyFieldScoreTxnValue, 0, len(next)+len(prev))
+		indices = map[bs.Key]int{}
+		missing = []s.KeyFieldScoreTxnValue{}
+	)
+	for _, v := range next {
+		indices[v.Field] = len(result)
+		result = append(result, v)
+	}
+	for _, v := range prev {
+		if i, ok := indices[v.Field]; ok {
+			if v.Score > result[i].Score {
+				result[i] = v
+			}
+			continue
+		}
+		missing = append(missing, v)
+	}
+
+	if len(missing) > 0 {
+		presence, err := m.Cluster.Score(s.KeyFieldScoreTxnValues(missing).KeyFieldTxnValues())
+		for _, v := range missing {
+			if err == nil {
+				if p, ok := presence[v.KeyFieldTxnValue()]; ok && p.Present && p.Score >= v.Score {
+					continue
+				}
+			}
+			result = append(result, v)
+		}
+	}
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func collect(elements <-chan t.Element) ([]s.KeyFieldScoreTxnValue, error) {
+	var (
+		result = []s.KeyFieldScoreTxnValue{}
+		err    error
+	)
+	// The channel is always drained, so that the cluster doesn't block.
+	for e := range elements {
+		if elementErr := t.ErrorFromElement(e); elementErr != nil {
+			err = elementErr
+			continue
+		}
+		result = append(result, t.ValuesFromElement(e)...)
+	}
+	return result, err
+}
+
+func collectKeys(elements <-chan t.Element) ([]bs.Key, error) {
+	var (
+		result = []bs.Key{}
+		err    error
+	)
+	// The channel is always drained, so that the cluster doesn't block.
+	for e := range elements {
+		if elementErr := t.ErrorFromElement(e); elementErr != nil {
+			err = elementErr
+			continue
+		}
+		result = append(result, t.KeysFromElement(e)...)
+	}
+	return result, err
+}
+
+// migrate wraps the clusters that have keys to move from the current clusters.
+// The current clusters that have nothing to move are closed straight away,
+// otherwise they're closed once the next topology change has settled them. A
+// topology change that moves the keys of an OR-Set is refused, leaving the
+// current clusters in place, as the keys can't be migrated.
+func (f *Farm) migrate(clusters []r.Cluster) ([]r.Cluster, error) {
+	var (
+		prev       = f.clusters
+		next       = make([]r.Cluster, len(clusters))
+		migrations = []*migrating{}
+		closing    = []r.Cluster{}
+	)
+	for k, v := range clusters {
+		next[k] = v
+		if k >= len(prev) {
+			continue
+		}
+
+		m := &migrating{
+			Cluster: v,
+			prev:    prev[k],
+			instr:   f.instrumentation,
+		}
+		migration, err := farm.NewMigration(m)
+		if err != nil {
+			if err == r.ErrNotMigratable {
+				teleprinter.L.Error().Printf("Refusing topology, unable to migrate cluster %d : %s\n", k, err.Error())
+			}
+			return nil, err
+		}
+
+		if migration.Len() < 1 {
+			closing = append(closing, prev[k])
+			continue
+		}
+
+		m.migration = migration
+		next[k] = m
+		migrations = append(migrations, m)
+	}
+	for k := len(clusters); k < len(prev); k++ {
+		closing = append(closing, prev[k])
+	}
+
+	for _, v := range closing {
+		if err := v.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(migrations) > 0 {
+		go f.instrumentation.MigrateCall()
+	}
+	for _, v := range migrations {
+		go v.migration.Run(v, f.instrumentation, teleprinter.L.Error())
+	}
+
+	f.migrations = migrations
+	return next, nil
+}
+
+// settle waits for the migrations of the last topology change to run, before
+// closing the previous clusters and unwrapping the current ones.
+func (f *Farm) settle() error {
+	for _, m := range f.migrations {
+		m.migration.Wait()
+
+		if num := m.migration.Len(); num > 0 {
+			teleprinter.L.Error().Printf("Abandoning migration of %d keys\n", num)
+		}
+
+		if err := m.prev.Close(); err != nil {
+			return err
+		}
+	}
+
+	for k, v := range f.clusters {
+		if m, ok := v.(*migrating); ok {
+			f.clusters[k] = m.Cluster
+			f.breakers.Rekey(m, m.Cluster)
+		}
+	}
+	f.migrations = nil
+	return nil
+}
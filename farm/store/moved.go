@@ -0,0 +1,94 @@
+package store
+
+import (
+	"math"
+
+	c "github.com/SimonRichardson/echelon/cluster/store"
+	"github.com/SimonRichardson/echelon/internal/fusion"
+	"github.com/SimonRichardson/echelon/internal/logs/generic"
+	"github.com/SimonRichardson/echelon/internal/strategies"
+)
+
+// Movement defines a range of keys that moves between the hosts of a cluster.
+type Movement struct {
+	strategies.Movement
+	Cluster int
+}
+
+type router interface {
+	Routing() fusion.SelectionStrategy
+}
+
+// Moved returns the ranges of keys that move between the hosts of each cluster
+// when the clusters are replaced. Only the clusters that are routed with a
+// consistent hash ring can report the ranges, the others report nothing.
+func (f *Farm) Moved(clusters []c.Cluster) []Movement {
+	res := []Movement{}
+	for k, v := range clusters {
+		if k >= len(f.clusters) {
+			break
+		}
+
+		prev, ok := ring(f.clusters[k])
+		if !ok {
+			continue
+		}
+		next, ok := ring(v)
+		if !ok {
+			continue
+		}
+
+		for _, m := range prev.Moved(next) {
+			res = append(res, Movement{
+				Movement: m,
+				Cluster:  k,
+			})
+		}
+	}
+	return res
+}
+
+func ring(cluster c.Cluster) (*strategies.Ring, bool) {
+	r, ok := cluster.(router)
+	if !ok {
+		return nil, false
+	}
+	ring, ok := r.Routing().(*strategies.Ring)
+	return ring, ok
+}
+
+// host returns how a node is logged, the name of a node is only used if it
+// doesn't have a host.
+func host(node fusion.Node) string {
+	if node.Host != "" {
+		return node.Host
+	}
+	return node.Name
+}
+
+// reportMoved logs how much of the ring moves between each of the hosts, as
+// the ranges themselves are too many to be of use in a log.
+func reportMoved(movements []Movement) {
+	type route struct {
+		cluster  int
+		from, to string
+	}
+
+	var (
+		routes = []route{}
+		shares = map[route]float64{}
+	)
+	for _, v := range movements {
+		r := route{v.Cluster, host(v.From), host(v.To)}
+		if _, ok := shares[r]; !ok {
+			routes = append(routes, r)
+		}
+		// Unsigned subtraction wraps around the ring by itself.
+		shares[r] += float64(v.End-v.Start) / math.MaxUint32
+	}
+
+	for _, r := range routes {
+		teleprinter.L.Info().Printf("Topology moved %.2f%% of cluster %d from %s to %s\n",
+			shares[r]*100, r.cluster, r.from, r.to)
+	}
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
 	s "github.com/SimonRichardson/echelon/selectors"
 )
@@ -29,34 +30,38 @@ type noop struct {
 	*Farm
 }
 
-func (n noop) Insert([]s.KeyFieldScoreTxnValue, s.KeySizeExpiry) (int, error) {
+func (n noop) Insert(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) (int, error) {
 	return 0, nil
 }
 
-func (n noop) Delete([]s.KeyFieldScoreTxnValue, s.KeySizeExpiry) (int, error) {
+func (n noop) Swap(context.Context, s.KeyFieldScoreTxnValue, float64, s.KeySizeExpiry) (int, error) {
 	return 0, nil
 }
 
-func (n noop) Rollback([]s.KeyFieldScoreTxnValue, s.KeySizeExpiry) error {
+func (n noop) Delete(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) (int, error) {
+	return 0, nil
+}
+
+func (n noop) Rollback(context.Context, []s.KeyFieldScoreTxnValue, s.KeySizeExpiry) error {
 	return nil
 }
 
-func (n noop) SelectRange(bs.Key, int, s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
+func (n noop) SelectRange(context.Context, bs.Key, int, s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
 	return make([]s.KeyFieldScoreTxnValue, 0, 0), nil
 }
 
-func (n noop) Keys() ([]bs.Key, error) {
+func (n noop) Keys(context.Context) ([]bs.Key, error) {
 	return make([]bs.Key, 0, 0), nil
 }
 
-func (n noop) Size(bs.Key) (int, error) {
+func (n noop) Size(context.Context, bs.Key) (int, error) {
 	return 0, nil
 }
 
-func (n noop) Members(bs.Key) ([]bs.Key, error) {
+func (n noop) Members(context.Context, bs.Key) ([]bs.Key, error) {
 	return make([]bs.Key, 0, 0), nil
 }
 
-func (n noop) Repair([]s.KeyFieldTxnValue, s.KeySizeExpiry) error {
+func (n noop) Repair(context.Context, []s.KeyFieldTxnValue, s.KeySizeExpiry) error {
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/SimonRichardson/echelon/common"
 	"github.com/SimonRichardson/echelon/env"
 	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/permitters"
 	r "github.com/SimonRichardson/echelon/internal/redis"
 	s "github.com/SimonRichardson/echelon/selectors"
 	"github.com/SimonRichardson/echelon/internal/typex"
@@ -19,13 +20,17 @@ var (
 
 // ParseString parses various inputs and returns a slice of clusters that we can
 // then use with in the farm.
-// - addresses is a semi-colon separated string of redis addresses
+// - addresses is a semi-colon separated string of redis addresses, or
+//   mem://name addresses for clusters that are held in memory
 // - connectTimeout, readTimeout and writeTimeout is a set of durations in
 //   string format
 // - poolRoutingStrategy defines a strategy for how the pool routing works
+// - setStrategy defines which set semantics the clusters use when resolving
+//   concurrent insertions and deletions
 func ParseString(addresses string,
 	connectTimeout, readTimeout, writeTimeout string,
 	poolRoutingStrategy string,
+	setStrategy string,
 	maxSize int,
 	opts *r.RedisOptions,
 	creator r.RedisCreator,
@@ -44,7 +49,23 @@ func ParseString(addresses string,
 		return empty, err
 	}
 
+	constructor, err := parseSetStrategy(setStrategy)
+	if err != nil {
+		return empty, err
+	}
+
 	for i, address := range strings.Split(common.StripWhitespace(addresses), ";") {
+		if name, ok, err := r.MemoryHost(address); err != nil {
+			return empty, err
+		} else if ok {
+			if common.Normalise(setStrategy) != "lwwelementset" {
+				return empty, typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
+					"Memory cluster %d (%q) only supports the LWWElementSet strategy", i+1, address)
+			}
+			clusters = append(clusters, c.NewMemory(name))
+			continue
+		}
+
 		hosts := []string{}
 		for _, host := range strings.Split(address, ",") {
 			if len(host) < 1 {
@@ -61,7 +82,7 @@ func ParseString(addresses string,
 				"Empty cluster %d (%q)", i+1, address)
 		}
 
-		clusters = append(clusters, c.New(
+		clusters = append(clusters, constructor(
 			r.New(hosts, strategy, timeouts, maxSize, creator),
 		))
 	}
@@ -74,6 +95,17 @@ func ParseString(addresses string,
 	return clusters, nil
 }
 
+func parseSetStrategy(strategy string) (func(*r.Pool) c.Cluster, error) {
+	switch common.Normalise(strategy) {
+	case "lwwelementset":
+		return c.New, nil
+	case "orset":
+		return c.NewORSet, nil
+	}
+	return c.New, typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
+		"Invalid set store strategy %q", strategy)
+}
+
 type selectStategyOpts struct {
 	Strategy func(*Farm, Tactic) s.Selector
 	Tactic   Tactic
@@ -116,14 +148,18 @@ type repairStategyOpts struct {
 
 func (o repairStategyOpts) Apply(f *Farm) s.Repairer { return o.Strategy(f, o.Tactic) }
 
-func ParseSelectStrategy(opts env.StrategyOptions) (selectStategyOpts, error) {
+func ParseSelectStrategy(opts env.StrategyOptions, repair env.StrategyOptions) (selectStategyOpts, error) {
 	var (
 		strategy selectStrategy
 		tactic   Tactic
+		permits  permitters.Permitter
 		err      error
 	)
 
-	if strategy, err = parseSelectStrategy(opts.Strategy, opts.Quorum); err != nil {
+	if permits, err = readPermits(repair.RequestsPerDuration, repair.RequestsDuration); err != nil {
+		return selectStategyOpts{}, err
+	}
+	if strategy, err = parseSelectStrategy(opts.Strategy, opts.Quorum, permits); err != nil {
 		return selectStategyOpts{}, err
 	}
 	if tactic, err = readTactic(opts.Tactic,
@@ -216,16 +252,18 @@ func ParseRepairStrategy(opts env.StrategyOptions) (repairStategyOpts, error) {
 	return repairStategyOpts{strategy, tactic}, nil
 }
 
-func parseSelectStrategy(strategy string, quorum float64) (selectStrategy, error) {
+func parseSelectStrategy(strategy string, quorum float64, permits permitters.Permitter) (selectStrategy, error) {
 	switch common.Normalise(strategy) {
 	case "noop":
 		return NoopSelector, nil
 	case "selectonereadone":
 		return SelectOneReadOne, nil
+	case "selecthedgedreadone":
+		return SelectHedgedReadOne, nil
 	case "selectallreadall":
-		return SelectAllReadAll, nil
+		return SelectAllReadAll(permits), nil
 	case "selectquorumreadall":
-		return SelectQuorumReadAll(quorum), nil
+		return SelectQuorumReadAll(quorum, permits), nil
 	}
 	return NoopSelector, typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
 		"Invalid store strategy %q", strategy)
@@ -279,6 +317,16 @@ func parseRepairStrategy(strategy string, quorum float64) (repairStrategy, error
 		"Invalid store strategy %q", strategy)
 }
 
+func readPermits(requestsPerDuration int,
+	requestsDuration string,
+) (permitters.Permitter, error) {
+	dur, err := time.ParseDuration(requestsDuration)
+	if err != nil {
+		return nil, err
+	}
+	return permitters.New(int64(requestsPerDuration), dur), nil
+}
+
 func readTactic(tactic string,
 	requestsPerDuration int,
 	requestsDuration string,
@@ -29,8 +29,10 @@ func TestParseFarmString(t *testing.T) {
 			farmString,
 			"1s", "1s", "1s",
 			"RoundRobin",
+			"LWWElementSet",
 			1,
 			nil,
+			nil,
 		)
 		if expected.success && err != nil {
 			t.Errorf("%q: %s", farmString, err)
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"math"
 	"math/rand"
 	"sync"
@@ -12,6 +13,7 @@ import (
 	"github.com/SimonRichardson/echelon/errors"
 	"github.com/SimonRichardson/echelon/farm"
 	"github.com/SimonRichardson/echelon/instrumentation"
+	"github.com/SimonRichardson/echelon/internal/permitters"
 	s "github.com/SimonRichardson/echelon/selectors"
 	"github.com/SimonRichardson/echelon/internal/typex"
 )
@@ -31,17 +33,18 @@ func SelectOneReadOne(f *Farm, t Tactic) s.Selector {
 }
 
 // SelectQuorumReadAll defines a strategy to write to just the minimum quorum of
-// servers and then read all the results back.
-func SelectQuorumReadAll(quorum float64) func(f *Farm, t Tactic) s.Selector {
+// servers and then read all the results back. Any divergence between the
+// results is repaired in the background, for as long as the permits allow it.
+func SelectQuorumReadAll(quorum float64, permits permitters.Permitter) func(f *Farm, t Tactic) s.Selector {
 	return func(f *Farm, t Tactic) s.Selector {
-		return selectQuorumReadAll{f, t, quorum}
+		return selectQuorumReadAll{f, t, quorum, permits}
 	}
 }
 
 // SelectAllReadAll defines a strategy to write to all of servers and then read
 // all the results back.
-func SelectAllReadAll(f *Farm, t Tactic) s.Selector {
-	return selectQuorumReadAll{f, t, 1.0}
+func SelectAllReadAll(permits permitters.Permitter) func(f *Farm, t Tactic) s.Selector {
+	return SelectQuorumReadAll(1.0, permits)
 }
 
 type selectOneReadOne struct {
@@ -49,20 +52,20 @@ type selectOneReadOne struct {
 	tactic Tactic
 }
 
-func (w selectOneReadOne) Select(key bs.Key, field bs.Key) (s.KeyFieldScoreTxnValue, error) {
-	return unwrapSelection(w.read(key, func(c r.Cluster) <-chan t.Element {
-		return c.Select(key, field)
+func (w selectOneReadOne) Select(ctx context.Context, key bs.Key, field bs.Key) (s.KeyFieldScoreTxnValue, error) {
+	return unwrapSelection(w.read(ctx, key, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Select(ctx, key, field)
 	}))
 }
 
-func (w selectOneReadOne) SelectRange(key bs.Key, limit int, maxSize s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
-	return w.read(key, func(c r.Cluster) <-chan t.Element {
-		return c.SelectRange(key, limit, maxSize)
+func (w selectOneReadOne) SelectRange(ctx context.Context, key bs.Key, limit int, maxSize s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
+	return w.read(ctx, key, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.SelectRange(ctx, key, limit, maxSize)
 	})
 }
 
-func (w selectOneReadOne) read(key bs.Key,
-	fn func(r.Cluster) <-chan t.Element,
+func (w selectOneReadOne) read(ctx context.Context, key bs.Key,
+	fn func(context.Context, r.Cluster) <-chan t.Element,
 ) ([]s.KeyFieldScoreTxnValue, error) {
 	var (
 		clusters      = w.Farm.clusters
@@ -74,7 +77,7 @@ func (w selectOneReadOne) read(key bs.Key,
 
 	var (
 		selected = []r.Cluster{clusters[rand.Intn(len(clusters))]}
-		elements = send(key, w.tactic, w.instrumentation, selected, numOfClusters, fn)
+		elements = send(ctx, key, w.tactic, w.instrumentation, w.breakers, selected, numOfClusters, fn)
 
 		response  = []s.KeyFieldScoreTxnValue{}
 		retrieved = 0
@@ -100,29 +103,34 @@ func (w selectOneReadOne) read(key bs.Key,
 		break
 	}
 
+	if err := farm.ContextError(ctx); err != nil {
+		return nil, err
+	}
+
 	resultsRead(w.Farm, retrieved, returned)
 	return response, nil
 }
 
 type selectQuorumReadAll struct {
 	*Farm
-	tactic Tactic
-	quorum float64
+	tactic  Tactic
+	quorum  float64
+	permits permitters.Permitter
 }
 
-func (w selectQuorumReadAll) Select(key bs.Key, field bs.Key) (s.KeyFieldScoreTxnValue, error) {
-	return unwrapSelection(w.read(func(c r.Cluster) <-chan t.Element {
-		return c.Select(key, field)
+func (w selectQuorumReadAll) Select(ctx context.Context, key bs.Key, field bs.Key) (s.KeyFieldScoreTxnValue, error) {
+	return unwrapSelection(w.read(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Select(ctx, key, field)
 	}, defaultLimit, s.MakeKeySizeSingleton(key, defaultMaxSize, defaultExpiry)))
 }
 
-func (w selectQuorumReadAll) SelectRange(key bs.Key, limit int, maxSize s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
-	return w.read(func(c r.Cluster) <-chan t.Element {
-		return c.SelectRange(key, limit, maxSize)
+func (w selectQuorumReadAll) SelectRange(ctx context.Context, key bs.Key, limit int, maxSize s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
+	return w.read(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.SelectRange(ctx, key, limit, maxSize)
 	}, limit, maxSize)
 }
 
-func (w selectQuorumReadAll) read(fn func(r.Cluster) <-chan t.Element, limit int, maxSize s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
+func (w selectQuorumReadAll) read(ctx context.Context, fn func(context.Context, r.Cluster) <-chan t.Element, limit int, maxSize s.KeySizeExpiry) ([]s.KeyFieldScoreTxnValue, error) {
 	var (
 		clusters      = w.Farm.clusters
 		numOfClusters = int(math.Ceil(float64(len(clusters)) * w.quorum))
@@ -146,11 +154,11 @@ func (w selectQuorumReadAll) read(fn func(r.Cluster) <-chan t.Element, limit int
 
 	go func() { wg.Wait(); close(elements) }()
 
-	if err := scatterReads(w.tactic, w.instrumentation, selected, fn, wg, elements); err != nil {
+	if err := scatterReads(ctx, w.tactic, w.instrumentation, w.breakers, selected, fn, wg, elements); err != nil {
 		return []s.KeyFieldScoreTxnValue{}, err
 	}
 
-	for element := range elements {
+	for element := range farm.Gather(ctx, elements) {
 		var (
 			members      = t.ValuesFromElement(element)
 			numOfMembers = len(members)
@@ -168,6 +176,10 @@ func (w selectQuorumReadAll) read(fn func(r.Cluster) <-chan t.Element, limit int
 		}
 	}
 
+	if err := farm.ContextError(ctx); err != nil {
+		return nil, err
+	}
+
 	var (
 		union, difference = farm.UnionDifference(responses)
 		response          = union.OrderedLimitedSlice(limit)
@@ -177,8 +189,16 @@ func (w selectQuorumReadAll) read(fn func(r.Cluster) <-chan t.Element, limit int
 
 	repairs.AddMany(difference)
 
-	if len(repairs) > 0 {
-		go w.Farm.Repair(repairs.Slice(), maxSize)
+	if num := len(repairs); num > 0 {
+		go w.Farm.instrumentation.RepairDivergent(num)
+
+		// The repairs are discarded rather than queued, as the next read of
+		// the same members will find them again.
+		if w.permits.Allowed(int64(num)) {
+			go w.Farm.Repair(context.Background(), repairs.Slice(), maxSize)
+		} else {
+			go w.Farm.instrumentation.RepairDiscarded(num)
+		}
 	}
 
 	resultsRead(w.Farm, retrieved, returned)
@@ -229,12 +249,13 @@ func afterRead(f *Farm, began time.Time) {
 	}()
 }
 
-func send(key bs.Key,
+func send(ctx context.Context, key bs.Key,
 	tactic Tactic,
 	instr instrumentation.Instrumentation,
+	breakers *farm.Breakers,
 	clusters []r.Cluster,
 	waitFor int,
-	fn func(r.Cluster) <-chan t.Element,
+	fn func(context.Context, r.Cluster) <-chan t.Element,
 ) <-chan t.Element {
 	elements := make(chan t.Element, waitFor)
 
@@ -242,18 +263,20 @@ func send(key bs.Key,
 	wg.Add(waitFor)
 	go func() { wg.Wait(); close(elements) }()
 
-	if err := scatterReads(tactic, instr, clusters, fn, &wg, elements); err != nil {
+	if err := scatterReads(ctx, tactic, instr, breakers, clusters, fn, &wg, elements); err != nil {
 		elements <- t.NewErrorElement(key, err)
 	}
 
-	return elements
+	return farm.Gather(ctx, elements)
 }
 
 func scatterReads(
+	ctx context.Context,
 	tactic Tactic,
 	instr instrumentation.Instrumentation,
+	breakers *farm.Breakers,
 	clusters []r.Cluster,
-	fn func(r.Cluster) <-chan t.Element,
+	fn func(context.Context, r.Cluster) <-chan t.Element,
 	wg *sync.WaitGroup,
 	dst chan t.Element,
 ) error {
@@ -265,7 +288,7 @@ func scatterReads(
 			go instr.ClusterDuration(k, time.Since(began))
 		}()
 
-		for e := range fn(c) {
+		for e := range breakers.Guard(ctx, c, func() <-chan t.Element { return fn(ctx, c) }) {
 			dst <- e
 		}
 	})
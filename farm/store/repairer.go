@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"log"
 	"strings"
 	"sync"
@@ -23,7 +24,7 @@ type repair struct {
 	tactic Tactic
 }
 
-func (w repair) Repair(keyFieldTxnValue []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
+func (w repair) Repair(ctx context.Context, keyFieldTxnValue []s.KeyFieldTxnValue, maxSize s.KeySizeExpiry) error {
 	var (
 		clusters      = w.Farm.clusters
 		numOfClusters = len(clusters)
@@ -92,6 +93,7 @@ func (w repair) Repair(keyFieldTxnValue []s.KeyFieldTxnValue, maxSize s.KeySizeE
 			Key:   keyFieldTxnValue.Key,
 			Field: keyFieldTxnValue.Field,
 			Score: highestScore,
+			Txn:   keyFieldTxnValue.Txn,
 			Value: keyFieldTxnValue.Value,
 		}
 
@@ -117,27 +119,23 @@ func (w repair) Repair(keyFieldTxnValue []s.KeyFieldTxnValue, maxSize s.KeySizeE
 
 	errs := []string{}
 	for index, keyFieldScoreTxnValues := range inserts {
-		elements := clusters[index].Insert(keyFieldScoreTxnValues, maxSize)
+		elements := clusters[index].Insert(ctx, keyFieldScoreTxnValues, maxSize)
 		for e := range elements {
-			func(e t.Element) {
-				if err := t.ErrorFromElement(e); err != nil {
-					errs = append(errs, err.Error())
-				}
-			}(e)
+			if err := t.ErrorFromElement(e); err != nil {
+				errs = append(errs, err.Error())
+			}
 		}
+		wg.Done()
 	}
 
 	for index, keyFieldScoreTxnValues := range deletes {
-		elements := clusters[index].Delete(keyFieldScoreTxnValues, maxSize)
+		elements := clusters[index].Delete(ctx, keyFieldScoreTxnValues, maxSize)
 		for e := range elements {
-			func(e t.Element) {
-				defer wg.Done()
-
-				if err := t.ErrorFromElement(e); err != nil {
-					errs = append(errs, err.Error())
-				}
-			}(e)
+			if err := t.ErrorFromElement(e); err != nil {
+				errs = append(errs, err.Error())
+			}
 		}
+		wg.Done()
 	}
 
 	if timeout(wg, defaultTimeoutLatency) {
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -24,25 +25,25 @@ type scanAllReadAll struct {
 	tactic Tactic
 }
 
-func (w scanAllReadAll) Keys() ([]bs.Key, error) {
-	return w.readKeys(func(c r.Cluster) <-chan t.Element {
-		return c.Keys()
+func (w scanAllReadAll) Keys(ctx context.Context) ([]bs.Key, error) {
+	return w.readKeys(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Keys(ctx)
 	})
 }
 
-func (w scanAllReadAll) Size(key bs.Key) (int, error) {
-	return w.readInt(func(c r.Cluster) <-chan t.Element {
-		return c.Size(key)
+func (w scanAllReadAll) Size(ctx context.Context, key bs.Key) (int, error) {
+	return w.readInt(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Size(ctx, key)
 	})
 }
 
-func (w scanAllReadAll) Members(key bs.Key) ([]bs.Key, error) {
-	return w.readKeys(func(c r.Cluster) <-chan t.Element {
-		return c.Members(key)
+func (w scanAllReadAll) Members(ctx context.Context, key bs.Key) ([]bs.Key, error) {
+	return w.readKeys(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Members(ctx, key)
 	})
 }
 
-func (w scanAllReadAll) readKeys(fn func(r.Cluster) <-chan t.Element) ([]bs.Key, error) {
+func (w scanAllReadAll) readKeys(ctx context.Context, fn func(context.Context, r.Cluster) <-chan t.Element) ([]bs.Key, error) {
 	var (
 		clusters      = w.Farm.clusters
 		numOfClusters = len(clusters)
@@ -64,11 +65,11 @@ func (w scanAllReadAll) readKeys(fn func(r.Cluster) <-chan t.Element) ([]bs.Key,
 	wg.Add(numOfClusters)
 	go func() { wg.Wait(); close(elements) }()
 
-	if err := scatterReads(w.tactic, w.instrumentation, clusters, fn, &wg, elements); err != nil {
+	if err := scatterReads(ctx, w.tactic, w.instrumentation, w.breakers, clusters, fn, &wg, elements); err != nil {
 		return nil, err
 	}
 
-	for element := range elements {
+	for element := range farm.Gather(ctx, elements) {
 		var (
 			keys      = t.KeysFromElement(element)
 			numOfKeys = len(keys)
@@ -86,11 +87,15 @@ func (w scanAllReadAll) readKeys(fn func(r.Cluster) <-chan t.Element) ([]bs.Key,
 		}
 	}
 
+	if err := farm.ContextError(ctx); err != nil {
+		return nil, err
+	}
+
 	resultsScan(w.Farm, retrieved, returned)
 	return responses, nil
 }
 
-func (w scanAllReadAll) readInt(fn func(r.Cluster) <-chan t.Element) (int, error) {
+func (w scanAllReadAll) readInt(ctx context.Context, fn func(context.Context, r.Cluster) <-chan t.Element) (int, error) {
 	var (
 		clusters      = w.Farm.clusters
 		numOfClusters = len(clusters)
@@ -116,11 +121,11 @@ func (w scanAllReadAll) readInt(fn func(r.Cluster) <-chan t.Element) (int, error
 	wg.Add(numOfClusters)
 	go func() { wg.Wait(); close(elements) }()
 
-	if err := scatterReads(w.tactic, w.instrumentation, clusters, fn, &wg, elements); err != nil {
+	if err := scatterReads(ctx, w.tactic, w.instrumentation, w.breakers, clusters, fn, &wg, elements); err != nil {
 		return -1, err
 	}
 
-	for element := range elements {
+	for element := range farm.Gather(ctx, elements) {
 		amount := t.AmountFromElement(element)
 		retrieved++
 
@@ -133,6 +138,10 @@ func (w scanAllReadAll) readInt(fn func(r.Cluster) <-chan t.Element) (int, error
 		changes = append(changes, amount)
 	}
 
+	if err := farm.ContextError(ctx); err != nil {
+		return -1, err
+	}
+
 	defer resultsScan(w.Farm, retrieved, returned)
 
 	response, err := master.Value()
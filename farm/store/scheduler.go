@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	r "github.com/SimonRichardson/echelon/cluster/store"
+	"github.com/SimonRichardson/echelon/farm"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+// ErrNotScheduled defines an error where the clusters don't maintain the expiry
+// schedule.
+var ErrNotScheduled = r.ErrNotScheduled
+
+// Due returns the members that are due to expire by now, in the order that
+// they expired. The members of every cluster are merged, keeping the member
+// with the highest score, as a cluster that missed a write can lag behind.
+func (f *Farm) Due(ctx context.Context, now time.Time, limit int) ([]s.KeyFieldScoreTxnValueExpiry, error) {
+	var (
+		unique = map[s.KeyField]int{}
+		result = []s.KeyFieldScoreTxnValueExpiry{}
+	)
+	for _, cluster := range f.clusters {
+		if err := farm.ContextError(ctx); err != nil {
+			return nil, err
+		}
+
+		members, err := cluster.Due(now, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, member := range members {
+			kf := s.KeyField{Key: member.Key, Field: member.Field}
+			if i, ok := unique[kf]; ok {
+				if member.Score > result[i].Score {
+					result[i] = member
+				}
+				continue
+			}
+			unique[kf] = len(result)
+			result = append(result, member)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Expiry.Before(result[j].Expiry)
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// Postpone moves the members that are still scheduled on any of the clusters,
+// so that they're not due again until then.
+func (f *Farm) Postpone(ctx context.Context, members []s.KeyField, until time.Time) error {
+	for _, cluster := range f.clusters {
+		if err := farm.ContextError(ctx); err != nil {
+			return err
+		}
+
+		if err := cluster.Postpone(members, until); err != nil {
+			return err
+		}
+	}
+	return nil
+}
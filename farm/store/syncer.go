@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	"github.com/SimonRichardson/echelon/internal/merkle"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+// Sync defines a way to perform anti-entropy for a key. It compares the hash
+// trees of the key on every cluster and only repairs the members that are with
+// in the buckets that differ. It returns the number of members that were sent
+// to be repaired.
+func (f *Farm) Sync(ctx context.Context, key bs.Key, buckets int, maxSize s.KeySizeExpiry) (int, error) {
+	clusters := f.clusters
+	if len(clusters) < 2 {
+		return 0, nil
+	}
+
+	trees := make([]merkle.Tree, len(clusters))
+	for k, cluster := range clusters {
+		tree, err := cluster.Summary(key, buckets)
+		if err != nil {
+			return 0, err
+		}
+		trees[k] = tree
+	}
+
+	unique := map[int]struct{}{}
+	for _, tree := range trees[1:] {
+		for _, index := range merkle.Diff(trees[0], tree) {
+			unique[index] = struct{}{}
+		}
+	}
+
+	if len(unique) < 1 {
+		return 0, nil
+	}
+
+	indices := make([]int, 0, len(unique))
+	for index := range unique {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+
+	// Only the member with the highest score is used for the repair, as the
+	// repair uses the value it's given.
+	members := map[bs.Key]s.KeyFieldScoreTxnValue{}
+	for _, cluster := range clusters {
+		values, err := cluster.Divergent(key, buckets, indices)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, v := range values {
+			if m, ok := members[v.Field]; !ok || v.Score > m.Score {
+				members[v.Field] = v
+			}
+		}
+	}
+
+	values := make([]s.KeyFieldScoreTxnValue, 0, len(members))
+	for _, v := range members {
+		values = append(values, v)
+	}
+
+	if err := f.Repair(ctx, s.KeyFieldScoreTxnValues(values).KeyFieldTxnValues(), maxSize); err != nil {
+		return 0, err
+	}
+
+	return len(values), nil
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"math"
 	"sync"
 	"time"
@@ -57,24 +58,30 @@ type writeAllReadAll struct {
 	tactic Tactic
 }
 
-func (w writeAllReadAll) Insert(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
-	return w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Insert(members, maxSize)
+func (w writeAllReadAll) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Insert(ctx, members, maxSize)
 	})
 }
 
-func (w writeAllReadAll) Delete(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
-	return w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Delete(members, maxSize)
+func (w writeAllReadAll) Swap(ctx context.Context, member s.KeyFieldScoreTxnValue, version float64, maxSize s.KeySizeExpiry) (int, error) {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Swap(ctx, member, version, maxSize)
 	})
 }
 
-func (w writeAllReadAll) Rollback(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
-	_, err := w.Delete(members, maxSize)
+func (w writeAllReadAll) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Delete(ctx, members, maxSize)
+	})
+}
+
+func (w writeAllReadAll) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
+	_, err := w.Delete(ctx, members, maxSize)
 	return err
 }
 
-func (w writeAllReadAll) write(fn func(r.Cluster) <-chan t.Element) (int, error) {
+func (w writeAllReadAll) write(ctx context.Context, fn func(context.Context, r.Cluster) <-chan t.Element) (int, error) {
 	var (
 		clusters      = w.Farm.clusters
 		numOfClusters = len(clusters)
@@ -100,11 +107,11 @@ func (w writeAllReadAll) write(fn func(r.Cluster) <-chan t.Element) (int, error)
 	wg.Add(numOfClusters)
 	go func() { wg.Wait(); close(elements) }()
 
-	if err := scatterWrites(w.tactic, w.instrumentation, clusters, fn, wg, elements); err != nil {
+	if err := scatterWrites(ctx, w.tactic, w.instrumentation, w.breakers, clusters, fn, wg, elements); err != nil {
 		return -1, err
 	}
 
-	for element := range elements {
+	for element := range farm.Gather(ctx, elements) {
 		amount := t.AmountFromElement(element)
 		retrieved += amount
 
@@ -120,6 +127,10 @@ func (w writeAllReadAll) write(fn func(r.Cluster) <-chan t.Element) (int, error)
 		changes = append(changes, amount)
 	}
 
+	if err := farm.ContextError(ctx); err != nil {
+		return -1, err
+	}
+
 	// If the repair is fale, then go through it
 	if len(errs) > 0 {
 		repairWrite(w.Farm, w.wtype)
@@ -148,24 +159,30 @@ type writeAllReadQuorum struct {
 	quorum float64
 }
 
-func (w writeAllReadQuorum) Insert(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
-	return w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Insert(members, maxSize)
+func (w writeAllReadQuorum) Insert(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Insert(ctx, members, maxSize)
 	})
 }
 
-func (w writeAllReadQuorum) Delete(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
-	return w.write(func(c r.Cluster) <-chan t.Element {
-		return c.Delete(members, maxSize)
+func (w writeAllReadQuorum) Swap(ctx context.Context, member s.KeyFieldScoreTxnValue, version float64, maxSize s.KeySizeExpiry) (int, error) {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Swap(ctx, member, version, maxSize)
 	})
 }
 
-func (w writeAllReadQuorum) Rollback(members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
-	_, err := w.Delete(members, maxSize)
+func (w writeAllReadQuorum) Delete(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) (int, error) {
+	return w.write(ctx, func(ctx context.Context, c r.Cluster) <-chan t.Element {
+		return c.Delete(ctx, members, maxSize)
+	})
+}
+
+func (w writeAllReadQuorum) Rollback(ctx context.Context, members []s.KeyFieldScoreTxnValue, maxSize s.KeySizeExpiry) error {
+	_, err := w.Delete(ctx, members, maxSize)
 	return err
 }
 
-func (w writeAllReadQuorum) write(fn func(r.Cluster) <-chan t.Element) (int, error) {
+func (w writeAllReadQuorum) write(ctx context.Context, fn func(context.Context, r.Cluster) <-chan t.Element) (int, error) {
 	var (
 		clusters           = w.Farm.clusters
 		numOfClusters      = int(math.Ceil(float64(len(clusters)) * w.quorum))
@@ -192,11 +209,11 @@ func (w writeAllReadQuorum) write(fn func(r.Cluster) <-chan t.Element) (int, err
 	wg.Add(numOfClusters)
 	go func() { wg.Wait(); close(elements) }()
 
-	if err := scatterWrites(w.tactic, w.instrumentation, selected, fn, wg, elements); err != nil {
+	if err := scatterWrites(ctx, w.tactic, w.instrumentation, w.breakers, selected, fn, wg, elements); err != nil {
 		return -1, err
 	}
 
-	for element := range elements {
+	for element := range farm.Gather(ctx, elements) {
 		amount := t.AmountFromElement(element)
 		retrieved += amount
 
@@ -212,6 +229,10 @@ func (w writeAllReadQuorum) write(fn func(r.Cluster) <-chan t.Element) (int, err
 		changes = append(changes, amount)
 	}
 
+	if err := farm.ContextError(ctx); err != nil {
+		return -1, err
+	}
+
 	// If the repair is fale, then go through it
 	if len(errs) > 0 {
 		repairWrite(w.Farm, w.wtype)
@@ -242,7 +263,9 @@ func (w writeAllReadQuorum) write(fn func(r.Cluster) <-chan t.Element) (int, err
 		wg.Add(len(deferred))
 		go func() { wg.Wait(); close(elements) }()
 
-		if err := scatterWrites(w.tactic, w.instrumentation, deferred, fn, wg, elements); err != nil {
+		// The rest of the quorum is written to after the caller has had its
+		// answer, so the write can't be bound to the context of the caller.
+		if err := scatterWrites(context.Background(), w.tactic, w.instrumentation, w.breakers, deferred, fn, wg, elements); err != nil {
 			return
 		}
 
@@ -262,10 +285,12 @@ func (w writeAllReadQuorum) write(fn func(r.Cluster) <-chan t.Element) (int, err
 }
 
 func scatterWrites(
+	ctx context.Context,
 	tactic Tactic,
 	instr instrumentation.Instrumentation,
+	breakers *farm.Breakers,
 	clusters []r.Cluster,
-	fn func(r.Cluster) <-chan t.Element,
+	fn func(context.Context, r.Cluster) <-chan t.Element,
 	wg *sync.WaitGroup,
 	dst chan t.Element,
 ) error {
@@ -277,7 +302,7 @@ func scatterWrites(
 			go instr.ClusterDuration(k, time.Since(began))
 		}()
 
-		for e := range fn(c) {
+		for e := range breakers.Guard(ctx, c, func() <-chan t.Element { return fn(ctx, c) }) {
 			dst <- e
 		}
 	})
@@ -68,55 +68,56 @@ func (m KeyFieldTxnValueSet) Slice() []s.KeyFieldTxnValue {
 	return a
 }
 
+// UnionDifference merges the sets that were read from the clusters. The union
+// holds a single winner for every key and field, the one with the highest score
+// (ties are broken by the txn and then the value, so every reader picks the same
+// winner). The difference holds the winners that any of the sets diverge from,
+// either by missing them or by holding a different score, txn or value, so that
+// the clusters can be repaired with them.
 func UnionDifference(sets []TupleSet) (TupleSet, KeyFieldTxnValueSet) {
 	var (
-		expectedCount = len(sets)
-		scores        = map[s.KeyFieldTxnValue]float64{}
-		counts        = map[s.KeyFieldScoreTxnValue]int{}
+		winners = map[s.KeyField]s.KeyFieldScoreTxnValue{}
+		counts  = map[s.KeyFieldScoreTxnValue]int{}
 	)
 
 	for _, set := range sets {
 		for tuple := range set {
-			// union
-			keyFieldTxnValue := s.KeyFieldTxnValue{
-				Key:   tuple.Key,
-				Field: tuple.Field,
-				Txn:   tuple.Txn,
-				Value: tuple.Value,
-			}
-			if score, ok := scores[keyFieldTxnValue]; !ok || tuple.Score > score {
-				scores[keyFieldTxnValue] = tuple.Score
+			keyField := s.KeyField{Key: tuple.Key, Field: tuple.Field}
+			if winner, ok := winners[keyField]; !ok || wins(tuple, winner) {
+				winners[keyField] = tuple
 			}
 
-			// difference
 			counts[tuple]++
 		}
 	}
 
 	var (
-		union      = make(TupleSet, len(scores))
-		difference = make(KeyFieldTxnValueSet, len(counts))
+		union      = make(TupleSet, len(winners))
+		difference = KeyFieldTxnValueSet{}
 	)
 
-	for keyFieldTxnValue, bestScore := range scores {
-		union.Add(s.KeyFieldScoreTxnValue{
-			Key:   keyFieldTxnValue.Key,
-			Field: keyFieldTxnValue.Field,
-			Score: bestScore,
-			Txn:   keyFieldTxnValue.Txn,
-			Value: keyFieldTxnValue.Value,
-		})
-	}
+	for _, winner := range winners {
+		union.Add(winner)
 
-	for keyFieldScoreTxnValue, count := range counts {
-		if count < expectedCount {
+		if counts[winner] < len(sets) {
 			difference.Add(s.KeyFieldTxnValue{
-				Key:   keyFieldScoreTxnValue.Key,
-				Field: keyFieldScoreTxnValue.Field,
-				Value: keyFieldScoreTxnValue.Value,
+				Key:   winner.Key,
+				Field: winner.Field,
+				Txn:   winner.Txn,
+				Value: winner.Value,
 			})
 		}
 	}
 
 	return union, difference
 }
+
+func wins(a, b s.KeyFieldScoreTxnValue) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Txn != b.Txn {
+		return a.Txn > b.Txn
+	}
+	return a.Value > b.Value
+}
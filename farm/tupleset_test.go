@@ -0,0 +1,54 @@
+package farm
+
+import (
+	"testing"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	s "github.com/SimonRichardson/echelon/selectors"
+)
+
+func TestUnionDifferenceSimilar(t *testing.T) {
+	member := s.KeyFieldScoreTxnValue{Key: bs.Key("a"), Field: bs.Key("b"), Score: 1, Txn: bs.Key("t"), Value: "v"}
+
+	union, difference := UnionDifference([]TupleSet{
+		MakeSet([]s.KeyFieldScoreTxnValue{member}),
+		MakeSet([]s.KeyFieldScoreTxnValue{member}),
+	})
+
+	if expected, actual := 1, len(union); expected != actual {
+		t.Errorf("Expected: %d, Actual: %d", expected, actual)
+	}
+	if expected, actual := 0, len(difference); expected != actual {
+		t.Errorf("Expected: %d, Actual: %d", expected, actual)
+	}
+}
+
+func TestUnionDifferenceDivergent(t *testing.T) {
+	var (
+		stale   = s.KeyFieldScoreTxnValue{Key: bs.Key("a"), Field: bs.Key("b"), Score: 1, Txn: bs.Key("t1"), Value: "old"}
+		winner  = s.KeyFieldScoreTxnValue{Key: bs.Key("a"), Field: bs.Key("b"), Score: 2, Txn: bs.Key("t2"), Value: "new"}
+		missing = s.KeyFieldScoreTxnValue{Key: bs.Key("a"), Field: bs.Key("c"), Score: 1, Txn: bs.Key("t3"), Value: "v"}
+	)
+
+	union, difference := UnionDifference([]TupleSet{
+		MakeSet([]s.KeyFieldScoreTxnValue{stale, missing}),
+		MakeSet([]s.KeyFieldScoreTxnValue{winner}),
+	})
+
+	if expected, actual := 2, len(union); expected != actual {
+		t.Fatalf("Expected: %d, Actual: %d", expected, actual)
+	}
+	if !union.Has(winner) || !union.Has(missing) {
+		t.Errorf("Expected union to hold the winners, Actual: %v", union.Slice())
+	}
+
+	for _, v := range []s.KeyFieldScoreTxnValue{winner, missing} {
+		kftv := s.KeyFieldTxnValue{Key: v.Key, Field: v.Field, Txn: v.Txn, Value: v.Value}
+		if _, ok := difference[kftv]; !ok {
+			t.Errorf("Expected difference to hold %v, Actual: %v", kftv, difference.Slice())
+		}
+	}
+	if expected, actual := 2, len(difference); expected != actual {
+		t.Errorf("Expected: %d, Actual: %d", expected, actual)
+	}
+}
@@ -17,5 +17,7 @@ require (
 	github.com/prometheus/client_golang v1.6.0
 	github.com/spf13/viper v1.7.0
 	github.com/tsenart/tb v0.0.0-20181025101425-0d2499c8b6e9
+	github.com/vmihailenco/msgpack v4.0.1+incompatible
+	google.golang.org/grpc v1.21.1
 	gopkg.in/mgo.v2 v2.0.0-20190816093944-a6b53ec6cb22
 )
@@ -16,6 +16,9 @@ type Instrumentation interface {
 	SelectInstrumentation
 	ScanInstrumentation
 	RepairInstrumentation
+	MigrateInstrumentation
+	SweepInstrumentation
+	HedgeInstrumentation
 	PerformanceDuration
 	PublishInstrumentation
 	consul.Instrumentation
@@ -29,6 +32,8 @@ type ClusterInstrumentation interface {
 type AggregateInstrumentation interface {
 	AInsertCall()
 	AInsertDuration(time.Duration)
+	ABatchCall()
+	ABatchDuration(time.Duration)
 	AModifyCall()
 	AModifyDuration(time.Duration)
 	AModifyWithOperationsCall()
@@ -49,8 +54,16 @@ type AggregateInstrumentation interface {
 	AMembersDuration(time.Duration)
 	ARepairCall()
 	ARepairDuration(time.Duration)
+	ASyncCall()
+	ASyncDuration(time.Duration)
+	AChangesCall()
+	AChangesDuration(time.Duration)
+	AReindexCall()
+	AReindexDuration(time.Duration)
 	AQueryCall()
 	AQueryDuration(time.Duration)
+	AWaitlistCall()
+	AWaitlistDuration(time.Duration)
 	APauseCall()
 	AResumeCall()
 	ATopologyCall()
@@ -136,6 +149,32 @@ type RepairInstrumentation interface {
 	RepairDuration(time.Duration)
 	RepairScoreError()
 	RepairError(int)
+	RepairDivergent(int)
+	RepairDiscarded(int)
+}
+
+type MigrateInstrumentation interface {
+	MigrateCall()
+	MigrateKeys(int)
+	MigrateMoved(int)
+	MigrateError(int)
+	MigrateDualRead()
+	MigrateDuration(time.Duration)
+}
+
+type SweepInstrumentation interface {
+	SweepCall()
+	SweepAcquired()
+	SweepHandover()
+	SweepExpired()
+	SweepDeleted(int)
+	SweepLag(time.Duration)
+	SweepDuration(time.Duration)
+}
+
+type HedgeInstrumentation interface {
+	HedgeFired()
+	HedgeWon()
 }
 
 type PerformanceDuration interface {
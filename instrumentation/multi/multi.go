@@ -36,6 +36,16 @@ func (i instrument) AInsertDuration(t time.Duration) {
 		v.AInsertDuration(t)
 	}
 }
+func (i instrument) ABatchCall() {
+	for _, v := range i.instruments {
+		v.ABatchCall()
+	}
+}
+func (i instrument) ABatchDuration(t time.Duration) {
+	for _, v := range i.instruments {
+		v.ABatchDuration(t)
+	}
+}
 func (i instrument) AModifyCall() {
 	for _, v := range i.instruments {
 		v.AModifyCall()
@@ -136,6 +146,36 @@ func (i instrument) ARepairDuration(t time.Duration) {
 		v.ARepairDuration(t)
 	}
 }
+func (i instrument) ASyncCall() {
+	for _, v := range i.instruments {
+		v.ASyncCall()
+	}
+}
+func (i instrument) ASyncDuration(t time.Duration) {
+	for _, v := range i.instruments {
+		v.ASyncDuration(t)
+	}
+}
+func (i instrument) AChangesCall() {
+	for _, v := range i.instruments {
+		v.AChangesCall()
+	}
+}
+func (i instrument) AChangesDuration(t time.Duration) {
+	for _, v := range i.instruments {
+		v.AChangesDuration(t)
+	}
+}
+func (i instrument) AReindexCall() {
+	for _, v := range i.instruments {
+		v.AReindexCall()
+	}
+}
+func (i instrument) AReindexDuration(t time.Duration) {
+	for _, v := range i.instruments {
+		v.AReindexDuration(t)
+	}
+}
 func (i instrument) AQueryCall() {
 	for _, v := range i.instruments {
 		v.AQueryCall()
@@ -146,6 +186,16 @@ func (i instrument) AQueryDuration(t time.Duration) {
 		v.AQueryDuration(t)
 	}
 }
+func (i instrument) AWaitlistCall() {
+	for _, v := range i.instruments {
+		v.AWaitlistCall()
+	}
+}
+func (i instrument) AWaitlistDuration(t time.Duration) {
+	for _, v := range i.instruments {
+		v.AWaitlistDuration(t)
+	}
+}
 func (i instrument) APauseCall() {
 	for _, v := range i.instruments {
 		v.APauseCall()
@@ -527,6 +577,108 @@ func (i instrument) RepairError(n int) {
 	}
 }
 
+func (i instrument) RepairDivergent(n int) {
+	for _, v := range i.instruments {
+		v.RepairDivergent(n)
+	}
+}
+
+func (i instrument) RepairDiscarded(n int) {
+	for _, v := range i.instruments {
+		v.RepairDiscarded(n)
+	}
+}
+
+func (i instrument) MigrateCall() {
+	for _, v := range i.instruments {
+		v.MigrateCall()
+	}
+}
+
+func (i instrument) MigrateKeys(n int) {
+	for _, v := range i.instruments {
+		v.MigrateKeys(n)
+	}
+}
+
+func (i instrument) MigrateMoved(n int) {
+	for _, v := range i.instruments {
+		v.MigrateMoved(n)
+	}
+}
+
+func (i instrument) MigrateError(n int) {
+	for _, v := range i.instruments {
+		v.MigrateError(n)
+	}
+}
+
+func (i instrument) MigrateDualRead() {
+	for _, v := range i.instruments {
+		v.MigrateDualRead()
+	}
+}
+
+func (i instrument) MigrateDuration(t time.Duration) {
+	for _, v := range i.instruments {
+		v.MigrateDuration(t)
+	}
+}
+
+func (i instrument) SweepCall() {
+	for _, v := range i.instruments {
+		v.SweepCall()
+	}
+}
+
+func (i instrument) SweepAcquired() {
+	for _, v := range i.instruments {
+		v.SweepAcquired()
+	}
+}
+
+func (i instrument) SweepHandover() {
+	for _, v := range i.instruments {
+		v.SweepHandover()
+	}
+}
+
+func (i instrument) SweepExpired() {
+	for _, v := range i.instruments {
+		v.SweepExpired()
+	}
+}
+
+func (i instrument) SweepDeleted(n int) {
+	for _, v := range i.instruments {
+		v.SweepDeleted(n)
+	}
+}
+
+func (i instrument) SweepLag(t time.Duration) {
+	for _, v := range i.instruments {
+		v.SweepLag(t)
+	}
+}
+
+func (i instrument) SweepDuration(t time.Duration) {
+	for _, v := range i.instruments {
+		v.SweepDuration(t)
+	}
+}
+
+func (i instrument) HedgeFired() {
+	for _, v := range i.instruments {
+		v.HedgeFired()
+	}
+}
+
+func (i instrument) HedgeWon() {
+	for _, v := range i.instruments {
+		v.HedgeWon()
+	}
+}
+
 func (i instrument) PerformanceDuration(t time.Duration) {
 	for _, v := range i.instruments {
 		v.PerformanceDuration(t)
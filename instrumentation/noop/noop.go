@@ -17,6 +17,8 @@ func (i instrument) ClusterDuration(int, time.Duration) {}
 
 func (i instrument) AInsertCall()                                {}
 func (i instrument) AInsertDuration(time.Duration)               {}
+func (i instrument) ABatchCall()                                 {}
+func (i instrument) ABatchDuration(time.Duration)                {}
 func (i instrument) AModifyCall()                                {}
 func (i instrument) AModifyDuration(time.Duration)               {}
 func (i instrument) AModifyWithOperationsCall()                  {}
@@ -37,8 +39,16 @@ func (i instrument) AMembersCall()                               {}
 func (i instrument) AMembersDuration(time.Duration)              {}
 func (i instrument) ARepairCall()                                {}
 func (i instrument) ARepairDuration(time.Duration)               {}
+func (i instrument) ASyncCall()                                  {}
+func (i instrument) ASyncDuration(time.Duration)                 {}
+func (i instrument) AChangesCall()                               {}
+func (i instrument) AChangesDuration(time.Duration)              {}
+func (i instrument) AReindexCall()                               {}
+func (i instrument) AReindexDuration(time.Duration)              {}
 func (i instrument) AQueryCall()                                 {}
 func (i instrument) AQueryDuration(time.Duration)                {}
+func (i instrument) AWaitlistCall()                              {}
+func (i instrument) AWaitlistDuration(time.Duration)             {}
 func (i instrument) APauseCall()                                 {}
 func (i instrument) AResumeCall()                                {}
 func (i instrument) ATopologyCall()                              {}
@@ -110,6 +120,26 @@ func (i instrument) RepairSendTo(int)             {}
 func (i instrument) RepairDuration(time.Duration) {}
 func (i instrument) RepairScoreError()            {}
 func (i instrument) RepairError(int)              {}
+func (i instrument) RepairDivergent(int)          {}
+func (i instrument) RepairDiscarded(int)          {}
+
+func (i instrument) MigrateCall()                  {}
+func (i instrument) MigrateKeys(int)               {}
+func (i instrument) MigrateMoved(int)              {}
+func (i instrument) MigrateError(int)              {}
+func (i instrument) MigrateDualRead()              {}
+func (i instrument) MigrateDuration(time.Duration) {}
+
+func (i instrument) SweepCall()                  {}
+func (i instrument) SweepAcquired()              {}
+func (i instrument) SweepHandover()              {}
+func (i instrument) SweepExpired()               {}
+func (i instrument) SweepDeleted(int)            {}
+func (i instrument) SweepLag(time.Duration)      {}
+func (i instrument) SweepDuration(time.Duration) {}
+
+func (i instrument) HedgeFired() {}
+func (i instrument) HedgeWon()   {}
 
 func (i instrument) PerformanceDuration(t time.Duration)                     {}
 func (i instrument) PerformanceNamespaceDuration(ns string, t time.Duration) {}
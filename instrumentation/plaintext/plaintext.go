@@ -30,6 +30,14 @@ func (i instrument) AInsertDuration(t time.Duration) {
 	fmt.Fprintf(i, "aggregate_insert.duration %d\n", t.Nanoseconds()/1e6)
 }
 
+func (i instrument) ABatchCall() {
+	fmt.Fprintf(i, "aggregate_batch.call.count 1\n")
+}
+
+func (i instrument) ABatchDuration(t time.Duration) {
+	fmt.Fprintf(i, "aggregate_batch.duration %d\n", t.Nanoseconds()/1e6)
+}
+
 func (i instrument) AModifyCall() {
 	fmt.Fprintf(i, "aggregate_modify.call.count 1\n")
 }
@@ -110,6 +118,30 @@ func (i instrument) ARepairDuration(t time.Duration) {
 	fmt.Fprintf(i, "aggregate_repair.duration %d\n", t.Nanoseconds()/1e6)
 }
 
+func (i instrument) ASyncCall() {
+	fmt.Fprintf(i, "aggregate_sync.call.count 1\n")
+}
+
+func (i instrument) ASyncDuration(t time.Duration) {
+	fmt.Fprintf(i, "aggregate_sync.duration %d\n", t.Nanoseconds()/1e6)
+}
+
+func (i instrument) AChangesCall() {
+	fmt.Fprintf(i, "aggregate_changes.call.count 1\n")
+}
+
+func (i instrument) AChangesDuration(t time.Duration) {
+	fmt.Fprintf(i, "aggregate_changes.duration %d\n", t.Nanoseconds()/1e6)
+}
+
+func (i instrument) AReindexCall() {
+	fmt.Fprintf(i, "aggregate_reindex.call.count 1\n")
+}
+
+func (i instrument) AReindexDuration(t time.Duration) {
+	fmt.Fprintf(i, "aggregate_reindex.duration %d\n", t.Nanoseconds()/1e6)
+}
+
 func (i instrument) AQueryCall() {
 	fmt.Fprintf(i, "aggregate_query.call.count 1\n")
 }
@@ -118,6 +150,14 @@ func (i instrument) AQueryDuration(t time.Duration) {
 	fmt.Fprintf(i, "aggregate_query.duration %d\n", t.Nanoseconds()/1e6)
 }
 
+func (i instrument) AWaitlistCall() {
+	fmt.Fprintf(i, "aggregate_waitlist.call.count 1\n")
+}
+
+func (i instrument) AWaitlistDuration(t time.Duration) {
+	fmt.Fprintf(i, "aggregate_waitlist.duration %d\n", t.Nanoseconds()/1e6)
+}
+
 func (i instrument) APauseCall() {
 	fmt.Fprintf(i, "aggregate_pause.call.count 1\n")
 }
@@ -374,6 +414,74 @@ func (i instrument) RepairError(n int) {
 	fmt.Fprintf(i, "repair.error.count %d\n", n)
 }
 
+func (i instrument) RepairDivergent(n int) {
+	fmt.Fprintf(i, "repair.divergent.count %d\n", n)
+}
+
+func (i instrument) RepairDiscarded(n int) {
+	fmt.Fprintf(i, "repair.discarded.count %d\n", n)
+}
+
+func (i instrument) MigrateCall() {
+	fmt.Fprintf(i, "migrate.call.count 1\n")
+}
+
+func (i instrument) MigrateKeys(n int) {
+	fmt.Fprintf(i, "migrate.keys.count %d\n", n)
+}
+
+func (i instrument) MigrateMoved(n int) {
+	fmt.Fprintf(i, "migrate.moved.count %d\n", n)
+}
+
+func (i instrument) MigrateError(n int) {
+	fmt.Fprintf(i, "migrate.error.count %d\n", n)
+}
+
+func (i instrument) MigrateDualRead() {
+	fmt.Fprintf(i, "migrate.dual_read.count 1\n")
+}
+
+func (i instrument) MigrateDuration(t time.Duration) {
+	fmt.Fprintf(i, "migrate.duration %d\n", t.Nanoseconds()/1e6)
+}
+
+func (i instrument) SweepCall() {
+	fmt.Fprintf(i, "sweep.call.count 1\n")
+}
+
+func (i instrument) SweepAcquired() {
+	fmt.Fprintf(i, "sweep.acquired.count 1\n")
+}
+
+func (i instrument) SweepHandover() {
+	fmt.Fprintf(i, "sweep.handover.count 1\n")
+}
+
+func (i instrument) SweepExpired() {
+	fmt.Fprintf(i, "sweep.expired.count 1\n")
+}
+
+func (i instrument) SweepDeleted(n int) {
+	fmt.Fprintf(i, "sweep.deleted.count %d\n", n)
+}
+
+func (i instrument) SweepLag(t time.Duration) {
+	fmt.Fprintf(i, "sweep.lag.duration %d\n", t.Nanoseconds()/1e6)
+}
+
+func (i instrument) SweepDuration(t time.Duration) {
+	fmt.Fprintf(i, "sweep.duration %d\n", t.Nanoseconds()/1e6)
+}
+
+func (i instrument) HedgeFired() {
+	fmt.Fprintf(i, "hedge.fired.count 1\n")
+}
+
+func (i instrument) HedgeWon() {
+	fmt.Fprintf(i, "hedge.won.count 1\n")
+}
+
 func (i instrument) PerformanceDuration(t time.Duration) {
 	fmt.Fprintf(i, "performance.duration %d\n", t.Nanoseconds()/1e6)
 }
@@ -19,6 +19,8 @@ type instrument struct {
 
 	aInsertCall                   prometheus.Counter
 	aInsertDuration               prometheus.Summary
+	aBatchCall                    prometheus.Counter
+	aBatchDuration                prometheus.Summary
 	aModifyCall                   prometheus.Counter
 	aModifyDuration               prometheus.Summary
 	aModifyWithOperationsCall     prometheus.Counter
@@ -39,8 +41,16 @@ type instrument struct {
 	aMembersDuration              prometheus.Summary
 	aRepairCall                   prometheus.Counter
 	aRepairDuration               prometheus.Summary
+	aSyncCall                     prometheus.Counter
+	aSyncDuration                 prometheus.Summary
+	aChangesCall                  prometheus.Counter
+	aChangesDuration              prometheus.Summary
+	aReindexCall                  prometheus.Counter
+	aReindexDuration              prometheus.Summary
 	aQueryCall                    prometheus.Counter
 	aQueryDuration                prometheus.Summary
+	aWaitlistCall                 prometheus.Counter
+	aWaitlistDuration             prometheus.Summary
 	aPauseCall                    prometheus.Counter
 	aResumeCall                   prometheus.Counter
 	aTopologyCall                 prometheus.Counter
@@ -113,8 +123,28 @@ type instrument struct {
 	repairSendTo     prometheus.Counter
 	repairScoreError prometheus.Counter
 	repairError      prometheus.Counter
+	repairDivergent  prometheus.Counter
+	repairDiscarded  prometheus.Counter
 	repairDuration   prometheus.Summary
 
+	migrateCall     prometheus.Counter
+	migrateKeys     prometheus.Counter
+	migrateMoved    prometheus.Counter
+	migrateError    prometheus.Counter
+	migrateDualRead prometheus.Counter
+	migrateDuration prometheus.Summary
+
+	sweepCall     prometheus.Counter
+	sweepAcquired prometheus.Counter
+	sweepHandover prometheus.Counter
+	sweepExpired  prometheus.Counter
+	sweepDeleted  prometheus.Counter
+	sweepLag      prometheus.Summary
+	sweepDuration prometheus.Summary
+
+	hedgeFired prometheus.Counter
+	hedgeWon   prometheus.Counter
+
 	performanceDuration          prometheus.Summary
 	performanceNamespaceDuration map[string]prometheus.Summary
 
@@ -164,6 +194,17 @@ func New(prefix string, maxSummaryAge time.Duration) instrumentation.Instrumenta
 			Help:      "How long the aggregate insertion calls took in nanoseconds.",
 			MaxAge:    maxSummaryAge,
 		}),
+		aBatchCall: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "aggregate_batch_call_count",
+			Help:      "How many aggregate batch calls have been made.",
+		}),
+		aBatchDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace: prefix,
+			Name:      "aggregate_batch_call_duration",
+			Help:      "How long the aggregate batch calls took in nanoseconds.",
+			MaxAge:    maxSummaryAge,
+		}),
 		aModifyCall: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: prefix,
 			Name:      "aggregate_modify_call_count",
@@ -274,6 +315,39 @@ func New(prefix string, maxSummaryAge time.Duration) instrumentation.Instrumenta
 			Help:      "How long the aggregate repair calls took in nanoseconds.",
 			MaxAge:    maxSummaryAge,
 		}),
+		aSyncCall: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "aggregate_sync_call_count",
+			Help:      "How many aggregate sync calls have been made.",
+		}),
+		aSyncDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace: prefix,
+			Name:      "aggregate_sync_call_duration",
+			Help:      "How long the aggregate sync calls took in nanoseconds.",
+			MaxAge:    maxSummaryAge,
+		}),
+		aChangesCall: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "aggregate_changes_call_count",
+			Help:      "How many aggregate changes calls have been made.",
+		}),
+		aChangesDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace: prefix,
+			Name:      "aggregate_changes_call_duration",
+			Help:      "How long the aggregate changes calls took in nanoseconds.",
+			MaxAge:    maxSummaryAge,
+		}),
+		aReindexCall: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "aggregate_reindex_call_count",
+			Help:      "How many aggregate reindex calls have been made.",
+		}),
+		aReindexDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace: prefix,
+			Name:      "aggregate_reindex_call_duration",
+			Help:      "How long the aggregate reindex calls took in nanoseconds.",
+			MaxAge:    maxSummaryAge,
+		}),
 		aQueryCall: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: prefix,
 			Name:      "aggregate_query_call_count",
@@ -285,6 +359,17 @@ func New(prefix string, maxSummaryAge time.Duration) instrumentation.Instrumenta
 			Help:      "How long the aggregate query calls took in nanoseconds.",
 			MaxAge:    maxSummaryAge,
 		}),
+		aWaitlistCall: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "aggregate_waitlist_call_count",
+			Help:      "How many aggregate waitlist calls have been made.",
+		}),
+		aWaitlistDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace: prefix,
+			Name:      "aggregate_waitlist_call_duration",
+			Help:      "How long the aggregate waitlist calls took in nanoseconds.",
+			MaxAge:    maxSummaryAge,
+		}),
 		aResumeCall: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: prefix,
 			Name:      "aggregate_resume_call_count",
@@ -625,6 +710,16 @@ func New(prefix string, maxSummaryAge time.Duration) instrumentation.Instrumenta
 			Name:      "repair_error_count",
 			Help:      "How many repair error have been made.",
 		}),
+		repairDivergent: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "repair_divergent_count",
+			Help:      "How many divergent members have been found by a read.",
+		}),
+		repairDiscarded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "repair_discarded_count",
+			Help:      "How many read repairs have been discarded by the rate limit.",
+		}),
 		repairDuration: prometheus.NewSummary(prometheus.SummaryOpts{
 			Namespace: prefix,
 			Name:      "repair_call_duration",
@@ -632,6 +727,87 @@ func New(prefix string, maxSummaryAge time.Duration) instrumentation.Instrumenta
 			MaxAge:    maxSummaryAge,
 		}),
 
+		migrateCall: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "migrate_call_count",
+			Help:      "How many migrations have been started.",
+		}),
+		migrateKeys: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "migrate_keys_count",
+			Help:      "How many keys have been found to move.",
+		}),
+		migrateMoved: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "migrate_moved_count",
+			Help:      "How many keys have been moved.",
+		}),
+		migrateError: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "migrate_error_count",
+			Help:      "How many keys have failed to move.",
+		}),
+		migrateDualRead: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "migrate_dual_read_count",
+			Help:      "How many reads have been made from both the old and the new hosts.",
+		}),
+		migrateDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace: prefix,
+			Name:      "migrate_call_duration",
+			Help:      "How long the migrations took in nanoseconds.",
+			MaxAge:    maxSummaryAge,
+		}),
+
+		sweepCall: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "sweep_call_count",
+			Help:      "How many sweeps have been run.",
+		}),
+		sweepAcquired: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "sweep_acquired_count",
+			Help:      "How many shard leases have been acquired.",
+		}),
+		sweepHandover: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "sweep_handover_count",
+			Help:      "How many shard leases have been handed over.",
+		}),
+		sweepExpired: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "sweep_expired_count",
+			Help:      "How many shard leases have expired without being renewed.",
+		}),
+		sweepDeleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "sweep_deleted_count",
+			Help:      "How many expired items have been swept.",
+		}),
+		sweepLag: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace: prefix,
+			Name:      "sweep_lag_duration",
+			Help:      "How long expired items waited to be swept in nanoseconds.",
+			MaxAge:    maxSummaryAge,
+		}),
+		sweepDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace: prefix,
+			Name:      "sweep_call_duration",
+			Help:      "How long the sweeps took in nanoseconds.",
+			MaxAge:    maxSummaryAge,
+		}),
+
+		hedgeFired: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "hedge_fired_count",
+			Help:      "How many hedged reads have been sent to a second cluster.",
+		}),
+		hedgeWon: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prefix,
+			Name:      "hedge_won_count",
+			Help:      "How many hedged reads answered before the first cluster.",
+		}),
+
 		performanceDuration: prometheus.NewSummary(prometheus.SummaryOpts{
 			Namespace: prefix,
 			Name:      "performance_call_duration",
@@ -752,6 +928,7 @@ func New(prefix string, maxSummaryAge time.Duration) instrumentation.Instrumenta
 	}
 
 	prometheus.MustRegister(i.aInsertCall, i.aInsertDuration)
+	prometheus.MustRegister(i.aBatchCall, i.aBatchDuration)
 	prometheus.MustRegister(i.aModifyCall, i.aModifyDuration)
 	prometheus.MustRegister(i.aModifyWithOperationsCall, i.aModifyWithOperationsDuration)
 	prometheus.MustRegister(i.aDeleteCall, i.aDeleteDuration)
@@ -762,7 +939,11 @@ func New(prefix string, maxSummaryAge time.Duration) instrumentation.Instrumenta
 	prometheus.MustRegister(i.aSizeCall, i.aSizeDuration)
 	prometheus.MustRegister(i.aMembersCall, i.aMembersDuration)
 	prometheus.MustRegister(i.aRepairCall, i.aRepairDuration)
+	prometheus.MustRegister(i.aSyncCall, i.aSyncDuration)
+	prometheus.MustRegister(i.aChangesCall, i.aChangesDuration)
+	prometheus.MustRegister(i.aReindexCall, i.aReindexDuration)
 	prometheus.MustRegister(i.aQueryCall, i.aQueryDuration)
+	prometheus.MustRegister(i.aWaitlistCall, i.aWaitlistDuration)
 	prometheus.MustRegister(i.aPauseCall, i.aResumeCall)
 	prometheus.MustRegister(i.aTopologyCall, i.aTopologyDuration)
 
@@ -801,8 +982,19 @@ func New(prefix string, maxSummaryAge time.Duration) instrumentation.Instrumenta
 
 	prometheus.MustRegister(i.repairCall, i.repairDuration, i.repairError,
 		i.repairRequest, i.repairScoreError, i.repairSendTo,
+		i.repairDivergent, i.repairDiscarded,
+	)
+
+	prometheus.MustRegister(i.migrateCall, i.migrateKeys, i.migrateMoved,
+		i.migrateError, i.migrateDualRead, i.migrateDuration,
+	)
+
+	prometheus.MustRegister(i.sweepCall, i.sweepAcquired, i.sweepHandover,
+		i.sweepExpired, i.sweepDeleted, i.sweepLag, i.sweepDuration,
 	)
 
+	prometheus.MustRegister(i.hedgeFired, i.hedgeWon)
+
 	prometheus.MustRegister(i.performanceDuration)
 
 	prometheus.MustRegister(i.publishCall, i.publishDuration, i.publishKeys,
@@ -885,6 +1077,14 @@ func (i instrument) AInsertDuration(t time.Duration) {
 	i.aInsertDuration.Observe(float64(t.Nanoseconds()))
 }
 
+func (i instrument) ABatchCall() {
+	i.aBatchCall.Inc()
+}
+
+func (i instrument) ABatchDuration(t time.Duration) {
+	i.aBatchDuration.Observe(float64(t.Nanoseconds()))
+}
+
 func (i instrument) AModifyCall() {
 	i.aModifyCall.Inc()
 }
@@ -965,6 +1165,30 @@ func (i instrument) ARepairDuration(t time.Duration) {
 	i.aRepairDuration.Observe(float64(t.Nanoseconds()))
 }
 
+func (i instrument) ASyncCall() {
+	i.aSyncCall.Inc()
+}
+
+func (i instrument) ASyncDuration(t time.Duration) {
+	i.aSyncDuration.Observe(float64(t.Nanoseconds()))
+}
+
+func (i instrument) AChangesCall() {
+	i.aChangesCall.Inc()
+}
+
+func (i instrument) AChangesDuration(t time.Duration) {
+	i.aChangesDuration.Observe(float64(t.Nanoseconds()))
+}
+
+func (i instrument) AReindexCall() {
+	i.aReindexCall.Inc()
+}
+
+func (i instrument) AReindexDuration(t time.Duration) {
+	i.aReindexDuration.Observe(float64(t.Nanoseconds()))
+}
+
 func (i instrument) AQueryCall() {
 	i.aQueryCall.Inc()
 }
@@ -973,6 +1197,14 @@ func (i instrument) AQueryDuration(t time.Duration) {
 	i.aQueryDuration.Observe(float64(t.Nanoseconds()))
 }
 
+func (i instrument) AWaitlistCall() {
+	i.aWaitlistCall.Inc()
+}
+
+func (i instrument) AWaitlistDuration(t time.Duration) {
+	i.aWaitlistDuration.Observe(float64(t.Nanoseconds()))
+}
+
 func (i instrument) APauseCall() {
 	i.aPauseCall.Inc()
 }
@@ -1229,6 +1461,74 @@ func (i instrument) RepairError(n int) {
 	i.repairError.Add(float64(n))
 }
 
+func (i instrument) RepairDivergent(n int) {
+	i.repairDivergent.Add(float64(n))
+}
+
+func (i instrument) RepairDiscarded(n int) {
+	i.repairDiscarded.Add(float64(n))
+}
+
+func (i instrument) MigrateCall() {
+	i.migrateCall.Inc()
+}
+
+func (i instrument) MigrateKeys(n int) {
+	i.migrateKeys.Add(float64(n))
+}
+
+func (i instrument) MigrateMoved(n int) {
+	i.migrateMoved.Add(float64(n))
+}
+
+func (i instrument) MigrateError(n int) {
+	i.migrateError.Add(float64(n))
+}
+
+func (i instrument) MigrateDualRead() {
+	i.migrateDualRead.Inc()
+}
+
+func (i instrument) MigrateDuration(t time.Duration) {
+	i.migrateDuration.Observe(float64(t.Nanoseconds()))
+}
+
+func (i instrument) SweepCall() {
+	i.sweepCall.Inc()
+}
+
+func (i instrument) SweepAcquired() {
+	i.sweepAcquired.Inc()
+}
+
+func (i instrument) SweepHandover() {
+	i.sweepHandover.Inc()
+}
+
+func (i instrument) SweepExpired() {
+	i.sweepExpired.Inc()
+}
+
+func (i instrument) SweepDeleted(n int) {
+	i.sweepDeleted.Add(float64(n))
+}
+
+func (i instrument) SweepLag(t time.Duration) {
+	i.sweepLag.Observe(float64(t.Nanoseconds()))
+}
+
+func (i instrument) SweepDuration(t time.Duration) {
+	i.sweepDuration.Observe(float64(t.Nanoseconds()))
+}
+
+func (i instrument) HedgeFired() {
+	i.hedgeFired.Inc()
+}
+
+func (i instrument) HedgeWon() {
+	i.hedgeWon.Inc()
+}
+
 func (i instrument) PerformanceDuration(t time.Duration) {
 	i.performanceDuration.Observe(float64(t.Nanoseconds()))
 }
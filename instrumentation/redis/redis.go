@@ -85,6 +85,14 @@ func (i instrument) AInsertDuration(t time.Duration) {
 	i.duration("aggregate_insert.duration", t)
 }
 
+func (i instrument) ABatchCall() {
+	i.counter("aggregate_batch.call.count", 1)
+}
+
+func (i instrument) ABatchDuration(t time.Duration) {
+	i.duration("aggregate_batch.duration", t)
+}
+
 func (i instrument) AModifyCall() {
 	i.counter("aggregate_modify.call.count", 1)
 }
@@ -165,6 +173,30 @@ func (i instrument) ARepairDuration(t time.Duration) {
 	i.duration("aggregate_repair.duration", t)
 }
 
+func (i instrument) ASyncCall() {
+	i.counter("aggregate_sync.call.count", 1)
+}
+
+func (i instrument) ASyncDuration(t time.Duration) {
+	i.duration("aggregate_sync.duration", t)
+}
+
+func (i instrument) AChangesCall() {
+	i.counter("aggregate_changes.call.count", 1)
+}
+
+func (i instrument) AChangesDuration(t time.Duration) {
+	i.duration("aggregate_changes.duration", t)
+}
+
+func (i instrument) AReindexCall() {
+	i.counter("aggregate_reindex.call.count", 1)
+}
+
+func (i instrument) AReindexDuration(t time.Duration) {
+	i.duration("aggregate_reindex.duration", t)
+}
+
 func (i instrument) AQueryCall() {
 	i.counter("aggregate_query.call.count", 1)
 }
@@ -173,6 +205,14 @@ func (i instrument) AQueryDuration(t time.Duration) {
 	i.duration("aggregate_query.duration", t)
 }
 
+func (i instrument) AWaitlistCall() {
+	i.counter("aggregate_waitlist.call.count", 1)
+}
+
+func (i instrument) AWaitlistDuration(t time.Duration) {
+	i.duration("aggregate_waitlist.duration", t)
+}
+
 func (i instrument) APauseCall() {
 	i.counter("aggregate_pause.call.count", 1)
 }
@@ -429,6 +469,74 @@ func (i instrument) RepairError(n int) {
 	i.counter("repair.error.count", n)
 }
 
+func (i instrument) RepairDivergent(n int) {
+	i.counter("repair.divergent.count", n)
+}
+
+func (i instrument) RepairDiscarded(n int) {
+	i.counter("repair.discarded.count", n)
+}
+
+func (i instrument) MigrateCall() {
+	i.counter("migrate.call.count", 1)
+}
+
+func (i instrument) MigrateKeys(n int) {
+	i.counter("migrate.keys.count", n)
+}
+
+func (i instrument) MigrateMoved(n int) {
+	i.counter("migrate.moved.count", n)
+}
+
+func (i instrument) MigrateError(n int) {
+	i.counter("migrate.error.count", n)
+}
+
+func (i instrument) MigrateDualRead() {
+	i.counter("migrate.dual_read.count", 1)
+}
+
+func (i instrument) MigrateDuration(t time.Duration) {
+	i.duration("migrate.duration", t)
+}
+
+func (i instrument) SweepCall() {
+	i.counter("sweep.call.count", 1)
+}
+
+func (i instrument) SweepAcquired() {
+	i.counter("sweep.acquired.count", 1)
+}
+
+func (i instrument) SweepHandover() {
+	i.counter("sweep.handover.count", 1)
+}
+
+func (i instrument) SweepExpired() {
+	i.counter("sweep.expired.count", 1)
+}
+
+func (i instrument) SweepDeleted(n int) {
+	i.counter("sweep.deleted.count", n)
+}
+
+func (i instrument) SweepLag(t time.Duration) {
+	i.duration("sweep.lag.duration", t)
+}
+
+func (i instrument) SweepDuration(t time.Duration) {
+	i.duration("sweep.duration", t)
+}
+
+func (i instrument) HedgeFired() {
+	i.counter("hedge.fired.count", 1)
+}
+
+func (i instrument) HedgeWon() {
+	i.counter("hedge.won.count", 1)
+}
+
 func (i instrument) PerformanceDuration(t time.Duration) {
 	i.duration("performance.duration", t)
 }
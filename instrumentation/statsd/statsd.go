@@ -33,6 +33,14 @@ func (i instrument) AInsertDuration(t time.Duration) {
 	i.statter.Timing(i.sampleRate, "aggregate_insert.duration", t)
 }
 
+func (i instrument) ABatchCall() {
+	i.statter.Counter(i.sampleRate, "aggregate_batch.call.count", 1)
+}
+
+func (i instrument) ABatchDuration(t time.Duration) {
+	i.statter.Timing(i.sampleRate, "aggregate_batch.duration", t)
+}
+
 func (i instrument) AModifyCall() {
 	i.statter.Counter(i.sampleRate, "aggregate_modify.call.count", 1)
 }
@@ -113,6 +121,30 @@ func (i instrument) ARepairDuration(t time.Duration) {
 	i.statter.Timing(i.sampleRate, "aggregate_repair.duration", t)
 }
 
+func (i instrument) ASyncCall() {
+	i.statter.Counter(i.sampleRate, "aggregate_sync.call.count", 1)
+}
+
+func (i instrument) ASyncDuration(t time.Duration) {
+	i.statter.Timing(i.sampleRate, "aggregate_sync.duration", t)
+}
+
+func (i instrument) AChangesCall() {
+	i.statter.Counter(i.sampleRate, "aggregate_changes.call.count", 1)
+}
+
+func (i instrument) AChangesDuration(t time.Duration) {
+	i.statter.Timing(i.sampleRate, "aggregate_changes.duration", t)
+}
+
+func (i instrument) AReindexCall() {
+	i.statter.Counter(i.sampleRate, "aggregate_reindex.call.count", 1)
+}
+
+func (i instrument) AReindexDuration(t time.Duration) {
+	i.statter.Timing(i.sampleRate, "aggregate_reindex.duration", t)
+}
+
 func (i instrument) AQueryCall() {
 	i.statter.Counter(i.sampleRate, "aggregate_query.call.count", 1)
 }
@@ -121,6 +153,14 @@ func (i instrument) AQueryDuration(t time.Duration) {
 	i.statter.Timing(i.sampleRate, "aggregate_query.duration", t)
 }
 
+func (i instrument) AWaitlistCall() {
+	i.statter.Counter(i.sampleRate, "aggregate_waitlist.call.count", 1)
+}
+
+func (i instrument) AWaitlistDuration(t time.Duration) {
+	i.statter.Timing(i.sampleRate, "aggregate_waitlist.duration", t)
+}
+
 func (i instrument) APauseCall() {
 	i.statter.Counter(i.sampleRate, "aggregate_pause.call.count", 1)
 }
@@ -377,6 +417,74 @@ func (i instrument) RepairError(n int) {
 	i.statter.Counter(i.sampleRate, "repair.error.count", n)
 }
 
+func (i instrument) RepairDivergent(n int) {
+	i.statter.Counter(i.sampleRate, "repair.divergent.count", n)
+}
+
+func (i instrument) RepairDiscarded(n int) {
+	i.statter.Counter(i.sampleRate, "repair.discarded.count", n)
+}
+
+func (i instrument) MigrateCall() {
+	i.statter.Counter(i.sampleRate, "migrate.call.count", 1)
+}
+
+func (i instrument) MigrateKeys(n int) {
+	i.statter.Counter(i.sampleRate, "migrate.keys.count", n)
+}
+
+func (i instrument) MigrateMoved(n int) {
+	i.statter.Counter(i.sampleRate, "migrate.moved.count", n)
+}
+
+func (i instrument) MigrateError(n int) {
+	i.statter.Counter(i.sampleRate, "migrate.error.count", n)
+}
+
+func (i instrument) MigrateDualRead() {
+	i.statter.Counter(i.sampleRate, "migrate.dual_read.count", 1)
+}
+
+func (i instrument) MigrateDuration(t time.Duration) {
+	i.statter.Timing(i.sampleRate, "migrate.duration", t)
+}
+
+func (i instrument) SweepCall() {
+	i.statter.Counter(i.sampleRate, "sweep.call.count", 1)
+}
+
+func (i instrument) SweepAcquired() {
+	i.statter.Counter(i.sampleRate, "sweep.acquired.count", 1)
+}
+
+func (i instrument) SweepHandover() {
+	i.statter.Counter(i.sampleRate, "sweep.handover.count", 1)
+}
+
+func (i instrument) SweepExpired() {
+	i.statter.Counter(i.sampleRate, "sweep.expired.count", 1)
+}
+
+func (i instrument) SweepDeleted(n int) {
+	i.statter.Counter(i.sampleRate, "sweep.deleted.count", n)
+}
+
+func (i instrument) SweepLag(t time.Duration) {
+	i.statter.Timing(i.sampleRate, "sweep.lag.duration", t)
+}
+
+func (i instrument) SweepDuration(t time.Duration) {
+	i.statter.Timing(i.sampleRate, "sweep.duration", t)
+}
+
+func (i instrument) HedgeFired() {
+	i.statter.Counter(i.sampleRate, "hedge.fired.count", 1)
+}
+
+func (i instrument) HedgeWon() {
+	i.statter.Counter(i.sampleRate, "hedge.won.count", 1)
+}
+
 func (i instrument) PerformanceDuration(t time.Duration) {
 	i.statter.Timing(i.sampleRate, "performance.duration", t)
 }
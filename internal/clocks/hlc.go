@@ -0,0 +1,135 @@
+package clocks
+
+import (
+	"sync"
+	"time"
+)
+
+// The packed score has to remain exactly representable as a float64, so the
+// physical and logical parts are limited to the 53 bits of the mantissa. The
+// physical part is packed relative to the epoch (2016-01-01 UTC), which leaves
+// 41 bits of milliseconds and keeps the score exact until the year 2085.
+const (
+	logicalBits = 12
+	logicalMask = (1 << logicalBits) - 1
+
+	epoch = 1451606400000
+)
+
+// Timestamp defines a hybrid logical clock timestamp, which pairs a physical
+// wall time (in milliseconds) with a logical counter to order events that
+// happen with in the same millisecond.
+type Timestamp struct {
+	Physical int64
+	Logical  int64
+}
+
+// FromScore unpacks a Timestamp from a score that was previously packed with
+// Score.
+func FromScore(score float64) Timestamp {
+	v := int64(score)
+	return Timestamp{
+		Physical: (v >> logicalBits) + epoch,
+		Logical:  v & logicalMask,
+	}
+}
+
+// Score packs the Timestamp into a float64, so that it can be used as a score
+// with in the stores.
+func (t Timestamp) Score() float64 {
+	return float64((t.Physical-epoch)<<logicalBits | (t.Logical & logicalMask))
+}
+
+// Time returns the physical part of the Timestamp as a time.Time
+func (t Timestamp) Time() time.Time {
+	return time.Unix(0, t.Physical*int64(time.Millisecond))
+}
+
+// Before reports if the Timestamp happened before the other Timestamp.
+func (t Timestamp) Before(other Timestamp) bool {
+	return t.Physical < other.Physical ||
+		(t.Physical == other.Physical && t.Logical < other.Logical)
+}
+
+// Clock defines a hybrid logical clock, which always moves forward even if the
+// underlying physical clock goes backwards.
+type Clock struct {
+	mutex  *sync.Mutex
+	last   Timestamp
+	source func() time.Time
+}
+
+// New creates a Clock using the system time as the physical clock.
+func New() *Clock {
+	return NewWithSource(time.Now)
+}
+
+// NewWithSource creates a Clock using the source as the physical clock.
+func NewWithSource(source func() time.Time) *Clock {
+	return &Clock{
+		mutex:  &sync.Mutex{},
+		source: source,
+	}
+}
+
+// Now returns a new Timestamp for a local event.
+func (c *Clock) Now() Timestamp {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if physical := c.physical(); physical > c.last.Physical {
+		c.last = Timestamp{Physical: physical}
+	} else {
+		c.last = tick(c.last, c.last.Logical)
+	}
+
+	return c.last
+}
+
+// Update merges a Timestamp received from elsewhere into the clock, so that
+// every following Timestamp will happen after it.
+func (c *Clock) Update(remote Timestamp) Timestamp {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	physical := c.physical()
+
+	switch {
+	case physical > c.last.Physical && physical > remote.Physical:
+		c.last = Timestamp{Physical: physical}
+	case remote.Physical > c.last.Physical:
+		c.last = tick(remote, remote.Logical)
+	case c.last.Physical > remote.Physical:
+		c.last = tick(c.last, c.last.Logical)
+	default:
+		c.last = tick(c.last, max(c.last.Logical, remote.Logical))
+	}
+
+	return c.last
+}
+
+// Drift returns how far the Timestamp is from the physical clock. A positive
+// duration means that the Timestamp is in the future.
+func (c *Clock) Drift(t Timestamp) time.Duration {
+	return t.Time().Sub(c.source())
+}
+
+func (c *Clock) physical() int64 {
+	return c.source().UnixNano() / int64(time.Millisecond)
+}
+
+func tick(t Timestamp, logical int64) Timestamp {
+	if logical >= logicalMask {
+		// Borrow from the physical time when the logical counter overflows,
+		// this keeps the Timestamp monotonic.
+		return Timestamp{Physical: t.Physical + 1}
+	}
+	return Timestamp{Physical: t.Physical, Logical: logical + 1}
+}
+
+func max(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
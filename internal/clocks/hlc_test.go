@@ -0,0 +1,152 @@
+package clocks
+
+import (
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+func config() *quick.Config {
+	if testing.Short() {
+		return &quick.Config{
+			MaxCount:      10,
+			MaxCountScale: 10,
+		}
+	}
+	return nil
+}
+
+func fixed(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func TestTimestamp_ScoreRoundTrip(t *testing.T) {
+	var (
+		f = func(p uint32, l uint16) bool {
+			var (
+				ts = Timestamp{
+					Physical: epoch + int64(p),
+					Logical:  int64(l) & logicalMask,
+				}
+				res = FromScore(ts.Score())
+			)
+			return res == ts
+		}
+	)
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTimestamp_ScoreIsExactUntil2085(t *testing.T) {
+	var (
+		limit = time.Date(2085, time.January, 1, 0, 0, 0, 0, time.UTC)
+		ts    = Timestamp{
+			Physical: limit.UnixNano() / int64(time.Millisecond),
+			Logical:  logicalMask,
+		}
+	)
+
+	if score := ts.Score(); score >= 1<<53 {
+		t.Errorf("Expected score to be less than 2^53, got %f", score)
+	}
+	if res := FromScore(ts.Score()); res != ts {
+		t.Errorf("Expected %v, got %v", ts, res)
+	}
+}
+
+func TestClock_NowIsMonotonic(t *testing.T) {
+	var (
+		f = func(n uint8) bool {
+			var (
+				clock = NewWithSource(fixed(time.Now()))
+				last  = clock.Now()
+			)
+			for i := 0; i < int(n); i++ {
+				next := clock.Now()
+				if !last.Before(next) {
+					return false
+				}
+				last = next
+			}
+			return true
+		}
+	)
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClock_NowWithLogicalOverflow(t *testing.T) {
+	var (
+		now   = time.Now()
+		clock = NewWithSource(fixed(now))
+		first = clock.Now()
+	)
+
+	for i := 0; i < logicalMask; i++ {
+		clock.Now()
+	}
+
+	if next := clock.Now(); next.Physical != first.Physical+1 || next.Logical != 0 {
+		t.Errorf("Expected overflow to borrow from physical time, got %v", next)
+	}
+}
+
+func TestClock_UpdateWithRemoteInTheFuture(t *testing.T) {
+	var (
+		f = func(offset uint16, logical uint8) bool {
+			var (
+				now    = time.Now()
+				clock  = NewWithSource(fixed(now))
+				remote = Timestamp{
+					Physical: clock.Now().Physical + int64(offset) + 1,
+					Logical:  int64(logical),
+				}
+				res = clock.Update(remote)
+			)
+			return remote.Before(res) && remote.Before(clock.Now())
+		}
+	)
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClock_UpdateWithRemoteInThePast(t *testing.T) {
+	var (
+		f = func(offset uint16) bool {
+			var (
+				now    = time.Now()
+				clock  = NewWithSource(fixed(now))
+				local  = clock.Now()
+				remote = Timestamp{
+					Physical: local.Physical - int64(offset) - 1,
+				}
+				res = clock.Update(remote)
+			)
+			return local.Before(res) && res.Physical == local.Physical
+		}
+	)
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClock_Drift(t *testing.T) {
+	var (
+		now   = time.Now()
+		clock = NewWithSource(fixed(now))
+		ts    = Timestamp{
+			Physical: now.Add(time.Second).UnixNano() / int64(time.Millisecond),
+		}
+	)
+
+	if drift := clock.Drift(ts); drift <= 0 || drift > time.Second {
+		t.Errorf("Expected drift to be positive and less than a second, got %s", drift)
+	}
+}
@@ -5,3 +5,20 @@ package fusion
 type SelectionStrategy interface {
 	Select(string, int) int
 }
+
+// Node defines a connection of a pool, the weight is relative to the other
+// nodes of the pool. The host is only the address of the node, so unlike the
+// name it's safe to log.
+type Node struct {
+	Name   string
+	Host   string
+	Weight int
+}
+
+// BindingStrategy defines a SelectionStrategy that can be bound to the nodes of
+// a pool, so that the selection depends on which nodes there are rather than
+// how many there is.
+type BindingStrategy interface {
+	SelectionStrategy
+	Bind([]Node) SelectionStrategy
+}
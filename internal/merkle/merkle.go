@@ -0,0 +1,101 @@
+package merkle
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/SimonRichardson/echelon/internal/errors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+)
+
+// Bucket returns the leaf bucket a field belongs to. The bucket is derived from
+// the first 32 bits of the sha1 of the field, so that the same bucket can be
+// computed with in a redis script.
+func Bucket(field string, buckets int) int {
+	sum := sha1.Sum([]byte(field))
+	v, _ := strconv.ParseUint(hex.EncodeToString(sum[:])[:8], 16, 64)
+	return int(v % uint64(buckets))
+}
+
+// Tree defines a hash tree over a fixed number of buckets. Every leaf is the
+// digest of a bucket and every node is the digest of its children, so that two
+// trees can be compared by only walking the branches that differ.
+type Tree struct {
+	levels [][]string
+}
+
+// New creates a Tree from the leaf digests, the amount of leaves is expected to
+// be a power of two.
+func New(leaves []string) (Tree, error) {
+	num := len(leaves)
+	if num < 1 || num&(num-1) != 0 {
+		return Tree{}, typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Expected power of two leaves, got %d", num)
+	}
+
+	levels := [][]string{leaves}
+	for level := leaves; len(level) > 1; {
+		next := make([]string, len(level)/2)
+		for k := range next {
+			next[k] = digest(level[k*2], level[k*2+1])
+		}
+		levels = append([][]string{next}, levels...)
+		level = next
+	}
+
+	return Tree{levels}, nil
+}
+
+// Root returns the digest of the whole Tree.
+func (t Tree) Root() string {
+	if len(t.levels) < 1 {
+		return ""
+	}
+	return t.levels[0][0]
+}
+
+// Leaves returns the amount of buckets with in the Tree.
+func (t Tree) Leaves() int {
+	if len(t.levels) < 1 {
+		return 0
+	}
+	return len(t.levels[len(t.levels)-1])
+}
+
+// Diff returns the buckets that differ between both trees. Both trees are
+// expected to have the same amount of leaves, if not every bucket is reported
+// as different.
+func Diff(a, b Tree) []int {
+	if a.Leaves() != b.Leaves() {
+		result := make([]int, a.Leaves())
+		for k := range result {
+			result[k] = k
+		}
+		return result
+	}
+
+	result := []int{}
+	diff(a, b, 0, 0, &result)
+	return result
+}
+
+func diff(a, b Tree, depth, index int, result *[]int) {
+	if a.levels[depth][index] == b.levels[depth][index] {
+		return
+	}
+	if depth == len(a.levels)-1 {
+		*result = append(*result, index)
+		return
+	}
+	diff(a, b, depth+1, index*2, result)
+	diff(a, b, depth+1, index*2+1, result)
+}
+
+func digest(left, right string) string {
+	if left == "" && right == "" {
+		return ""
+	}
+	sum := sha1.Sum([]byte(left + right))
+	return hex.EncodeToString(sum[:])
+}
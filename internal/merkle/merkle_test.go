@@ -0,0 +1,111 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+	"testing/quick"
+)
+
+func config() *quick.Config {
+	if testing.Short() {
+		return &quick.Config{
+			MaxCount:      10,
+			MaxCountScale: 10,
+		}
+	}
+	return nil
+}
+
+func leaves(values []string, buckets int) []string {
+	result := make([]string, buckets)
+	for _, v := range values {
+		index := Bucket(v, buckets)
+		result[index] = result[index] + v
+	}
+	return result
+}
+
+func TestBucket(t *testing.T) {
+	var (
+		f = func(field string, n uint8) bool {
+			var (
+				buckets = int(n) + 1
+				index   = Bucket(field, buckets)
+			)
+			return index >= 0 && index < buckets && index == Bucket(field, buckets)
+		}
+	)
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewWithInvalidLeaves(t *testing.T) {
+	for _, num := range []int{0, 3, 5, 12} {
+		if _, err := New(make([]string, num)); err == nil {
+			t.Errorf("Expected error for %d leaves", num)
+		}
+	}
+}
+
+func TestDiffWithSameTrees(t *testing.T) {
+	var (
+		f = func(values []string) bool {
+			a, err := New(leaves(values, 16))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := New(leaves(values, 16))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return a.Root() == b.Root() && len(Diff(a, b)) == 0
+		}
+	)
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDiffWithDivergentTrees(t *testing.T) {
+	var (
+		f = func(values []string, extra string) bool {
+			extra = fmt.Sprintf("extra:%s", extra)
+
+			a, err := New(leaves(values, 16))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := New(leaves(append(values, extra), 16))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res := Diff(a, b)
+			return a.Root() != b.Root() &&
+				len(res) == 1 &&
+				res[0] == Bucket(extra, 16)
+		}
+	)
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDiffWithDifferentLeaves(t *testing.T) {
+	a, err := New(make([]string, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := New(make([]string, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res := Diff(a, b); len(res) != 4 {
+		t.Errorf("Expected every bucket to differ, got %v", res)
+	}
+}
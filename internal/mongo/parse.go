@@ -36,6 +36,8 @@ func readStrategy(strategy string) (fusion.SelectionStrategy, error) {
 	switch common.Normalise(strategy) {
 	case "hash":
 		return strategies.NewHash(), nil
+	case "consistenthash":
+		return strategies.NewConsistentHash(strategies.DefaultVirtualNodes), nil
 	case "roundrobin":
 		return strategies.NewRoundRobin(), nil
 	case "random":
@@ -33,6 +33,30 @@ func ValidRedisHost(host string) error {
 					"Invalid password %q in host %q", password, host)
 			}
 		}
+
+		if _, err := (&RedisURL{url}).Weight(); err != nil {
+			return typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
+				"Invalid weight %q in host %q (%s)", url.Query().Get(weightParam), host, err)
+		}
 	}
 	return nil
 }
+
+// MemoryScheme defines the scheme used for a cluster that should be held in
+// memory rather than in redis, which is useful for tests and local development.
+const MemoryScheme = "mem://"
+
+// MemoryHost returns the name of the in memory cluster, if the address is using
+// the MemoryScheme.
+func MemoryHost(address string) (string, bool, error) {
+	if !strings.HasPrefix(address, MemoryScheme) {
+		return "", false, nil
+	}
+
+	name := strings.TrimPrefix(address, MemoryScheme)
+	if len(name) < 1 || strings.ContainsAny(name, ",/") {
+		return "", true, typex.Errorf(errors.Source, errors.UnexpectedParseArgument,
+			"Invalid memory host %q", address)
+	}
+	return name, true, nil
+}
@@ -0,0 +1,34 @@
+package redis
+
+import (
+	r "github.com/garyburd/redigo/redis"
+)
+
+// Host returns the address of the connection at the index.
+func (p *Pool) Host(index int) string {
+	return p.connections[index].address
+}
+
+// Moved returns the keys held by every connection of the pool, that the next
+// pool routes to a different host. The keys of a connection are found with the
+// scan function.
+func (p *Pool) Moved(next *Pool, scan func(r.Conn) ([]string, error)) ([]string, error) {
+	result := []string{}
+	for i := 0; i < p.Size(); i++ {
+		var keys []string
+		if err := p.WithIndex(i, func(conn r.Conn) (err error) {
+			keys, err = scan(conn)
+			return
+		}); err != nil {
+			return nil, err
+		}
+
+		host := p.Host(i)
+		for _, key := range keys {
+			if next.Host(next.Index(key)) != host {
+				result = append(result, key)
+			}
+		}
+	}
+	return result, nil
+}
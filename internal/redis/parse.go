@@ -60,6 +60,8 @@ func readStrategy(strategy string, opts *RedisOptions) (fusion.SelectionStrategy
 	switch common.Normalise(strategy) {
 	case "hash":
 		return strategies.NewHash(), nil
+	case "consistenthash":
+		return strategies.NewConsistentHash(strategies.DefaultVirtualNodes), nil
 	case "roundrobin":
 		return strategies.NewRoundRobin(), nil
 	case "random":
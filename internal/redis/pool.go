@@ -22,7 +22,10 @@ func New(addresses []string,
 	maxConnectionsPerInstance int,
 	creator RedisCreator,
 ) *Pool {
-	connections := make([]*connectionPool, len(addresses))
+	var (
+		connections = make([]*connectionPool, len(addresses))
+		nodes       = make([]fusion.Node, len(addresses))
+	)
 	for k, host := range addresses {
 		uri, err := ParseRedisURL(host)
 		if err != nil {
@@ -30,6 +33,16 @@ func New(addresses []string,
 			panic(fmt.Errorf("Invalid redis host %s : %v", host, err.Error()))
 		}
 
+		weight, err := uri.Weight()
+		if err != nil {
+			panic(fmt.Errorf("Invalid redis host weight %s : %v", host, err.Error()))
+		}
+		nodes[k] = fusion.Node{
+			Name:   uri.String(),
+			Host:   uri.Host(),
+			Weight: weight,
+		}
+
 		connections[k] = newConnectionPool(
 			uri.String(),
 			uri.Password(),
@@ -38,9 +51,21 @@ func New(addresses []string,
 			creator,
 		)
 	}
+
+	// Bind the strategy to the nodes, so that the routing depends on the hosts
+	// and not just how many there are.
+	if strategy, ok := routing.(fusion.BindingStrategy); ok {
+		routing = strategy.Bind(nodes)
+	}
+
 	return &Pool{connections, routing}
 }
 
+// Routing returns the routing strategy of the pool.
+func (p *Pool) Routing() fusion.SelectionStrategy {
+	return p.routing
+}
+
 // Size returns the number of connections that the pool is holding on to.
 func (p *Pool) Size() int {
 	return len(p.connections)
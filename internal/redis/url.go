@@ -3,8 +3,13 @@ package redis
 import (
 	"bytes"
 	"net/url"
+	"strconv"
 )
 
+// weightParam defines the query parameter of a host, that sets the weight of
+// the host for the strategies that use it.
+const weightParam = "weight"
+
 type RedisURL struct {
 	url *url.URL
 }
@@ -28,6 +33,27 @@ func (u *RedisURL) Password() string {
 	return ""
 }
 
+// Weight returns the weight of the host, which defaults to 1.
+func (u *RedisURL) Weight() (int, error) {
+	value := u.url.Query().Get(weightParam)
+	if value == "" {
+		return 1, nil
+	}
+	weight, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if weight < 1 {
+		return 0, strconv.ErrRange
+	}
+	return weight, nil
+}
+
+// Host returns the host (and port) of the url.
+func (u *RedisURL) Host() string {
+	return u.url.Host
+}
+
 func (u *RedisURL) String() string {
 	var buf bytes.Buffer
 	if u.url.Host != "" {
@@ -40,9 +66,13 @@ func (u *RedisURL) String() string {
 		buf.WriteByte('/')
 	}
 	buf.WriteString(path)
-	if u.url.RawQuery != "" {
-		buf.WriteByte('?')
-		buf.WriteString(u.url.RawQuery)
+	if query := u.url.Query(); len(query) > 0 {
+		// The weight is only used for routing, so it's not passed on.
+		query.Del(weightParam)
+		if len(query) > 0 {
+			buf.WriteByte('?')
+			buf.WriteString(query.Encode())
+		}
 	}
 	if u.url.Fragment != "" {
 		buf.WriteByte('#')
@@ -29,6 +29,10 @@ func (n Namespace) String() string {
 // SemaphoreUnlock is a type alias for unlocking a lock.
 type SemaphoreUnlock func() error
 
+// SemaphoreLost is a type alias for a channel that's closed once a lock is
+// lost, a nil channel is never closed.
+type SemaphoreLost <-chan struct{}
+
 // The HealthStatus of the service.
 type HealthStatus string
 
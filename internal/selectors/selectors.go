@@ -2,6 +2,7 @@ package selectors
 
 import (
 	"net/http"
+	"time"
 )
 
 type Prefix string
@@ -13,7 +14,7 @@ func (p Prefix) String() string {
 // Semaphore describes how to lock and release a operation that needs to be
 // synchronised between distributed applications.
 type Semaphore interface {
-	Lock(Namespace) (SemaphoreUnlock, error)
+	Lock(Namespace) (SemaphoreUnlock, SemaphoreLost, error)
 }
 
 // Heartbeat describes how to notify the reset of the datacenter that the
@@ -45,9 +46,11 @@ type EventSelector interface {
 	SelectEventsByOffset(int, int) ([]Event, error)
 }
 
-// Charger defines an interface for chargine items via a interface
+// Charger defines an interface for chargine items via a interface, a charge
+// can be refunded using the same payment that made the charge.
 type Charger interface {
 	Charge(Event, User, Payment) (Key, error)
+	Refund(Event, User, Payment) (Key, error)
 }
 
 type CodeSetSelector interface {
@@ -66,3 +69,11 @@ type Encoder interface {
 	SetBytes(Key, []byte) error
 	DelBytes(Key) error
 }
+
+// Reserver describes how to set a key only if it's not already set, so that
+// only one caller holds it until it expires. A reserved key is read back from
+// where it was reserved, as it's only ever reserved in the one place.
+type Reserver interface {
+	ReserveBytes(Key, []byte, time.Duration) (bool, error)
+	GetReservedBytes(Key) ([]byte, error)
+}
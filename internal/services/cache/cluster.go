@@ -17,6 +17,10 @@ const (
 // Cluster defines a interface for parallelised requesting.
 type Cluster interface {
 	sv.Encoder
+
+	// ReserveBytes sets the key only if it's not already set, the bytes are
+	// sent back if it was.
+	ReserveBytes(bs.Key, []byte, time.Duration) <-chan sv.Element
 }
 
 type cluster struct {
@@ -46,6 +50,19 @@ func (c *cluster) SetBytes(key bs.Key, bytes []byte) <-chan sv.Element {
 	})
 }
 
+func (c *cluster) ReserveBytes(key bs.Key, bytes []byte, expiry time.Duration) <-chan sv.Element {
+	return c.common(func(conn r.Conn, dst chan sv.Element) {
+		_, err := r.String(conn.Do("SET", key.String(), bytes, "NX", "PX", milliseconds(expiry)))
+		if err == r.ErrNil {
+			return
+		} else if err != nil {
+			dst <- sv.NewErrorElement(err)
+			return
+		}
+		dst <- sv.NewBytesElement(bytes)
+	})
+}
+
 func (c *cluster) DelBytes(key bs.Key) <-chan sv.Element {
 	return c.common(func(conn r.Conn, dst chan sv.Element) {
 		_, err := conn.Do("DEL", key.String())
@@ -75,3 +92,13 @@ func (c *cluster) common(fn func(r.Conn, chan sv.Element)) <-chan sv.Element {
 	}()
 	return out
 }
+
+// milliseconds rounds the expiry up, so that it's never less than a
+// millisecond, which redis would refuse.
+func milliseconds(d time.Duration) int64 {
+	ms := int64(d / time.Millisecond)
+	if d%time.Millisecond != 0 || ms < 1 {
+		ms++
+	}
+	return ms
+}
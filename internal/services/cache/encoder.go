@@ -118,6 +118,14 @@ func require(clusters []Cluster) (Cluster, error) {
 	return clusters[rand.Intn(len(clusters))], nil
 }
 
+func first(clusters []Cluster) (Cluster, error) {
+	if len(clusters) < 1 {
+		return nil, typex.Errorf(errors.Source, errors.UnexpectedArgument,
+			"No cluster to locate.")
+	}
+	return clusters[0], nil
+}
+
 func headBytes(bytes [][]byte) ([]byte, error) {
 	if len(bytes) < 1 {
 		return nil, typex.Errorf(errors.Source, errors.UnexpectedResults,
@@ -6,6 +6,7 @@ import (
 	"github.com/SimonRichardson/echelon/internal/errors"
 	"github.com/SimonRichardson/echelon/internal/selectors"
 	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	sv "github.com/SimonRichardson/echelon/internal/services"
 	"github.com/SimonRichardson/echelon/internal/typex"
 )
 
@@ -75,3 +76,50 @@ func (s *Service) SetBytes(key bs.Key, bytes []byte) error {
 func (s *Service) DelBytes(key bs.Key) error {
 	return s.encoder.DelBytes(key)
 }
+
+// ReserveBytes sets the key only if it's not already set. The reservation is
+// only ever made on the first cluster, so that every caller contends for the
+// same key.
+func (s *Service) ReserveBytes(key bs.Key, bytes []byte, expiry time.Duration) (bool, error) {
+	cluster, err := first(s.clusters)
+	if err != nil {
+		return false, err
+	}
+
+	reserved := false
+	for element := range cluster.ReserveBytes(key, bytes, expiry) {
+		if err := sv.ErrorFromElement(element); err != nil {
+			return false, err
+		}
+		reserved = true
+	}
+	return reserved, nil
+}
+
+// GetReservedBytes reads a key that's been reserved from the first cluster,
+// which is where the reservation was made. Reading any other cluster could miss
+// a reservation that's still in flight.
+func (s *Service) GetReservedBytes(key bs.Key) ([]byte, error) {
+	cluster, err := first(s.clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		errs    = make([]error, 0)
+		changes = make([][]byte, 0)
+	)
+	for element := range cluster.GetBytes(key) {
+		if err := sv.ErrorFromElement(element); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changes = append(changes, sv.BytesFromElement(element))
+	}
+
+	if len(errs) > 0 {
+		return nil, typex.Errorf(errors.Source, errors.Complete,
+			"Complete failure").With(errs...)
+	}
+	return headBytes(changes)
+}
@@ -1,7 +1,14 @@
 package cache
 
-import "testing"
-import "time"
+import (
+	"sync"
+	"testing"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	sv "github.com/SimonRichardson/echelon/internal/services"
+	r "github.com/garyburd/redigo/redis"
+)
 
 func TestService(t *testing.T) {
 	defer func() {
@@ -11,3 +18,95 @@ func TestService(t *testing.T) {
 	}()
 	DefaultService("tcp://lorenz", time.Minute)
 }
+
+// memoryCluster holds the bytes in memory, so that it can be checked which of
+// the clusters has been written to.
+type memoryCluster struct {
+	mutex  sync.Mutex
+	values map[bs.Key][]byte
+}
+
+func newMemoryCluster() *memoryCluster {
+	return &memoryCluster{values: map[bs.Key][]byte{}}
+}
+
+func (c *memoryCluster) GetBytes(key bs.Key) <-chan sv.Element {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if value, ok := c.values[key]; ok {
+		return elements(sv.NewBytesElement(value))
+	}
+	return elements(sv.NewErrorElement(r.ErrNil))
+}
+
+func (c *memoryCluster) SetBytes(key bs.Key, bytes []byte) <-chan sv.Element {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.values[key] = bytes
+	return elements()
+}
+
+func (c *memoryCluster) DelBytes(key bs.Key) <-chan sv.Element {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.values, key)
+	return elements()
+}
+
+func (c *memoryCluster) ReserveBytes(key bs.Key, bytes []byte, expiry time.Duration) <-chan sv.Element {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.values[key]; ok {
+		return elements()
+	}
+	c.values[key] = bytes
+	return elements(sv.NewBytesElement(bytes))
+}
+
+func elements(values ...sv.Element) <-chan sv.Element {
+	out := make(chan sv.Element, len(values))
+	for _, v := range values {
+		out <- v
+	}
+	close(out)
+	return out
+}
+
+func TestServiceReservedBytesAreReadFromTheReservation(t *testing.T) {
+	var (
+		clusters = []Cluster{newMemoryCluster(), newMemoryCluster(), newMemoryCluster()}
+		service  = New(clusters,
+			encodeStategyOpts{Strategy: Encoder, Tactic: nonBlocking},
+			NoopInstrumentation{},
+		)
+		key = bs.Key("key")
+	)
+
+	reserved, err := service.ReserveBytes(key, []byte("in flight"), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reserved {
+		t.Fatal("Expected the key to be reserved")
+	}
+
+	// The other clusters never see the reservation, so a random read would
+	// miss it.
+	for i := 0; i < 20; i++ {
+		value, err := service.GetReservedBytes(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(value) != "in flight" {
+			t.Fatalf("Expected %q, got %q", "in flight", value)
+		}
+	}
+
+	if reserved, err := service.ReserveBytes(key, []byte("retry"), time.Minute); err != nil || reserved {
+		t.Errorf("Expected the key to already be reserved, got %t, %v", reserved, err)
+	}
+}
@@ -21,7 +21,7 @@ const (
 type ClientCreator func(string, string, string) Client
 
 type Client interface {
-	Lock(selectors.Namespace) (selectors.SemaphoreUnlock, error)
+	Lock(selectors.Namespace) (selectors.SemaphoreUnlock, selectors.SemaphoreLost, error)
 	Heartbeat(selectors.HealthStatus) error
 	List(fs.Prefix) (map[string]int, error)
 }
@@ -48,23 +48,25 @@ func NewClient(address, checkId, output string) Client {
 	}
 }
 
-func (c *client) Lock(ns selectors.Namespace) (selectors.SemaphoreUnlock, error) {
+// Lock the namespace, the channel returned is closed once the lock is lost,
+// which happens when the session is invalidated.
+func (c *client) Lock(ns selectors.Namespace) (selectors.SemaphoreUnlock, selectors.SemaphoreLost, error) {
 	lock, err := c.api.LockKey(ns.Lock())
 	if err != nil {
-		return noop, err
+		return noop, nil, err
 	}
 
 	ch, err := lock.Lock(nil)
 	if err != nil {
-		return noop, err
+		return noop, nil, err
 	}
 	if ch == nil {
-		return noop, typex.Errorf(errors.Source, errors.UnexpectedResults, "Lock not held.")
+		return noop, nil, typex.Errorf(errors.Source, errors.UnexpectedResults, "Lock not held.")
 	}
 
 	return func() error {
 		return lock.Unlock()
-	}, nil
+	}, selectors.SemaphoreLost(ch), nil
 }
 
 func (c *client) Heartbeat(s selectors.HealthStatus) error {
@@ -26,10 +26,10 @@ func newCluster(pools *client.Pools) *cluster {
 
 func (c *cluster) Lock(ns selectors.Namespace) <-chan sv.Element {
 	return c.common(func(cli client.Client, dst chan sv.Element) {
-		if fn, err := lock(cli, ns); err != nil {
+		if fn, lost, err := lock(cli, ns); err != nil {
 			dst <- sv.NewErrorElement(err)
 		} else {
-			dst <- sv.NewSemaphoreUnlockElement(fn)
+			dst <- sv.NewSemaphoreUnlockElement(fn, lost)
 		}
 	})
 }
@@ -72,7 +72,7 @@ func (c *cluster) common(fn func(client.Client, chan sv.Element)) <-chan sv.Elem
 	return out
 }
 
-func lock(cli client.Client, ns selectors.Namespace) (selectors.SemaphoreUnlock, error) {
+func lock(cli client.Client, ns selectors.Namespace) (selectors.SemaphoreUnlock, selectors.SemaphoreLost, error) {
 	return cli.Lock(ns)
 }
 
@@ -23,8 +23,8 @@ type noop struct {
 	*Service
 }
 
-func (n noop) Lock(selectors.Namespace) (selectors.SemaphoreUnlock, error) {
-	return noopUnlock, nil
+func (n noop) Lock(selectors.Namespace) (selectors.SemaphoreUnlock, selectors.SemaphoreLost, error) {
+	return noopUnlock, nil, nil
 }
 
 func (n noop) Heartbeat(selectors.HealthStatus) error {
@@ -20,13 +20,13 @@ type semaphore struct {
 	tactic  Tactic
 }
 
-func (s semaphore) Lock(ns selectors.Namespace) (selectors.SemaphoreUnlock, error) {
+func (s semaphore) Lock(ns selectors.Namespace) (selectors.SemaphoreUnlock, selectors.SemaphoreLost, error) {
 	return s.write(func(c Cluster) <-chan sv.Element {
 		return c.Lock(ns)
 	})
 }
 
-func (s semaphore) write(fn func(Cluster) <-chan sv.Element) (selectors.SemaphoreUnlock, error) {
+func (s semaphore) write(fn func(Cluster) <-chan sv.Element) (selectors.SemaphoreUnlock, selectors.SemaphoreLost, error) {
 	var (
 		service       = s.service
 		clusters, err = selectClusters(service.clusters)
@@ -35,7 +35,7 @@ func (s semaphore) write(fn func(Cluster) <-chan sv.Element) (selectors.Semaphor
 		returned  = 0
 	)
 	if err != nil {
-		return noopUnlock, err
+		return noopUnlock, nil, err
 	}
 
 	began := beforeWrite(service.instrumentation, 1)
@@ -45,6 +45,7 @@ func (s semaphore) write(fn func(Cluster) <-chan sv.Element) (selectors.Semaphor
 		elements = make(chan sv.Element, 1)
 		errs     = []error{}
 		changes  = []selectors.SemaphoreUnlock{}
+		losses   = []selectors.SemaphoreLost{}
 
 		wg = &sync.WaitGroup{}
 	)
@@ -64,16 +65,17 @@ func (s semaphore) write(fn func(Cluster) <-chan sv.Element) (selectors.Semaphor
 
 		unlock := sv.SemaphoreUnlockFromElement(element)
 		changes = append(changes, unlock)
+		losses = append(losses, sv.SemaphoreLostFromElement(element))
 
 		returned++
 	}
 
 	if len(errs) > 0 {
-		return noopUnlock, typex.Errorf(errors.Source, errors.Complete,
+		return noopUnlock, nil, typex.Errorf(errors.Source, errors.Complete,
 			"Complete failure").With(errs...)
 	}
 
-	return head(changes)
+	return head(changes, losses)
 }
 
 func selectClusters(clusters []Cluster) ([]Cluster, error) {
@@ -84,12 +86,12 @@ func selectClusters(clusters []Cluster) ([]Cluster, error) {
 	return clusters[offset : offset+1], nil
 }
 
-func head(x []selectors.SemaphoreUnlock) (selectors.SemaphoreUnlock, error) {
+func head(x []selectors.SemaphoreUnlock, y []selectors.SemaphoreLost) (selectors.SemaphoreUnlock, selectors.SemaphoreLost, error) {
 	if len(x) < 1 {
-		return noopUnlock, typex.Errorf(errors.Source, errors.Complete,
+		return noopUnlock, nil, typex.Errorf(errors.Source, errors.Complete,
 			"Complete failure: no valid changes")
 	}
-	return x[0], nil
+	return x[0], y[0], nil
 }
 
 func scatterReads(tactic Tactic,
@@ -77,7 +77,7 @@ func New(clusters []Cluster,
 	return service
 }
 
-func (s *Service) Lock(ns selectors.Namespace) (selectors.SemaphoreUnlock, error) {
+func (s *Service) Lock(ns selectors.Namespace) (selectors.SemaphoreUnlock, selectors.SemaphoreLost, error) {
 	return s.semaphore.Lock(ns)
 }
 
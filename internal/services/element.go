@@ -171,11 +171,12 @@ func IndexFromElement(e Element) int {
 type SemaphoreUnlockElement struct {
 	typ    ElementType
 	unlock s.SemaphoreUnlock
+	lost   s.SemaphoreLost
 }
 
 // NewSemaphoreUnlockElement creates a new SemaphoreUnlockElement
-func NewSemaphoreUnlockElement(unlock s.SemaphoreUnlock) *SemaphoreUnlockElement {
-	return &SemaphoreUnlockElement{SemaphoreUnlockElementType, unlock}
+func NewSemaphoreUnlockElement(unlock s.SemaphoreUnlock, lost s.SemaphoreLost) *SemaphoreUnlockElement {
+	return &SemaphoreUnlockElement{SemaphoreUnlockElementType, unlock, lost}
 }
 
 // Type defines the type associated with the SemaphoreUnlockElement
@@ -184,8 +185,12 @@ func (e *SemaphoreUnlockElement) Type() ElementType { return e.typ }
 // SemaphoreUnlock defines the SemaphoreUnlock associated with the SemaphoreUnlockElement
 func (e *SemaphoreUnlockElement) SemaphoreUnlock() s.SemaphoreUnlock { return e.unlock }
 
+// SemaphoreLost defines the SemaphoreLost associated with the SemaphoreUnlockElement
+func (e *SemaphoreUnlockElement) SemaphoreLost() s.SemaphoreLost { return e.lost }
+
 type unlockElement interface {
 	SemaphoreUnlock() s.SemaphoreUnlock
+	SemaphoreLost() s.SemaphoreLost
 }
 
 // SemaphoreUnlockFromElement attempts to get an SemaphoreUnlock from the element if it exists.
@@ -196,6 +201,14 @@ func SemaphoreUnlockFromElement(e Element) s.SemaphoreUnlock {
 	return func() error { return nil }
 }
 
+// SemaphoreLostFromElement attempts to get an SemaphoreLost from the element if it exists.
+func SemaphoreLostFromElement(e Element) s.SemaphoreLost {
+	if ae, ok := e.(unlockElement); ok {
+		return ae.SemaphoreLost()
+	}
+	return nil
+}
+
 // MapStringIntElement defines a struct that is a container for unlock items
 type MapStringIntElement struct {
 	typ    ElementType
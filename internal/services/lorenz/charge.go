@@ -50,6 +50,11 @@ func charge(client cli.Client,
 		cli.Versioned,
 		func(headers http.Header) {
 			headers.Set("Authorization", fmt.Sprintf("Bearer %s", user.Access.Token))
+
+			// Retrying a charge with the same key is never charged twice.
+			if key := payment.IdempotencyKey.String(); key != "" {
+				headers.Set("Idempotency-Key", key)
+			}
 		},
 	)
 	if err != nil {
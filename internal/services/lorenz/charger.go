@@ -30,6 +30,15 @@ func (s charger) Charge(event selectors.Event,
 	})
 }
 
+func (s charger) Refund(event selectors.Event,
+	user selectors.User,
+	element selectors.Payment,
+) (selectors.Key, error) {
+	return s.write(func(c Cluster) <-chan sv.Element {
+		return c.Refund(event, user, element)
+	})
+}
+
 func (s charger) write(fn func(Cluster) <-chan sv.Element) (selectors.Key, error) {
 	var (
 		clusters, err = selectClusters(s.clusters)
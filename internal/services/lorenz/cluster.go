@@ -40,6 +40,19 @@ func (c *cluster) Charge(event selectors.Event,
 	})
 }
 
+func (c *cluster) Refund(event selectors.Event,
+	user selectors.User,
+	payment selectors.Payment,
+) <-chan sv.Element {
+	return c.common(func(dst chan sv.Element) {
+		if result, err := refund(c.client, event, user, payment); err != nil {
+			dst <- sv.NewErrorElement(err)
+		} else {
+			dst <- sv.NewKeyTxnElement(result.Key, result.Txn)
+		}
+	})
+}
+
 func (c *cluster) SelectEventByKey(key selectors.Key) <-chan sv.Element {
 	return c.common(func(dst chan sv.Element) {
 		if result, err := readEvent(c.client, key); err != nil {
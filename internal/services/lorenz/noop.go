@@ -35,6 +35,13 @@ func (n noop) Charge(selectors.Event,
 	return selectors.Key(""), nil
 }
 
+func (n noop) Refund(selectors.Event,
+	selectors.User,
+	selectors.Payment,
+) (selectors.Key, error) {
+	return selectors.Key(""), nil
+}
+
 func (n noop) SelectEventByKey(selectors.Key) (selectors.Event, error) {
 	return selectors.Event{}, nil
 }
@@ -0,0 +1,75 @@
+package lorenz
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/SimonRichardson/echelon/internal/common"
+	"github.com/SimonRichardson/echelon/internal/selectors"
+	cli "github.com/SimonRichardson/echelon/internal/services/lorenz/client"
+)
+
+// refund refunds the charge that was made for the transaction of the payment.
+// A transaction that was never charged has nothing to refund, so it's not
+// treated as an error.
+func refund(client cli.Client,
+	event selectors.Event,
+	user selectors.User,
+	payment selectors.Payment,
+) (selectors.KeyTxn, error) {
+	bytes, err := json.Marshal(lorenzRefund{
+		Transaction: common.StringPtr(payment.Key.String()),
+	})
+	if err != nil {
+		return selectors.KeyTxn{Key: event.Id}, err
+	}
+
+	response, err := client.Post(fmt.Sprintf("/events/%s/refund", event.Id.String()),
+		bytes,
+		cli.Versioned,
+		func(headers http.Header) {
+			headers.Set("Authorization", fmt.Sprintf("Bearer %s", user.Access.Token))
+
+			// Retrying a refund with the same key is never refunded twice.
+			if key := refundIdempotencyKey(payment); key != "" {
+				headers.Set("Idempotency-Key", key)
+			}
+		},
+	)
+	if err != nil {
+		return selectors.KeyTxn{Key: event.Id}, err
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return selectors.KeyTxn{Key: event.Id}, nil
+	}
+
+	if err := readError(response, http.StatusOK); err != nil {
+		return selectors.KeyTxn{Key: event.Id}, err
+	}
+
+	var record transactionBody
+	if err := json.Unmarshal(response.Bytes, &record); err != nil {
+		return selectors.KeyTxn{Key: event.Id}, err
+	}
+
+	return selectors.KeyTxn{
+		Key: event.Id,
+		Txn: selectors.Key(record.Records.Transaction),
+	}, nil
+}
+
+// refundIdempotencyKey derives the key of the refund from the key of the charge,
+// so that the refund is never mistaken for a retry of the charge.
+func refundIdempotencyKey(payment selectors.Payment) string {
+	if key := payment.IdempotencyKey.String(); key != "" {
+		return "refund:" + key
+	}
+	return ""
+}
+
+// Refund describes the transaction of the charge to refund.
+type lorenzRefund struct {
+	Transaction *string `json:"transaction"`
+}
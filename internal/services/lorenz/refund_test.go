@@ -0,0 +1,56 @@
+package lorenz
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/SimonRichardson/echelon/internal/selectors"
+	cli "github.com/SimonRichardson/echelon/internal/services/lorenz/client"
+)
+
+type headerClient struct {
+	headers []http.Header
+}
+
+func (c *headerClient) Get(url string, options cli.Options, fn func(http.Header)) (*cli.Response, error) {
+	return c.Post(url, nil, options, fn)
+}
+
+func (c *headerClient) Post(url string, body []byte, options cli.Options, fn func(http.Header)) (*cli.Response, error) {
+	headers := http.Header{}
+	fn(headers)
+	c.headers = append(c.headers, headers)
+	return &cli.Response{
+		StatusCode: http.StatusOK,
+		Bytes:      []byte(`{"records":{"transaction":"txn"}}`),
+	}, nil
+}
+
+func TestRefundIdempotencyKey(t *testing.T) {
+	var (
+		client  = &headerClient{}
+		event   = selectors.Event{Id: selectors.Key("event")}
+		payment = selectors.Payment{
+			Key:            selectors.Key("txn"),
+			IdempotencyKey: selectors.IdempotencyKey("txn"),
+		}
+	)
+
+	if _, err := charge(client, event, selectors.User{}, payment); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := refund(client, event, selectors.User{}, payment); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		chargeKey = client.headers[0].Get("Idempotency-Key")
+		refundKey = client.headers[1].Get("Idempotency-Key")
+	)
+	if expected, actual := "txn", chargeKey; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if expected, actual := "refund:txn", refundKey; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
@@ -98,6 +98,13 @@ func (s *Service) Charge(event selectors.Event,
 	return s.charger.Charge(event, user, element)
 }
 
+func (s *Service) Refund(event selectors.Event,
+	user selectors.User,
+	element selectors.Payment,
+) (selectors.Key, error) {
+	return s.charger.Refund(event, user, element)
+}
+
 func (s *Service) SelectEventByKey(key selectors.Key) (selectors.Event, error) {
 	return s.events.SelectEventByKey(key)
 }
@@ -62,9 +62,10 @@ type CodeSelector interface {
 	SelectCodeForEvent(selectors.Event, selectors.User) <-chan Element
 }
 
-// Charger represents a way to purchase items from the service.
+// Charger represents a way to purchase (and refund) items from the service.
 type Charger interface {
 	Charge(selectors.Event, selectors.User, selectors.Payment) <-chan Element
+	Refund(selectors.Event, selectors.User, selectors.Payment) <-chan Element
 }
 
 // Encoder represents a way to enqueue items into a message bus.
@@ -0,0 +1,182 @@
+package strategies
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/SimonRichardson/echelon/internal/cribs"
+	"github.com/SimonRichardson/echelon/internal/fusion"
+)
+
+// DefaultVirtualNodes defines how many virtual nodes a node with a weight of
+// one has on the ring.
+const DefaultVirtualNodes = 160
+
+type consistentHash struct {
+	mutex  sync.Mutex
+	vnodes int
+	rings  map[int]*Ring
+}
+
+// NewConsistentHash defines a strategy that routes a key to a node of a
+// consistent hash ring, so that adding or removing a node only moves the keys
+// of that node. When it's bound to the nodes of a pool the ring is made from
+// the names and weights of the nodes, otherwise the nodes are named after their
+// index.
+func NewConsistentHash(vnodes int) *consistentHash {
+	return &consistentHash{
+		mutex:  sync.Mutex{},
+		vnodes: vnodes,
+		rings:  make(map[int]*Ring),
+	}
+}
+
+func (r *consistentHash) Select(key string, max int) int {
+	r.mutex.Lock()
+	ring, ok := r.rings[max]
+	if !ok {
+		nodes := make([]fusion.Node, max)
+		for k := range nodes {
+			nodes[k] = fusion.Node{Name: strconv.Itoa(k), Weight: 1}
+		}
+		ring = NewRing(nodes, r.vnodes)
+		r.rings[max] = ring
+	}
+	r.mutex.Unlock()
+
+	return ring.Select(key, max)
+}
+
+func (r *consistentHash) Bind(nodes []fusion.Node) fusion.SelectionStrategy {
+	return NewRing(nodes, r.vnodes)
+}
+
+type point struct {
+	hash  uint32
+	index int
+}
+
+// Ring defines a consistent hash ring, every node has virtual nodes (points)
+// on the ring in relation to it's weight and owns the keys that hash up to
+// each of them.
+type Ring struct {
+	nodes  []fusion.Node
+	points []point
+}
+
+// NewRing creates a Ring from the nodes, with vnodes virtual nodes for every
+// unit of weight.
+func NewRing(nodes []fusion.Node, vnodes int) *Ring {
+	if vnodes < 1 {
+		vnodes = DefaultVirtualNodes
+	}
+
+	points := []point{}
+	for k, v := range nodes {
+		weight := v.Weight
+		if weight < 1 {
+			weight = 1
+		}
+		for i := 0; i < weight*vnodes; i++ {
+			points = append(points, point{
+				hash:  cribs.New(v.Name + "-" + strconv.Itoa(i)),
+				index: k,
+			})
+		}
+	}
+
+	// Collisions are ordered by name, so that the ring is the same no matter
+	// what order the nodes are in.
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].hash == points[j].hash {
+			return nodes[points[i].index].Name < nodes[points[j].index].Name
+		}
+		return points[i].hash < points[j].hash
+	})
+
+	return &Ring{
+		nodes:  nodes,
+		points: points,
+	}
+}
+
+// Nodes returns the nodes of the ring.
+func (r *Ring) Nodes() []fusion.Node {
+	return r.nodes
+}
+
+func (r *Ring) Select(key string, max int) int {
+	if len(r.points) < 1 || max < 1 {
+		return 0
+	}
+	return r.owner(cribs.New(key)) % max
+}
+
+// owner returns the index of the node that owns the hash, which is the node of
+// the first point at or after the hash.
+func (r *Ring) owner(hash uint32) int {
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= hash
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].index
+}
+
+// Movement defines a range of hashes (Start, End] that moves from one node to
+// another. The range wraps around the ring when Start is greater or equal to
+// End.
+type Movement struct {
+	Start, End uint32
+	From, To   fusion.Node
+}
+
+// Moved returns the ranges of hashes that move to a different node, when the
+// ring is replaced by the next ring.
+func (r *Ring) Moved(next *Ring) []Movement {
+	if len(r.points) < 1 || len(next.points) < 1 {
+		return []Movement{}
+	}
+
+	// Every boundary of either ring splits the ring into arcs, with in an arc
+	// every hash has the same owner on both rings.
+	unique := map[uint32]struct{}{}
+	for _, v := range r.points {
+		unique[v.hash] = struct{}{}
+	}
+	for _, v := range next.points {
+		unique[v.hash] = struct{}{}
+	}
+	bounds := make([]uint32, 0, len(unique))
+	for k := range unique {
+		bounds = append(bounds, k)
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	res := []Movement{}
+	for k, end := range bounds {
+		var (
+			start = bounds[(k+len(bounds)-1)%len(bounds)]
+			from  = r.nodes[r.owner(end)]
+			to    = next.nodes[next.owner(end)]
+		)
+		if from.Name == to.Name {
+			continue
+		}
+
+		// Join adjacent arcs that move between the same nodes.
+		if n := len(res); n > 0 && res[n-1].End == start && res[n-1].From.Name == from.Name && res[n-1].To.Name == to.Name {
+			res[n-1].End = end
+			continue
+		}
+		res = append(res, Movement{
+			Start: start,
+			End:   end,
+			From:  from,
+			To:    to,
+		})
+	}
+	return res
+}
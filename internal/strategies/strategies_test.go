@@ -6,6 +6,8 @@ import (
 	"testing"
 	"testing/quick"
 
+	"github.com/SimonRichardson/echelon/internal/cribs"
+	"github.com/SimonRichardson/echelon/internal/fusion"
 	"github.com/SimonRichardson/echelon/internal/logs/generic"
 )
 
@@ -64,3 +66,75 @@ func TestRoundRobin(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestConsistentHash(t *testing.T) {
+	var (
+		f = func(a string) int {
+			return NewConsistentHash(DefaultVirtualNodes).Select(a, 10)
+		}
+		g = func(a string) int {
+			return NewConsistentHash(DefaultVirtualNodes).Select(a, 10)
+		}
+	)
+
+	if err := quick.CheckEqual(f, g, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRingOnlyMovesToNewNode(t *testing.T) {
+	var (
+		prev = NewRing([]fusion.Node{{Name: "a", Weight: 1}, {Name: "b", Weight: 1}, {Name: "c", Weight: 2}}, DefaultVirtualNodes)
+		next = NewRing([]fusion.Node{{Name: "a", Weight: 1}, {Name: "b", Weight: 1}, {Name: "c", Weight: 2}, {Name: "d", Weight: 1}}, DefaultVirtualNodes)
+	)
+
+	f := func(a string) bool {
+		var (
+			from = prev.Nodes()[prev.Select(a, 3)].Name
+			to   = next.Nodes()[next.Select(a, 4)].Name
+		)
+		return from == to || to == "d"
+	}
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRingMoved(t *testing.T) {
+	var (
+		prev = NewRing([]fusion.Node{{Name: "a", Weight: 1}, {Name: "b", Weight: 1}, {Name: "c", Weight: 1}}, DefaultVirtualNodes)
+		next = NewRing([]fusion.Node{{Name: "c", Weight: 1}, {Name: "a", Weight: 1}}, DefaultVirtualNodes)
+
+		movements = prev.Moved(next)
+	)
+
+	f := func(a string) bool {
+		var (
+			hash = cribs.New(a)
+			from = prev.Nodes()[prev.Select(a, 3)].Name
+			to   = next.Nodes()[next.Select(a, 2)].Name
+		)
+
+		moved := false
+		for _, v := range movements {
+			var within bool
+			if v.Start < v.End {
+				within = hash > v.Start && hash <= v.End
+			} else {
+				within = hash > v.Start || hash <= v.End
+			}
+			if within {
+				if v.From.Name != from || v.To.Name != to {
+					return false
+				}
+				moved = true
+			}
+		}
+		return moved == (from != to) && (!moved || from == "b")
+	}
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
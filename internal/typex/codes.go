@@ -20,6 +20,10 @@ var (
 	InternalServerError = makeErrorCode(http.StatusInternalServerError)
 	NotFound            = makeErrorCode(http.StatusNotFound)
 	Unauthorized        = makeErrorCode(http.StatusUnauthorized)
+	Conflict            = makeErrorCode(http.StatusConflict)
+	UnprocessableEntity = makeErrorCode(http.StatusUnprocessableEntity)
+	TooManyRequests     = makeErrorCode(http.StatusTooManyRequests)
+	GatewayTimeout      = makeErrorCode(http.StatusGatewayTimeout)
 )
 
 func makeErrorCode(code int) ErrorCode {
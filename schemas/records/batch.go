@@ -0,0 +1,90 @@
+package records
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/SimonRichardson/echelon/schemas/schema"
+	"github.com/google/flatbuffers/go"
+)
+
+type BatchRecords struct {
+	Keys   []BatchKey    `json:"keys"`
+	Score  float64       `json:"score"`
+	Expiry time.Duration `json:"expiry"`
+}
+
+func (r BatchRecords) Write(fb *flatbuffers.Builder) ([]byte, error) {
+	var (
+		num       = len(r.Keys)
+		positions = make([]flatbuffers.UOffsetT, 0, num)
+	)
+
+	for _, v := range r.Keys {
+		position, err := v.WriteSub(fb)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, position)
+	}
+
+	schema.BatchRequestStartKeysVector(fb, num)
+
+	for _, v := range positions {
+		fb.PrependUOffsetT(v)
+	}
+
+	vector := fb.EndVector(num)
+
+	schema.BatchRequestStart(fb)
+	schema.BatchRequestAddScore(fb, r.Score)
+	schema.BatchRequestAddExpiry(fb, uint64(r.Expiry.Nanoseconds()))
+	schema.BatchRequestAddKeys(fb, vector)
+	position := schema.BatchRequestEnd(fb)
+
+	fb.Finish(position)
+	return fb.FinishedBytes(), nil
+}
+
+// BatchKey represents all the records of a batch that belong to a key.
+type BatchKey struct {
+	Key     bson.ObjectId `json:"key"`
+	MaxSize int64         `json:"max_size"`
+	Records []PostRecord  `json:"records"`
+}
+
+func (r BatchKey) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
+	keyPosition, err := MakeId(r.Key.Hex()).WriteSub(fb)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		num       = len(r.Records)
+		positions = make([]flatbuffers.UOffsetT, 0, num)
+	)
+
+	for _, v := range r.Records {
+		position, err := v.WriteSub(fb)
+		if err != nil {
+			return 0, err
+		}
+		positions = append(positions, position)
+	}
+
+	schema.BatchKeyStartRecordsVector(fb, num)
+
+	for _, v := range positions {
+		fb.PrependUOffsetT(v)
+	}
+
+	vector := fb.EndVector(num)
+
+	schema.BatchKeyStart(fb)
+	schema.BatchKeyAddKey(fb, keyPosition)
+	schema.BatchKeyAddMaxSize(fb, uint64(r.MaxSize))
+	schema.BatchKeyAddRecords(fb, vector)
+
+	return schema.BatchKeyEnd(fb), nil
+}
@@ -12,11 +12,11 @@ import (
 )
 
 type DeleteRecords struct {
-	Key     bs.Key
-	Records []DeleteRecord
-	Score   float64
-	MaxSize int64
-	Expiry  time.Duration
+	Key     bs.Key         `json:"key,omitempty"`
+	Records []DeleteRecord `json:"records"`
+	Score   float64        `json:"score"`
+	MaxSize int64          `json:"max_size"`
+	Expiry  time.Duration  `json:"expiry"`
 }
 
 func (r DeleteRecords) Write(fb *flatbuffers.Builder) ([]byte, error) {
@@ -53,9 +53,10 @@ func (r DeleteRecords) Write(fb *flatbuffers.Builder) ([]byte, error) {
 }
 
 type DeleteRecord struct {
-	Id                     bson.ObjectId
-	Updated                time.Time
-	OwnerId, TransactionId bson.ObjectId
+	Id            bson.ObjectId `json:"id"`
+	Updated       time.Time     `json:"updated"`
+	OwnerId       bson.ObjectId `json:"owner_id"`
+	TransactionId bson.ObjectId `json:"transaction_id"`
 }
 
 // WriteDeleteRecord represents away of writing a DeleteRecord to a byte buffer
@@ -0,0 +1,185 @@
+package records
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/SimonRichardson/echelon/errors"
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/schema"
+	"github.com/vmihailenco/msgpack"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Encoding defines an encoding of the records other than flatbuffers, for the
+// clients that would rather not use the schemas. Both encodings use the json
+// tags of the records.
+type Encoding int
+
+const (
+	// JSON encodes the records as json.
+	JSON Encoding = iota
+
+	// MessagePack encodes the records as msgpack.
+	MessagePack
+)
+
+func init() {
+	// ObjectIds are encoded as hex, the same as json does.
+	msgpack.Register(bson.ObjectId(""), encodeObjectIdValue, decodeObjectIdValue)
+}
+
+func (e Encoding) String() string {
+	switch e {
+	case JSON:
+		return "json"
+	case MessagePack:
+		return "msgpack"
+	}
+	return ""
+}
+
+// Marshal encodes the record with the encoding.
+func (e Encoding) Marshal(v interface{}) ([]byte, error) {
+	switch e {
+	case JSON:
+		return json.Marshal(v)
+	case MessagePack:
+		var buffer bytes.Buffer
+		if err := msgpack.NewEncoder(&buffer).UseJSONTag(true).Encode(v); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	}
+	return nil, typex.Errorf(errors.Source, errors.NoCaseFound, "Unknown Encoding")
+}
+
+// Unmarshal decodes the bytes into the record with the encoding.
+func (e Encoding) Unmarshal(data []byte, v interface{}) error {
+	switch e {
+	case JSON:
+		return json.Unmarshal(data, v)
+	case MessagePack:
+		return msgpack.NewDecoder(bytes.NewReader(data)).UseJSONTag(true).Decode(v)
+	}
+	return typex.Errorf(errors.Source, errors.NoCaseFound, "Unknown Encoding")
+}
+
+func encodeObjectIdValue(e *msgpack.Encoder, v reflect.Value) error {
+	return e.EncodeString(v.Interface().(bson.ObjectId).Hex())
+}
+
+func decodeObjectIdValue(d *msgpack.Decoder, v reflect.Value) error {
+	hex, err := d.DecodeString()
+	if err != nil {
+		return err
+	}
+	if !bson.IsObjectIdHex(hex) {
+		return ErrInvalidIdHex(12)
+	}
+	v.Set(reflect.ValueOf(bson.ObjectIdHex(hex)))
+	return nil
+}
+
+// ReadValue reads the record that's stored with in a value.
+func ReadValue(value string) (interface{}, error) {
+	header, err := ReadType(value)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ReadBody(value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch header {
+	case schema.TypePost:
+		record := PostRecord{}
+		if err := record.Read(body); err != nil {
+			return nil, err
+		}
+		return record, nil
+	case schema.TypePut:
+		record := PutRecord{}
+		if err := record.Read(body); err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+	return nil, typex.Errorf(errors.Source, errors.NoCaseFound, "Unknown Type")
+}
+
+type keyFieldScoreTxnValueDocument struct {
+	Key   bs.Key      `json:"key"`
+	Field bs.Key      `json:"field"`
+	Score float64     `json:"score"`
+	Txn   bs.Key      `json:"txn"`
+	Value interface{} `json:"value"`
+}
+
+func (k KeyFieldScoreTxnValue) document() (keyFieldScoreTxnValueDocument, error) {
+	value, err := ReadValue(k.Value)
+	if err != nil {
+		return keyFieldScoreTxnValueDocument{}, err
+	}
+
+	return keyFieldScoreTxnValueDocument{
+		Key:   k.Key,
+		Field: k.Field,
+		Score: k.Score,
+		Txn:   k.Txn,
+		Value: value,
+	}, nil
+}
+
+// MarshalJSON encodes the value as the record it stores, rather than as the
+// flatbuffers.
+func (k KeyFieldScoreTxnValue) MarshalJSON() ([]byte, error) {
+	doc, err := k.document()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// EncodeMsgpack encodes the value as the record it stores, rather than as the
+// flatbuffers.
+func (k KeyFieldScoreTxnValue) EncodeMsgpack(e *msgpack.Encoder) error {
+	doc, err := k.document()
+	if err != nil {
+		return err
+	}
+	return e.Encode(doc)
+}
+
+type queryRecordDocument struct {
+	Key    bs.Key      `json:"key"`
+	Field  bs.Key      `json:"field"`
+	Record interface{} `json:"record"`
+}
+
+// MarshalJSON encodes the record as a document, rather than as a string.
+func (k QueryRecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(queryRecordDocument{
+		Key:    k.Key,
+		Field:  k.Field,
+		Record: json.RawMessage(k.Record),
+	})
+}
+
+// EncodeMsgpack encodes the record as a document, rather than as a string.
+func (k QueryRecord) EncodeMsgpack(e *msgpack.Encoder) error {
+	var record interface{}
+	if err := json.Unmarshal([]byte(k.Record), &record); err != nil {
+		return err
+	}
+
+	return e.Encode(queryRecordDocument{
+		Key:    k.Key,
+		Field:  k.Field,
+		Record: record,
+	})
+}
@@ -0,0 +1,79 @@
+package records
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestEncodingPostRecords(t *testing.T) {
+	var (
+		now      = time.Unix(0, time.Now().UnixNano()).UTC()
+		expected = PostRecords{
+			Records: []PostRecord{
+				{
+					Id:            bson.NewObjectId(),
+					Updated:       now,
+					Reserved:      now,
+					Expiry:        now.Add(time.Minute),
+					Cost:          Cost{Currency: "GBP", Price: 100},
+					OwnerId:       bson.NewObjectId(),
+					TransactionId: bson.NewObjectId(),
+				},
+			},
+			Score:   1,
+			MaxSize: 10,
+			Expiry:  time.Minute,
+		}
+	)
+
+	for _, encoding := range []Encoding{JSON, MessagePack} {
+		bytes, err := encoding.Marshal(expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual PostRecords
+		if err := encoding.Unmarshal(bytes, &actual); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected, actual := expected.MaxSize, actual.MaxSize; expected != actual {
+			t.Errorf("%s: Expected: %v, Actual: %v", encoding, expected, actual)
+		}
+		if expected, actual := expected.Expiry, actual.Expiry; expected != actual {
+			t.Errorf("%s: Expected: %v, Actual: %v", encoding, expected, actual)
+		}
+		if expected, actual := len(expected.Records), len(actual.Records); expected != actual {
+			t.Fatalf("%s: Expected: %v, Actual: %v", encoding, expected, actual)
+		}
+
+		var (
+			a = expected.Records[0]
+			b = actual.Records[0]
+		)
+		if a.Id != b.Id || a.OwnerId != b.OwnerId || a.TransactionId != b.TransactionId {
+			t.Errorf("%s: Expected: %v, Actual: %v", encoding, a, b)
+		}
+		if !a.Expiry.Equal(b.Expiry) || a.Cost != b.Cost {
+			t.Errorf("%s: Expected: %v, Actual: %v", encoding, a, b)
+		}
+	}
+}
+
+func TestEncodingInvalidObjectId(t *testing.T) {
+	for _, encoding := range []Encoding{JSON, MessagePack} {
+		bytes, err := encoding.Marshal(map[string]interface{}{
+			"id": "nope",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var record PostRecord
+		if err := encoding.Unmarshal(bytes, &record); err == nil {
+			t.Errorf("%s: Expected: error, Actual: %v", encoding, err)
+		}
+	}
+}
@@ -6,9 +6,9 @@ import (
 )
 
 type Error struct {
-	Error       string
-	Code        string
-	Description string
+	Error       string `json:"error"`
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
 }
 
 func (e Error) Write(fb *flatbuffers.Builder) ([]byte, error) {
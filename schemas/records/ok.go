@@ -9,8 +9,8 @@ import (
 )
 
 type OKVersion struct {
-	Duration time.Duration
-	Records  Version
+	Duration time.Duration `json:"duration"`
+	Records  Version       `json:"records"`
 }
 
 func (o OKVersion) Write(fb *flatbuffers.Builder) ([]byte, error) {
@@ -47,9 +47,48 @@ func (o *OKVersion) Read(bytes []byte) error {
 	return nil
 }
 
+type OKAdmission struct {
+	Duration time.Duration `json:"duration"`
+	Records  Admission     `json:"records"`
+}
+
+func (o OKAdmission) Write(fb *flatbuffers.Builder) ([]byte, error) {
+	position, err := o.Records.WriteSub(fb)
+	if err != nil {
+		return nil, err
+	}
+
+	schema.OKAdmissionStart(fb)
+	schema.OKAdmissionAddDuration(fb, int64(o.Duration))
+	schema.OKAdmissionAddRecords(fb, position)
+
+	position = schema.OKAdmissionEnd(fb)
+
+	fb.Finish(position)
+	return fb.FinishedBytes(), nil
+}
+
+func (o *OKAdmission) Read(bytes []byte) error {
+	record := schema.GetRootAsOKAdmission(bytes, 0)
+
+	o.Duration = time.Duration(record.Duration())
+
+	admission := record.Records(nil)
+	if admission == nil {
+		return ErrInvalidRecord(2)
+	}
+	o.Records = Admission{
+		Token:    string(admission.Token()),
+		Position: admission.Position(),
+		ETA:      time.Duration(admission.Eta()),
+	}
+
+	return nil
+}
+
 type OKInt struct {
-	Duration time.Duration
-	Records  int
+	Duration time.Duration `json:"duration"`
+	Records  int           `json:"records"`
 }
 
 func (o OKInt) Write(fb *flatbuffers.Builder) ([]byte, error) {
@@ -77,7 +116,7 @@ func (o *OKInt) Read(bytes []byte) error {
 }
 
 type OKNoContent struct {
-	Duration time.Duration
+	Duration time.Duration `json:"duration"`
 }
 
 func (o OKNoContent) Write(fb *flatbuffers.Builder) ([]byte, error) {
@@ -103,8 +142,8 @@ func (o *OKNoContent) Read(bytes []byte) error {
 }
 
 type OKKeyFieldScoreTxnValues struct {
-	Duration time.Duration
-	Records  []KeyFieldScoreTxnValue
+	Duration time.Duration           `json:"duration"`
+	Records  []KeyFieldScoreTxnValue `json:"records"`
 }
 
 func (o OKKeyFieldScoreTxnValues) Write(fb *flatbuffers.Builder) ([]byte, error) {
@@ -170,8 +209,8 @@ func (o *OKKeyFieldScoreTxnValues) Read(bytes []byte) error {
 }
 
 type OKKeyFieldScoreTxnValue struct {
-	Duration time.Duration
-	Records  KeyFieldScoreTxnValue
+	Duration time.Duration         `json:"duration"`
+	Records  KeyFieldScoreTxnValue `json:"records"`
 }
 
 func (o OKKeyFieldScoreTxnValue) Write(fb *flatbuffers.Builder) ([]byte, error) {
@@ -208,9 +247,61 @@ func (o *OKKeyFieldScoreTxnValue) Read(bytes []byte) error {
 	return nil
 }
 
+type OKKeys struct {
+	Duration time.Duration `json:"duration"`
+	Records  []bs.Key      `json:"records"`
+}
+
+func (o OKKeys) Write(fb *flatbuffers.Builder) ([]byte, error) {
+	var (
+		num       = len(o.Records)
+		positions = make([]flatbuffers.UOffsetT, num, num)
+	)
+
+	for k, v := range o.Records {
+		positions[num-1-k] = fb.CreateString(v.String())
+	}
+
+	schema.OKKeysStartRecordsVector(fb, num)
+
+	for _, v := range positions {
+		fb.PrependUOffsetT(v)
+	}
+
+	vector := fb.EndVector(num)
+
+	schema.OKKeysStart(fb)
+	schema.OKKeysAddDuration(fb, int64(o.Duration))
+	schema.OKKeysAddRecords(fb, vector)
+
+	position := schema.OKKeysEnd(fb)
+
+	fb.Finish(position)
+	return fb.FinishedBytes(), nil
+}
+
+func (o *OKKeys) Read(bytes []byte) error {
+	record := schema.GetRootAsOKKeys(bytes, 0)
+
+	o.Duration = time.Duration(record.Duration())
+
+	var (
+		num    = record.RecordsLength()
+		vector = make([]bs.Key, 0, num)
+	)
+
+	for i := 0; i < num; i++ {
+		vector = append(vector, bs.Key(string(record.Records(i))))
+	}
+
+	o.Records = vector
+
+	return nil
+}
+
 type OKQuery struct {
-	Duration time.Duration
-	Records  []QueryRecord
+	Duration time.Duration `json:"duration"`
+	Records  []QueryRecord `json:"records"`
 }
 
 func (o OKQuery) Write(fb *flatbuffers.Builder) ([]byte, error) {
@@ -8,10 +8,10 @@ import (
 )
 
 type PatchRecords struct {
-	Operations []Operation
-	Score      float64
-	MaxSize    int64
-	Expiry     time.Duration
+	Operations []Operation   `json:"operations"`
+	Score      float64       `json:"score"`
+	MaxSize    int64         `json:"max_size"`
+	Expiry     time.Duration `json:"expiry"`
 }
 
 func (r PatchRecords) Write(fb *flatbuffers.Builder) ([]byte, error) {
@@ -48,9 +48,9 @@ func (r PatchRecords) Write(fb *flatbuffers.Builder) ([]byte, error) {
 }
 
 type Operation struct {
-	Op    string
-	Path  string
-	Value string
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value string `json:"value"`
 }
 
 func (r Operation) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
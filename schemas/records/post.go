@@ -11,10 +11,10 @@ import (
 )
 
 type PostRecords struct {
-	Records []PostRecord
-	Score   float64
-	MaxSize int64
-	Expiry  time.Duration
+	Records []PostRecord  `json:"records"`
+	Score   float64       `json:"score"`
+	MaxSize int64         `json:"max_size"`
+	Expiry  time.Duration `json:"expiry"`
 }
 
 func (r PostRecords) Write(fb *flatbuffers.Builder) ([]byte, error) {
@@ -51,10 +51,14 @@ func (r PostRecords) Write(fb *flatbuffers.Builder) ([]byte, error) {
 }
 
 type PostRecord struct {
-	Id                        bson.ObjectId
-	Updated, Reserved, Expiry time.Time
-	Cost                      Cost
-	OwnerId, TransactionId    bson.ObjectId
+	Id            bson.ObjectId `json:"id"`
+	Updated       time.Time     `json:"updated"`
+	Reserved      time.Time     `json:"reserved"`
+	Expiry        time.Time     `json:"expiry"`
+	Cost          Cost          `json:"cost"`
+	OwnerId       bson.ObjectId `json:"owner_id"`
+	TransactionId bson.ObjectId `json:"transaction_id"`
+	State         State         `json:"state,omitempty"`
 }
 
 // WritePostRecord represents away of writing a PostRecord to a byte buffer
@@ -99,6 +103,7 @@ func (r PostRecord) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, err
 	schema.PostRecordAddCost(fb, costPosition)
 	schema.PostRecordAddOwnerId(fb, ownerIdPosition)
 	schema.PostRecordAddTransactionId(fb, transactionIdPosition)
+	schema.PostRecordAddState(fb, r.State.schema())
 
 	return schema.PostRecordEnd(fb), nil
 }
@@ -129,6 +134,7 @@ func (r *PostRecord) Read(bytes []byte) error {
 	r.Reserved = time.Unix(0, int64(obj.Reserved()))
 	r.Expiry = time.Unix(0, int64(obj.Expiry()))
 	r.TransactionId = bson.ObjectIdHex(transactionId)
+	r.State = stateFromSchema(obj.State())
 
 	var (
 		cost     = obj.Cost(nil)
@@ -166,6 +172,7 @@ func PostRecordFromSchemaToByte(fb *flatbuffers.Builder, record *schema.PostReco
 		},
 		OwnerId:       bson.ObjectIdHex(string(ownerId)),
 		TransactionId: bson.ObjectIdHex(string(transactionId)),
+		State:         stateFromSchema(record.State()),
 	}
 
 	return value.Write(fb)
@@ -12,11 +12,11 @@ import (
 )
 
 type PutRecords struct {
-	Key     bs.Key
-	Records []PutRecord
-	Score   float64
-	MaxSize int64
-	Expiry  time.Duration
+	Key     bs.Key        `json:"key,omitempty"`
+	Records []PutRecord   `json:"records"`
+	Score   float64       `json:"score"`
+	MaxSize int64         `json:"max_size"`
+	Expiry  time.Duration `json:"expiry"`
 }
 
 func (r PutRecords) Write(fb *flatbuffers.Builder) ([]byte, error) {
@@ -53,13 +53,15 @@ func (r PutRecords) Write(fb *flatbuffers.Builder) ([]byte, error) {
 }
 
 type PutRecord struct {
-	Id                 bson.ObjectId
-	Updated, Purchased time.Time
-	EventCost          Cost
-	EventDates         Dates
-	OwnerId            bson.ObjectId
-	TransactionId      bson.ObjectId
-	Codes              Codes
+	Id            bson.ObjectId `json:"id"`
+	Updated       time.Time     `json:"updated"`
+	Purchased     time.Time     `json:"purchased"`
+	EventCost     Cost          `json:"event_cost"`
+	EventDates    Dates         `json:"event_dates"`
+	OwnerId       bson.ObjectId `json:"owner_id"`
+	TransactionId bson.ObjectId `json:"transaction_id"`
+	Codes         Codes         `json:"codes"`
+	State         State         `json:"state,omitempty"`
 }
 
 // WritePutRecord represents away of writing a PutRecord to a byte buffer
@@ -96,6 +98,12 @@ func (r PutRecord) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, erro
 		return 0, err
 	}
 
+	// A put record is purchased, so unless it's told otherwise it's confirmed.
+	state := r.State
+	if state == "" {
+		state = Confirmed
+	}
+
 	now := time.Now()
 
 	schema.PutRecordStart(fb)
@@ -107,6 +115,7 @@ func (r PutRecord) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, erro
 	schema.PutRecordAddEventCost(fb, costPosition)
 	schema.PutRecordAddEventDates(fb, datesPosition)
 	schema.PutRecordAddTransactionId(fb, transactionIdPosition)
+	schema.PutRecordAddState(fb, state.schema())
 
 	return schema.PutRecordEnd(fb), nil
 }
@@ -136,6 +145,7 @@ func (r *PutRecord) Read(bytes []byte) error {
 	r.Updated = time.Unix(0, int64(obj.Updated()))
 	r.Purchased = time.Unix(0, int64(obj.Purchased()))
 	r.TransactionId = bson.ObjectIdHex(transactionId)
+	r.State = stateFromSchema(obj.State())
 
 	var (
 		cost     = obj.EventCost(nil)
@@ -183,6 +193,7 @@ func PutRecordFromSchemaToByte(fb *flatbuffers.Builder, record *schema.PutRecord
 			End:   dates.End(),
 		},
 		TransactionId: bson.ObjectIdHex(string(transactionId)),
+		State:         stateFromSchema(record.State()),
 	}
 
 	return value.Write(fb)
@@ -71,7 +71,7 @@ func (i Id) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
 
 // Version defines a struct that represents a Version
 type Version struct {
-	Version string
+	Version string `json:"version"`
 }
 
 func (c Version) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
@@ -83,10 +83,29 @@ func (c Version) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error)
 	return schema.VersionEnd(fb), nil
 }
 
+// Admission defines a struct that represents where a token is in the queue of
+// a key, along with how long it's expected to wait for.
+type Admission struct {
+	Token    string        `json:"token"`
+	Position int64         `json:"position"`
+	ETA      time.Duration `json:"eta"`
+}
+
+func (a Admission) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
+	position := fb.CreateString(a.Token)
+
+	schema.AdmissionStart(fb)
+	schema.AdmissionAddToken(fb, position)
+	schema.AdmissionAddPosition(fb, a.Position)
+	schema.AdmissionAddEta(fb, int64(a.ETA))
+
+	return schema.AdmissionEnd(fb), nil
+}
+
 // Cost defines a struct that represents a currency and price as a tuple
 type Cost struct {
-	Currency string
-	Price    uint64
+	Currency string `json:"currency"`
+	Price    uint64 `json:"price"`
 }
 
 func (c Cost) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
@@ -104,7 +123,8 @@ func (c Cost) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
 }
 
 type Dates struct {
-	Start, End uint64
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
 }
 
 func (r Dates) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
@@ -116,7 +136,10 @@ func (r Dates) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
 }
 
 type Codes struct {
-	BarcodeType, BarcodeOrigin, BarcodeSource, QRCode string
+	BarcodeType   string `json:"barcode_type"`
+	BarcodeOrigin string `json:"barcode_origin"`
+	BarcodeSource string `json:"barcode_source"`
+	QRCode        string `json:"qr_code"`
 }
 
 func (c Codes) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
@@ -190,6 +213,16 @@ func (k KeyFieldScoreTxnValue) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UO
 	return schema.KeyFieldScoreTxnValueEnd(fb), nil
 }
 
+func (k KeyFieldScoreTxnValue) Write(fb *flatbuffers.Builder) ([]byte, error) {
+	position, err := k.WriteSub(fb)
+	if err != nil {
+		return nil, err
+	}
+
+	fb.Finish(position)
+	return fb.FinishedBytes(), nil
+}
+
 func (k KeyFieldScoreTxnValue) KeyFieldTxnValue() selectors.KeyFieldTxnValue {
 	return selectors.KeyFieldTxnValue{
 		Key:   k.Key,
@@ -270,6 +303,27 @@ func ReadBody(value string) ([]byte, error) {
 	return []byte(value[1:]), nil
 }
 
+// ReadOwnerId returns the owner id from the header of the value, a value that
+// isn't a valid record returns an error.
+func ReadOwnerId(value string) (ownerId bs.Key, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ownerId, err = "", ErrInvalidLength(23)
+		}
+	}()
+
+	body, err := ReadBody(value)
+	if err != nil {
+		return "", err
+	}
+
+	var header Header
+	if err := header.Read(body); err != nil {
+		return "", err
+	}
+	return header.OwnerId, nil
+}
+
 type KeyField struct {
 	Key, Field bs.Key
 }
@@ -487,3 +541,75 @@ func FromQueryRecords(values []selectors.QueryRecord) ([]QueryRecord, error) {
 	}
 	return res, nil
 }
+
+// Change defines a struct that represents a change of a member, as read from the
+// change feed.
+type Change struct {
+	Type       string
+	Key, Field bs.Key
+	Score      float64
+	Txn        bs.Key
+	State      string
+}
+
+func (c Change) Write(fb *flatbuffers.Builder) ([]byte, error) {
+	position, err := c.WriteSub(fb)
+	if err != nil {
+		return nil, err
+	}
+
+	fb.Finish(position)
+	return fb.FinishedBytes(), nil
+}
+
+func (c Change) WriteSub(fb *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
+	if len(c.Type) < 1 {
+		return 0, ErrInvalidLength(24)
+	}
+
+	var (
+		position0 = fb.CreateString(c.Type)
+		position1 = fb.CreateString(c.Key.String())
+		position2 = fb.CreateString(c.Field.String())
+		position3 = fb.CreateString(c.Txn.String())
+		position4 = fb.CreateString(c.State)
+	)
+
+	schema.ChangeStart(fb)
+	schema.ChangeAddTyp(fb, position0)
+	schema.ChangeAddKey(fb, position1)
+	schema.ChangeAddField(fb, position2)
+	schema.ChangeAddScore(fb, c.Score)
+	schema.ChangeAddTxn(fb, position3)
+	schema.ChangeAddState(fb, position4)
+
+	return schema.ChangeEnd(fb), nil
+}
+
+func (c *Change) Read(bytes []byte) error {
+	if len(bytes) < 1 {
+		return ErrInvalidLength(25)
+	}
+
+	record := schema.GetRootAsChange(bytes, 0)
+
+	c.Type = string(record.Typ())
+	c.Key = bs.Key(string(record.Key()))
+	c.Field = bs.Key(string(record.Field()))
+	c.Score = record.Score()
+	c.Txn = bs.Key(string(record.Txn()))
+	c.State = string(record.State())
+
+	return nil
+}
+
+func FromChange(value selectors.Change) Change {
+	return Change{
+		Type:  value.Type.String(),
+		Key:   value.Key,
+		Field: value.Field,
+		Score: value.Score,
+		Txn:   value.Txn,
+		State: value.State,
+	}
+}
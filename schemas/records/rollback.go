@@ -12,11 +12,11 @@ import (
 )
 
 type RollbackRecords struct {
-	Key     bs.Key
-	Records []RollbackRecord
-	Score   float64
-	MaxSize int64
-	Expiry  time.Duration
+	Key     bs.Key           `json:"key,omitempty"`
+	Records []RollbackRecord `json:"records"`
+	Score   float64          `json:"score"`
+	MaxSize int64            `json:"max_size"`
+	Expiry  time.Duration    `json:"expiry"`
 }
 
 func (r RollbackRecords) Write(fb *flatbuffers.Builder) ([]byte, error) {
@@ -53,9 +53,10 @@ func (r RollbackRecords) Write(fb *flatbuffers.Builder) ([]byte, error) {
 }
 
 type RollbackRecord struct {
-	Id                     bson.ObjectId
-	Updated                time.Time
-	OwnerId, TransactionId bson.ObjectId
+	Id            bson.ObjectId `json:"id"`
+	Updated       time.Time     `json:"updated"`
+	OwnerId       bson.ObjectId `json:"owner_id"`
+	TransactionId bson.ObjectId `json:"transaction_id"`
 }
 
 // WriteRollbackRecord represents away of writing a RollbackRecord to a byte buffer
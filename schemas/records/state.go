@@ -0,0 +1,103 @@
+package records
+
+import (
+	"time"
+
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/schema"
+)
+
+// State defines where a record is with in the lifecycle of a reservation. A
+// record is held, then charged, before it's confirmed. A record that's been
+// charged (or confirmed) can then be refunded.
+type State string
+
+const (
+	// Held is a record that's reserved, until it expires.
+	Held State = "held"
+
+	// Charged is a held record that's been paid for.
+	Charged State = "charged"
+
+	// Confirmed is a record that's been purchased.
+	Confirmed State = "confirmed"
+
+	// Refunded is a record where the payment has been given back.
+	Refunded State = "refunded"
+)
+
+// transitions defines which states a state can move to.
+var transitions = map[State][]State{
+	Held:      []State{Charged},
+	Charged:   []State{Confirmed, Refunded},
+	Confirmed: []State{Refunded},
+	Refunded:  []State{},
+}
+
+// ParseState returns the state of the value.
+func ParseState(value string) (State, error) {
+	state := State(value)
+	if _, ok := transitions[state]; !ok {
+		return Held, typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid state (%s)", value)
+	}
+	return state, nil
+}
+
+func (s State) String() string {
+	return string(s)
+}
+
+// Hold returns true if the state is still a hold, that expires.
+func (s State) Hold() bool {
+	return s == Held || s == Charged
+}
+
+// Transition checks that the state can move to the other state. A hold that's
+// expired can't move forward, it can only be refunded. Moving to the same
+// state is allowed, so that the transitions can be retried.
+func (s State) Transition(to State, expiry, now time.Time) error {
+	if s == to {
+		return nil
+	}
+
+	for _, v := range transitions[s] {
+		if v != to {
+			continue
+		}
+
+		if s.Hold() && to != Refunded && !expiry.After(now) {
+			return typex.Errorf(errors.Source, errors.IllegalTransition,
+				"Unable to move from %s to %s, the hold expired", s, to)
+		}
+		return nil
+	}
+
+	return typex.Errorf(errors.Source, errors.IllegalTransition,
+		"Unable to move from %s to %s", s, to)
+}
+
+func (s State) schema() int8 {
+	switch s {
+	case Charged:
+		return schema.StateCharged
+	case Confirmed:
+		return schema.StateConfirmed
+	case Refunded:
+		return schema.StateRefunded
+	}
+	return schema.StateHeld
+}
+
+func stateFromSchema(state int8) State {
+	switch state {
+	case schema.StateCharged:
+		return Charged
+	case schema.StateConfirmed:
+		return Confirmed
+	case schema.StateRefunded:
+		return Refunded
+	}
+	return Held
+}
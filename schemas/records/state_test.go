@@ -0,0 +1,111 @@
+package records
+
+import (
+	"testing"
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestStateTransition(t *testing.T) {
+	var (
+		now     = time.Now()
+		active  = now.Add(time.Minute)
+		expired = now.Add(-time.Minute)
+	)
+
+	for _, v := range []struct {
+		from, to State
+		expiry   time.Time
+		valid    bool
+	}{
+		{Held, Charged, active, true},
+		{Held, Charged, expired, false},
+		{Held, Confirmed, active, false},
+		{Held, Refunded, active, false},
+		{Held, Held, expired, true},
+		{Charged, Confirmed, active, true},
+		{Charged, Confirmed, expired, false},
+		{Charged, Refunded, expired, true},
+		{Charged, Held, active, false},
+		{Confirmed, Refunded, expired, true},
+		{Confirmed, Charged, active, false},
+		{Refunded, Confirmed, active, false},
+	} {
+		err := v.from.Transition(v.to, v.expiry, now)
+		if expected, actual := v.valid, err == nil; expected != actual {
+			t.Errorf("%s to %s: Expected: %v, Actual: %v", v.from, v.to, expected, actual)
+		}
+	}
+}
+
+func TestParseState(t *testing.T) {
+	for _, v := range []State{Held, Charged, Confirmed, Refunded} {
+		state, err := ParseState(v.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := v, state; expected != actual {
+			t.Errorf("Expected: %v, Actual: %v", expected, actual)
+		}
+	}
+
+	if _, err := ParseState("reserved"); err == nil {
+		t.Errorf("Expected: error, Actual: %v", err)
+	}
+}
+
+func TestPostRecordState(t *testing.T) {
+	for _, v := range []struct {
+		state, expected State
+	}{
+		{"", Held},
+		{Charged, Charged},
+		{Refunded, Refunded},
+	} {
+		record := PostRecord{
+			Id:            bson.NewObjectId(),
+			Expiry:        time.Now().Add(time.Minute),
+			Cost:          Cost{Currency: "GBP", Price: 100},
+			OwnerId:       bson.NewObjectId(),
+			TransactionId: bson.NewObjectId(),
+			State:         v.state,
+		}
+
+		bytes, err := record.Write(flatbuffers.NewBuilder(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual PostRecord
+		if err := actual.Read(bytes); err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := v.expected, actual.State; expected != actual {
+			t.Errorf("Expected: %v, Actual: %v", expected, actual)
+		}
+	}
+}
+
+func TestPutRecordState(t *testing.T) {
+	record := PutRecord{
+		Id:            bson.NewObjectId(),
+		OwnerId:       bson.NewObjectId(),
+		TransactionId: bson.NewObjectId(),
+		EventCost:     Cost{Currency: "GBP", Price: 100},
+	}
+
+	bytes, err := record.Write(flatbuffers.NewBuilder(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual PutRecord
+	if err := actual.Read(bytes); err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := Confirmed, actual.State; expected != actual {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
@@ -0,0 +1,172 @@
+package records
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+	"github.com/SimonRichardson/echelon/schemas/schema"
+	"github.com/SimonRichardson/echelon/selectors"
+)
+
+// Accessor reads and writes the fields of a post record, that a modification
+// with operations can match or replace.
+type Accessor struct{}
+
+// GetFieldValue returns the value of the field of the record.
+func (a Accessor) GetFieldValue(i interface{}, field string) (string, error) {
+	record, ok := i.(*PostRecord)
+	if !ok {
+		return "", typex.Errorf(errors.Source, errors.InvalidArgument, "Invalid Type")
+	}
+
+	switch field {
+	case "txn":
+		return record.TransactionId.Hex(), nil
+	case "owner_id":
+		return record.OwnerId.Hex(), nil
+	case "expiry_time":
+		return fmt.Sprintf("%d", record.Expiry.UnixNano()), nil
+	case "state":
+		return record.State.String(), nil
+	default:
+		return "", typex.Errorf(errors.Source, errors.UnexpectedResults,
+			"Invalid property %s", field)
+	}
+}
+
+// SetFieldValue replaces the value of the field of the record.
+func (a Accessor) SetFieldValue(i interface{}, field, value string) error {
+	record, ok := i.(*PostRecord)
+	if !ok {
+		return typex.Errorf(errors.Source, errors.InvalidArgument, "Invalid Type")
+	}
+
+	switch field {
+	case "txn":
+		if !bson.IsObjectIdHex(value) {
+			return typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid ObjectId (%s)", value)
+		}
+		record.TransactionId = bson.ObjectIdHex(value)
+	case "owner_id":
+		if !bson.IsObjectIdHex(value) {
+			return typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid ObjectId (%s)", value)
+		}
+		record.OwnerId = bson.ObjectIdHex(value)
+	case "expiry_time":
+		s, err := strconv.Atoi(value)
+		if err != nil {
+			return typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid time (%s)", value)
+		}
+		record.Expiry = time.Unix(0, int64(s))
+	case "state":
+		state, err := ParseState(value)
+		if err != nil {
+			return err
+		}
+		record.State = state
+	default:
+		return typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid property %s", field)
+	}
+	return nil
+}
+
+// Transform turns a stored value into a document, so that it can be persisted
+// or queried.
+func Transform(value selectors.KeyFieldScoreTxnValue) (map[string]interface{}, error) {
+	header, err := ReadType(value.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		meta = func(updated time.Time) map[string]interface{} {
+			m := map[string]interface{}{
+				"model": map[string]interface{}{
+					"created_at": time.Now(),
+					"updated_at": updated,
+				},
+			}
+			return m
+		}
+		m = map[string]interface{}{}
+	)
+
+	switch header {
+	case schema.TypePost:
+		var (
+			record    = &PostRecord{}
+			body, err = ReadBody(value.Value)
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err = record.Read(body); err != nil {
+			return nil, err
+		}
+
+		m["_id"] = bson.ObjectIdHex(value.Field.String())
+		m["owner_id"] = record.OwnerId
+		m["expiry_time"] = record.Expiry
+		m["reserved_at"] = record.Reserved
+		m["meta"] = meta(record.Updated)
+		m["txn"] = record.TransactionId
+		m["state"] = record.State.String()
+
+		cost := record.Cost
+		m["cost"] = map[string]interface{}{
+			"currency": cost.Currency,
+			"price":    cost.Price,
+		}
+
+	case schema.TypePut:
+		var (
+			record    = &PutRecord{}
+			body, err = ReadBody(value.Value)
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err = record.Read(body); err != nil {
+			return nil, err
+		}
+
+		m["_id"] = bson.ObjectIdHex(value.Field.String())
+		m["owner_id"] = record.OwnerId
+		m["purchased_at"] = record.Purchased
+		m["meta"] = meta(record.Updated)
+		m["txn"] = record.TransactionId
+		m["state"] = record.State.String()
+
+		cost := record.EventCost
+		m["cost"] = map[string]interface{}{
+			"currency": cost.Currency,
+			"price":    cost.Price,
+		}
+
+		dates := record.EventDates
+		m["event_date"] = time.Unix(0, int64(dates.Start))
+		m["event_date_end"] = time.Unix(0, int64(dates.End))
+
+		codes := record.Codes
+		m["bar_code"] = map[string]interface{}{
+			"type":   codes.BarcodeType,
+			"origin": codes.BarcodeOrigin,
+			"source": codes.BarcodeSource,
+		}
+		m["qr_code"] = codes.QRCode
+
+	default:
+		return nil, typex.Errorf(errors.Source, errors.NoCaseFound, "Unknown Type")
+	}
+
+	return m, nil
+}
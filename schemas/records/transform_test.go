@@ -0,0 +1,82 @@
+package records
+
+import (
+	"testing"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+	"github.com/SimonRichardson/echelon/selectors"
+	"github.com/google/flatbuffers/go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func transformed(t *testing.T, record PostRecord) selectors.QueryRecord {
+	value, err := record.Write(flatbuffers.NewBuilder(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	member := selectors.KeyFieldScoreTxnValue{
+		Key:   bs.Key("key"),
+		Field: bs.Key(record.Id.Hex()),
+		Score: 1,
+		Txn:   bs.Key(record.TransactionId.Hex()),
+		Value: PackagePostRecord(value),
+	}
+	m, err := Transform(member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return selectors.QueryRecord{
+		Key:    member.Key,
+		Field:  member.Field,
+		Record: m,
+	}
+}
+
+func TestTransformFilterTransaction(t *testing.T) {
+	var (
+		now     = time.Now()
+		txn     = bson.NewObjectId()
+		records = []selectors.QueryRecord{}
+	)
+
+	for _, v := range []bson.ObjectId{txn, bson.NewObjectId(), txn} {
+		records = append(records, transformed(t, PostRecord{
+			Id:            bson.NewObjectId(),
+			Updated:       now,
+			Reserved:      now,
+			Expiry:        now.Add(time.Minute),
+			Cost:          Cost{Currency: "GBP", Price: 100},
+			OwnerId:       bson.NewObjectId(),
+			TransactionId: v,
+		}))
+	}
+
+	for _, field := range []string{"transaction_id", "txn"} {
+		options := selectors.QueryOptions{
+			Predicates: []selectors.Predicate{
+				{Field: field, Comparison: selectors.Equal, Values: []string{txn.Hex()}},
+			},
+		}
+
+		index, value, ok := options.Index()
+		if expected, actual := true, ok; expected != actual {
+			t.Fatalf("%s: Expected: %v, Actual: %v", field, expected, actual)
+		}
+		if expected, actual := selectors.TransactionIndex, index; expected != actual {
+			t.Errorf("%s: Expected: %v, Actual: %v", field, expected, actual)
+		}
+		if expected, actual := txn.Hex(), value; expected != actual {
+			t.Errorf("%s: Expected: %v, Actual: %v", field, expected, actual)
+		}
+
+		result, err := options.Filter(records, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := 2, len(result); expected != actual {
+			t.Errorf("%s: Expected: %v, Actual: %v", field, expected, actual)
+		}
+	}
+}
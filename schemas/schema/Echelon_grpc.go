@@ -0,0 +1,534 @@
+//Generated by gRPC Go plugin
+//If you make any local changes, they will be lost
+//source: echelon
+
+package schema
+
+import (
+	context "context"
+	flatbuffers "github.com/google/flatbuffers/go"
+	grpc "google.golang.org/grpc"
+)
+
+// Client API for Echelon service
+type EchelonClient interface {
+	Insert(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (*OKInt, error)
+	Modify(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (*OKInt, error)
+	ModifyWithOperations(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (*OKInt, error)
+	Delete(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (*OKInt, error)
+	Rollback(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (*OKNoContent, error)
+	Select(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (*OKKeyFieldScoreTxnValue, error)
+	SelectRange(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (*OKKeyFieldScoreTxnValues, error)
+	StreamRange(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (Echelon_StreamRangeClient, error)
+	Query(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (*OKQuery, error)
+	Size(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (*OKInt, error)
+	Keys(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (*OKKeys, error)
+	Changes(ctx context.Context, in *flatbuffers.Builder,
+		opts ...grpc.CallOption) (Echelon_ChangesClient, error)
+}
+
+type echelonClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewEchelonClient(cc *grpc.ClientConn) EchelonClient {
+	return &echelonClient{cc}
+}
+
+func (c *echelonClient) Insert(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (*OKInt, error) {
+	out := new(OKInt)
+	err := grpc.Invoke(ctx, "/schema.Echelon/Insert", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echelonClient) Modify(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (*OKInt, error) {
+	out := new(OKInt)
+	err := grpc.Invoke(ctx, "/schema.Echelon/Modify", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echelonClient) ModifyWithOperations(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (*OKInt, error) {
+	out := new(OKInt)
+	err := grpc.Invoke(ctx, "/schema.Echelon/ModifyWithOperations", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echelonClient) Delete(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (*OKInt, error) {
+	out := new(OKInt)
+	err := grpc.Invoke(ctx, "/schema.Echelon/Delete", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echelonClient) Rollback(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (*OKNoContent, error) {
+	out := new(OKNoContent)
+	err := grpc.Invoke(ctx, "/schema.Echelon/Rollback", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echelonClient) Select(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (*OKKeyFieldScoreTxnValue, error) {
+	out := new(OKKeyFieldScoreTxnValue)
+	err := grpc.Invoke(ctx, "/schema.Echelon/Select", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echelonClient) SelectRange(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (*OKKeyFieldScoreTxnValues, error) {
+	out := new(OKKeyFieldScoreTxnValues)
+	err := grpc.Invoke(ctx, "/schema.Echelon/SelectRange", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echelonClient) StreamRange(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (Echelon_StreamRangeClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Echelon_serviceDesc.Streams[0], c.cc, "/schema.Echelon/StreamRange", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &echelonStreamRangeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Echelon_StreamRangeClient interface {
+	Recv() (*KeyFieldScoreTxnValue, error)
+	grpc.ClientStream
+}
+
+type echelonStreamRangeClient struct {
+	grpc.ClientStream
+}
+
+func (x *echelonStreamRangeClient) Recv() (*KeyFieldScoreTxnValue, error) {
+	m := new(KeyFieldScoreTxnValue)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *echelonClient) Query(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (*OKQuery, error) {
+	out := new(OKQuery)
+	err := grpc.Invoke(ctx, "/schema.Echelon/Query", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echelonClient) Size(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (*OKInt, error) {
+	out := new(OKInt)
+	err := grpc.Invoke(ctx, "/schema.Echelon/Size", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echelonClient) Keys(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (*OKKeys, error) {
+	out := new(OKKeys)
+	err := grpc.Invoke(ctx, "/schema.Echelon/Keys", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echelonClient) Changes(ctx context.Context, in *flatbuffers.Builder,
+	opts ...grpc.CallOption) (Echelon_ChangesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Echelon_serviceDesc.Streams[1], c.cc, "/schema.Echelon/Changes", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &echelonChangesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Echelon_ChangesClient interface {
+	Recv() (*Change, error)
+	grpc.ClientStream
+}
+
+type echelonChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *echelonChangesClient) Recv() (*Change, error) {
+	m := new(Change)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Echelon service
+type EchelonServer interface {
+	Insert(context.Context, *InsertCall) (*flatbuffers.Builder, error)
+	Modify(context.Context, *ModifyCall) (*flatbuffers.Builder, error)
+	ModifyWithOperations(context.Context, *ModifyWithOperationsCall) (*flatbuffers.Builder, error)
+	Delete(context.Context, *DeleteCall) (*flatbuffers.Builder, error)
+	Rollback(context.Context, *RollbackCall) (*flatbuffers.Builder, error)
+	Select(context.Context, *SelectCall) (*flatbuffers.Builder, error)
+	SelectRange(context.Context, *SelectRangeCall) (*flatbuffers.Builder, error)
+	StreamRange(*SelectRangeCall, Echelon_StreamRangeServer) error
+	Query(context.Context, *QueryCall) (*flatbuffers.Builder, error)
+	Size(context.Context, *SizeCall) (*flatbuffers.Builder, error)
+	Keys(context.Context, *KeysCall) (*flatbuffers.Builder, error)
+	Changes(*ChangesCall, Echelon_ChangesServer) error
+}
+
+func RegisterEchelonServer(s *grpc.Server, srv EchelonServer) {
+	s.RegisterService(&_Echelon_serviceDesc, srv)
+}
+
+func _Echelon_Insert_Handler(srv interface{}, ctx context.Context,
+	dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertCall)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchelonServer).Insert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schema.Echelon/Insert",
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchelonServer).Insert(ctx, req.(*InsertCall))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Echelon_Modify_Handler(srv interface{}, ctx context.Context,
+	dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModifyCall)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchelonServer).Modify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schema.Echelon/Modify",
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchelonServer).Modify(ctx, req.(*ModifyCall))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Echelon_ModifyWithOperations_Handler(srv interface{}, ctx context.Context,
+	dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModifyWithOperationsCall)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchelonServer).ModifyWithOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schema.Echelon/ModifyWithOperations",
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchelonServer).ModifyWithOperations(ctx, req.(*ModifyWithOperationsCall))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Echelon_Delete_Handler(srv interface{}, ctx context.Context,
+	dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCall)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchelonServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schema.Echelon/Delete",
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchelonServer).Delete(ctx, req.(*DeleteCall))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Echelon_Rollback_Handler(srv interface{}, ctx context.Context,
+	dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackCall)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchelonServer).Rollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schema.Echelon/Rollback",
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchelonServer).Rollback(ctx, req.(*RollbackCall))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Echelon_Select_Handler(srv interface{}, ctx context.Context,
+	dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectCall)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchelonServer).Select(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schema.Echelon/Select",
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchelonServer).Select(ctx, req.(*SelectCall))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Echelon_SelectRange_Handler(srv interface{}, ctx context.Context,
+	dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectRangeCall)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchelonServer).SelectRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schema.Echelon/SelectRange",
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchelonServer).SelectRange(ctx, req.(*SelectRangeCall))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Echelon_StreamRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SelectRangeCall)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EchelonServer).StreamRange(m, &echelonStreamRangeServer{stream})
+}
+
+type Echelon_StreamRangeServer interface {
+	Send(*flatbuffers.Builder) error
+	grpc.ServerStream
+}
+
+type echelonStreamRangeServer struct {
+	grpc.ServerStream
+}
+
+func (x *echelonStreamRangeServer) Send(m *flatbuffers.Builder) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Echelon_Query_Handler(srv interface{}, ctx context.Context,
+	dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryCall)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchelonServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schema.Echelon/Query",
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchelonServer).Query(ctx, req.(*QueryCall))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Echelon_Size_Handler(srv interface{}, ctx context.Context,
+	dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SizeCall)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchelonServer).Size(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schema.Echelon/Size",
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchelonServer).Size(ctx, req.(*SizeCall))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Echelon_Keys_Handler(srv interface{}, ctx context.Context,
+	dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeysCall)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchelonServer).Keys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schema.Echelon/Keys",
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchelonServer).Keys(ctx, req.(*KeysCall))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Echelon_Changes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChangesCall)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EchelonServer).Changes(m, &echelonChangesServer{stream})
+}
+
+type Echelon_ChangesServer interface {
+	Send(*flatbuffers.Builder) error
+	grpc.ServerStream
+}
+
+type echelonChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *echelonChangesServer) Send(m *flatbuffers.Builder) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Echelon_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "schema.Echelon",
+	HandlerType: (*EchelonServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Insert",
+			Handler:    _Echelon_Insert_Handler,
+		},
+		{
+			MethodName: "Modify",
+			Handler:    _Echelon_Modify_Handler,
+		},
+		{
+			MethodName: "ModifyWithOperations",
+			Handler:    _Echelon_ModifyWithOperations_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Echelon_Delete_Handler,
+		},
+		{
+			MethodName: "Rollback",
+			Handler:    _Echelon_Rollback_Handler,
+		},
+		{
+			MethodName: "Select",
+			Handler:    _Echelon_Select_Handler,
+		},
+		{
+			MethodName: "SelectRange",
+			Handler:    _Echelon_SelectRange_Handler,
+		},
+		{
+			MethodName: "Query",
+			Handler:    _Echelon_Query_Handler,
+		},
+		{
+			MethodName: "Size",
+			Handler:    _Echelon_Size_Handler,
+		},
+		{
+			MethodName: "Keys",
+			Handler:    _Echelon_Keys_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamRange",
+			Handler:       _Echelon_StreamRange_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Changes",
+			Handler:       _Echelon_Changes_Handler,
+			ServerStreams: true,
+		},
+	},
+}
@@ -126,8 +126,20 @@ func (rcv *PostRecord) TransactionId(obj *Id) *Id {
 	return nil
 }
 
+func (rcv *PostRecord) State() int8 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(20))
+	if o != 0 {
+		return rcv._tab.GetInt8(o + rcv._tab.Pos)
+	}
+	return 1
+}
+
+func (rcv *PostRecord) MutateState(n int8) bool {
+	return rcv._tab.MutateInt8Slot(20, n)
+}
+
 func PostRecordStart(builder *flatbuffers.Builder) {
-	builder.StartObject(8)
+	builder.StartObject(9)
 }
 func PostRecordAddTyp(builder *flatbuffers.Builder, typ int8) {
 	builder.PrependInt8Slot(0, typ, 2)
@@ -153,6 +165,9 @@ func PostRecordAddCost(builder *flatbuffers.Builder, cost flatbuffers.UOffsetT)
 func PostRecordAddTransactionId(builder *flatbuffers.Builder, transactionId flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(7, flatbuffers.UOffsetT(transactionId), 0)
 }
+func PostRecordAddState(builder *flatbuffers.Builder, state int8) {
+	builder.PrependInt8Slot(8, state, 1)
+}
 func PostRecordEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
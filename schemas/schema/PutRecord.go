@@ -140,8 +140,20 @@ func (rcv *PutRecord) Codes(obj *Codes) *Codes {
 	return nil
 }
 
+func (rcv *PutRecord) State() int8 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(22))
+	if o != 0 {
+		return rcv._tab.GetInt8(o + rcv._tab.Pos)
+	}
+	return 3
+}
+
+func (rcv *PutRecord) MutateState(n int8) bool {
+	return rcv._tab.MutateInt8Slot(22, n)
+}
+
 func PutRecordStart(builder *flatbuffers.Builder) {
-	builder.StartObject(9)
+	builder.StartObject(10)
 }
 func PutRecordAddTyp(builder *flatbuffers.Builder, typ int8) {
 	builder.PrependInt8Slot(0, typ, 3)
@@ -170,6 +182,9 @@ func PutRecordAddTransactionId(builder *flatbuffers.Builder, transactionId flatb
 func PutRecordAddCodes(builder *flatbuffers.Builder, codes flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(8, flatbuffers.UOffsetT(codes), 0)
 }
+func PutRecordAddState(builder *flatbuffers.Builder, state int8) {
+	builder.PrependInt8Slot(9, state, 3)
+}
 func PutRecordEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
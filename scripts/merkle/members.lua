@@ -0,0 +1,29 @@
+-- The following code should be treated as a pure function like the following:
+-- script(key string, buckets int, indices ...int) []string
+local key = KEYS[1]
+local buckets = tonumber(ARGV[1])
+
+-- The bucket has to be computed exactly the same as merkle.Bucket.
+local bucket = function(field)
+    return tonumber(string.sub(redis.sha1hex(field), 1, 8), 16) % buckets
+end
+
+local wanted = {}
+for i = 2, #ARGV do
+    wanted[tonumber(ARGV[i])] = true
+end
+
+-- Return the field and the value for every field with in the wanted buckets,
+-- regardless of if they're inserted or deleted.
+local result = {}
+for _, suffix in ipairs({'INSERTSUFFIX', 'DELETESUFFIX'}) do
+    local values = redis.call('HGETALL', key .. suffix)
+    for i = 1, #values, 2 do
+        if wanted[bucket(values[i])] then
+            table.insert(result, values[i])
+            table.insert(result, values[i + 1])
+        end
+    end
+end
+
+return result
@@ -0,0 +1,50 @@
+-- The following code should be treated as a pure function like the following:
+-- script(key string, buckets int) []string
+local key = KEYS[1]
+local buckets = tonumber(ARGV[1])
+
+-- The bucket has to be computed exactly the same as merkle.Bucket.
+local bucket = function(field)
+    return tonumber(string.sub(redis.sha1hex(field), 1, 8), 16) % buckets
+end
+
+-- Only the score and the transaction are used for the digest, the expiry is
+-- computed when it's written to each cluster, so it will always differ.
+local version = function(value)
+    local index = string.find(value, 'SEPARATOR', 1, true)
+    if not index then
+        return value
+    end
+    local next = string.find(value, 'SEPARATOR', index + 1, true)
+    if not next then
+        return value
+    end
+    return string.sub(value, 1, next - 1)
+end
+
+local entries = {}
+for i = 1, buckets do
+    entries[i] = {}
+end
+
+for _, suffix in ipairs({'INSERTSUFFIX', 'DELETESUFFIX'}) do
+    local values = redis.call('HGETALL', key .. suffix)
+    for i = 1, #values, 2 do
+        local field = values[i]
+        local index = bucket(field) + 1
+        table.insert(entries[index], redis.sha1hex(suffix .. field .. version(values[i + 1])))
+    end
+end
+
+-- Empty buckets have an empty digest, so that they're equal on every cluster.
+local result = {}
+for i = 1, buckets do
+    if #entries[i] == 0 then
+        result[i] = ''
+    else
+        table.sort(entries[i])
+        result[i] = redis.sha1hex(table.concat(entries[i]))
+    end
+end
+
+return result
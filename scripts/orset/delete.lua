@@ -0,0 +1,100 @@
+-- The following code should be treated as a pure function like the following:
+-- script(key, field string, score float64, txn, data string, retention int)
+local key = KEYS[1]
+local field = ARGV[1]
+local score = tonumber(ARGV[2])
+local txn = ARGV[3]
+local data = ARGV[4]
+local retention = tonumber(ARGV[5])
+
+-- SMEMBERS and TIME are non deterministic, so replicate the effects of the
+-- script rather than the script itself.
+redis.replicate_commands()
+
+-- A deletion only removes the tags it has observed, which are the insertions of
+-- the same transaction that are scored no later than the deletion. Any
+-- concurrent insertion with a different transaction, or a later insertion of
+-- the same transaction, survives.
+local prefix = string.len(field) .. 'SEPARATOR' .. field
+local tags = key .. 'TAGSSUFFIX' .. field
+
+local extract = function(value)
+    local index = string.find(value, 'SEPARATOR', 1, true)
+    if not index or index < 2 then
+        return nil, ''
+    end
+    local next = string.find(value, 'SEPARATOR', index + 1, true)
+    if not next then
+        return nil, ''
+    end
+    return tonumber(string.sub(value, 1, index - 1)), string.sub(value, index + 1, next - 1)
+end
+
+-- Record the highest score observed for the transaction, even if none of its
+-- tags have been seen yet, so that a deletion arriving before its insertion
+-- still converges. The tombstones are scored by when they were written and are
+-- only kept for the retention (in milliseconds), any insertion that arrives
+-- later than that is lost.
+local observed = key .. 'OBSERVEDSUFFIX'
+local previous = redis.call('HGET', observed, prefix .. txn)
+if not previous or score > tonumber(previous) then
+    redis.call('HSET', observed, prefix .. txn, ARGV[2])
+end
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local tombstones = key .. 'TOMBSTONESUFFIX'
+redis.call('ZADD', tombstones, now, prefix .. txn)
+for _, expired in ipairs(redis.call('ZRANGEBYSCORE', tombstones, '-inf', '(' .. (now - retention))) do
+    redis.call('HDEL', observed, expired)
+end
+redis.call('ZREMRANGEBYSCORE', tombstones, '-inf', '(' .. (now - retention))
+redis.call('PEXPIRE', tombstones, retention)
+redis.call('PEXPIRE', observed, retention)
+
+-- The members of the tags are the score and the transaction of each insertion,
+-- the score can't hold the separator so it's always the first part.
+local split = function(member)
+    local index = string.find(member, 'SEPARATOR', 1, true)
+    if not index then
+        return nil, ''
+    end
+    return tonumber(string.sub(member, 1, index - 1)), string.sub(member, index + 1)
+end
+
+local members = redis.call('SMEMBERS', tags)
+local remaining = {}
+for _, member in ipairs(members) do
+    local memberScore, memberTxn = split(member)
+    if memberTxn == txn and memberScore and memberScore <= score then
+        redis.call('HDEL', key .. 'TAGGEDSUFFIX', prefix .. member)
+        redis.call('SREM', tags, member)
+    else
+        table.insert(remaining, member)
+    end
+end
+
+if #remaining == 0 then
+    redis.call('HDEL', key .. 'INSERTSUFFIX', field)
+    redis.call('HSET', key .. 'DELETESUFFIX', field, data)
+    return 1
+end
+
+-- Note: add wins, so elect the winning value from the tags that remain.
+local winner, winnerScore, winnerTxn = nil, nil, nil
+for _, other in ipairs(remaining) do
+    local value = redis.call('HGET', key .. 'TAGGEDSUFFIX', prefix .. other)
+    if value then
+        local valueScore, valueTxn = extract(value)
+        if valueScore and (not winner or valueScore > winnerScore or
+            (valueScore == winnerScore and valueTxn > winnerTxn)) then
+            winner, winnerScore, winnerTxn = value, valueScore, valueTxn
+        end
+    end
+end
+
+if winner then
+    redis.call('HSET', key .. 'INSERTSUFFIX', field, winner)
+end
+
+return 1
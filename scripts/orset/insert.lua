@@ -0,0 +1,61 @@
+-- The following code should be treated as a pure function like the following:
+-- script(key, field string, score float64, txn, data string)
+local key = KEYS[1]
+local field = ARGV[1]
+local score = tonumber(ARGV[2])
+local txn = ARGV[3]
+local data = ARGV[4]
+
+-- Every insertion is tagged with its score and transaction, so that the same
+-- transaction can add the field back after it's been deleted. The field is
+-- prefixed by it's length, as it could hold the separator.
+local member = ARGV[2] .. 'SEPARATOR' .. txn
+local tag = string.len(field) .. 'SEPARATOR' .. field .. member
+
+local extract = function(value)
+    local index = string.find(value, 'SEPARATOR', 1, true)
+    if not index or index < 2 then
+        return nil, ''
+    end
+    local next = string.find(value, 'SEPARATOR', index + 1, true)
+    if not next then
+        return nil, ''
+    end
+    return tonumber(string.sub(value, 1, index - 1)), string.sub(value, index + 1, next - 1)
+end
+
+-- The winning value for a field is the one with the highest score, ties are
+-- broken on the transaction so that every replica picks the same value.
+local wins = function(score, txn, value)
+    local valueScore, valueTxn = extract(value)
+    if not valueScore then
+        return true
+    end
+    return score > valueScore or (score == valueScore and txn > valueTxn)
+end
+
+-- Insertions of the transaction that a deletion has already observed can never
+-- be added back, otherwise a deletion that arrives before its insertion would
+-- be lost.
+local observed = redis.call('HGET', key .. 'OBSERVEDSUFFIX', string.len(field) .. 'SEPARATOR' .. field .. txn)
+if observed and score <= tonumber(observed) then
+    return -1
+end
+
+-- Replaying the same insertion is a no-op, this keeps replays idempotent.
+if redis.call('HEXISTS', key .. 'TAGGEDSUFFIX', tag) == 1 then
+    return -1
+end
+
+redis.call('HSET', key .. 'TAGGEDSUFFIX', tag, data)
+redis.call('SADD', key .. 'TAGSSUFFIX' .. field, member)
+
+-- Note: add wins
+redis.call('HDEL', key .. 'DELETESUFFIX', field)
+
+local insertion = redis.call('HGET', key .. 'INSERTSUFFIX', field)
+if insertion and not wins(score, txn, insertion) then
+    return 0
+end
+
+return redis.call('HSET', key .. 'INSERTSUFFIX', field, data)
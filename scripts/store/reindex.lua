@@ -0,0 +1,51 @@
+-- The following code should be treated as a pure function like the following:
+-- script(key, field, owner string)
+local key = KEYS[1]
+local field = ARGV[1]
+local owner = ARGV[2]
+
+local unindex = function(field)
+    local entry = redis.call('HGET', key .. 'INDEXSUFFIX', field)
+    if not entry then
+        return
+    end
+
+    local index = string.find(entry, 'SEPARATOR', 1, true)
+    if index then
+        local entryOwner = string.sub(entry, 1, index - 1)
+        if entryOwner ~= '' then
+            redis.call('SREM', key .. 'OWNERSUFFIX' .. entryOwner, field)
+        end
+        redis.call('SREM', key .. 'TXNSUFFIX' .. string.sub(entry, index + 1), field)
+    end
+    redis.call('HDEL', key .. 'INDEXSUFFIX', field)
+end
+
+local index = function(field, owner, txn)
+    if owner ~= '' then
+        redis.call('SADD', key .. 'OWNERSUFFIX' .. owner, field)
+    end
+    redis.call('SADD', key .. 'TXNSUFFIX' .. txn, field)
+    redis.call('HSET', key .. 'INDEXSUFFIX', field, owner .. 'SEPARATOR' .. txn)
+end
+
+-- Drop whatever the field was indexed under, then index it again with the
+-- transaction of the current insertion (if there still is one).
+unindex(field)
+
+local insertion = redis.call('HGET', key .. 'INSERTSUFFIX', field)
+if not insertion then
+    return 0
+end
+
+local first = string.find(insertion, 'SEPARATOR', 1, true)
+if not first then
+    return 0
+end
+local second = string.find(insertion, 'SEPARATOR', first + 1, true)
+if not second then
+    return 0
+end
+
+index(field, owner, string.sub(insertion, first + 1, second - 1))
+return 1
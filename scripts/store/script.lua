@@ -1,10 +1,15 @@
 -- The following code should be treated as a pure function like the following:
--- script(key, field string, score float64, txn, data string)
+-- script(key, field string, score float64, txn, data, owner string,
+--        expiry int64, scheduled, version string)
 local key = KEYS[1]
 local field = ARGV[1]
 local score = tonumber(ARGV[2])
 local txn = ARGV[3]
 local data = ARGV[4]
+local owner = ARGV[5]
+local expiry = ARGV[6]
+local scheduled = ARGV[7]
+local version = ARGV[8]
 
 local extract = function(value, start)
     local index = string.find(value, 'SEPARATOR', start, true)
@@ -37,6 +42,34 @@ local invalid = function(value, score, txn)
     return false
 end
 
+-- The secondary indexes map the owner and the transaction to the fields of the
+-- key, the index hash keeps track of what each field was indexed under so that
+-- it can be removed again.
+local unindex = function(field)
+    local entry = redis.call('HGET', key .. 'INDEXSUFFIX', field)
+    if not entry then
+        return
+    end
+
+    local index = string.find(entry, 'SEPARATOR', 1, true)
+    if index then
+        local entryOwner = string.sub(entry, 1, index - 1)
+        if entryOwner ~= '' then
+            redis.call('SREM', key .. 'OWNERSUFFIX' .. entryOwner, field)
+        end
+        redis.call('SREM', key .. 'TXNSUFFIX' .. string.sub(entry, index + 1), field)
+    end
+    redis.call('HDEL', key .. 'INDEXSUFFIX', field)
+end
+
+local index = function(field, owner, txn)
+    if owner ~= '' then
+        redis.call('SADD', key .. 'OWNERSUFFIX' .. owner, field)
+    end
+    redis.call('SADD', key .. 'TXNSUFFIX' .. txn, field)
+    redis.call('HSET', key .. 'INDEXSUFFIX', field, owner .. 'SEPARATOR' .. txn)
+end
+
 -- Check if the score associated with a key is greater than the one already
 -- existing in the store for insertions
 -- Note: last write wins
@@ -45,6 +78,19 @@ if insertion and invalid(insertion, score, txn) then
     return -1
 end
 
+-- A swap only writes if the insertion is still the version (score) that was
+-- read, so that a change that was checked against it can't over write another
+-- change that happened in the meantime.
+if version ~= '' then
+    if not insertion then
+        return -1
+    end
+    local ok, _, valueScore = extract(insertion, 1)
+    if not ok or valueScore ~= version then
+        return -1
+    end
+end
+
 -- Check if the score associated with a key is greater than the one already
 -- existing in the store for deletions
 -- Note: last write wins
@@ -56,5 +102,16 @@ end
 -- Remove the existing key if it's got a REMSUFFIX.
 redis.call('HDEL', key .. 'REMSUFFIX', field)
 
+-- Only the insertions are indexed, so a deletion just removes the field from
+-- the indexes. The schedule orders the insertions by when they expire, so that
+-- they can be swept once they're due, a deletion no longer needs sweeping.
+unindex(field)
+if 'ADDSUFFIX' == 'INSERTSUFFIX' then
+    index(field, owner, txn)
+    redis.call('ZADD', 'SCHEDULEKEY', expiry, scheduled)
+else
+    redis.call('ZREM', 'SCHEDULEKEY', scheduled)
+end
+
 -- Add the key to the store
 return redis.call('HSET', key .. 'ADDSUFFIX', field, data)
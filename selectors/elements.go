@@ -50,6 +50,28 @@ type KeyFieldScoreSizeExpiry struct {
 	Expiry     time.Duration
 }
 
+// Delivery defines a member that's been delivered to a subscriber. A member of
+// a stream is delivered again until it's acknowledged, so it should only be
+// acknowledged once it's been handled.
+type Delivery struct {
+	KeyFieldScoreSizeExpiry
+	ack func() error
+}
+
+// NewDelivery creates a Delivery of the member, which calls ack when it's
+// acknowledged.
+func NewDelivery(member KeyFieldScoreSizeExpiry, ack func() error) Delivery {
+	return Delivery{member, ack}
+}
+
+// Ack acknowledges the delivery of the member.
+func (d Delivery) Ack() error {
+	if d.ack == nil {
+		return nil
+	}
+	return d.ack()
+}
+
 // KeyFieldScoreSizeExpiries represents an alias for a slice of KeyFieldScoreSizeExpiry
 type KeyFieldScoreSizeExpiries []KeyFieldScoreSizeExpiry
 
@@ -176,6 +198,27 @@ func (k KeyFieldScoreTxnValue) FieldValue() KeyValue {
 	return KeyValue{Key: k.Field, Value: k.Value}
 }
 
+// KeyFieldScoreTxnValueExpiry pairs a key, field, score, transaction and a
+// value with the time it expires
+type KeyFieldScoreTxnValueExpiry struct {
+	Key, Field s.Key
+	Score      float64
+	Txn        s.Key
+	Value      string
+	Expiry     time.Time
+}
+
+// KeyFieldScoreTxnValue returns the KeyFieldScoreTxnValue without the expiry
+func (k KeyFieldScoreTxnValueExpiry) KeyFieldScoreTxnValue() KeyFieldScoreTxnValue {
+	return KeyFieldScoreTxnValue{
+		Key:   k.Key,
+		Field: k.Field,
+		Score: k.Score,
+		Txn:   k.Txn,
+		Value: k.Value,
+	}
+}
+
 // KeyFieldScoreTxnValues represents an alias for a slice of
 // KeyFieldScoreTxnValue
 type KeyFieldScoreTxnValues []KeyFieldScoreTxnValue
@@ -273,6 +316,21 @@ func (k KeyFieldScoreTxnValues) KeyFieldSizeExpiry(size KeySizeExpiry) []KeyFiel
 	return result
 }
 
+// Changes returns a slice of Change, marking every member with the change.
+func (k KeyFieldScoreTxnValues) Changes(change ChangeType) []Change {
+	result := make([]Change, 0, len(k))
+	for _, v := range k {
+		result = append(result, Change{
+			Type:  change,
+			Key:   v.Key,
+			Field: v.Field,
+			Score: v.Score,
+			Txn:   v.Txn,
+		})
+	}
+	return result
+}
+
 // KeyCount pairs a key, count
 type KeyCount struct {
 	Key   s.Key
@@ -341,9 +399,15 @@ func (p Path) String() string {
 	return string(p)
 }
 
-// QueryOptions defines items that we can query on.
+// QueryOptions defines items that we can query on. All the predicates have to
+// match for a record to be returned, the matching records are then sorted
+// before the offset and limit are applied.
 type QueryOptions struct {
-	OwnerId s.Key
+	OwnerId    s.Key
+	Predicates []Predicate
+	Sort       []QuerySort
+	Limit      int
+	Offset     int
 }
 
 type QueryRecord struct {
@@ -351,3 +415,85 @@ type QueryRecord struct {
 	Field  s.Key
 	Record map[string]interface{}
 }
+
+// ChangeType defines a typed alias for the types of changes that can happen to
+// a member.
+type ChangeType string
+
+// ChangeInsert and the following defines all the types of changes that are
+// recorded.
+const (
+	ChangeInsert   ChangeType = "insert"
+	ChangeDelete   ChangeType = "delete"
+	ChangeRollback ChangeType = "rollback"
+	ChangeState    ChangeType = "state"
+)
+
+func (c ChangeType) String() string {
+	return string(c)
+}
+
+// Change describes a change that happened to a member, the score of the change
+// is used to order the changes and to resume reading them. The state is only
+// set when the change moved the reservation to a new state.
+type Change struct {
+	Type       ChangeType
+	Key, Field s.Key
+	Score      float64
+	Txn        s.Key
+	State      string
+}
+
+// Changes represents an alias for a slice of Change
+type Changes []Change
+
+// Len, Swap and Less implement sort.Interface, ordering by the score and then
+// by the field, so the order is stable across clusters.
+func (c Changes) Len() int      { return len(c) }
+func (c Changes) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c Changes) Less(i, j int) bool {
+	if c[i].Score == c[j].Score {
+		return c[i].Field.String() < c[j].Field.String()
+	}
+	return c[i].Score < c[j].Score
+}
+
+// Bucketize removes the duplicate keys so we can efficently call the storage.
+func (c Changes) Bucketize() ([]s.Key, map[s.Key][]Change) {
+	a := map[s.Key][]Change{}
+
+	for _, v := range c {
+		a[v.Key] = append(a[v.Key], v)
+	}
+
+	b := make([]s.Key, 0, len(a))
+	for k := range a {
+		b = append(b, k)
+	}
+
+	return b, a
+}
+
+// WaitlistEntry defines a customer waiting for a key to have room, the members
+// are the hold that's offered to them once it does. The score is when they
+// joined, which keeps the waiting list in order.
+type WaitlistEntry struct {
+	Id      s.Key
+	Score   float64
+	Members []KeyFieldScoreTxnValue
+	MaxSize SizeExpiry
+}
+
+// Len returns how many members the entry is waiting for.
+func (w WaitlistEntry) Len() int {
+	return len(w.Members)
+}
+
+// ConsumerGroup defines a consumer with in a group of consumers reading from a
+// stream. Every group receives all the messages, but each message is only
+// delivered to one consumer with in the group. The offset is where a new group
+// starts to read from.
+type ConsumerGroup struct {
+	Group, Consumer string
+	Offset          string
+}
@@ -0,0 +1,356 @@
+package selectors
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SimonRichardson/echelon/errors"
+	"github.com/SimonRichardson/echelon/internal/typex"
+)
+
+// Comparison defines a typed alias for all the comparisons a predicate can
+// make.
+type Comparison string
+
+// Equal and the following defines all the comparisons that are available with
+// in a predicate.
+const (
+	Equal              Comparison = "eq"
+	NotEqual           Comparison = "ne"
+	LessThan           Comparison = "lt"
+	LessThanOrEqual    Comparison = "lte"
+	GreaterThan        Comparison = "gt"
+	GreaterThanOrEqual Comparison = "gte"
+	In                 Comparison = "in"
+)
+
+func (c Comparison) String() string {
+	return string(c)
+}
+
+// Index defines a typed alias for the secondary indexes of the store.
+type Index string
+
+// OwnerIndex and the following defines all the secondary indexes that are
+// maintained by the store.
+const (
+	OwnerIndex       Index = "owner_id"
+	TransactionIndex Index = "transaction_id"
+)
+
+func (i Index) String() string {
+	return string(i)
+}
+
+const (
+	predicateSeparator = ":"
+	inSeparator        = ","
+	pathSeparator      = "."
+	descendingPrefix   = "-"
+	nowValue           = "now"
+)
+
+// queryAliases defines the short hand names for the fields with in a record.
+var queryAliases = map[string]string{
+	"updated":        "meta.model.updated_at",
+	"expiry":         "expiry_time",
+	"transaction_id": "txn",
+}
+
+// queryIndexes defines the fields (or aliases) with in a record that can be
+// resolved via a secondary index.
+var queryIndexes = map[string]Index{
+	"owner_id":       OwnerIndex,
+	"transaction_id": TransactionIndex,
+	"txn":            TransactionIndex,
+}
+
+// Predicate defines a comparison of a field with in a record, against a set of
+// values. Fields can be nested by using a "." (e.g. "cost.price").
+type Predicate struct {
+	Field      string
+	Comparison Comparison
+	Values     []string
+}
+
+// QuerySort defines how the records should be sorted by a field.
+type QuerySort struct {
+	Field      string
+	Descending bool
+}
+
+// ParsePredicate parses a predicate in the form of "field:comparison:value",
+// where the values of the "in" comparison are separated by a ",". Time values
+// can be relative to now (e.g. "now+1m").
+func ParsePredicate(value string) (Predicate, error) {
+	parts := strings.SplitN(value, predicateSeparator, 3)
+	if len(parts) != 3 || len(parts[0]) < 1 {
+		return Predicate{}, typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid predicate %q", value)
+	}
+
+	comparison := Comparison(strings.ToLower(parts[1]))
+	switch comparison {
+	case Equal, NotEqual, LessThan, LessThanOrEqual, GreaterThan, GreaterThanOrEqual:
+		return Predicate{parts[0], comparison, []string{parts[2]}}, nil
+	case In:
+		return Predicate{parts[0], comparison, strings.Split(parts[2], inSeparator)}, nil
+	}
+	return Predicate{}, typex.Errorf(errors.Source, errors.InvalidArgument,
+		"Invalid predicate comparison %q", parts[1])
+}
+
+// ParseQuerySort parses a sort in the form of "field", or "-field" for a
+// descending sort.
+func ParseQuerySort(value string) (QuerySort, error) {
+	descending := strings.HasPrefix(value, descendingPrefix)
+	field := strings.TrimPrefix(value, descendingPrefix)
+	if len(field) < 1 {
+		return QuerySort{}, typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid sort %q", value)
+	}
+	return QuerySort{field, descending}, nil
+}
+
+// Match checks to see if the record matches the predicate. A record that
+// doesn't have the field never matches.
+func (p Predicate) Match(record map[string]interface{}, now time.Time) (bool, error) {
+	actual, ok := lookup(record, p.Field)
+	if !ok {
+		return false, nil
+	}
+
+	for _, v := range p.Values {
+		expected, err := parseLike(actual, v, now)
+		if err != nil {
+			return false, err
+		}
+
+		res := compare(actual, expected)
+		switch p.Comparison {
+		case Equal, In:
+			if res == 0 {
+				return true, nil
+			}
+		case NotEqual:
+			return res != 0, nil
+		case LessThan:
+			return res < 0, nil
+		case LessThanOrEqual:
+			return res <= 0, nil
+		case GreaterThan:
+			return res > 0, nil
+		case GreaterThanOrEqual:
+			return res >= 0, nil
+		}
+	}
+	return false, nil
+}
+
+// Index returns the secondary index (and the value to look up) that can be
+// used to narrow down the records before they're filtered. Only the owner and
+// an equal predicate on an indexed field can be resolved via an index.
+func (q QueryOptions) Index() (Index, string, bool) {
+	if q.OwnerId.Len() > 0 {
+		return OwnerIndex, q.OwnerId.String(), true
+	}
+	for _, v := range q.Predicates {
+		if v.Comparison != Equal || len(v.Values) != 1 {
+			continue
+		}
+		if index, ok := queryIndexes[v.Field]; ok {
+			return index, v.Values[0], true
+		}
+	}
+	return "", "", false
+}
+
+// Filter applies the predicates, sorting, offset and limit of the options to
+// the records.
+func (q QueryOptions) Filter(records []QueryRecord, now time.Time) ([]QueryRecord, error) {
+	predicates := q.Predicates
+	if q.OwnerId.Len() > 0 {
+		predicates = append([]Predicate{
+			Predicate{"owner_id", Equal, []string{q.OwnerId.String()}},
+		}, predicates...)
+	}
+
+	result := make([]QueryRecord, 0, len(records))
+	for _, record := range records {
+		matched := true
+		for _, predicate := range predicates {
+			ok, err := predicate.Match(record.Record, now)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, record)
+		}
+	}
+
+	if len(q.Sort) > 0 {
+		sort.SliceStable(result, func(i, j int) bool {
+			for _, v := range q.Sort {
+				a, _ := lookup(result[i].Record, v.Field)
+				b, _ := lookup(result[j].Record, v.Field)
+				if res := compare(normalise(a), normalise(b)); res != 0 {
+					return (res < 0) != v.Descending
+				}
+			}
+			return false
+		})
+	}
+
+	if q.Offset > 0 {
+		if q.Offset >= len(result) {
+			return []QueryRecord{}, nil
+		}
+		result = result[q.Offset:]
+	}
+	if q.Limit > 0 && q.Limit < len(result) {
+		result = result[:q.Limit]
+	}
+	return result, nil
+}
+
+func lookup(record map[string]interface{}, field string) (interface{}, bool) {
+	if alias, ok := queryAliases[field]; ok {
+		field = alias
+	}
+
+	var (
+		parts   = strings.Split(field, pathSeparator)
+		current = record
+	)
+	for k, part := range parts {
+		value, ok := current[part]
+		if !ok {
+			return nil, false
+		}
+		if k == len(parts)-1 {
+			return normalise(value), true
+		}
+		if current, ok = value.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+type hexer interface {
+	Hex() string
+}
+
+// normalise reduces the value to either a time, a number or a string, so that
+// values can be compared.
+func normalise(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case string:
+		return v
+	case hexer:
+		return v.Hex()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+// parseLike parses the value, so that it's the same type as the actual value.
+func parseLike(actual interface{}, value string, now time.Time) (interface{}, error) {
+	switch actual.(type) {
+	case time.Time:
+		return parseTime(value, now)
+	case float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid number %q", value)
+		}
+		return v, nil
+	}
+	return value, nil
+}
+
+// parseTime parses either a RFC3339 time, unix nanoseconds, or a time that is
+// relative to now (e.g. "now", "now+1m", "now-30s").
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if strings.HasPrefix(value, nowValue) {
+		offset := strings.TrimPrefix(value, nowValue)
+		if len(offset) < 1 {
+			return now, nil
+		}
+		duration, err := time.ParseDuration(offset)
+		if err != nil {
+			return time.Time{}, typex.Errorf(errors.Source, errors.InvalidArgument,
+				"Invalid relative time %q", value)
+		}
+		return now.Add(duration), nil
+	}
+
+	if nanos, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(0, nanos), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, typex.Errorf(errors.Source, errors.InvalidArgument,
+			"Invalid time %q", value)
+	}
+	return t, nil
+}
+
+// compare returns -1, 0 or 1 depending on if a is less than, equal to or
+// greater than b. Values of different types are compared as strings.
+func compare(a, b interface{}) int {
+	switch x := a.(type) {
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			switch {
+			case x.Before(y):
+				return -1
+			case x.After(y):
+				return 1
+			}
+			return 0
+		}
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
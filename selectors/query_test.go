@@ -0,0 +1,224 @@
+package selectors
+
+import (
+	"fmt"
+	"testing"
+	"testing/quick"
+	"time"
+
+	bs "github.com/SimonRichardson/echelon/internal/selectors"
+)
+
+func queryRecord(field string, price float64, expiry time.Time) QueryRecord {
+	return QueryRecord{
+		Key:   bs.Key("key"),
+		Field: bs.Key(field),
+		Record: map[string]interface{}{
+			"owner_id":    field,
+			"expiry_time": expiry,
+			"cost": map[string]interface{}{
+				"currency": "GBP",
+				"price":    price,
+			},
+		},
+	}
+}
+
+func TestParsePredicate(t *testing.T) {
+	var (
+		f = func(field, value string) bool {
+			if len(field) < 1 {
+				return true
+			}
+
+			predicate, err := ParsePredicate(fmt.Sprintf("%s:gte:%s", field, value))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return predicate.Comparison == GreaterThanOrEqual &&
+				len(predicate.Values) == 1 &&
+				predicate.Values[0] == value
+		}
+	)
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParsePredicateWithTime(t *testing.T) {
+	predicate, err := ParsePredicate("expiry:lt:2016-06-07T14:36:03Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, actual := "2016-06-07T14:36:03Z", predicate.Values[0]; expected != actual {
+		t.Errorf("Expected: %q, Actual: %q", expected, actual)
+	}
+}
+
+func TestParsePredicateWithInvalidComparison(t *testing.T) {
+	if _, err := ParsePredicate("expiry:between:now"); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestQueryOptionsFilterRange(t *testing.T) {
+	var (
+		f = func(a, b float64) bool {
+			var (
+				now     = time.Now()
+				records = []QueryRecord{
+					queryRecord("a", a, now),
+					queryRecord("b", b, now),
+				}
+				options = QueryOptions{
+					Predicates: []Predicate{
+						Predicate{"cost.price", GreaterThan, []string{fmt.Sprint(a)}},
+					},
+				}
+			)
+
+			result, err := options.Filter(records, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if b > a {
+				return len(result) == 1 && result[0].Field == bs.Key("b")
+			}
+			return len(result) == 0
+		}
+	)
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQueryOptionsFilterRelativeTime(t *testing.T) {
+	var (
+		now     = time.Now()
+		records = []QueryRecord{
+			queryRecord("a", 1, now.Add(-time.Second)),
+			queryRecord("b", 1, now.Add(time.Second*30)),
+			queryRecord("c", 1, now.Add(time.Minute*2)),
+		}
+		options = QueryOptions{
+			Predicates: []Predicate{
+				Predicate{"expiry", GreaterThanOrEqual, []string{"now"}},
+				Predicate{"expiry", LessThanOrEqual, []string{"now+1m"}},
+			},
+		}
+	)
+
+	result, err := options.Filter(records, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 1 || result[0].Field != bs.Key("b") {
+		t.Errorf("Expected: [b], Actual: %v", result)
+	}
+}
+
+func TestQueryOptionsFilterIn(t *testing.T) {
+	var (
+		now     = time.Now()
+		records = []QueryRecord{
+			queryRecord("a", 1, now),
+			queryRecord("b", 1, now),
+			queryRecord("c", 1, now),
+		}
+		options = QueryOptions{
+			Predicates: []Predicate{
+				Predicate{"owner_id", In, []string{"a", "c"}},
+			},
+		}
+	)
+
+	result, err := options.Filter(records, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 || result[0].Field != bs.Key("a") || result[1].Field != bs.Key("c") {
+		t.Errorf("Expected: [a c], Actual: %v", result)
+	}
+}
+
+func TestQueryOptionsFilterSortLimitOffset(t *testing.T) {
+	var (
+		f = func(prices []float64, offset, limit uint8) bool {
+			var (
+				now     = time.Now()
+				records = make([]QueryRecord, 0, len(prices))
+			)
+			for k, v := range prices {
+				records = append(records, queryRecord(fmt.Sprint(k), v, now))
+			}
+
+			options := QueryOptions{
+				Sort:   []QuerySort{QuerySort{"cost.price", true}},
+				Offset: int(offset % 10),
+				Limit:  int(limit%10) + 1,
+			}
+
+			result, err := options.Filter(records, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := len(prices) - options.Offset
+			if expected < 0 {
+				expected = 0
+			}
+			if expected > options.Limit {
+				expected = options.Limit
+			}
+			if len(result) != expected {
+				return false
+			}
+
+			for i := 1; i < len(result); i++ {
+				a := result[i-1].Record["cost"].(map[string]interface{})["price"].(float64)
+				b := result[i].Record["cost"].(map[string]interface{})["price"].(float64)
+				if a < b {
+					return false
+				}
+			}
+			return true
+		}
+	)
+
+	if err := quick.Check(f, config()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQueryOptionsIndex(t *testing.T) {
+	for _, v := range []struct {
+		options QueryOptions
+		index   Index
+		value   string
+		ok      bool
+	}{
+		{QueryOptions{OwnerId: bs.Key("a")}, OwnerIndex, "a", true},
+		{QueryOptions{Predicates: []Predicate{
+			Predicate{"cost.price", Equal, []string{"1"}},
+			Predicate{"transaction_id", Equal, []string{"b"}},
+		}}, TransactionIndex, "b", true},
+		{QueryOptions{Predicates: []Predicate{
+			Predicate{"txn", Equal, []string{"c"}},
+		}}, TransactionIndex, "c", true},
+		{QueryOptions{Predicates: []Predicate{
+			Predicate{"owner_id", In, []string{"a", "c"}},
+		}}, "", "", false},
+		{QueryOptions{}, "", "", false},
+	} {
+		index, value, ok := v.options.Index()
+		if index != v.index || value != v.value || ok != v.ok {
+			t.Errorf("Expected: %v %q %t, Actual: %v %q %t", v.index, v.value, v.ok, index, value, ok)
+		}
+	}
+}
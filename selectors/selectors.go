@@ -1,54 +1,68 @@
 package selectors
 
 import (
+	"context"
+
 	s "github.com/SimonRichardson/echelon/internal/selectors"
 )
 
+// The context passed to the storage defines how long a caller is willing to
+// wait for an answer. Once it's done, the call returns the error of the
+// context and the outstanding requests are abandoned.
+
 // Inserter defines a way to insert a series of items into the storage
 type Inserter interface {
-	Insert([]KeyFieldScoreTxnValue, KeySizeExpiry) (int, error)
+	Insert(context.Context, []KeyFieldScoreTxnValue, KeySizeExpiry) (int, error)
+}
+
+// Batcher defines a way to insert a series of items over multiple keys into the
+// storage, where either all of the items are inserted or none of them are.
+// Recover rolls back the batches that were left part way through.
+type Batcher interface {
+	Batch(context.Context, []KeyFieldScoreTxnValue, KeySizeExpiry) (int, error)
+	Recover(context.Context) (int, error)
 }
 
 // Modifier defines a way to modify values already existing with in the storage
 // system. Essentially this boils down to a new insert that over-writes existing
 // values.
 type Modifier interface {
-	Modify([]KeyFieldScoreTxnValue, KeySizeExpiry) (int, error)
-	ModifyWithOperations(s.Key, s.Key, []Operation, float64, SizeExpiry) (int, error)
+	Modify(context.Context, []KeyFieldScoreTxnValue, KeySizeExpiry) (int, error)
+	ModifyWithOperations(context.Context, s.Key, s.Key, []Operation, float64, SizeExpiry) (int, error)
 }
 
 // Deleter defines a way to remove items that where set with in the storage
 type Deleter interface {
-	Delete([]KeyFieldScoreTxnValue, KeySizeExpiry) (int, error)
-	Rollback([]KeyFieldScoreTxnValue, KeySizeExpiry) error
+	Delete(context.Context, []KeyFieldScoreTxnValue, KeySizeExpiry) (int, error)
+	Rollback(context.Context, []KeyFieldScoreTxnValue, KeySizeExpiry) error
 }
 
 // Selector defines a way to query the storage
 type Selector interface {
-	Select(s.Key, s.Key) (KeyFieldScoreTxnValue, error)
-	SelectRange(s.Key, int, KeySizeExpiry) ([]KeyFieldScoreTxnValue, error)
+	Select(context.Context, s.Key, s.Key) (KeyFieldScoreTxnValue, error)
+	SelectRange(context.Context, s.Key, int, KeySizeExpiry) ([]KeyFieldScoreTxnValue, error)
 	// TODO (Implement SelectOffset)
 }
 
 // Scanner defines a way to introspect the storage
 type Scanner interface {
-	Keys() ([]s.Key, error)
-	Size(s.Key) (int, error)
-	Members(s.Key) ([]s.Key, error)
+	Keys(context.Context) ([]s.Key, error)
+	Size(context.Context, s.Key) (int, error)
+	Members(context.Context, s.Key) ([]s.Key, error)
 }
 
 // Repairer defines a way to repair the storage
 // This is mainly for internal use, but could be used as a peridoical repairing
 // stragegy
 type Repairer interface {
-	Repair([]KeyFieldTxnValue, KeySizeExpiry) error
+	Repair(context.Context, []KeyFieldTxnValue, KeySizeExpiry) error
 }
 
 // Notifier defines a way to know when a change in the system has occured
 type Notifier interface {
-	Publish(Channel, []KeyFieldScoreSizeExpiry) error
-	Unpublish(Channel, []KeyFieldScoreSizeExpiry) error
-	Subscribe(Channel) <-chan KeyFieldScoreSizeExpiry
+	Publish(context.Context, Channel, []KeyFieldScoreSizeExpiry) error
+	Unpublish(context.Context, Channel, []KeyFieldScoreSizeExpiry) error
+	Subscribe(context.Context, Channel) <-chan Delivery
 }
 
 // Manager defines a way to start something then stop something with in a system
@@ -60,7 +74,16 @@ type Manager interface {
 // Inspector defines a way to inspect the store.
 // Note: it's not optimised and can be considered exploitative and slow
 type Inspector interface {
-	Query(s.Key, QueryOptions, SizeExpiry) ([]QueryRecord, error)
+	Query(context.Context, s.Key, QueryOptions, SizeExpiry) ([]QueryRecord, error)
+}
+
+// Waitlister defines a way to wait for room with in the storage, where the
+// entries are offered a hold in the order that they joined.
+type Waitlister interface {
+	Join(context.Context, s.Key, WaitlistEntry) (int, error)
+	Leave(context.Context, s.Key, s.Key) (bool, error)
+	Position(context.Context, s.Key, s.Key) (int, int, bool, error)
+	Promote(context.Context, s.Key) (int, error)
 }
 
 // LifeCycleManager defines a way to know approximately who's calling what, so